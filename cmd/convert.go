@@ -0,0 +1,158 @@
+// Copyright © 2025 Ping Identity Corporation
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pingidentity/pingcli/shared/grpc"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/exporter"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
+	"github.com/spf13/pflag"
+)
+
+// Command metadata for the convert subcommand
+var (
+	// ConvertExample provides usage examples for the command
+	ConvertExample = `  # Convert a flow exported from the DaVinci UI
+  pingcli tf convert --input ./my-flow.json --out ./terraform
+
+  # Convert a multi-flow export (parent flow with its subflows)
+  pingcli tf convert --input ./flows-export.json --out ./terraform
+
+  # Convert a directory of saved PingOne API responses
+  pingcli tf convert --input ./api_responses --out ./terraform
+
+  # Provide the target environment ID to generate import blocks
+  pingcli tf convert --input ./api_responses \
+    --environment-id <uuid> \
+    --include-imports`
+
+	// ConvertLong provides a detailed description of the command
+	ConvertLong = `Convert saved DaVinci JSON to a Terraform module without connecting to PingOne.
+
+Accepts any mix of files and directories (directories are searched recursively
+for *.json files). Each file may contain:
+  • A single flow exported from the DaVinci UI
+  • A multi-flow export (top-level "flows" array)
+  • A PingOne API response for a flow, variable, connector instance,
+    application or flow policy (single resource or "_embedded" collection)
+
+The output is the same module structure generated by the export subcommand.
+References to resources that are not part of the input are left as TODO
+placeholders.`
+
+	// ConvertShort provides a brief, one-line description of the command
+	ConvertShort = "Convert saved DaVinci JSON to a Terraform module"
+
+	// ConvertUse defines the command's name and its arguments/flags syntax
+	ConvertUse = "convert --input <file|dir> [flags]"
+)
+
+// ConvertCommand is the implementation of the convert subcommand.
+// It converts local DaVinci JSON files to a Terraform module.
+type ConvertCommand struct{}
+
+// A compile-time check to ensure ConvertCommand correctly implements the
+// grpc.PingCliCommand interface.
+var _ grpc.PingCliCommand = (*ConvertCommand)(nil)
+
+// Configuration returns the convert subcommand metadata
+func (c *ConvertCommand) Configuration() (*grpc.PingCliCommandConfiguration, error) {
+	cmdConfig := &grpc.PingCliCommandConfiguration{
+		Example: ConvertExample,
+		Long:    ConvertLong,
+		Short:   ConvertShort,
+		Use:     ConvertUse,
+	}
+
+	return cmdConfig, nil
+}
+
+// Run is the execution entry point for the convert subcommand.
+func (c *ConvertCommand) Run(args []string, logger grpc.Logger) error {
+	flags := pflag.NewFlagSet("convert", pflag.ContinueOnError)
+
+	inputs := flags.StringSliceP("input", "i", nil, "DaVinci JSON file or directory to convert (repeatable; positional arguments are also accepted)")
+	environmentID := flags.String("environment-id", "", "PingOne environment ID for environment_id values and import IDs (default: detected from input)")
+	out := flags.StringP("out", "o", "", "Output directory path (default: current directory)")
+	skipDependencies := flags.Bool("skip-dependencies", false, "Skip dependency resolution")
+
+	// Module generation flags (shared with export)
+	moduleDir := flags.String("module-dir", "ping-export-module", "Name of the child module directory")
+	moduleName := flags.String("module-name", "ping-export", "Used to define Terraform module and prefix generated content (default \"ping-export\")")
+	includeImports := flags.Bool("include-imports", false, "Generate import blocks in root module (requires an environment ID)")
+	includeValues := flags.Bool("include-values", false, "Populate variable values in module.tf from the input")
+	format := registerFormatFlag(flags)
+	naming := registerNamingFlags(flags)
+
+	// Resource filter flags (shared with export)
+	filters := registerFilterFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := module.ValidateFormat(*format); err != nil {
+		return err
+	}
+
+	paths := append(append([]string{}, *inputs...), flags.Args()...)
+	if len(paths) == 0 {
+		return fmt.Errorf("input is required: use --input <file|dir>")
+	}
+
+	filter, err := filters.resourceFilter()
+	if err != nil {
+		return err
+	}
+
+	resources, err := exporter.LoadLocalResources(paths)
+	if err != nil {
+		return fmt.Errorf("failed to load input: %w", err)
+	}
+	if resources.Count() == 0 {
+		return fmt.Errorf("no DaVinci resources found in input")
+	}
+
+	outputDir := *out
+	if outputDir == "" {
+		outputDir = "."
+	}
+
+	// Resources keep the names locked by previous conversions
+	nameLock, strategy, err := naming.load(outputDir)
+	if err != nil {
+		return err
+	}
+
+	exportedData, err := exporter.ExportLocalResourcesForModule(resources, *environmentID, exporter.ExportOptions{
+		SkipDependencies: *skipDependencies,
+		GenerateImports:  *includeImports,
+		Filter:           filter,
+		NameLock:         nameLock,
+		NamingStrategy:   strategy,
+	}, logger)
+	if err != nil {
+		return fmt.Errorf("failed to convert input: %w", err)
+	}
+
+	if err := logger.Message(fmt.Sprintf("Generating Terraform module in: %s/%s", outputDir, *moduleDir), nil); err != nil {
+		return fmt.Errorf("failed to log message: %w", err)
+	}
+
+	moduleConfig := module.ModuleConfig{
+		OutputDir:      outputDir,
+		ModuleDirName:  *moduleDir,
+		ModuleName:     *moduleName,
+		IncludeImports: *includeImports && exportedData.EnvironmentID != "",
+		IncludeValues:  *includeValues,
+		EnvironmentID:  exportedData.EnvironmentID,
+		Format:         *format,
+	}
+
+	if err := generateModule(exportedData, moduleConfig, logger); err != nil {
+		return err
+	}
+	return writeNameLock(outputDir, exportedData, nameLock, filter != nil)
+}
@@ -0,0 +1,121 @@
+// Copyright © 2025 Ping Identity Corporation
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestConvertCommand_GeneratesModule converts saved API responses into a module
+func TestConvertCommand_GeneratesModule(t *testing.T) {
+	outDir := t.TempDir()
+	cmd := &ConvertCommand{}
+	logger := &mockLogger{}
+
+	err := cmd.Run([]string{
+		"--input", filepath.Join("..", "internal", "converter", "testdata", "api_responses"),
+		"--out", outDir,
+		"--include-imports",
+	}, logger)
+	if err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join("ping-export-module", "pingone_davinci_flow.tf"),
+		filepath.Join("ping-export-module", "pingone_davinci_variable.tf"),
+		"ping-export-module.tf",
+		"ping-export-imports.tf",
+		"ping-export-terraform.auto.tfvars",
+	}
+	for _, name := range expected {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("Expected %s to be generated: %v", name, err)
+		}
+	}
+}
+
+// TestConvertCommand_InvalidInput verifies errors for unreadable input
+func TestConvertCommand_InvalidInput(t *testing.T) {
+	cmd := &ConvertCommand{}
+	logger := &mockLogger{}
+
+	err := cmd.Run([]string{"--input", filepath.Join(t.TempDir(), "missing.json")}, logger)
+	if err == nil {
+		t.Fatal("Expected error for missing input file")
+	}
+	if !contains(err.Error(), "failed to load input") {
+		t.Errorf("Expected load error, got %q", err.Error())
+	}
+}
+
+// TestConvertCommand_Filters verifies filter flags are applied and validated
+func TestConvertCommand_Filters(t *testing.T) {
+	input := filepath.Join("..", "internal", "converter", "testdata", "api_responses")
+
+	t.Run("Excluded types are not generated", func(t *testing.T) {
+		outDir := t.TempDir()
+		cmd := &ConvertCommand{}
+		logger := &mockLogger{}
+
+		err := cmd.Run([]string{"--input", input, "--out", outDir, "--exclude-types", "variable"}, logger)
+		if err != nil {
+			t.Fatalf("Run() returned error: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(outDir, "ping-export-module", "pingone_davinci_variable.tf"))
+		if err == nil && contains(string(content), "resource \"pingone_davinci_variable\"") {
+			t.Error("Expected variables to be excluded")
+		}
+	})
+
+	t.Run("Invalid filter returns error", func(t *testing.T) {
+		cmd := &ConvertCommand{}
+		logger := &mockLogger{}
+
+		err := cmd.Run([]string{"--input", input, "--include-types", "users"}, logger)
+		if err == nil {
+			t.Fatal("Expected error for unknown resource type")
+		}
+		if !contains(err.Error(), "unknown resource type") {
+			t.Errorf("Expected unknown type error, got %q", err.Error())
+		}
+	})
+}
+
+// TestConvertCommand_JSONFormat verifies --format json writes every file in JSON syntax
+func TestConvertCommand_JSONFormat(t *testing.T) {
+	input := filepath.Join("..", "internal", "converter", "testdata", "api_responses")
+
+	t.Run("Files are written as JSON", func(t *testing.T) {
+		outDir := t.TempDir()
+		err := (&ConvertCommand{}).Run([]string{"--input", input, "--out", outDir, "--include-imports", "--format", "json"}, &mockLogger{})
+		if err != nil {
+			t.Fatalf("Run() returned error: %v", err)
+		}
+
+		for _, name := range []string{
+			filepath.Join("ping-export-module", "pingone_davinci_flow.tf"),
+			filepath.Join("ping-export-module", "variables.tf"),
+			"ping-export-module.tf",
+			"ping-export-imports.tf",
+			"ping-export-terraform.auto.tfvars",
+		} {
+			if _, err := os.Stat(filepath.Join(outDir, name+".json")); err != nil {
+				t.Errorf("Expected %s.json to be generated: %v", name, err)
+			}
+			if _, err := os.Stat(filepath.Join(outDir, name)); err == nil {
+				t.Errorf("Expected no %s in JSON format", name)
+			}
+		}
+	})
+
+	t.Run("Unknown format returns error", func(t *testing.T) {
+		err := (&ConvertCommand{}).Run([]string{"--input", input, "--format", "yaml"}, &mockLogger{})
+		if err == nil || !contains(err.Error(), `unsupported format "yaml"`) {
+			t.Errorf("Expected unsupported format error, got %v", err)
+		}
+	})
+}
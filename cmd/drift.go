@@ -0,0 +1,223 @@
+// Copyright © 2025 Ping Identity Corporation
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pingidentity/pingcli/shared/grpc"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/drift"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/exporter"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
+	"github.com/spf13/pflag"
+)
+
+// Command metadata for the drift subcommand
+var (
+	// DriftExample provides usage examples for the command
+	DriftExample = `  # Compare a previously exported module with the live environment
+  pingcli tf drift --module-dir ./terraform/ping-export-module \
+    --pingone-worker-environment-id <auth-uuid> \
+    --pingone-worker-client-id <client-id> \
+    --pingone-worker-client-secret <secret> \
+    --pingone-region-code NA
+
+  # Write a JSON report for CI (exits non-zero when drift is found)
+  pingcli tf drift --module-dir ./terraform/ping-export-module \
+    --format json --report ./drift.json
+
+  # Compare against a snapshot instead of the API (no credentials required)
+  pingcli tf drift --module-dir ./terraform/ping-export-module --from-snapshot ./snapshot`
+
+	// DriftLong provides a detailed description of the command
+	DriftLong = `Detect drift between a generated Terraform module and the live environment.
+
+Re-exports the environment and compares the result with the resource blocks of
+an existing child module (the directory containing pingone_davinci_*.tf files).
+The report lists resources added to or removed from the environment and, for
+resources in both, every changed attribute, including individual node
+properties in flow graph_data and connector instance properties.
+
+Values passed to the module as input variables are compared when the root
+module next to it has them in its *.tfvars files. Empty values, such as
+secrets, are treated as unknown and never reported as drift.
+
+Use the same --module-name and filter flags as the original export so
+resources excluded from the module are not reported as added. The command exits with an error when
+drift is detected, so it can gate CI pipelines.`
+
+	// DriftShort provides a brief, one-line description of the command
+	DriftShort = "Detect drift between a generated module and the live environment"
+
+	// DriftUse defines the command's name and its arguments/flags syntax
+	DriftUse = "drift --module-dir <dir> [flags]"
+)
+
+// DriftCommand is the implementation of the drift subcommand.
+// It compares an existing module with a fresh export of the environment.
+type DriftCommand struct{}
+
+// A compile-time check to ensure DriftCommand correctly implements the
+// grpc.PingCliCommand interface.
+var _ grpc.PingCliCommand = (*DriftCommand)(nil)
+
+// Configuration returns the drift subcommand metadata
+func (c *DriftCommand) Configuration() (*grpc.PingCliCommandConfiguration, error) {
+	cmdConfig := &grpc.PingCliCommandConfiguration{
+		Example: DriftExample,
+		Long:    DriftLong,
+		Short:   DriftShort,
+		Use:     DriftUse,
+	}
+
+	return cmdConfig, nil
+}
+
+// Run is the execution entry point for the drift subcommand.
+func (c *DriftCommand) Run(args []string, logger grpc.Logger) error {
+	flags := pflag.NewFlagSet("drift", pflag.ContinueOnError)
+
+	moduleDir := flags.String("module-dir", "", "Path of the existing child module directory to compare")
+	moduleName := flags.String("module-name", "ping-export", "Terraform module name used by the original export (default \"ping-export\")")
+	format := flags.String("format", "text", "Report format (text, json)")
+	report := flags.String("report", "", "Write the report to this file instead of the output")
+
+	// API export flags (shared with export)
+	client := registerClientFlags(flags)
+	skipDependencies := flags.Bool("skip-dependencies", false, "Skip dependency resolution")
+	fromSnapshot := flags.String("from-snapshot", "", "Compare against a snapshot directory instead of the API (no credentials required)")
+
+	// Resource filter flags (shared with export)
+	filters := registerFilterFlags(flags)
+	selectedFlows := flags.StringArray("flow", nil, "Only compare this flow (name or ID) and its transitive dependencies (repeatable)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *moduleDir == "" {
+		return fmt.Errorf("module directory is required: use --module-dir <dir>")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unsupported format: %s. Supported: text, json", *format)
+	}
+
+	settings, err := client.settings()
+	if err != nil {
+		return err
+	}
+
+	filter, err := filters.resourceFilter()
+	if err != nil {
+		return err
+	}
+
+	// Read the existing module first so a wrong path fails before any API request
+	existing, err := drift.ReadModule(*moduleDir)
+	if err != nil {
+		return fmt.Errorf("failed to read module: %w", err)
+	}
+
+	// The live export uses the resource names locked next to the module so addresses line up
+	nameLock, strategy, err := loadNaming(filepath.Dir(filepath.Clean(*moduleDir)), "", false)
+	if err != nil {
+		return err
+	}
+
+	var apiClient *api.Client
+	if *fromSnapshot != "" {
+		apiClient, err = api.NewClientFromSnapshot(*fromSnapshot)
+		if err != nil {
+			return fmt.Errorf("failed to load snapshot: %w", err)
+		}
+	} else {
+		apiClient, err = newAPIClient(context.Background(), logger, settings)
+		if err != nil {
+			return err
+		}
+	}
+
+	live, err := c.exportLiveModule(apiClient, logger, exporter.ExportOptions{
+		SkipDependencies: *skipDependencies,
+		Filter:           filter,
+		Flows:            *selectedFlows,
+		NameLock:         nameLock,
+		NamingStrategy:   strategy,
+	}, filepath.Base(filepath.Clean(*moduleDir)), *moduleName)
+	if err != nil {
+		return err
+	}
+
+	result := drift.Compare(existing, live)
+	if err := writeDriftReport(result, *format, *report, logger); err != nil {
+		return err
+	}
+
+	if result.HasDrift() {
+		return fmt.Errorf("drift detected: %d added, %d removed, %d changed", result.Added, result.Removed, result.Changed)
+	}
+	return nil
+}
+
+// exportLiveModule exports the environment into a temporary module named like the existing
+// one and reads it back for comparison
+func (c *DriftCommand) exportLiveModule(client *api.Client, logger grpc.Logger, opts exporter.ExportOptions, moduleDirName, moduleName string) (*drift.Module, error) {
+	exportedData, err := exporter.ExportEnvironmentForModule(context.Background(), client, opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export environment data: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "pingcli-drift-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Values are included so variable-backed attributes can be compared with the existing tfvars
+	moduleConfig := module.ModuleConfig{
+		OutputDir:     tempDir,
+		ModuleDirName: moduleDirName,
+		ModuleName:    moduleName,
+		IncludeValues: true,
+		EnvironmentID: exportedData.EnvironmentID,
+	}
+	moduleStructure, err := exporter.ConvertExportedDataToModuleStructure(exportedData, moduleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert exported data to module structure: %w", err)
+	}
+	if err := module.NewGenerator(moduleConfig).Generate(moduleStructure); err != nil {
+		return nil, fmt.Errorf("failed to generate module: %w", err)
+	}
+
+	live, err := drift.ReadModule(filepath.Join(tempDir, moduleDirName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exported module: %w", err)
+	}
+	return live, nil
+}
+
+// writeDriftReport renders the report in format and writes it to path, or to the output when path is empty
+func writeDriftReport(result *drift.Report, format, path string, logger grpc.Logger) error {
+	var content string
+	if format == "json" {
+		out, err := result.JSON()
+		if err != nil {
+			return err
+		}
+		content = string(out)
+	} else {
+		content = result.Text()
+	}
+
+	if path == "" {
+		return logger.Message(content, nil)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write drift report: %w", err)
+	}
+	return logger.Message(fmt.Sprintf("Drift report written to: %s", path), nil)
+}
@@ -0,0 +1,197 @@
+// Copyright © 2025 Ping Identity Corporation
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/drift"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+)
+
+// runFakeDrift runs the drift command against server for the module in moduleDir
+func runFakeDrift(server *fakeserver.Server, moduleDir string, logger *mockLogger, args ...string) error {
+	cmd := &DriftCommand{}
+	return cmd.Run(append([]string{
+		"--pingone-worker-environment-id", server.EnvironmentID(),
+		"--pingone-worker-client-id", fakeserver.ClientID,
+		"--pingone-worker-client-secret", fakeserver.ClientSecret,
+		"--pingone-api-url", server.URL,
+		"--pingone-auth-url", server.URL,
+		"--module-dir", moduleDir,
+	}, args...), logger)
+}
+
+// exportForDrift exports the fake server environment and returns the child module directory
+func exportForDrift(t *testing.T, server *fakeserver.Server) string {
+	t.Helper()
+	outDir := t.TempDir()
+	if err := runFakeExport(server, outDir, "--include-values"); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	return filepath.Join(outDir, "ping-export-module")
+}
+
+// editFile replaces old with new in path, failing when old is not present
+func editFile(t *testing.T, path, old, new string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if !strings.Contains(string(content), old) {
+		t.Fatalf("Expected %s to contain %q", path, old)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(content), old, new, 1)), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// TestDriftCommand_NoDrift verifies a freshly exported module matches the environment
+func TestDriftCommand_NoDrift(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	moduleDir := exportForDrift(t, server)
+
+	logger := &mockLogger{}
+	if err := runFakeDrift(server, moduleDir, logger); err != nil {
+		t.Fatalf("Expected no drift, got error: %v\n%v", err, logger.messages)
+	}
+	if !contains(strings.Join(logger.messages, "\n"), "No drift detected") {
+		t.Errorf("Expected no drift message, got %v", logger.messages)
+	}
+}
+
+// TestDriftCommand_ModuleName verifies a module exported with a custom name matches the environment
+func TestDriftCommand_ModuleName(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	outDir := t.TempDir()
+	if err := runFakeExport(server, outDir, "--include-values", "--module-name", "custom", "--module-dir", "custom-module"); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	logger := &mockLogger{}
+	if err := runFakeDrift(server, filepath.Join(outDir, "custom-module"), logger, "--module-name", "custom"); err != nil {
+		t.Fatalf("Expected no drift, got error: %v\n%v", err, logger.messages)
+	}
+	if !contains(strings.Join(logger.messages, "\n"), "No drift detected") {
+		t.Errorf("Expected no drift message, got %v", logger.messages)
+	}
+}
+
+// TestDriftCommand_Drift verifies module edits are reported as drift with a non-zero exit
+func TestDriftCommand_Drift(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(t *testing.T, moduleDir string)
+		expected []string
+	}{
+		{
+			name: "Node property change",
+			edit: func(t *testing.T, moduleDir string) {
+				editFile(t, filepath.Join(moduleDir, "pingone_davinci_flow.tf"), `"nodeTitle"`, `"editedTitle"`)
+			},
+			expected: []string{"~ pingone_davinci_flow.pingcli__PingOne-0020-reCAPTCHA-0020-v3-0020-subflow", "graph_data.elements.nodes", "nodeTitle", "editedTitle"},
+		},
+		{
+			name: "Connector property change",
+			edit: func(t *testing.T, moduleDir string) {
+				tfvars := filepath.Join(filepath.Dir(moduleDir), "ping-export-terraform.auto.tfvars")
+				editFile(t, tfvars, `= "d2671735-e614-486c-9ae6-bdd72c5cd716"`, `= "edited-client"`)
+			},
+			expected: []string{"~ pingone_davinci_connector_instance.pingcli__PingOne-0020-Protect", "clientId", "edited-client"},
+		},
+		{
+			name: "Resource removed from environment",
+			edit: func(t *testing.T, moduleDir string) {
+				path := filepath.Join(moduleDir, "pingone_davinci_variable.tf")
+				content, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("Failed to read variables: %v", err)
+				}
+				extra := strings.ReplaceAll(string(content), `"pingcli__companyBool_company"`, `"pingcli__deleted_company"`)
+				if err := os.WriteFile(path, []byte(string(content)+"\n"+extra), 0644); err != nil {
+					t.Fatalf("Failed to write variables: %v", err)
+				}
+			},
+			expected: []string{"- pingone_davinci_variable.pingcli__deleted_company", "1 removed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startFakeServer(t, fakeserver.Options{})
+			moduleDir := exportForDrift(t, server)
+			tt.edit(t, moduleDir)
+
+			logger := &mockLogger{}
+			err := runFakeDrift(server, moduleDir, logger)
+			if err == nil || !contains(err.Error(), "drift detected") {
+				t.Fatalf("Expected drift error, got %v", err)
+			}
+
+			output := strings.Join(logger.messages, "\n")
+			for _, element := range tt.expected {
+				if !contains(output, element) {
+					t.Errorf("Expected report to contain %q, got:\n%s", element, output)
+				}
+			}
+		})
+	}
+}
+
+// TestDriftCommand_JSONReport verifies the JSON report written with --report
+func TestDriftCommand_JSONReport(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	moduleDir := exportForDrift(t, server)
+	if err := os.Remove(filepath.Join(moduleDir, "pingone_davinci_application.tf")); err != nil {
+		t.Fatalf("Failed to remove application file: %v", err)
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "drift.json")
+	err := runFakeDrift(server, moduleDir, &mockLogger{}, "--format", "json", "--report", reportPath)
+	if err == nil {
+		t.Fatal("Expected drift error for application missing from the module")
+	}
+
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Expected report file: %v", err)
+	}
+	var report drift.Report
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Expected JSON report, got error %v:\n%s", err, content)
+	}
+	if report.Added != 1 || len(report.Resources) != 1 {
+		t.Fatalf("Expected one added resource, got %+v", report)
+	}
+	if report.Resources[0].Address != "pingone_davinci_application.pingcli__reCAPTCHA-0020-Sample-0020-Application" || report.Resources[0].Change != drift.Added {
+		t.Errorf("Expected added application, got %+v", report.Resources[0])
+	}
+}
+
+// TestDriftCommand_Validation verifies flag validation
+func TestDriftCommand_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "Missing module dir", args: []string{}, expected: "module directory is required"},
+		{name: "Unsupported format", args: []string{"--module-dir", ".", "--format", "yaml"}, expected: "unsupported format"},
+		{name: "Module without tf files", args: []string{"--module-dir", "testdata-missing"}, expected: "failed to read module"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &DriftCommand{}
+			err := cmd.Run(tt.args, &mockLogger{})
+			if err == nil || !contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -7,11 +7,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/pingidentity/pingcli/shared/grpc"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/exporter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 	"github.com/spf13/pflag"
 )
 
@@ -48,6 +53,56 @@ var (
     --pingone-worker-environment-id <uuid> \
     --skip-dependencies
 
+  # Export only flows and their connector instances, skipping test flows
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --include-types flow,connector_instance \
+    --exclude-name 'test-*'
+
+  # Export one flow with everything it depends on (connectors, variables,
+  # subflows) and the applications and flow policies that use it
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --flow "Customer Login" \
+    --flow <flow-uuid>
+
+  # Exclude resources by regular expression or ID
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --exclude-name 're:(?i)^deprecated' \
+    --exclude-id <resource-uuid>
+
+  # Fetch flow, connector instance and flow policy details 8 at a time
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --concurrency 8
+
+  # Use readable snake_case resource names instead of pingcli__ hex encoding
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --naming-strategy snake
+
+  # Export through a proxy or custom domain
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --pingone-api-url https://pingone-proxy.example.com \
+    --pingone-auth-url https://auth.example.com
+
+  # Retry rate-limited requests up to 10 times with a 2 minute timeout per request
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --max-attempts 10 \
+    --request-timeout 2m
+
+  # Record every API response to a snapshot directory (application secrets are redacted;
+  # other values may contain credentials, so keep the directory private)
+  pingcli tf export \
+    --pingone-worker-environment-id <uuid> \
+    --snapshot-dir ./snapshot
+
+  # Replay an export from a snapshot (no credentials required)
+  pingcli tf export --from-snapshot ./snapshot --out ./terraform
+
   # Use environment variables for credentials
   export PINGCLI_PINGONE_ENVIRONMENT_ID="..."
   export PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_ID="..."
@@ -74,8 +129,10 @@ Authentication for PingOne services can be provided via flags or environment var
   PINGCLI_PINGONE_ENVIRONMENT_ID                    - Environment containing the worker app
   PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_ID      - Worker app client ID
   PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_SECRET   - Worker app client secret
-  PINGCLI_PINGONE_REGION_CODE                       - Region code (AP, AU, CA, EU, NA)
-  PINGCLI_PINGONE_EXPORT_ENVIRONMENT_ID  - Target environment to export (optional, defaults to worker environment)`
+  PINGCLI_PINGONE_REGION_CODE                       - Region code (NA, EU, AP, CA, AU, SG)
+  PINGCLI_PINGONE_EXPORT_ENVIRONMENT_ID  - Target environment to export (optional, defaults to worker environment)
+  PINGCLI_PINGONE_API_URL                           - API URL override, scheme and host (optional, defaults to the region's URL)
+  PINGCLI_PINGONE_AUTH_URL                          - Auth URL override for worker tokens (optional, defaults to the region's URL)`
 
 	// ExportShort provides a brief, one-line description of the command
 	ExportShort = "Export Ping Identity resources to Terraform HCL"
@@ -115,11 +172,7 @@ func (c *ExportCommand) Run(args []string, logger grpc.Logger) error {
 	services := flags.StringSlice("services", []string{"pingone-davinci"}, "Services to export (comma-separated). Supported: pingone-davinci. Defaults to all services if not specified.")
 
 	// Define API export flags matching Ping CLI standards
-	workerEnvironmentID := flags.String("pingone-worker-environment-id", "", "PingOne environment ID containing the worker app")
-	exportEnvironmentID := flags.String("pingone-export-environment-id", "", "PingOne environment ID to export resources from (defaults to worker environment)")
-	regionCode := flags.String("pingone-region-code", "", "PingOne region code (NA, EU, AP, CA, AU)")
-	clientID := flags.String("pingone-worker-client-id", "", "OAuth worker app client ID")
-	clientSecret := flags.String("pingone-worker-client-secret", "", "OAuth worker app client secret")
+	client := registerClientFlags(flags)
 	out := flags.StringP("out", "o", "", "Output file path (default: stdout)")
 	skipDependencies := flags.Bool("skip-dependencies", false, "Skip dependency resolution")
 	skipImports := flags.Bool("skip-imports", false, "Skip generating Terraform import blocks (imports generated by default, requires Terraform 1.5+)")
@@ -129,6 +182,20 @@ func (c *ExportCommand) Run(args []string, logger grpc.Logger) error {
 	moduleName := flags.String("module-name", "ping-export", "Used to define Terraform module and prefix generated content (default \"ping-export\")")
 	includeImports := flags.Bool("include-imports", false, "Generate import blocks in root module")
 	includeValues := flags.Bool("include-values", false, "Populate variable values in module.tf from export")
+	update := flags.Bool("update", false, "Update an existing module in --out in place, rewriting only blocks whose upstream content changed")
+	format := registerFormatFlag(flags)
+	naming := registerNamingFlags(flags)
+
+	// Resource filter flags
+	filters := registerFilterFlags(flags)
+	selectedFlows := flags.StringArray("flow", nil, "Only export this flow (name or ID) and its transitive dependencies (repeatable)")
+
+	// API request flags
+	concurrency := flags.Int("concurrency", 1, "Maximum number of API requests in flight while fetching resources")
+
+	// Snapshot flags for reproducible exports
+	snapshotDir := flags.String("snapshot-dir", "", "Write every fetched API response as JSON to this directory (with manifest.json). WARNING: application secrets are redacted, but connector properties and variable values may contain credentials; treat the directory as sensitive")
+	fromSnapshot := flags.String("from-snapshot", "", "Run the export against a snapshot directory instead of the API (no credentials required)")
 
 	// Parse the provided arguments
 	if err := flags.Parse(args); err != nil {
@@ -142,85 +209,99 @@ func (c *ExportCommand) Run(args []string, logger grpc.Logger) error {
 		}
 	}
 
-	// Execute export (invert skipImports to get generateImports)
-	return c.runExport(logger, *services, *workerEnvironmentID, *exportEnvironmentID, *regionCode, *clientID, *clientSecret, *out, *skipDependencies, !*skipImports, *moduleDir, *moduleName, *includeImports, *includeValues)
-}
-
-// runExport handles API export of all resources from an environment
-// All exports now generate Terraform module structure
-func (c *ExportCommand) runExport(logger grpc.Logger, services []string, workerEnvironmentID, exportEnvironmentID, regionCode, clientID, clientSecret, out string, skipDeps bool, generateImports bool, moduleDir string, moduleName string, includeImports bool, includeValues bool) error {
-	// Log which services are being exported
-	if err := logger.Message(fmt.Sprintf("Exporting services: %v", services), nil); err != nil {
+	if err := module.ValidateFormat(*format); err != nil {
 		return err
 	}
-
-	// Currently only pingone-davinci is supported, so we can proceed directly
-	// In the future, this would route to different exporters based on services list
-
-	// Get credentials from environment variables if not provided via flags
-	if workerEnvironmentID == "" {
-		workerEnvironmentID = os.Getenv("PINGCLI_PINGONE_ENVIRONMENT_ID")
+	if *update && *format == module.FormatJSON {
+		return fmt.Errorf("--update is only supported with --format %s", module.FormatHCL)
 	}
-	if exportEnvironmentID == "" {
-		exportEnvironmentID = os.Getenv("PINGCLI_PINGONE_EXPORT_ENVIRONMENT_ID")
-		// Default export environment to worker environment if not specified
-		if exportEnvironmentID == "" {
-			exportEnvironmentID = workerEnvironmentID
-		}
+
+	if *concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", *concurrency)
 	}
-	if regionCode == "" {
-		regionCode = os.Getenv("PINGCLI_PINGONE_REGION_CODE")
+
+	settings, err := client.settings()
+	if err != nil {
+		return err
 	}
-	if clientID == "" {
-		clientID = os.Getenv("PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_ID")
+
+	if *snapshotDir != "" && *fromSnapshot != "" {
+		return fmt.Errorf("--snapshot-dir and --from-snapshot cannot be used together")
 	}
-	if clientSecret == "" {
-		clientSecret = os.Getenv("PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_SECRET")
+
+	filter, err := filters.resourceFilter()
+	if err != nil {
+		return err
 	}
 
-	// Validate required credentials
-	if workerEnvironmentID == "" {
-		return fmt.Errorf("worker environment ID is required: use --pingone-worker-environment-id flag or PINGCLI_PINGONE_ENVIRONMENT_ID env var")
+	opts := exporter.ExportOptions{
+		SkipDependencies: *skipDependencies,
+		GenerateImports:  *includeImports,
+		Filter:           filter,
+		Flows:            *selectedFlows,
+		Concurrency:      *concurrency,
 	}
-	if clientID == "" {
-		return fmt.Errorf("client ID is required: use --pingone-worker-client-id flag or PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_ID env var")
+
+	// Replay a previous snapshot without credentials
+	if *fromSnapshot != "" {
+		return c.runExportFromSnapshot(logger, *fromSnapshot, *out, opts, *moduleDir, *moduleName, *format, *includeValues, *update, naming)
 	}
-	if clientSecret == "" {
-		return fmt.Errorf("client secret is required: use --pingone-worker-client-secret flag or PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_SECRET env var")
+
+	// Execute export (invert skipImports to get generateImports)
+	return c.runExport(logger, *services, *out, opts, !*skipImports, *moduleDir, *moduleName, *format, *includeValues, *update, naming, *snapshotDir, settings)
+}
+
+// runExportFromSnapshot replays an export from a snapshot directory written with --snapshot-dir
+func (c *ExportCommand) runExportFromSnapshot(logger grpc.Logger, snapshotDir, out string, opts exporter.ExportOptions, moduleDir, moduleName, format string, includeValues, update bool, naming *namingFlags) error {
+	client, err := api.NewClientFromSnapshot(snapshotDir)
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot: %w", err)
 	}
 
-	// Default region to NA if not specified
-	if regionCode == "" {
-		regionCode = "NA"
+	if err := logger.Message(fmt.Sprintf("Exporting DaVinci from snapshot: %s (Environment: %s, Region: %s)", snapshotDir, client.EnvironmentID, client.Region), nil); err != nil {
+		return err
 	}
 
-	// Log export start
-	if err := logger.Message(fmt.Sprintf("Exporting DaVinci from environment: %s (Region: %s)", exportEnvironmentID, regionCode), nil); err != nil {
+	return c.exportAsModule(context.Background(), client, logger, opts, includeValues, update, naming, moduleDir, moduleName, format, out, client.EnvironmentID)
+}
+
+// runExport handles API export of all resources from an environment
+// All exports now generate Terraform module structure
+func (c *ExportCommand) runExport(logger grpc.Logger, services []string, out string, opts exporter.ExportOptions, generateImports bool, moduleDir string, moduleName string, format string, includeValues, update bool, naming *namingFlags, snapshotDir string, settings clientSettings) error {
+	// Log which services are being exported
+	if err := logger.Message(fmt.Sprintf("Exporting services: %v", services), nil); err != nil {
 		return err
 	}
 
-	// Create API client
-	// Use NewClient to support two-environment model: worker environment for auth, export environment for resources
+	// Currently only pingone-davinci is supported, so we can proceed directly
+	// In the future, this would route to different exporters based on services list
+
 	ctx := context.Background()
-	client, err := api.NewClient(ctx, workerEnvironmentID, exportEnvironmentID, regionCode, clientID, clientSecret)
+	client, err := newAPIClient(ctx, logger, settings)
 	if err != nil {
-		if logErr := logger.PluginError("Failed to create API client", map[string]string{
-			"worker_environment_id": workerEnvironmentID,
-			"export_environment_id": exportEnvironmentID,
-			"region_code":           regionCode,
-			"error":                 err.Error(),
-		}); logErr != nil {
-			return fmt.Errorf("failed to log error: %w", logErr)
+		return err
+	}
+
+	// Record every API response when a snapshot directory is requested
+	if snapshotDir != "" {
+		if err := client.RecordSnapshot(snapshotDir); err != nil {
+			return fmt.Errorf("failed to initialize snapshot: %w", err)
+		}
+		if err := logger.Message(fmt.Sprintf("Recording API responses to snapshot: %s", snapshotDir), nil); err != nil {
+			return err
+		}
+		if err := logger.Warn("The snapshot may contain credentials from connector properties and variable values (application secrets are redacted); keep it private", nil); err != nil {
+			return err
 		}
-		return fmt.Errorf("failed to create API client: %w", err)
 	}
 
 	// Export as module (always - module generation is now the only supported mode)
-	return c.exportAsModule(ctx, client, logger, skipDeps, includeImports, includeValues, moduleDir, moduleName, out, exportEnvironmentID)
+	return c.exportAsModule(ctx, client, logger, opts, includeValues, update, naming, moduleDir, moduleName, format, out, client.EnvironmentID)
 }
 
 // exportAsModule handles module-based export
-func (c *ExportCommand) exportAsModule(ctx context.Context, client *api.Client, logger grpc.Logger, skipDeps, includeImports, includeValues bool, moduleDir, moduleName, out, environmentID string) error {
+// opts.GenerateImports controls whether import blocks are written to the root module
+func (c *ExportCommand) exportAsModule(ctx context.Context, client *api.Client, logger grpc.Logger, opts exporter.ExportOptions, includeValues, update bool, naming *namingFlags, moduleDir, moduleName, format, out, environmentID string) error {
 	// Determine output directory
 	outputDir := out
 	if outputDir == "" {
@@ -232,11 +313,20 @@ func (c *ExportCommand) exportAsModule(ctx context.Context, client *api.Client,
 		return fmt.Errorf("failed to log message: %w", err)
 	}
 
+	// Import IDs are always collected: the module manifest records them to match resources on update
+	includeImports := opts.GenerateImports
+	opts.GenerateImports = true
+
+	// Resources keep the names locked by previous exports
+	nameLock, strategy, err := naming.load(outputDir)
+	if err != nil {
+		return err
+	}
+	opts.NameLock = nameLock
+	opts.NamingStrategy = strategy
+
 	// Export resources in structured format
-	exportedData, err := exporter.ExportEnvironmentForModule(ctx, client, exporter.ExportOptions{
-		SkipDependencies: skipDeps,
-		GenerateImports:  includeImports,
-	}, logger)
+	exportedData, err := exporter.ExportEnvironmentForModule(ctx, client, opts, logger)
 	if err != nil {
 		return fmt.Errorf("failed to export environment data: %w", err)
 	}
@@ -249,12 +339,102 @@ func (c *ExportCommand) exportAsModule(ctx context.Context, client *api.Client,
 		IncludeImports: includeImports,
 		IncludeValues:  includeValues,
 		EnvironmentID:  environmentID,
+		Format:         format,
+	}
+
+	if update {
+		err = updateModule(exportedData, moduleConfig, logger)
+	} else {
+		err = generateModule(exportedData, moduleConfig, logger)
+	}
+	if err != nil {
+		return err
+	}
+	return writeNameLock(outputDir, exportedData, nameLock, isPartialExport(opts))
+}
+
+// registerFormatFlag defines the output format flag shared by the export and convert subcommands
+func registerFormatFlag(flags *pflag.FlagSet) *string {
+	return flags.String("format", module.FormatHCL, fmt.Sprintf("Syntax of the generated files (%s); json writes .tf.json and .tfvars.json files", strings.Join(module.Formats(), ", ")))
+}
+
+// namingFlags holds the resource naming flags shared by the export and convert subcommands
+type namingFlags struct {
+	strategy   *string
+	resetNames *bool
+}
+
+// registerNamingFlags defines the resource naming flags on a flag set
+func registerNamingFlags(flags *pflag.FlagSet) *namingFlags {
+	return &namingFlags{
+		strategy:   flags.String("naming-strategy", "", fmt.Sprintf("Resource naming strategy (%s); defaults to the strategy recorded in %s, else pingcli", strings.Join(utils.NamingStrategies(), ", "), resolver.NameLockFile)),
+		resetNames: flags.Bool("reset-names", false, "Ignore the resource names locked in "+resolver.NameLockFile+" and regenerate the lock"),
+	}
+}
+
+// load reads the name lock in the output directory and resolves the naming strategy
+func (f *namingFlags) load(outputDir string) (*resolver.NameLock, utils.NamingStrategy, error) {
+	return loadNaming(outputDir, *f.strategy, *f.resetNames)
+}
+
+// loadNaming reads the name lock in the output directory, or starts a new one when resetNames is
+// set, and returns the naming strategy: the requested one, else the one the locked names were
+// derived with. Switching the strategy of locked names requires resetNames.
+func loadNaming(outputDir, strategyName string, resetNames bool) (*resolver.NameLock, utils.NamingStrategy, error) {
+	if strategyName != "" {
+		if _, err := utils.NewNamingStrategy(strategyName); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	lock := resolver.NewNameLock()
+	if !resetNames {
+		var err error
+		lock, err = resolver.ReadNameLock(filepath.Join(outputDir, resolver.NameLockFile))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load resource names: %w", err)
+		}
+	}
+
+	recorded := lock.RecordedNamingStrategy()
+	if strategyName == "" {
+		strategyName = recorded
 	}
+	if recorded != "" && strategyName != recorded {
+		return nil, nil, fmt.Errorf("resource names in %s use the %s naming strategy: use --reset-names to switch to %s", resolver.NameLockFile, recorded, strategyName)
+	}
+
+	strategy, err := utils.NewNamingStrategy(strategyName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", resolver.NameLockFile, err)
+	}
+	return lock, strategy, nil
+}
+
+// writeNameLock records the name of every exported resource in the output directory. A partial
+// export keeps the locked names of the resources it left out.
+func writeNameLock(outputDir string, exportedData *exporter.ExportedData, previous *resolver.NameLock, partial bool) error {
+	lock := exportedData.DependencyGraph.NameLock()
+	if partial {
+		lock.Merge(previous)
+	}
+	if err := lock.Write(filepath.Join(outputDir, resolver.NameLockFile)); err != nil {
+		return fmt.Errorf("failed to save resource names: %w", err)
+	}
+	return nil
+}
+
+// isPartialExport reports whether the export options leave resources out of the export
+func isPartialExport(opts exporter.ExportOptions) bool {
+	return opts.Filter != nil || len(opts.Flows) > 0
+}
 
-	// Convert exported data to module structure
-	moduleStructure, err := exporter.ConvertExportedDataToModuleStructure(exportedData, moduleConfig)
+// generateModule converts exported data to a module structure and writes the module files.
+// Shared by the export and convert subcommands.
+func generateModule(exportedData *exporter.ExportedData, moduleConfig module.ModuleConfig, logger grpc.Logger) error {
+	moduleStructure, err := buildModuleStructure(exportedData, &moduleConfig)
 	if err != nil {
-		return fmt.Errorf("failed to convert exported data to module structure: %w", err)
+		return err
 	}
 
 	// Generate module files
@@ -264,13 +444,256 @@ func (c *ExportCommand) exportAsModule(ctx context.Context, client *api.Client,
 	}
 
 	// Log success
-	if err := logger.Message(fmt.Sprintf("✓ Module successfully generated in: %s", outputDir), map[string]string{
-		"module_dir":      moduleDir,
-		"include_imports": fmt.Sprintf("%v", includeImports),
-		"include_values":  fmt.Sprintf("%v", includeValues),
+	if err := logger.Message(fmt.Sprintf("✓ Module successfully generated in: %s", moduleConfig.OutputDir), map[string]string{
+		"module_dir":      moduleConfig.ModuleDirName,
+		"include_imports": fmt.Sprintf("%v", moduleConfig.IncludeImports),
+		"include_values":  fmt.Sprintf("%v", moduleConfig.IncludeValues),
+		"format":          moduleConfig.Format,
+	}); err != nil {
+		return fmt.Errorf("failed to log success: %w", err)
+	}
+
+	return nil
+}
+
+// buildModuleStructure converts exported data to a module structure, configuring the provider
+// region when the export knows its region
+func buildModuleStructure(exportedData *exporter.ExportedData, moduleConfig *module.ModuleConfig) (*module.ModuleStructure, error) {
+	if region, ok := api.LookupRegion(exportedData.Region); ok && moduleConfig.ProviderRegionCode == "" {
+		moduleConfig.ProviderRegionCode = region.ProviderRegionCode
+	}
+
+	moduleStructure, err := exporter.ConvertExportedDataToModuleStructure(exportedData, *moduleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert exported data to module structure: %w", err)
+	}
+	return moduleStructure, nil
+}
+
+// updateModule merges exported data into the module previously generated in the output
+// directory and logs what changed
+func updateModule(exportedData *exporter.ExportedData, moduleConfig module.ModuleConfig, logger grpc.Logger) error {
+	moduleStructure, err := buildModuleStructure(exportedData, &moduleConfig)
+	if err != nil {
+		return err
+	}
+
+	report, err := module.NewGenerator(moduleConfig).Update(moduleStructure)
+	if err != nil {
+		return fmt.Errorf("failed to update module: %w", err)
+	}
+
+	for _, change := range report.Conflicts {
+		if err := logger.Warn(fmt.Sprintf("Local edits to %s in %s were overwritten by upstream changes", change.Key, change.File), nil); err != nil {
+			return fmt.Errorf("failed to log warning: %w", err)
+		}
+	}
+	for _, address := range report.Deleted {
+		if err := logger.Warn(fmt.Sprintf("%s no longer exists upstream; its block was left in place, remove it to destroy the resource", address), nil); err != nil {
+			return fmt.Errorf("failed to log warning: %w", err)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("✓ Module updated in: %s (%d added, %d updated, %d renamed, %d deleted upstream)", moduleConfig.OutputDir, len(report.Added), len(report.Updated), len(report.Renamed), len(report.Deleted)))
+	for _, change := range report.Added {
+		sb.WriteString(fmt.Sprintf("\n  + %s: %s", change.File, change.Key))
+	}
+	for _, change := range report.Updated {
+		sb.WriteString(fmt.Sprintf("\n  ~ %s: %s", change.File, change.Key))
+	}
+	for _, rename := range report.Renamed {
+		sb.WriteString(fmt.Sprintf("\n  > %s -> %s", rename.From, rename.To))
+	}
+	for _, change := range report.Kept {
+		sb.WriteString(fmt.Sprintf("\n  = %s: %s kept (set locally)", change.File, change.Key))
+	}
+	if err := logger.Message(sb.String(), map[string]string{
+		"module_dir": moduleConfig.ModuleDirName,
 	}); err != nil {
 		return fmt.Errorf("failed to log success: %w", err)
 	}
 
 	return nil
 }
+
+// filterFlags holds the resource filter flags shared by the export and convert subcommands
+type filterFlags struct {
+	includeTypes *[]string
+	excludeTypes *[]string
+	includeNames *[]string
+	excludeNames *[]string
+	excludeIDs   *[]string
+}
+
+// registerFilterFlags defines the resource filter flags on a flag set
+func registerFilterFlags(flags *pflag.FlagSet) *filterFlags {
+	return &filterFlags{
+		includeTypes: flags.StringSlice("include-types", nil, "Only export these resource types (e.g. flow,variable,connector_instance,application,flow_policy)"),
+		excludeTypes: flags.StringSlice("exclude-types", nil, "Do not export these resource types"),
+		includeNames: flags.StringArray("include-name", nil, "Only export resources whose name matches this glob, or regex with \"re:\" prefix (repeatable)"),
+		excludeNames: flags.StringArray("exclude-name", nil, "Do not export resources whose name matches this glob, or regex with \"re:\" prefix (repeatable)"),
+		excludeIDs:   flags.StringSlice("exclude-id", nil, "Do not export the resource with this ID (repeatable)"),
+	}
+}
+
+// resourceFilter builds the exporter filter from the parsed flags (nil when no filter flag is set)
+func (f *filterFlags) resourceFilter() (*exporter.ResourceFilter, error) {
+	return exporter.NewResourceFilter(exporter.ResourceFilterOptions{
+		IncludeTypes: *f.includeTypes,
+		ExcludeTypes: *f.excludeTypes,
+		IncludeNames: *f.includeNames,
+		ExcludeNames: *f.excludeNames,
+		ExcludeIDs:   *f.excludeIDs,
+	})
+}
+
+// clientFlags holds the PingOne connection and API request flags shared by the export and drift subcommands
+type clientFlags struct {
+	workerEnvironmentID *string
+	exportEnvironmentID *string
+	regionCode          *string
+	clientID            *string
+	clientSecret        *string
+	apiURL              *string
+	authURL             *string
+	maxAttempts         *int
+	requestTimeout      *time.Duration
+	pageSize            *int
+}
+
+// registerClientFlags defines the PingOne connection and API request flags on a flag set
+func registerClientFlags(flags *pflag.FlagSet) *clientFlags {
+	defaultRetry := api.DefaultRetryConfig()
+	return &clientFlags{
+		workerEnvironmentID: flags.String("pingone-worker-environment-id", "", "PingOne environment ID containing the worker app"),
+		exportEnvironmentID: flags.String("pingone-export-environment-id", "", "PingOne environment ID to export resources from (defaults to worker environment)"),
+		regionCode:          flags.String("pingone-region-code", "", fmt.Sprintf("PingOne region code (%s)", api.ValidRegionsString())),
+		clientID:            flags.String("pingone-worker-client-id", "", "OAuth worker app client ID"),
+		clientSecret:        flags.String("pingone-worker-client-secret", "", "OAuth worker app client secret"),
+		apiURL:              flags.String("pingone-api-url", "", "Override the PingOne API URL (scheme and host), e.g. a local stand-in server or proxy"),
+		authURL:             flags.String("pingone-auth-url", "", "Override the PingOne auth URL; tokens are requested from <url>/<worker-environment-id>/as/token"),
+		maxAttempts:         flags.Int("max-attempts", defaultRetry.MaxAttempts, "Maximum attempts per API request; rate-limited (429) and transient 5xx responses are retried with backoff"),
+		requestTimeout:      flags.Duration("request-timeout", defaultRetry.RequestTimeout, "Timeout for each API request attempt (0 disables it)"),
+		pageSize:            flags.Int("page-size", 0, "Number of items to request per page from list endpoints (0 uses the API default)"),
+	}
+}
+
+// settings validates the parsed request flags and returns the client settings
+func (f *clientFlags) settings() (clientSettings, error) {
+	if *f.maxAttempts < 1 {
+		return clientSettings{}, fmt.Errorf("--max-attempts must be at least 1, got %d", *f.maxAttempts)
+	}
+	if *f.requestTimeout < 0 {
+		return clientSettings{}, fmt.Errorf("--request-timeout must not be negative, got %s", *f.requestTimeout)
+	}
+	if *f.pageSize < 0 {
+		return clientSettings{}, fmt.Errorf("--page-size must not be negative, got %d", *f.pageSize)
+	}
+
+	settings := clientSettings{
+		workerEnvironmentID: *f.workerEnvironmentID,
+		exportEnvironmentID: *f.exportEnvironmentID,
+		regionCode:          *f.regionCode,
+		clientID:            *f.clientID,
+		clientSecret:        *f.clientSecret,
+		retry:               api.DefaultRetryConfig(),
+		pageSize:            *f.pageSize,
+		urls:                api.ClientOptions{APIURL: *f.apiURL, AuthURL: *f.authURL},
+	}
+	settings.retry.MaxAttempts = *f.maxAttempts
+	settings.retry.RequestTimeout = *f.requestTimeout
+	return settings, nil
+}
+
+// clientSettings holds the credentials, API endpoints and request tuning for a new API client
+type clientSettings struct {
+	workerEnvironmentID string
+	exportEnvironmentID string
+	regionCode          string
+	clientID            string
+	clientSecret        string
+	retry               api.RetryConfig
+	pageSize            int
+	urls                api.ClientOptions
+}
+
+// apply configures client with the settings
+func (s clientSettings) apply(client *api.Client) {
+	client.SetRetryConfig(s.retry)
+	client.SetPageSize(s.pageSize)
+}
+
+// newAPIClient creates an API client from the settings, falling back to environment variables
+// for values not provided via flags
+func newAPIClient(ctx context.Context, logger grpc.Logger, settings clientSettings) (*api.Client, error) {
+	// Get credentials from environment variables if not provided via flags
+	if settings.workerEnvironmentID == "" {
+		settings.workerEnvironmentID = os.Getenv("PINGCLI_PINGONE_ENVIRONMENT_ID")
+	}
+	if settings.exportEnvironmentID == "" {
+		settings.exportEnvironmentID = os.Getenv("PINGCLI_PINGONE_EXPORT_ENVIRONMENT_ID")
+		// Default export environment to worker environment if not specified
+		if settings.exportEnvironmentID == "" {
+			settings.exportEnvironmentID = settings.workerEnvironmentID
+		}
+	}
+	if settings.regionCode == "" {
+		settings.regionCode = os.Getenv("PINGCLI_PINGONE_REGION_CODE")
+	}
+	if settings.clientID == "" {
+		settings.clientID = os.Getenv("PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_ID")
+	}
+	if settings.clientSecret == "" {
+		settings.clientSecret = os.Getenv("PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_SECRET")
+	}
+	if settings.urls.APIURL == "" {
+		settings.urls.APIURL = os.Getenv("PINGCLI_PINGONE_API_URL")
+	}
+	if settings.urls.AuthURL == "" {
+		settings.urls.AuthURL = os.Getenv("PINGCLI_PINGONE_AUTH_URL")
+	}
+
+	// Validate required credentials
+	if settings.workerEnvironmentID == "" {
+		return nil, fmt.Errorf("worker environment ID is required: use --pingone-worker-environment-id flag or PINGCLI_PINGONE_ENVIRONMENT_ID env var")
+	}
+	if settings.clientID == "" {
+		return nil, fmt.Errorf("client ID is required: use --pingone-worker-client-id flag or PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_ID env var")
+	}
+	if settings.clientSecret == "" {
+		return nil, fmt.Errorf("client secret is required: use --pingone-worker-client-secret flag or PINGCLI_PINGONE_CLIENT_CREDENTIALS_CLIENT_SECRET env var")
+	}
+
+	// Default region to NA if not specified
+	if settings.regionCode == "" {
+		settings.regionCode = "NA"
+	}
+
+	// Log export start
+	if err := logger.Message(fmt.Sprintf("Exporting DaVinci from environment: %s (Region: %s)", settings.exportEnvironmentID, settings.regionCode), nil); err != nil {
+		return nil, err
+	}
+	if settings.urls.APIURL != "" || settings.urls.AuthURL != "" {
+		if err := logger.Message(fmt.Sprintf("Using custom PingOne endpoints (API URL: %q, auth URL: %q)", settings.urls.APIURL, settings.urls.AuthURL), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create API client
+	// Use NewClient to support two-environment model: worker environment for auth, export environment for resources
+	client, err := api.NewClientWithOptions(ctx, settings.workerEnvironmentID, settings.exportEnvironmentID, settings.regionCode, settings.clientID, settings.clientSecret, settings.urls)
+	if err != nil {
+		if logErr := logger.PluginError("Failed to create API client", map[string]string{
+			"worker_environment_id": settings.workerEnvironmentID,
+			"export_environment_id": settings.exportEnvironmentID,
+			"region_code":           settings.regionCode,
+			"error":                 err.Error(),
+		}); logErr != nil {
+			return nil, fmt.Errorf("failed to log error: %w", logErr)
+		}
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	settings.apply(client)
+	return client, nil
+}
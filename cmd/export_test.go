@@ -0,0 +1,454 @@
+// Copyright © 2025 Ping Identity Corporation
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+)
+
+// startFakeServer starts a fake PingOne API serving the saved API response fixtures
+func startFakeServer(t *testing.T, opts fakeserver.Options) *fakeserver.Server {
+	t.Helper()
+	dataset, err := fakeserver.LoadDefaultDataset()
+	if err != nil {
+		t.Fatalf("Failed to load fake server dataset: %v", err)
+	}
+	server := fakeserver.New(dataset, opts)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runFakeExport runs the export command against server, writing the module to outDir
+func runFakeExport(server *fakeserver.Server, outDir string, args ...string) error {
+	cmd := &ExportCommand{}
+	return cmd.Run(append([]string{
+		"--pingone-worker-environment-id", server.EnvironmentID(),
+		"--pingone-worker-client-id", fakeserver.ClientID,
+		"--pingone-worker-client-secret", fakeserver.ClientSecret,
+		"--pingone-api-url", server.URL,
+		"--pingone-auth-url", server.URL,
+		"--out", outDir,
+	}, args...), &mockLogger{})
+}
+
+// readModuleFiles returns the content of every generated file keyed by path relative to dir
+func readModuleFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read generated files: %v", err)
+	}
+	return files
+}
+
+// TestExportCommand_FakeServer runs a full export against the fake PingOne API
+func TestExportCommand_FakeServer(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	outDir := t.TempDir()
+
+	if err := runFakeExport(server, outDir, "--include-imports"); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	files := readModuleFiles(t, outDir)
+
+	expected := map[string][]string{
+		"ping-export-module/pingone_davinci_flow.tf":                    {`resource "pingone_davinci_flow" "pingcli__PingOne-0020-reCAPTCHA-0020-v3-0020-subflow"`},
+		"ping-export-module/pingone_davinci_variable.tf":                {`resource "pingone_davinci_variable" "pingcli__companyBool_company"`, `resource "pingone_davinci_variable" "pingcli__recaptchaSecret_company"`},
+		"ping-export-module/pingone_davinci_connector_instance.tf":      {`resource "pingone_davinci_connector_instance" "pingcli__PingOne-0020-Protect"`},
+		"ping-export-module/pingone_davinci_application.tf":             {`resource "pingone_davinci_application" "pingcli__reCAPTCHA-0020-Sample-0020-Application"`},
+		"ping-export-module/pingone_davinci_application_flow_policy.tf": {`resource "pingone_davinci_application_flow_policy" "pingcli__reCAPTCHA-0020-Policy"`, `pingone_davinci_flow.pingcli__PingOne-0020-reCAPTCHA-0020-v3-0020-subflow.id`},
+		"ping-export-imports.tf":                                        {"62f10a04-6c54-40c2-a97d-80a98522ff9a/087ccb17aacec9279b4c4a4b60c283a8/3f1c9d2e7a8b4c6d9e0f1a2b3c4d5e6f"},
+		"ping-export-provider.tf":                                       {`region_code = "NA"`},
+	}
+	for name, elements := range expected {
+		content, ok := files[name]
+		if !ok {
+			t.Errorf("Expected %s to be generated", name)
+			continue
+		}
+		for _, element := range elements {
+			if !contains(content, element) {
+				t.Errorf("Expected %s to contain %q", name, element)
+			}
+		}
+	}
+
+	if server.TokenRequests() != 1 {
+		t.Errorf("Expected 1 token request, got %d", server.TokenRequests())
+	}
+}
+
+// TestExportCommand_FakeServerMaskedSecrets verifies masked API secrets become sensitive
+// variables without defaults instead of being written to the module
+func TestExportCommand_FakeServerMaskedSecrets(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	outDir := t.TempDir()
+
+	if err := runFakeExport(server, outDir, "--include-values"); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	files := readModuleFiles(t, outDir)
+
+	for name, content := range files {
+		if contains(content, "******") {
+			t.Errorf("Expected masked secret to be replaced in %s", name)
+		}
+	}
+
+	connectors := files["ping-export-module/pingone_davinci_connector_instance.tf"]
+	if !contains(connectors, "${var.davinci_connection_PingOne-0020-Protect_clientSecret}") {
+		t.Errorf("Expected connector secret to reference a variable, got:\n%s", connectors)
+	}
+
+	variables := files["ping-export-module/variables.tf"]
+	for _, name := range []string{"davinci_connection_PingOne-0020-Protect_clientSecret", "davinci_variable_recaptchaSecret_company_value"} {
+		block := variableBlock(variables, name)
+		if block == "" {
+			t.Errorf("Expected module variable %s, got:\n%s", name, variables)
+			continue
+		}
+		if !contains(block, "sensitive") || contains(block, "default") {
+			t.Errorf("Expected %s to be sensitive without a default, got:\n%s", name, block)
+		}
+	}
+}
+
+// TestExportCommand_FakeServerPagination verifies every page of each list endpoint is exported
+func TestExportCommand_FakeServerPagination(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	outDir := t.TempDir()
+
+	if err := runFakeExport(server, outDir, "--page-size", "1"); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	variables := readModuleFiles(t, outDir)["ping-export-module/pingone_davinci_variable.tf"]
+	if strings.Count(variables, `resource "pingone_davinci_variable"`) != 2 {
+		t.Errorf("Expected both variables across two pages, got:\n%s", variables)
+	}
+
+	nextPage := "/v1/environments/" + server.EnvironmentID() + "/variables?cursor=1&limit=1"
+	found := false
+	for _, request := range server.Requests() {
+		if strings.HasSuffix(request, nextPage) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected second variables page to be requested, got %v", server.Requests())
+	}
+}
+
+// TestExportCommand_FakeServerErrors verifies API and authentication failures are returned
+func TestExportCommand_FakeServerErrors(t *testing.T) {
+	t.Run("API error", func(t *testing.T) {
+		server := startFakeServer(t, fakeserver.Options{Failures: map[string]int{
+			"/v1/environments/62f10a04-6c54-40c2-a97d-80a98522ff9a/connectorInstances": http.StatusForbidden,
+		}})
+
+		err := runFakeExport(server, t.TempDir())
+		if err == nil {
+			t.Fatal("Expected error for failed connector instance request")
+		}
+		if !contains(err.Error(), "failed to list connector instances") || !contains(err.Error(), "403") {
+			t.Errorf("Expected connector instance error with status, got %q", err.Error())
+		}
+	})
+
+	t.Run("Invalid credentials", func(t *testing.T) {
+		server := startFakeServer(t, fakeserver.Options{})
+
+		err := runFakeExport(server, t.TempDir(), "--pingone-worker-client-secret", "wrong")
+		if err == nil {
+			t.Fatal("Expected error for invalid credentials")
+		}
+		if !contains(err.Error(), "invalid_client") {
+			t.Errorf("Expected token error, got %q", err.Error())
+		}
+		if len(server.Requests()) != 0 {
+			t.Errorf("Expected no API requests without a token, got %v", server.Requests())
+		}
+	})
+}
+
+// variableBlock returns the variable "<name>" block from HCL content, or "" if absent
+func variableBlock(content, name string) string {
+	start := strings.Index(content, `variable "`+name+`"`)
+	if start < 0 {
+		return ""
+	}
+	end := strings.Index(content[start:], "\n}")
+	if end < 0 {
+		return content[start:]
+	}
+	return content[start : start+end+2]
+}
+
+// TestExportCommand_FakeServerUpdate verifies --update rewrites only blocks changed upstream
+// and keeps local edits and hand-added files
+func TestExportCommand_FakeServerUpdate(t *testing.T) {
+	outDir := t.TempDir()
+	if err := runFakeExport(startFakeServer(t, fakeserver.Options{}), outDir, "--include-values"); err != nil {
+		t.Fatalf("Initial export returned error: %v", err)
+	}
+
+	// Local edits
+	flowsPath := filepath.Join(outDir, "ping-export-module", "pingone_davinci_flow.tf")
+	flows, _ := os.ReadFile(flowsPath)
+	if err := os.WriteFile(flowsPath, []byte(strings.Replace(string(flows), "environment_id = var.pingone_environment_id", "environment_id = var.pingone_environment_id # managed by CI", 1)), 0644); err != nil {
+		t.Fatalf("Failed to edit flows: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "backend.tf"), []byte("terraform {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write backend.tf: %v", err)
+	}
+	tfvarsPath := filepath.Join(outDir, "ping-export-terraform.auto.tfvars")
+	tfvars, _ := os.ReadFile(tfvarsPath)
+	if err := os.WriteFile(tfvarsPath, []byte(strings.Replace(string(tfvars), `davinci_variable_recaptchaSecret_company_value = ""`, `davinci_variable_recaptchaSecret_company_value = "filled"`, 1)), 0644); err != nil {
+		t.Fatalf("Failed to edit tfvars: %v", err)
+	}
+
+	// Upstream: the connector instance is deleted
+	dataset, err := fakeserver.LoadDefaultDataset()
+	if err != nil {
+		t.Fatalf("Failed to load dataset: %v", err)
+	}
+	dataset.ConnectorInstances = nil
+	server := fakeserver.New(dataset, fakeserver.Options{})
+	t.Cleanup(server.Close)
+
+	logger := &mockLogger{}
+	cmd := &ExportCommand{}
+	err = cmd.Run([]string{
+		"--pingone-worker-environment-id", server.EnvironmentID(),
+		"--pingone-worker-client-id", fakeserver.ClientID,
+		"--pingone-worker-client-secret", fakeserver.ClientSecret,
+		"--pingone-api-url", server.URL,
+		"--pingone-auth-url", server.URL,
+		"--out", outDir,
+		"--include-values",
+		"--update",
+	}, logger)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+
+	files := readModuleFiles(t, outDir)
+	if !contains(files["ping-export-module/pingone_davinci_flow.tf"], "# managed by CI") {
+		t.Error("Expected local edit to unchanged flow to be kept")
+	}
+	if files["backend.tf"] != "terraform {}\n" {
+		t.Errorf("Expected hand-added file to be kept, got %q", files["backend.tf"])
+	}
+	if !contains(files["ping-export-terraform.auto.tfvars"], `davinci_variable_recaptchaSecret_company_value = "filled"`) {
+		t.Error("Expected filled tfvars value to be kept")
+	}
+	if !contains(files["ping-export-module/pingone_davinci_connector_instance.tf"], `resource "pingone_davinci_connector_instance" "pingcli__PingOne-0020-Protect"`) {
+		t.Error("Expected deleted connector instance block to be left in place")
+	}
+	if !contains(strings.Join(logger.warnings, "\n"), "pingone_davinci_connector_instance.pingcli__PingOne-0020-Protect no longer exists upstream") {
+		t.Errorf("Expected deleted connector instance warning, got %v", logger.warnings)
+	}
+}
+
+// TestExportCommand_FakeServerMovedBlocks verifies a flow renamed between exports, with the
+// name lock reset, gets a moved block so existing state follows the new address
+func TestExportCommand_FakeServerMovedBlocks(t *testing.T) {
+	outDir := t.TempDir()
+	if err := runFakeExport(startFakeServer(t, fakeserver.Options{}), outDir); err != nil {
+		t.Fatalf("Initial export returned error: %v", err)
+	}
+
+	dataset, err := fakeserver.LoadDefaultDataset()
+	if err != nil {
+		t.Fatalf("Failed to load dataset: %v", err)
+	}
+	dataset.Flows[0]["name"] = "reCAPTCHA subflow"
+	server := fakeserver.New(dataset, fakeserver.Options{})
+	t.Cleanup(server.Close)
+
+	if err := runFakeExport(server, outDir, "--reset-names"); err != nil {
+		t.Fatalf("Second export returned error: %v", err)
+	}
+
+	moved := readModuleFiles(t, outDir)["ping-export-moved.tf"]
+	for _, element := range []string{
+		"from = module.ping-export.pingone_davinci_flow.pingcli__PingOne-0020-reCAPTCHA-0020-v3-0020-subflow",
+		"to   = module.ping-export.pingone_davinci_flow.pingcli__reCAPTCHA-0020-subflow",
+	} {
+		if !contains(moved, element) {
+			t.Errorf("Expected moved file to contain %q, got:\n%s", element, moved)
+		}
+	}
+}
+
+// TestExportCommand_FakeServerJSONFormat verifies --format json writes the module in JSON
+// syntax and cannot be combined with --update
+func TestExportCommand_FakeServerJSONFormat(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	outDir := t.TempDir()
+	if err := runFakeExport(server, outDir, "--format", "json"); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	files := readModuleFiles(t, outDir)
+	flows, ok := files["ping-export-module/pingone_davinci_flow.tf.json"]
+	if !ok {
+		t.Fatal("Expected ping-export-module/pingone_davinci_flow.tf.json to be generated")
+	}
+	if !contains(flows, `"environment_id": "${var.pingone_environment_id}"`) {
+		t.Errorf("Expected environment reference in flows, got:\n%s", flows)
+	}
+	if _, ok := files["ping-export-module/pingone_davinci_flow.tf"]; ok {
+		t.Error("Expected no native syntax flows file")
+	}
+
+	requests := len(server.Requests())
+	err := runFakeExport(server, outDir, "--format", "json", "--update")
+	if err == nil || !contains(err.Error(), "--update is only supported with --format hcl") {
+		t.Errorf("Expected --update to be rejected in JSON format, got %v", err)
+	}
+	if len(server.Requests()) != requests {
+		t.Error("Expected no API requests when flags are rejected")
+	}
+}
+
+// TestExportCommand_FakeServerNameLock verifies resource names are locked across exports:
+// duplicate names keep their suffix when the API order changes, renamed resources keep
+// their name, and --reset-names regenerates the lock
+func TestExportCommand_FakeServerNameLock(t *testing.T) {
+	export := func(t *testing.T, outDir string, reverse bool, rename string, args ...string) string {
+		t.Helper()
+		dataset, err := fakeserver.LoadDefaultDataset()
+		if err != nil {
+			t.Fatalf("Failed to load dataset: %v", err)
+		}
+		// A second flow with the same name gets a suffix by the order flows are listed
+		duplicate := make(map[string]interface{}, len(dataset.Flows[0]))
+		for key, value := range dataset.Flows[0] {
+			duplicate[key] = value
+		}
+		duplicate["flowId"] = "0a1b2c3d4e5f60718293a4b5c6d7e8f9"
+		dataset.Flows = append(dataset.Flows, duplicate)
+		if reverse {
+			dataset.Flows[0], dataset.Flows[1] = dataset.Flows[1], dataset.Flows[0]
+		}
+		if rename != "" {
+			dataset.Flows[0]["name"] = rename
+			for i, variable := range dataset.Variables {
+				variable["name"] = fmt.Sprintf("%s%d", rename, i)
+			}
+			for i, instance := range dataset.ConnectorInstances {
+				instance["name"] = fmt.Sprintf("%s connector %d", rename, i)
+			}
+		}
+		server := fakeserver.New(dataset, fakeserver.Options{})
+		t.Cleanup(server.Close)
+
+		if err := runFakeExport(server, outDir, args...); err != nil {
+			t.Fatalf("Export returned error: %v", err)
+		}
+		return readModuleFiles(t, outDir)["names.lock.json"]
+	}
+
+	outDir := t.TempDir()
+	lock := export(t, outDir, false, "")
+	if !contains(lock, `"pingcli__PingOne-0020-reCAPTCHA-0020-v3-0020-subflow_2"`) {
+		t.Fatalf("Expected lock to record the suffixed flow name, got:\n%s", lock)
+	}
+
+	if got := export(t, outDir, true, ""); got != lock {
+		t.Errorf("Expected lock to be unchanged when the API order changes, got:\n%s", got)
+	}
+
+	if got := export(t, outDir, true, "Renamed"); got != lock {
+		t.Errorf("Expected renamed resources to keep their locked name, got:\n%s", got)
+	}
+	files := readModuleFiles(t, outDir)
+	for _, locked := range []struct{ file, label string }{
+		{"pingone_davinci_variable.tf", `"pingcli__recaptchaSecret_company"`},
+		{"pingone_davinci_variable.tf", `"pingcli__companyBool_company"`},
+		{"pingone_davinci_connector_instance.tf", `"pingcli__PingOne-0020-Protect"`},
+	} {
+		if content := files["ping-export-module/"+locked.file]; !contains(content, locked.label) {
+			t.Errorf("Expected renamed resource to keep its locked label %s, got:\n%s", locked.label, content)
+		}
+	}
+
+	reset := export(t, outDir, true, "Renamed", "--reset-names")
+	if reset == lock || !contains(reset, `"pingcli__Renamed"`) {
+		t.Errorf("Expected --reset-names to regenerate the lock, got:\n%s", reset)
+	}
+}
+
+// TestExportCommand_FakeServerNamingStrategy verifies --naming-strategy names every resource,
+// is recorded in the name lock and reused by later exports, and can only be switched with
+// --reset-names
+func TestExportCommand_FakeServerNamingStrategy(t *testing.T) {
+	server := startFakeServer(t, fakeserver.Options{})
+	outDir := t.TempDir()
+
+	if err := runFakeExport(server, outDir, "--naming-strategy", "snake", "--include-imports"); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	files := readModuleFiles(t, outDir)
+	for file, element := range map[string]string{
+		"ping-export-module/pingone_davinci_flow.tf":               `resource "pingone_davinci_flow" "pingone_recaptcha_v3_subflow"`,
+		"ping-export-module/pingone_davinci_variable.tf":           `resource "pingone_davinci_variable" "recaptchasecret_company"`,
+		"ping-export-module/pingone_davinci_connector_instance.tf": `resource "pingone_davinci_connector_instance" "pingone_protect"`,
+		"ping-export-imports.tf":                                   "to = module.ping-export.pingone_davinci_flow.pingone_recaptcha_v3_subflow",
+		"names.lock.json":                                          `"naming_strategy": "snake"`,
+	} {
+		if !contains(files[file], element) {
+			t.Errorf("Expected %s to contain %q, got:\n%s", file, element, files[file])
+		}
+	}
+	for file, content := range files {
+		if contains(content, "pingcli__") {
+			t.Errorf("Expected no pingcli names in %s", file)
+		}
+	}
+
+	// Later exports reuse the recorded strategy
+	if err := runFakeExport(server, outDir); err != nil {
+		t.Fatalf("Second export returned error: %v", err)
+	}
+	if after := readModuleFiles(t, outDir); after["ping-export-module/pingone_davinci_flow.tf"] != files["ping-export-module/pingone_davinci_flow.tf"] {
+		t.Error("Expected second export to reuse the snake naming strategy")
+	}
+
+	err := runFakeExport(server, outDir, "--naming-strategy", "kebab")
+	if err == nil || !contains(err.Error(), "resource names in names.lock.json use the snake naming strategy: use --reset-names to switch to kebab") {
+		t.Errorf("Expected naming strategy switch error, got %v", err)
+	}
+
+	if err := runFakeExport(server, outDir, "--naming-strategy", "kebab", "--reset-names"); err != nil {
+		t.Fatalf("Export with --reset-names returned error: %v", err)
+	}
+	if flows := readModuleFiles(t, outDir)["ping-export-module/pingone_davinci_flow.tf"]; !contains(flows, `"pingone-recaptcha-v3-subflow"`) {
+		t.Errorf("Expected kebab flow name after --reset-names, got:\n%s", flows)
+	}
+
+	err = runFakeExport(server, t.TempDir(), "--naming-strategy", "camel")
+	if err == nil || !contains(err.Error(), "unsupported naming strategy: camel") {
+		t.Errorf("Expected unsupported naming strategy error, got %v", err)
+	}
+}
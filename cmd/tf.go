@@ -11,6 +11,12 @@ var (
 	TfExample = `  # Export PingOne DaVinci resources to Terraform HCL
   pingcli tf export --services pingone-davinci --environment-id <uuid> --out ./environment.tf
 
+  # Convert saved DaVinci flow JSON to a Terraform module (no credentials needed)
+  pingcli tf convert --input ./my-flow.json --out ./terraform
+
+  # Report drift between a generated module and the live environment
+  pingcli tf drift --module-dir ./terraform/ping-export-module
+
   # Get help for subcommands
   pingcli tf export --help`
 
@@ -20,7 +26,9 @@ Provides tools to export resources to Terraform HCL format
 compatible with the PingOne Terraform Provider.
 
 Available subcommands:
-  export - Export Ping Identity resources from live environments to HCL
+  export  - Export Ping Identity resources from live environments to HCL
+  convert - Convert saved DaVinci JSON files to HCL without API access
+  drift   - Compare a generated module with the live environment
 
 Supported services for export:
   pingone-davinci - PingOne DaVinci flows, variables, connections, apps, policies`
@@ -66,6 +74,14 @@ func (c *TfCommand) Run(args []string, logger grpc.Logger) error {
 		cmd := &ExportCommand{}
 		return cmd.Run(subArgs, logger)
 
+	case "convert":
+		cmd := &ConvertCommand{}
+		return cmd.Run(subArgs, logger)
+
+	case "drift":
+		cmd := &DriftCommand{}
+		return cmd.Run(subArgs, logger)
+
 	case "--help", "-h", "help":
 		// Show help text
 		config, _ := c.Configuration()
@@ -71,6 +71,12 @@ func TestTfCommand_Routing(t *testing.T) {
 			expectError: true,
 			errorMsg:    "worker environment ID is required",
 		},
+		{
+			name:        "convert subcommand with missing input",
+			args:        []string{"convert"},
+			expectError: true,
+			errorMsg:    "input is required",
+		},
 		{
 			name:        "help subcommand",
 			args:        []string{"help"},
@@ -10,6 +10,13 @@ import (
 
 // ListApplications retrieves all DaVinci applications for an environment
 func (c *Client) ListApplications(ctx context.Context, environmentID string) ([]pingone.DaVinciApplicationResponse, error) {
+	return cachedCall(c, cacheKey("applications"), func() ([]pingone.DaVinciApplicationResponse, error) {
+		return c.listApplications(ctx, environmentID)
+	})
+}
+
+// listApplications retrieves all DaVinci applications for an environment, following pagination links
+func (c *Client) listApplications(ctx context.Context, environmentID string) ([]pingone.DaVinciApplicationResponse, error) {
 	if environmentID == "" {
 		return nil, fmt.Errorf("environment ID is required")
 	}
@@ -19,22 +26,24 @@ func (c *Client) ListApplications(ctx context.Context, environmentID string) ([]
 		return nil, fmt.Errorf("invalid environment ID format: %w", err)
 	}
 
-	response, _, err := c.apiClient.DaVinciApplicationsApi.GetDavinciApplications(ctx, envUUID).Execute()
+	path := fmt.Sprintf("/environments/%s/davinciApplications", envUUID.String())
+	applications, err := listHALPages[pingone.DaVinciApplicationResponse](ctx, c, path, "davinciApplications")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching applications: %w", err)
 	}
 
-	embedded := response.Embedded
-	applications, ok := embedded.GetDavinciApplicationsOk()
-	if !ok || applications == nil {
-		return []pingone.DaVinciApplicationResponse{}, nil
-	}
-
 	return applications, nil
 }
 
 // GetApplication retrieves a specific DaVinci application by ID
 func (c *Client) GetApplication(ctx context.Context, environmentID, applicationID string) (*pingone.DaVinciApplicationResponse, error) {
+	return cachedCall(c, cacheKey("applications", applicationID), func() (*pingone.DaVinciApplicationResponse, error) {
+		return c.getApplication(ctx, environmentID, applicationID)
+	})
+}
+
+// getApplication retrieves a specific DaVinci application by ID
+func (c *Client) getApplication(ctx context.Context, environmentID, applicationID string) (*pingone.DaVinciApplicationResponse, error) {
 	if environmentID == "" {
 		return nil, fmt.Errorf("environment ID is required")
 	}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// responseCache keeps successful List/Get responses in memory, keyed by cacheKey.
+// Values are stored as JSON so every caller receives its own copy.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+// EnableResponseCache makes the client remember successful List/Get responses for its
+// lifetime, so a prefetch followed by the exporters only calls the API once per resource.
+// Safe for concurrent use.
+func (c *Client) EnableResponseCache() {
+	if c.cache == nil {
+		c.cache = &responseCache{entries: make(map[string][]byte)}
+	}
+}
+
+// load decodes the cached response for key into out, reporting whether it was found
+func (rc *responseCache) load(key string, out interface{}) bool {
+	rc.mu.Lock()
+	data, ok := rc.entries[key]
+	rc.mu.Unlock()
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
+// store caches value for key; values that cannot be marshaled are not cached
+func (rc *responseCache) store(key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	rc.mu.Lock()
+	rc.entries[key] = data
+	rc.mu.Unlock()
+}
+
+// cachedCall runs fetch, serving the result from the response cache when enabled
+func cachedCall[T any](c *Client, key string, fetch func() (T, error)) (T, error) {
+	if c == nil || c.cache == nil {
+		return fetch()
+	}
+
+	var result T
+	if c.cache.load(key, &result) {
+		return result, nil
+	}
+	result, err := fetch()
+	if err != nil {
+		return result, err
+	}
+	c.cache.store(key, result)
+	return result, nil
+}
+
+// cacheKey joins a resource name and IDs into a cache key, escaping the IDs so keys are unambiguous
+func cacheKey(resource string, ids ...string) string {
+	parts := []string{resource}
+	for _, id := range ids {
+		parts = append(parts, url.PathEscape(id))
+	}
+	return strings.Join(parts, "/")
+}
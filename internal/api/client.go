@@ -3,25 +3,54 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/pingidentity/pingone-go-client/config"
 	"github.com/pingidentity/pingone-go-client/oauth2"
 	"github.com/pingidentity/pingone-go-client/pingone"
+	xoauth2 "golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 // Client wraps the PingOne API client for DaVinci operations
 type Client struct {
 	apiClient         *pingone.APIClient
-	serviceCfg        *config.Configuration // WORKAROUND: Used for raw HTTP token access in GetFlow()
+	serviceCfg        *config.Configuration // WORKAROUND: Used for raw HTTP token access in GetFlow(); nil with a custom auth URL
+	httpClient        *http.Client          // Base HTTP client for the SDK and raw HTTP requests; authenticated when serviceCfg is nil
+	transport         *retryTransport       // Retries rate-limited and transient failures
+	apiBaseURL        string                // API URL including /v1; empty uses the regional default
+	pageSize          int                   // Items requested per page from list endpoints; 0 uses the API default
+	snapshot          *snapshot             // Optional recorder/replayer of API response bodies
+	cache             *responseCache        // Optional in-memory cache of API responses
 	AuthEnvironmentID string                // Environment where OAuth client exists
 	EnvironmentID     string                // Target environment for DaVinci operations
 	Region            string
 }
 
+// ClientOptions holds optional settings for NewClientWithOptions
+type ClientOptions struct {
+	// APIURL overrides the regional API URL, e.g. "http://localhost:8080" for a local
+	// stand-in server or "https://pingone-proxy.example.com". Only the scheme and host are
+	// used; requests go to <APIURL>/v1/...
+	APIURL string
+
+	// AuthURL overrides the regional auth URL, e.g. "https://auth.example.com". Tokens are
+	// requested from <AuthURL>/<authEnvironmentID>/as/token.
+	AuthURL string
+}
+
 // NewClient creates a new API client for DaVinci operations with OAuth authentication
 // authEnvironmentID: Environment where the OAuth client exists
 // targetEnvironmentID: Environment to perform DaVinci operations on
 func NewClient(ctx context.Context, authEnvironmentID, targetEnvironmentID, region, clientID, clientSecret string) (*Client, error) {
+	return NewClientWithOptions(ctx, authEnvironmentID, targetEnvironmentID, region, clientID, clientSecret, ClientOptions{})
+}
+
+// NewClientWithOptions creates a new API client like NewClient, applying the API and auth URL
+// overrides in opts to both the SDK configuration and the raw HTTP requests
+func NewClientWithOptions(ctx context.Context, authEnvironmentID, targetEnvironmentID, region, clientID, clientSecret string, opts ClientOptions) (*Client, error) {
 	if authEnvironmentID == "" {
 		return nil, fmt.Errorf("auth environment ID is required")
 	}
@@ -31,7 +60,8 @@ func NewClient(ctx context.Context, authEnvironmentID, targetEnvironmentID, regi
 	if region == "" {
 		return nil, fmt.Errorf("region is required")
 	}
-	if !IsValidRegion(region) {
+	regionInfo, ok := LookupRegion(region)
+	if !ok {
 		return nil, fmt.Errorf("invalid region: %s (valid regions: %v)", region, ValidRegions())
 	}
 	if clientID == "" {
@@ -41,18 +71,64 @@ func NewClient(ctx context.Context, authEnvironmentID, targetEnvironmentID, regi
 		return nil, fmt.Errorf("client secret is required")
 	}
 
+	var apiURL *url.URL
+	if opts.APIURL != "" {
+		parsed, err := parseAPIURL(opts.APIURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API URL: %w", err)
+		}
+		apiURL = parsed
+	}
+	var tokenURL string
+	if opts.AuthURL != "" {
+		authURL, err := parseBaseURL(opts.AuthURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth URL: %w", err)
+		}
+		tokenURL = authURL.JoinPath(authEnvironmentID, "as", "token").String()
+	}
+
 	// Create service configuration with OAuth credentials
 	// Use authEnvironmentID for token acquisition
 	serviceCfg := config.NewConfiguration().
 		WithEnvironmentID(authEnvironmentID).
-		WithTopLevelDomain(config.TopLevelDomain(getRegionDomain(region))).
+		WithTopLevelDomain(config.TopLevelDomain(regionInfo.TopLevelDomain)).
 		WithClientID(clientID).
 		WithClientSecret(clientSecret).
 		WithGrantType(oauth2.GrantTypeClientCredentials).
 		WithStorageType(config.StorageTypeNone)
+	if apiURL != nil {
+		serviceCfg = serviceCfg.WithAPIDomain(apiURL.Host)
+	}
+
+	// Route SDK, token and raw HTTP requests through the retry transport
+	transport := newRetryTransport(nil, DefaultRetryConfig())
+	httpClient := &http.Client{Transport: transport}
+
+	// With a custom auth URL the client credentials token is fetched directly, since the SDK
+	// only supports HTTPS custom domains. The authenticated HTTP client then replaces the SDK
+	// service configuration for both SDK and raw HTTP requests.
+	if tokenURL != "" {
+		credentials := clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL,
+		}
+		tokenCtx := context.WithValue(context.Background(), xoauth2.HTTPClient, httpClient)
+		httpClient = xoauth2.NewClient(tokenCtx, credentials.TokenSource(tokenCtx))
+		serviceCfg = nil
+	}
 
 	// Initialize PingOne API client
 	cfg := pingone.NewConfiguration(serviceCfg)
+	cfg.Service = serviceCfg // NewConfiguration allocates an empty service configuration from the environment when nil
+	cfg.HTTPClient = httpClient
+	if apiURL != nil {
+		cfg.Host = apiURL.Host
+		cfg.Scheme = apiURL.Scheme
+	} else if serviceCfg == nil {
+		cfg.Host = regionInfo.APIDomain()
+	}
 	apiClient, err := pingone.NewAPIClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize API client: %w", err)
@@ -61,46 +137,52 @@ func NewClient(ctx context.Context, authEnvironmentID, targetEnvironmentID, regi
 	client := &Client{
 		apiClient:         apiClient,
 		serviceCfg:        serviceCfg,
+		httpClient:        httpClient,
+		transport:         transport,
 		AuthEnvironmentID: authEnvironmentID,
 		EnvironmentID:     targetEnvironmentID,
 		Region:            region,
 	}
+	if apiURL != nil {
+		client.apiBaseURL = apiURL.JoinPath("v1").String()
+	}
 
 	return client, nil
 }
 
-// NewClientSingleEnvironment creates a client where auth and target environment are the same
-func NewClientSingleEnvironment(ctx context.Context, environmentID, region, clientID, clientSecret string) (*Client, error) {
-	return NewClient(ctx, environmentID, environmentID, region, clientID, clientSecret)
-}
-
-// getRegionDomain returns the domain suffix for a given region code
-func getRegionDomain(region string) string {
-	switch region {
-	case "NA":
-		return "com"
-	case "EU":
-		return "eu"
-	case "AP":
-		return "asia"
-	case "CA":
-		return "ca"
-	default:
-		return "com"
+// parseAPIURL validates an API URL override. The SDK can only override the scheme and host,
+// so a path other than the API version is rejected rather than silently dropped.
+func parseAPIURL(value string) (*url.URL, error) {
+	parsed, err := parseBaseURL(value)
+	if err != nil {
+		return nil, err
+	}
+	if path := strings.TrimSuffix(parsed.Path, "/"); path != "" && path != "/v1" {
+		return nil, fmt.Errorf("%s: only scheme and host are supported (requests go to <url>/v1)", value)
 	}
+	parsed.Path = ""
+	return parsed, nil
 }
 
-// ValidRegions returns the list of valid PingOne region codes
-func ValidRegions() []string {
-	return []string{"NA", "EU", "AP", "CA"}
+// parseBaseURL parses an absolute http or https URL without query or fragment
+func parseBaseURL(value string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimRight(value, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("%s: scheme must be http or https", value)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("%s: host is required", value)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return nil, fmt.Errorf("%s: query and fragment are not supported", value)
+	}
+	return parsed, nil
 }
 
-// IsValidRegion checks if the given region code is valid
-func IsValidRegion(region string) bool {
-	for _, r := range ValidRegions() {
-		if r == region {
-			return true
-		}
-	}
-	return false
+// NewClientSingleEnvironment creates a client where auth and target environment are the same
+func NewClientSingleEnvironment(ctx context.Context, environmentID, region, clientID, clientSecret string) (*Client, error) {
+	return NewClient(ctx, environmentID, environmentID, region, clientID, clientSecret)
 }
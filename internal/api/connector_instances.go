@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/pingidentity/pingone-go-client/pingone"
 )
 
 // ConnectorInstanceSummary represents a summary of a DaVinci connector instance from the API
@@ -24,27 +25,25 @@ type ConnectorInstanceDetail struct {
 
 // ListConnectorInstances retrieves all connector instances from the environment using the SDK
 func (c *Client) ListConnectorInstances(ctx context.Context) ([]ConnectorInstanceSummary, error) {
+	return cachedCall(c, cacheKey("connector_instances"), func() ([]ConnectorInstanceSummary, error) {
+		return c.listConnectorInstances(ctx)
+	})
+}
+
+// listConnectorInstances retrieves all connector instances from the environment, following
+// pagination links and decoding each page into SDK models
+func (c *Client) listConnectorInstances(ctx context.Context) ([]ConnectorInstanceSummary, error) {
 	envID, err := uuid.Parse(c.EnvironmentID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid environment ID: %w", err)
 	}
 
-	resp, _, err := c.apiClient.DaVinciConnectorsApi.GetConnectorInstances(ctx, envID).Execute()
+	path := fmt.Sprintf("/environments/%s/connectorInstances", envID.String())
+	instancesResp, err := listHALPages[pingone.DaVinciConnectorInstanceResponse](ctx, c, path, "connectorInstances")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list connector instances: %w", err)
 	}
 
-	// Extract connector instances from embedded response
-	embedded, ok := resp.GetEmbeddedOk()
-	if !ok || embedded == nil {
-		return []ConnectorInstanceSummary{}, nil
-	}
-
-	instancesResp, ok := embedded.GetConnectorInstancesOk()
-	if !ok || instancesResp == nil {
-		return []ConnectorInstanceSummary{}, nil
-	}
-
 	// Convert to summary structure
 	instances := make([]ConnectorInstanceSummary, 0, len(instancesResp))
 	for _, instance := range instancesResp {
@@ -66,6 +65,13 @@ func (c *Client) ListConnectorInstances(ctx context.Context) ([]ConnectorInstanc
 
 // GetConnectorInstance retrieves detailed connector instance data including properties using the SDK
 func (c *Client) GetConnectorInstance(ctx context.Context, instanceID string) (*ConnectorInstanceDetail, error) {
+	return cachedCall(c, cacheKey("connector_instances", instanceID), func() (*ConnectorInstanceDetail, error) {
+		return c.getConnectorInstance(ctx, instanceID)
+	})
+}
+
+// getConnectorInstance retrieves detailed connector instance data including properties using the SDK
+func (c *Client) getConnectorInstance(ctx context.Context, instanceID string) (*ConnectorInstanceDetail, error) {
 	envID, err := uuid.Parse(c.EnvironmentID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid environment ID: %w", err)
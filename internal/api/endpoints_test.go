@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientWithOptions_CustomURLs(t *testing.T) {
+	const authEnvID = "aaaaaaaa-1234-1234-1234-123456789012"
+
+	var tokenRequests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+authEnvID+"/as/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		require.NoError(t, r.ParseForm())
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" || clientID != "client" || clientSecret != "secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/v1/environments/"+paginationTestEnvID+"/flows", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"_embedded": map[string]interface{}{"flows": []interface{}{
+				map[string]string{"id": "flow-1", "name": "Login"},
+			}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClientWithOptions(context.Background(), authEnvID, paginationTestEnvID, "EU", "client", "secret", ClientOptions{
+		APIURL:  server.URL,
+		AuthURL: server.URL + "/",
+	})
+	require.NoError(t, err)
+
+	t.Run("Raw HTTP requests use the overrides", func(t *testing.T) {
+		flows, err := client.ListFlows(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []FlowSummary{{FlowID: "flow-1", Name: "Login"}}, flows)
+
+		_, err = client.ListFlows(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests), "token is reused")
+	})
+
+	t.Run("SDK configuration uses the overrides", func(t *testing.T) {
+		cfg := client.apiClient.GetConfig()
+		assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), cfg.Host)
+		assert.Equal(t, "http", cfg.Scheme)
+		assert.Same(t, client.httpClient, cfg.HTTPClient)
+	})
+}
+
+func TestNewClientWithOptions_APIURLOnly(t *testing.T) {
+	client, err := NewClientWithOptions(context.Background(), "auth-env", "target-env", "NA", "client", "secret", ClientOptions{
+		APIURL: "https://pingone-proxy.example.com/v1/",
+	})
+	require.NoError(t, err)
+
+	requestURL, err := client.apiURL("/environments/env/flows")
+	require.NoError(t, err)
+	assert.Equal(t, "https://pingone-proxy.example.com/v1/environments/env/flows", requestURL)
+	assert.NotNil(t, client.serviceCfg, "regional auth is kept")
+	assert.Equal(t, "pingone-proxy.example.com", client.apiClient.GetConfig().Host)
+}
+
+func TestNewClientWithOptions_InvalidURLs(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          ClientOptions
+		errorContains string
+	}{
+		{name: "Missing scheme", opts: ClientOptions{APIURL: "api.example.com"}, errorContains: "invalid API URL"},
+		{name: "Unsupported scheme", opts: ClientOptions{APIURL: "ftp://api.example.com"}, errorContains: "scheme must be http or https"},
+		{name: "Unsupported path", opts: ClientOptions{APIURL: "https://proxy.example.com/pingone"}, errorContains: "only scheme and host are supported"},
+		{name: "Query string", opts: ClientOptions{AuthURL: "https://auth.example.com?x=1"}, errorContains: "query and fragment are not supported"},
+		{name: "Missing auth host", opts: ClientOptions{AuthURL: "https://"}, errorContains: "invalid auth URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClientWithOptions(context.Background(), "auth-env", "target-env", "NA", "client", "secret", tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
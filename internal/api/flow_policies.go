@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/google/uuid"
 	"github.com/pingidentity/pingone-go-client/pingone"
@@ -28,52 +29,38 @@ type FlowPolicyDetail struct {
 
 // ListFlowPolicies retrieves all flow policies from all applications in the environment
 func (c *Client) ListFlowPolicies(ctx context.Context) ([]FlowPolicySummary, error) {
+	return cachedCall(c, cacheKey("flow_policies"), func() ([]FlowPolicySummary, error) {
+		return c.listFlowPolicies(ctx)
+	})
+}
+
+// listFlowPolicies retrieves all flow policies from all applications in the environment
+func (c *Client) listFlowPolicies(ctx context.Context) ([]FlowPolicySummary, error) {
 	envID, err := uuid.Parse(c.EnvironmentID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid environment ID: %w", err)
 	}
 
 	// First, get all applications in the environment
-	appsResp, _, err := c.apiClient.DaVinciApplicationsApi.GetDavinciApplications(ctx, envID).Execute()
+	applications, err := c.listApplications(ctx, envID.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to list applications: %w", err)
 	}
 
-	// Extract applications from embedded response
-	embedded, ok := appsResp.GetEmbeddedOk()
-	if !ok || embedded == nil {
-		return []FlowPolicySummary{}, nil
-	}
-
-	applications, ok := embedded.GetDavinciApplicationsOk()
-	if !ok || applications == nil {
-		return []FlowPolicySummary{}, nil
-	}
-
 	// Collect all flow policies from all applications
 	var allPolicies []FlowPolicySummary
 
 	for _, app := range applications {
 		appID := app.GetId()
 
-		// Get flow policies for this application
-		policiesResp, _, err := c.apiClient.DaVinciApplicationsApi.GetFlowPoliciesByDavinciApplicationId(ctx, envID, appID).Execute()
+		// Get every page of flow policies for this application
+		path := fmt.Sprintf("/environments/%s/davinciApplications/%s/flowPolicies", envID.String(), url.PathEscape(appID))
+		policies, err := listHALPages[pingone.DaVinciFlowPolicyResponse](ctx, c, path, "flowPolicies")
 		if err != nil {
 			// Skip applications that don't have flow policies or have errors
 			continue
 		}
 
-		// Extract flow policies from embedded response
-		policiesEmbedded, ok := policiesResp.GetEmbeddedOk()
-		if !ok || policiesEmbedded == nil {
-			continue
-		}
-
-		policies, ok := policiesEmbedded.GetFlowPoliciesOk()
-		if !ok || policies == nil {
-			continue
-		}
-
 		// Add policies to the collection
 		for _, policy := range policies {
 			summary := FlowPolicySummary{
@@ -91,6 +78,13 @@ func (c *Client) ListFlowPolicies(ctx context.Context) ([]FlowPolicySummary, err
 
 // GetFlowPolicy retrieves detailed flow policy data including distributions and triggers
 func (c *Client) GetFlowPolicy(ctx context.Context, applicationID, policyID string) (*FlowPolicyDetail, error) {
+	return cachedCall(c, cacheKey("flow_policies", applicationID, policyID), func() (*FlowPolicyDetail, error) {
+		return c.getFlowPolicy(ctx, applicationID, policyID)
+	})
+}
+
+// getFlowPolicy retrieves detailed flow policy data including distributions and triggers
+func (c *Client) getFlowPolicy(ctx context.Context, applicationID, policyID string) (*FlowPolicyDetail, error) {
 	envID, err := uuid.Parse(c.EnvironmentID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid environment ID: %w", err)
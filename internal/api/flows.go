@@ -2,10 +2,8 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"net/url"
 
 	"github.com/google/uuid"
 )
@@ -33,9 +31,17 @@ type FlowDetail struct {
 	Enabled           bool        // Flow enabled status (API responses)
 	PublishedVersion  *int        // Published version number (API responses)
 	// Add other relevant fields as needed
+	Raw map[string]interface{} // The full API response, including fields not extracted above
 }
 
 // ListFlows retrieves all flows from the environment
+func (c *Client) ListFlows(ctx context.Context) ([]FlowSummary, error) {
+	return cachedCall(c, cacheKey("flows"), func() ([]FlowSummary, error) {
+		return c.listFlows(ctx)
+	})
+}
+
+// listFlows retrieves all flows from the environment, following pagination links
 //
 // WORKAROUND: Uses raw HTTP request to bypass SDK's strict validation of optional fields.
 // The SDK requires the Version field in flow responses, but the API returns flows where
@@ -45,70 +51,36 @@ type FlowDetail struct {
 // See WORKAROUND_RAW_HTTP.md for detailed reversion instructions.
 //
 // Related SDK Issue: Version field in DaVinciFlowResponse lacks omitempty tag.
-func (c *Client) ListFlows(ctx context.Context) ([]FlowSummary, error) {
+func (c *Client) listFlows(ctx context.Context) ([]FlowSummary, error) {
 	envID, err := uuid.Parse(c.EnvironmentID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid environment ID: %w", err)
 	}
 
-	// Build authenticated HTTP client from SDK configuration
-	base := http.DefaultClient
-	httpClient, err := c.serviceCfg.Client(ctx, base)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build authenticated HTTP client: %w", err)
-	}
-
-	// Make raw HTTP request
-	// Use the correct path structure matching the SDK
-	baseURL := fmt.Sprintf("https://api.pingone.%s/v1/environments/%s/flows",
-		getRegionDomain(c.Region), envID.String())
-
-	req, err := http.NewRequestWithContext(ctx, "GET", baseURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Accept", "application/json")
-
-	httpResp, err := httpClient.Do(req)
+	// Parse flows as raw JSON
+	flowsData, err := listHALPages[interface{}](ctx, c, fmt.Sprintf("/environments/%s/flows", envID.String()), "flows")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(httpResp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", httpResp.StatusCode, string(body))
-	}
-
-	// Parse response as raw JSON
-	var rawResponse map[string]interface{}
-	if err := json.NewDecoder(httpResp.Body).Decode(&rawResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
 
-	// Extract flows from _embedded structure
+	// Extract flow summaries from the merged pages
 	summaries := []FlowSummary{}
 
-	if embedded, ok := rawResponse["_embedded"].(map[string]interface{}); ok {
-		if flowsData, ok := embedded["flows"].([]interface{}); ok {
-			for _, flowItem := range flowsData {
-				if flowMap, ok := flowItem.(map[string]interface{}); ok {
-					summary := FlowSummary{}
-
-					if id, ok := flowMap["id"].(string); ok {
-						summary.FlowID = id
-					}
-					if name, ok := flowMap["name"].(string); ok {
-						summary.Name = name
-					}
-					if desc, ok := flowMap["description"].(string); ok {
-						summary.Description = desc
-					}
-
-					summaries = append(summaries, summary)
-				}
+	for _, flowItem := range flowsData {
+		if flowMap, ok := flowItem.(map[string]interface{}); ok {
+			summary := FlowSummary{}
+
+			if id, ok := flowMap["id"].(string); ok {
+				summary.FlowID = id
+			}
+			if name, ok := flowMap["name"].(string); ok {
+				summary.Name = name
 			}
+			if desc, ok := flowMap["description"].(string); ok {
+				summary.Description = desc
+			}
+
+			summaries = append(summaries, summary)
 		}
 	}
 
@@ -116,6 +88,13 @@ func (c *Client) ListFlows(ctx context.Context) ([]FlowSummary, error) {
 }
 
 // GetFlow retrieves detailed flow data including graph structure
+func (c *Client) GetFlow(ctx context.Context, flowID string) (*FlowDetail, error) {
+	return cachedCall(c, cacheKey("flows", flowID), func() (*FlowDetail, error) {
+		return c.getFlow(ctx, flowID)
+	})
+}
+
+// getFlow retrieves detailed flow data including graph structure
 //
 // WORKAROUND: Uses raw HTTP request to bypass SDK's strict validation of optional fields.
 // The SDK requires the Position field in flow graph nodes/edges, but the API returns flows
@@ -126,51 +105,35 @@ func (c *Client) ListFlows(ctx context.Context) ([]FlowSummary, error) {
 //
 // Related SDK Issue: Position field in DaVinciFlowGraphDataResponseElementsNode and
 // DaVinciFlowGraphDataResponseElementsEdge lacks omitempty tag and is not a pointer.
-func (c *Client) GetFlow(ctx context.Context, flowID string) (*FlowDetail, error) {
+func (c *Client) getFlow(ctx context.Context, flowID string) (*FlowDetail, error) {
 	envID, err := uuid.Parse(c.EnvironmentID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid environment ID: %w", err)
 	}
 
 	// Build authenticated HTTP client from SDK configuration
-	base := http.DefaultClient
-	httpClient, err := c.serviceCfg.Client(ctx, base)
+	httpClient, err := c.authenticatedHTTPClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build authenticated HTTP client: %w", err)
+		return nil, err
 	}
 
 	// Make raw HTTP request
 	// Use the correct path structure matching the SDK
-	baseURL := fmt.Sprintf("https://api.pingone.%s/v1/environments/%s/flows/%s",
-		getRegionDomain(c.Region), envID.String(), flowID)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", baseURL, nil)
+	requestURL, err := c.apiURL(fmt.Sprintf("/environments/%s/flows/%s", envID.String(), url.PathEscape(flowID)))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Accept", "application/json")
-
-	httpResp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(httpResp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", httpResp.StatusCode, string(body))
+		return nil, err
 	}
 
 	// Parse response as raw JSON
 	var rawResponse map[string]interface{}
-	if err := json.NewDecoder(httpResp.Body).Decode(&rawResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := getJSON(ctx, httpClient, requestURL, &rawResponse); err != nil {
+		return nil, err
 	}
 
 	// Extract flow details
 	detail := &FlowDetail{
 		FlowID: flowID,
+		Raw:    rawResponse,
 	}
 
 	if name, ok := rawResponse["name"].(string); ok {
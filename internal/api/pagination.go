@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SetPageSize sets the number of items requested per page from list endpoints.
+// 0 leaves the page size to the API default. Clients replaying a snapshot keep the page size
+// it was recorded with, so their requests match the recorded ones.
+func (c *Client) SetPageSize(size int) {
+	if c.snapshot != nil && c.snapshot.replay {
+		return
+	}
+	c.pageSize = size
+	if c.snapshot != nil {
+		c.snapshot.setPageSize(size)
+	}
+}
+
+// halCollection is the part of a HAL collection response needed to read one page
+type halCollection struct {
+	Embedded map[string]json.RawMessage `json:"_embedded"`
+	Links    struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"_links"`
+}
+
+// listHALPages fetches every page of a HAL collection, starting at path (relative to the API base
+// URL) and following _links.next until it is absent. The items under _embedded[embeddedKey] of each
+// page are decoded into T and merged in order.
+//
+// WORKAROUND: The SDK only pages the variables endpoint. The connector instance, application,
+// flow policy and flow collections are read with raw HTTP requests so no page is dropped.
+func listHALPages[T any](ctx context.Context, c *Client, path, embeddedKey string) ([]T, error) {
+	httpClient, err := c.authenticatedHTTPClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := c.firstPageURL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []T{}
+	visited := make(map[string]bool)
+	for next != "" {
+		if visited[next] {
+			return nil, fmt.Errorf("pagination loop detected at %s", next)
+		}
+		visited[next] = true
+
+		var page halCollection
+		if err := getJSON(ctx, httpClient, next, &page); err != nil {
+			return nil, err
+		}
+
+		if raw, ok := page.Embedded[embeddedKey]; ok && len(raw) > 0 {
+			var pageItems []T
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", embeddedKey, err)
+			}
+			items = append(items, pageItems...)
+		}
+
+		next = ""
+		if page.Links.Next != nil {
+			next = page.Links.Next.Href
+		}
+	}
+
+	return items, nil
+}
+
+// firstPageURL builds the URL of the first page, adding the configured page size
+func (c *Client) firstPageURL(path string) (string, error) {
+	requestURL, err := c.apiURL(path)
+	if err != nil {
+		return "", err
+	}
+	pageURL, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid request URL: %w", err)
+	}
+	if c.pageSize > 0 {
+		query := pageURL.Query()
+		query.Set("limit", strconv.Itoa(c.pageSize))
+		pageURL.RawQuery = query.Encode()
+	}
+	return pageURL.String(), nil
+}
+
+// apiURL returns the full URL for an API path such as "/environments/{id}/flows"
+func (c *Client) apiURL(path string) (string, error) {
+	base := c.apiBaseURL
+	if base == "" {
+		region, ok := LookupRegion(c.Region)
+		if !ok {
+			return "", fmt.Errorf("invalid region: %s (valid regions: %v)", c.Region, ValidRegions())
+		}
+		base = region.APIURL()
+	}
+	return strings.TrimRight(base, "/") + path, nil
+}
+
+// authenticatedHTTPClient builds an HTTP client that adds the OAuth token from the SDK
+// configuration to raw HTTP requests. Without an SDK configuration (custom auth URL or
+// tests) the base client is used as is.
+func (c *Client) authenticatedHTTPClient(ctx context.Context) (*http.Client, error) {
+	base := c.httpClient
+	if base == nil {
+		base = http.DefaultClient
+	}
+	if c.serviceCfg == nil {
+		return base, nil
+	}
+
+	httpClient, err := c.serviceCfg.Client(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build authenticated HTTP client: %w", err)
+	}
+	return httpClient, nil
+}
+
+// getJSON sends a GET request and decodes a 200 response body into out
+func getJSON(ctx context.Context, httpClient *http.Client, requestURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	httpResp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("API returned status %d: %s", httpResp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
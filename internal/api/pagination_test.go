@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const paginationTestEnvID = "12345678-1234-1234-1234-123456789012"
+
+// halPagesServer serves HAL collections split into pages, linked with _links.next
+type halPagesServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	limits []string // limit query parameter of every first-page request
+}
+
+// halPages holds the embedded key and the items of each page of one collection
+type halPages struct {
+	key   string
+	pages [][]map[string]interface{}
+}
+
+// newHALPagesServer serves collections keyed by path (e.g. "/v1/environments/<env>/flows")
+func newHALPagesServer(t *testing.T, collections map[string]halPages) *halPagesServer {
+	t.Helper()
+	s := &halPagesServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		collection, ok := collections[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		page := 0
+		if p := r.URL.Query().Get("page"); p != "" {
+			_, _ = fmt.Sscanf(p, "%d", &page)
+		} else {
+			s.mu.Lock()
+			s.limits = append(s.limits, r.URL.Query().Get("limit"))
+			s.mu.Unlock()
+		}
+
+		links := map[string]interface{}{
+			"self": map[string]string{"href": s.URL + r.URL.RequestURI()},
+		}
+		if page+1 < len(collection.pages) {
+			links["next"] = map[string]string{"href": fmt.Sprintf("%s%s?page=%d", s.URL, r.URL.Path, page+1)}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"_embedded": map[string]interface{}{collection.key: collection.pages[page]},
+			"_links":    links,
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newPaginationTestClient(server *halPagesServer) *Client {
+	return &Client{
+		httpClient:    server.Client(),
+		apiBaseURL:    server.URL + "/v1",
+		EnvironmentID: paginationTestEnvID,
+		Region:        "NA",
+	}
+}
+
+func envPath(path string) string {
+	return "/v1/environments/" + paginationTestEnvID + path
+}
+
+// The item helpers include every property the SDK models require when decoding
+
+func halLinks(names ...string) map[string]interface{} {
+	links := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		links[name] = map[string]string{"href": "https://api.pingone.com/v1/" + name}
+	}
+	return links
+}
+
+func connectorInstanceItem(id, name, connectorID string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"connector":   map[string]string{"id": connectorID},
+		"environment": map[string]string{"id": paginationTestEnvID},
+		"_links":      halLinks("environment", "self", "connectorInstance.clone"),
+	}
+}
+
+func applicationItem(id, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"apiKey":      map[string]interface{}{"enabled": true, "value": "key"},
+		"oauth":       map[string]interface{}{"clientSecret": "secret"},
+		"environment": map[string]string{"id": paginationTestEnvID},
+		"_links":      halLinks("self", "environment", "flowPolicies", "davinciApplication.rotateKey", "davinciApplication.rotateSecret"),
+	}
+}
+
+func flowPolicyItem(id, name, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                id,
+		"name":              name,
+		"status":            status,
+		"flowDistributions": []interface{}{},
+		"environment":       map[string]string{"id": paginationTestEnvID},
+		"_links":            halLinks("self", "environment", "davinciApplication"),
+	}
+}
+
+func TestListFlows_Pagination(t *testing.T) {
+	server := newHALPagesServer(t, map[string]halPages{
+		envPath("/flows"): {key: "flows", pages: [][]map[string]interface{}{
+			{{"id": "flow-1", "name": "One"}, {"id": "flow-2", "name": "Two"}},
+			{{"id": "flow-3", "name": "Three"}},
+			{{"id": "flow-4", "name": "Four", "description": "Last page"}},
+		}},
+	})
+	client := newPaginationTestClient(server)
+	client.SetPageSize(2)
+
+	flows, err := client.ListFlows(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, flows, 4)
+	assert.Equal(t, "flow-1", flows[0].FlowID)
+	assert.Equal(t, "flow-3", flows[2].FlowID)
+	assert.Equal(t, FlowSummary{FlowID: "flow-4", Name: "Four", Description: "Last page"}, flows[3])
+	assert.Equal(t, []string{"2"}, server.limits)
+}
+
+func TestListConnectorInstances_Pagination(t *testing.T) {
+	server := newHALPagesServer(t, map[string]halPages{
+		envPath("/connectorInstances"): {key: "connectorInstances", pages: [][]map[string]interface{}{
+			{connectorInstanceItem("conn-1", "Http", "httpConnector")},
+			{connectorInstanceItem("conn-2", "Mail", "smtpConnector")},
+		}},
+	})
+	client := newPaginationTestClient(server)
+
+	instances, err := client.ListConnectorInstances(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []ConnectorInstanceSummary{
+		{InstanceID: "conn-1", Name: "Http", ConnectorID: "httpConnector"},
+		{InstanceID: "conn-2", Name: "Mail", ConnectorID: "smtpConnector"},
+	}, instances)
+	assert.Equal(t, []string{""}, server.limits, "no limit is sent without a page size")
+}
+
+func TestListApplications_Pagination(t *testing.T) {
+	server := newHALPagesServer(t, map[string]halPages{
+		envPath("/davinciApplications"): {key: "davinciApplications", pages: [][]map[string]interface{}{
+			{applicationItem("app-1", "Web")},
+			{applicationItem("app-2", "Mobile")},
+			{},
+		}},
+	})
+	client := newPaginationTestClient(server)
+
+	applications, err := client.ListApplications(context.Background(), paginationTestEnvID)
+	require.NoError(t, err)
+
+	require.Len(t, applications, 2)
+	assert.Equal(t, "app-1", applications[0].GetId())
+	assert.Equal(t, "app-2", applications[1].GetId())
+}
+
+func TestListFlowPolicies_Pagination(t *testing.T) {
+	server := newHALPagesServer(t, map[string]halPages{
+		envPath("/davinciApplications"): {key: "davinciApplications", pages: [][]map[string]interface{}{
+			{applicationItem("app-1", "Web")},
+			{applicationItem("app-2", "Mobile")},
+		}},
+		envPath("/davinciApplications/app-1/flowPolicies"): {key: "flowPolicies", pages: [][]map[string]interface{}{
+			{flowPolicyItem("pol-1", "Login", "enabled")},
+			{flowPolicyItem("pol-2", "Register", "disabled")},
+		}},
+		envPath("/davinciApplications/app-2/flowPolicies"): {key: "flowPolicies", pages: [][]map[string]interface{}{
+			{flowPolicyItem("pol-3", "Mobile Login", "enabled")},
+		}},
+	})
+	client := newPaginationTestClient(server)
+
+	policies, err := client.ListFlowPolicies(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []FlowPolicySummary{
+		{PolicyID: "pol-1", Name: "Login", Status: "enabled", ApplicationID: "app-1"},
+		{PolicyID: "pol-2", Name: "Register", Status: "disabled", ApplicationID: "app-1"},
+		{PolicyID: "pol-3", Name: "Mobile Login", Status: "enabled", ApplicationID: "app-2"},
+	}, policies)
+}
+
+func TestListHALPages(t *testing.T) {
+	t.Run("Detects pagination loops", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"_embedded": map[string]interface{}{"flows": []interface{}{}},
+				"_links":    map[string]interface{}{"next": map[string]string{"href": server.URL + "/v1/flows"}},
+			})
+		}))
+		defer server.Close()
+		client := &Client{httpClient: server.Client(), apiBaseURL: server.URL + "/v1"}
+
+		_, err := listHALPages[interface{}](context.Background(), client, "/flows", "flows")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pagination loop detected")
+	})
+
+	t.Run("Fails when a later page fails", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") != "" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"_embedded": map[string]interface{}{"flows": []interface{}{map[string]string{"id": "flow-1"}}},
+				"_links":    map[string]interface{}{"next": map[string]string{"href": server.URL + "/v1/flows?page=1"}},
+			})
+		}))
+		defer server.Close()
+		client := &Client{httpClient: server.Client(), apiBaseURL: server.URL + "/v1"}
+
+		_, err := listHALPages[interface{}](context.Background(), client, "/flows", "flows")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API returned status 403")
+	})
+}
+
+func TestAPIURL(t *testing.T) {
+	requestURL, err := (&Client{Region: "EU"}).apiURL("/environments/env/flows")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.pingone.eu/v1/environments/env/flows", requestURL)
+
+	requestURL, err = (&Client{apiBaseURL: "http://localhost:8080/v1/"}).apiURL("/flows")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/v1/flows", requestURL)
+
+	_, err = (&Client{Region: "XX"}).apiURL("/flows")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid region: XX")
+}
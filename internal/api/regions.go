@@ -0,0 +1,78 @@
+package api
+
+import "strings"
+
+// Region describes a PingOne region: the code accepted by --pingone-region-code, the domains
+// its API and auth services are served from, and the matching Terraform provider setting
+type Region struct {
+	Code               string // Region code, e.g. "NA"
+	Name               string // Display name, e.g. "North America"
+	TopLevelDomain     string // Domain suffix after "pingone.", e.g. "com" or "com.au"
+	ProviderRegionCode string // region_code value for the pingidentity/pingone Terraform provider
+}
+
+// regions lists every PingOne region in display order
+var regions = []Region{
+	{Code: "NA", Name: "North America", TopLevelDomain: "com", ProviderRegionCode: "NA"},
+	{Code: "EU", Name: "Europe", TopLevelDomain: "eu", ProviderRegionCode: "EU"},
+	{Code: "AP", Name: "Asia-Pacific", TopLevelDomain: "asia", ProviderRegionCode: "AP"},
+	{Code: "CA", Name: "Canada", TopLevelDomain: "ca", ProviderRegionCode: "CA"},
+	{Code: "AU", Name: "Australia", TopLevelDomain: "com.au", ProviderRegionCode: "AU"},
+	{Code: "SG", Name: "Singapore", TopLevelDomain: "sg", ProviderRegionCode: "SG"},
+}
+
+// APIDomain returns the host serving the PingOne management API, e.g. "api.pingone.eu"
+func (r Region) APIDomain() string {
+	return "api.pingone." + r.TopLevelDomain
+}
+
+// AuthDomain returns the host serving PingOne authentication, e.g. "auth.pingone.eu"
+func (r Region) AuthDomain() string {
+	return "auth.pingone." + r.TopLevelDomain
+}
+
+// APIURL returns the base URL of the PingOne management API, including the version path
+func (r Region) APIURL() string {
+	return "https://" + r.APIDomain() + "/v1"
+}
+
+// AuthURL returns the base URL of PingOne authentication; tokens are issued at
+// <AuthURL>/<environmentID>/as/token
+func (r Region) AuthURL() string {
+	return "https://" + r.AuthDomain()
+}
+
+// Regions returns every supported PingOne region
+func Regions() []Region {
+	return append([]Region(nil), regions...)
+}
+
+// LookupRegion returns the region with the given code
+func LookupRegion(code string) (Region, bool) {
+	for _, r := range regions {
+		if r.Code == code {
+			return r, true
+		}
+	}
+	return Region{}, false
+}
+
+// ValidRegions returns the list of valid PingOne region codes
+func ValidRegions() []string {
+	codes := make([]string, 0, len(regions))
+	for _, r := range regions {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
+
+// ValidRegionsString returns the valid region codes as a comma-separated list for help text
+func ValidRegionsString() string {
+	return strings.Join(ValidRegions(), ", ")
+}
+
+// IsValidRegion checks if the given region code is valid
+func IsValidRegion(region string) bool {
+	_, ok := LookupRegion(region)
+	return ok
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegions(t *testing.T) {
+	expected := map[string]struct {
+		apiURL  string
+		authURL string
+	}{
+		"NA": {"https://api.pingone.com/v1", "https://auth.pingone.com"},
+		"EU": {"https://api.pingone.eu/v1", "https://auth.pingone.eu"},
+		"AP": {"https://api.pingone.asia/v1", "https://auth.pingone.asia"},
+		"CA": {"https://api.pingone.ca/v1", "https://auth.pingone.ca"},
+		"AU": {"https://api.pingone.com.au/v1", "https://auth.pingone.com.au"},
+		"SG": {"https://api.pingone.sg/v1", "https://auth.pingone.sg"},
+	}
+
+	// Values accepted by the SDK tld server variable and the provider region_code attribute
+	sdkTopLevelDomains := []string{"eu", "com", "asia", "com.au", "ca", "sg"}
+	providerRegionCodes := []string{"AP", "AU", "CA", "EU", "NA", "SG"}
+
+	require.Len(t, Regions(), len(expected))
+
+	for _, region := range Regions() {
+		t.Run(region.Code, func(t *testing.T) {
+			want, ok := expected[region.Code]
+			require.True(t, ok, "unexpected region %s", region.Code)
+
+			assert.Equal(t, want.apiURL, region.APIURL())
+			assert.Equal(t, want.authURL, region.AuthURL())
+			assert.NotEmpty(t, region.Name)
+			assert.Contains(t, sdkTopLevelDomains, region.TopLevelDomain)
+			assert.Contains(t, providerRegionCodes, region.ProviderRegionCode)
+
+			parsed, err := url.Parse(region.APIURL())
+			require.NoError(t, err)
+			assert.Equal(t, region.APIDomain(), parsed.Host)
+
+			client, err := NewClient(context.Background(), "auth-env", "target-env", region.Code, "client", "secret")
+			require.NoError(t, err)
+			requestURL, err := client.apiURL("/environments/env/flows")
+			require.NoError(t, err)
+			assert.Equal(t, want.apiURL+"/environments/env/flows", requestURL)
+		})
+	}
+}
+
+func TestLookupRegion(t *testing.T) {
+	region, ok := LookupRegion("AU")
+	require.True(t, ok)
+	assert.Equal(t, "com.au", region.TopLevelDomain)
+	assert.Equal(t, "api.pingone.com.au", region.APIDomain())
+
+	_, ok = LookupRegion("US")
+	assert.False(t, ok)
+
+	assert.Equal(t, "NA, EU, AP, CA, AU, SG", ValidRegionsString())
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig controls how API requests are retried on rate limiting (429) and transient
+// server errors (500, 502, 503, 504)
+type RetryConfig struct {
+	MaxAttempts    int           // Total attempts per request including the first; values below 1 mean 1
+	InitialBackoff time.Duration // Wait before the first retry; doubled for each further retry
+	MaxBackoff     time.Duration // Upper bound for the computed backoff (Retry-After is honored as sent)
+	RequestTimeout time.Duration // Timeout for each attempt; 0 disables it
+}
+
+// DefaultRetryConfig returns the retry settings used by NewClient
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		RequestTimeout: 60 * time.Second,
+	}
+}
+
+// SetRetryConfig replaces the retry settings for subsequent requests. It must be called before
+// the client is used concurrently. Clients replaying a snapshot make no network requests and ignore it.
+func (c *Client) SetRetryConfig(cfg RetryConfig) {
+	if c.transport != nil {
+		c.transport.config = cfg
+	}
+}
+
+// retryTransport is an http.RoundTripper that retries rate-limited and transient failures with
+// exponential backoff and jitter. It sits underneath the OAuth transport, so the SDK calls, the
+// raw HTTP workaround paths and token requests are all retried.
+type retryTransport struct {
+	base   http.RoundTripper
+	config RetryConfig
+	sleep  func(ctx context.Context, d time.Duration) error // Replaced in tests
+}
+
+// newRetryTransport wraps base (http.DefaultTransport when nil) with retries
+func newRetryTransport(base http.RoundTripper, cfg RetryConfig) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{base: base, config: cfg, sleep: sleepContext}
+}
+
+// RoundTrip sends the request, retrying while the response or error is retryable and attempts remain
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := t.config.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 1 {
+		var err error
+		if req, err = rewindableBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, cancel, err := t.prepareAttempt(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err == nil && resp.Body != nil && cancel != nil {
+			// Keep the attempt deadline alive until the caller has read the body
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+			cancel = nil
+		}
+
+		if attempt >= attempts || !shouldRetry(req.Context(), resp, err) {
+			if cancel != nil {
+				cancel()
+			}
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = retryAfter
+			}
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if cancel != nil {
+			cancel()
+		}
+
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// prepareAttempt clones the request for one attempt, rewinding the body and applying the
+// per-attempt timeout
+func (t *retryTransport) prepareAttempt(req *http.Request, attempt int) (*http.Request, context.CancelFunc, error) {
+	ctx := req.Context()
+	var cancel context.CancelFunc
+	if t.config.RequestTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.config.RequestTimeout)
+	}
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			if cancel != nil {
+				cancel()
+			}
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	return attemptReq, cancel, nil
+}
+
+// rewindableBody returns req with a body that can be replayed on retries. The SDK replaces
+// request bodies without setting GetBody, so such bodies are read into memory.
+func rewindableBody(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	buffered := req.Clone(req.Context())
+	buffered.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	buffered.Body, _ = buffered.GetBody()
+	return buffered, nil
+}
+
+// backoff returns the jittered exponential wait before retry number attempt (1-based):
+// a random duration between half and all of InitialBackoff * 2^(attempt-1), capped at MaxBackoff
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	wait := t.config.InitialBackoff
+	for i := 1; i < attempt && (t.config.MaxBackoff <= 0 || wait < t.config.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if t.config.MaxBackoff > 0 && wait > t.config.MaxBackoff {
+		wait = t.config.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	half := wait / 2
+	return half + rand.N(wait-half+1)
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying. Transport errors are retried
+// unless the caller's context is done; responses are retried on 429 and transient 5xx statuses.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelOnCloseBody releases an attempt's timeout context once the response body is closed
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
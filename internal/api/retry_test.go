@@ -0,0 +1,295 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRetryClient returns an HTTP client whose retry transport records waits instead of sleeping
+func newTestRetryClient(cfg RetryConfig) (*http.Client, *[]time.Duration) {
+	var waits []time.Duration
+	transport := newRetryTransport(nil, cfg)
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return &http.Client{Transport: transport}, &waits
+}
+
+// statusSequenceServer responds with the given statuses in order, then 200 with body "ok"
+func statusSequenceServer(t *testing.T, headers http.Header, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n <= len(statuses) {
+			for k, v := range headers {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n-1])
+			_, _ = w.Write([]byte("error"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRetryTransport(t *testing.T) {
+	cfg := RetryConfig{MaxAttempts: 4, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	t.Run("Retries rate limiting using Retry-After", func(t *testing.T) {
+		server, calls := statusSequenceServer(t, http.Header{"Retry-After": {"7"}}, http.StatusTooManyRequests)
+		client, waits := newTestRetryClient(cfg)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "ok", string(body))
+		assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+		assert.Equal(t, []time.Duration{7 * time.Second}, *waits)
+	})
+
+	t.Run("Retries transient server errors with exponential backoff", func(t *testing.T) {
+		server, calls := statusSequenceServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusInternalServerError)
+		client, waits := newTestRetryClient(cfg)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+		require.Len(t, *waits, 3)
+		for i, wait := range *waits {
+			base := cfg.InitialBackoff << i
+			assert.GreaterOrEqual(t, wait, base/2, "retry %d", i+1)
+			assert.LessOrEqual(t, wait, base, "retry %d", i+1)
+		}
+	})
+
+	t.Run("Returns last response when attempts are exhausted", func(t *testing.T) {
+		server, calls := statusSequenceServer(t, nil, 503, 503, 503, 503, 503)
+		client, _ := newTestRetryClient(cfg)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "error", string(body))
+		assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+	})
+
+	t.Run("Does not retry client errors", func(t *testing.T) {
+		server, calls := statusSequenceServer(t, nil, http.StatusNotFound)
+		client, waits := newTestRetryClient(cfg)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+		assert.Empty(t, *waits)
+	})
+
+	t.Run("Replays request body on retry", func(t *testing.T) {
+		var bodies []string
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		defer server.Close()
+		client, _ := newTestRetryClient(cfg)
+
+		resp, err := client.Post(server.URL, "application/x-www-form-urlencoded", strings.NewReader("grant_type=client_credentials"))
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"grant_type=client_credentials", "grant_type=client_credentials"}, bodies)
+
+		// The SDK replaces request bodies without setting GetBody
+		bodies = nil
+		atomic.StoreInt32(&calls, 0)
+		req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+		require.NoError(t, err)
+		req.Body = io.NopCloser(strings.NewReader(`{"name":"x"}`))
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{`{"name":"x"}`, `{"name":"x"}`}, bodies)
+	})
+
+	t.Run("Retries attempts that exceed the request timeout", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(2 * time.Second):
+				}
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		timeoutCfg := cfg
+		timeoutCfg.RequestTimeout = 50 * time.Millisecond
+		client, _ := newTestRetryClient(timeoutCfg)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, "ok", string(body))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Stops when the context is canceled", func(t *testing.T) {
+		server, calls := statusSequenceServer(t, nil, 503, 503, 503)
+		transport := newRetryTransport(nil, cfg)
+		ctx, cancel := context.WithCancel(context.Background())
+		transport.sleep = func(ctx context.Context, d time.Duration) error {
+			cancel()
+			return ctx.Err()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		_, err = (&http.Client{Transport: transport}).Do(req)
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	})
+}
+
+// TestClientRetries verifies transient errors are retried through the client for both SDK and
+// raw HTTP requests
+func TestClientRetries(t *testing.T) {
+	dataset, err := fakeserver.LoadDefaultDataset()
+	require.NoError(t, err)
+	envPath := "/v1/environments/" + dataset.EnvironmentID
+
+	server := fakeserver.New(dataset, fakeserver.Options{FailureSequences: map[string][]int{
+		envPath + "/variables": {http.StatusServiceUnavailable},
+		envPath + "/flows":     {http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	}})
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithOptions(context.Background(), server.EnvironmentID(), server.EnvironmentID(), "NA", fakeserver.ClientID, fakeserver.ClientSecret, ClientOptions{
+		APIURL:  server.URL,
+		AuthURL: server.URL,
+	})
+	require.NoError(t, err)
+	var waits []time.Duration
+	client.transport.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+
+	t.Run("SDK requests", func(t *testing.T) {
+		waits = nil
+		variables, err := client.ListVariables(context.Background(), client.EnvironmentID)
+		require.NoError(t, err)
+		assert.Len(t, variables, len(dataset.Variables))
+		assert.Len(t, waits, 1)
+	})
+
+	t.Run("Raw HTTP requests", func(t *testing.T) {
+		waits = nil
+		flows, err := client.ListFlows(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, flows, len(dataset.Flows))
+		assert.Len(t, waits, 2)
+	})
+
+	assert.Equal(t, 2, countRequests(server.Requests(), "GET "+envPath+"/variables"))
+	assert.Equal(t, 3, countRequests(server.Requests(), "GET "+envPath+"/flows"))
+}
+
+// countRequests returns the number of requests to a path, ignoring the query
+func countRequests(requests []string, prefix string) int {
+	count := 0
+	for _, request := range requests {
+		if request == prefix || strings.HasPrefix(request, prefix+"?") {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	transport := newRetryTransport(nil, RetryConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		wait := transport.backoff(attempt)
+		assert.GreaterOrEqual(t, wait, 500*time.Millisecond)
+		assert.LessOrEqual(t, wait, 5*time.Second)
+	}
+	assert.GreaterOrEqual(t, transport.backoff(10), 2500*time.Millisecond)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "Seconds", value: "30", expected: 30 * time.Second, ok: true},
+		{name: "Zero seconds", value: "0", expected: 0, ok: true},
+		{name: "HTTP date", value: "Wed, 01 Jan 2025 12:00:10 GMT", expected: 10 * time.Second, ok: true},
+		{name: "HTTP date in the past", value: "Wed, 01 Jan 2025 11:00:00 GMT", expected: 0, ok: true},
+		{name: "Empty", value: "", ok: false},
+		{name: "Negative", value: "-5", ok: false},
+		{name: "Invalid", value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, wait)
+		})
+	}
+}
+
+func TestSetRetryConfig(t *testing.T) {
+	client, err := NewClient(context.Background(), "auth-env", "target-env", "NA", "client", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetryConfig(), client.transport.config)
+
+	custom := RetryConfig{MaxAttempts: 2, RequestTimeout: time.Second}
+	client.SetRetryConfig(custom)
+	assert.Equal(t, custom, client.transport.config)
+
+	// Snapshot clients make no requests
+	(&Client{}).SetRetryConfig(custom)
+}
@@ -0,0 +1,365 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingidentity/pingone-go-client/pingone"
+)
+
+// SnapshotManifestFile is the name of the manifest written to the root of a snapshot directory
+const SnapshotManifestFile = "manifest.json"
+
+// snapshotFormatVersion is bumped when the on-disk layout changes incompatibly
+const snapshotFormatVersion = 2
+
+// redactedValue replaces secrets in recorded responses
+const redactedValue = "REDACTED"
+
+// SnapshotManifest describes the contents of a snapshot directory
+type SnapshotManifest struct {
+	Version       int             `json:"version"`
+	EnvironmentID string          `json:"environment_id"`
+	Region        string          `json:"region"`
+	APIURL        string          `json:"api_url,omitempty"`   // Scheme and host of the recorded requests
+	PageSize      int             `json:"page_size,omitempty"` // Page size of the recorded list requests
+	CreatedAt     string          `json:"created_at"`
+	Entries       []SnapshotEntry `json:"entries"`
+}
+
+// SnapshotEntry maps an API request to the file holding its response body
+type SnapshotEntry struct {
+	Key  string `json:"key"`  // Request path and query, e.g. "/v1/environments/<id>/flows?limit=100"
+	File string `json:"file"` // Path relative to the snapshot directory
+}
+
+// snapshot records API response bodies to disk or replays them from a previous recording
+type snapshot struct {
+	dir      string
+	replay   bool
+	mu       sync.Mutex
+	manifest SnapshotManifest
+	files    map[string]string // key -> relative file path
+}
+
+// RecordSnapshot enables snapshot recording: the body of every subsequent successful GET
+// request to the API is written as JSON under dir, and dir/manifest.json is kept up to date.
+// Application OAuth client secrets and API key values are redacted, but other configuration
+// such as connector instance properties may still hold credentials, so files are only
+// readable by the current user.
+func (c *Client) RecordSnapshot(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("snapshot directory is required")
+	}
+	if c.transport == nil {
+		return fmt.Errorf("snapshots can only be recorded from a client connected to the API")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	s := &snapshot{
+		dir: dir,
+		manifest: SnapshotManifest{
+			Version:       snapshotFormatVersion,
+			EnvironmentID: c.EnvironmentID,
+			Region:        c.Region,
+			PageSize:      c.pageSize,
+			CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		},
+		files: make(map[string]string),
+	}
+	if err := s.writeManifest(); err != nil {
+		return err
+	}
+
+	// Record underneath the retry transport so only the successful attempt is written
+	c.transport.base = &recordingTransport{base: c.transport.base, snapshot: s}
+	c.snapshot = s
+	return nil
+}
+
+// NewClientFromSnapshot creates a client that serves every API request from a snapshot
+// directory written by RecordSnapshot. Recorded bodies go through the same decoding as live
+// responses. No credentials or network access are needed.
+func NewClientFromSnapshot(dir string) (*Client, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("snapshot directory is required")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, SnapshotManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+
+	var manifest SnapshotManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot manifest: %w", err)
+	}
+	if manifest.Version != snapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (expected %d)", manifest.Version, snapshotFormatVersion)
+	}
+	region, ok := LookupRegion(manifest.Region)
+	if !ok {
+		return nil, fmt.Errorf("invalid region in snapshot manifest: %s", manifest.Region)
+	}
+
+	files := make(map[string]string, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		files[entry.Key] = entry.File
+	}
+	s := &snapshot{
+		dir:      dir,
+		replay:   true,
+		manifest: manifest,
+		files:    files,
+	}
+
+	// Requests go to the recorded API URL, so pagination links in the recorded bodies match
+	// it, but never leave the replay transport
+	apiURL, err := url.Parse(region.APIURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL for region %s: %w", manifest.Region, err)
+	}
+	if manifest.APIURL != "" {
+		if apiURL, err = parseAPIURL(manifest.APIURL); err != nil {
+			return nil, fmt.Errorf("invalid API URL in snapshot manifest: %w", err)
+		}
+	}
+	httpClient := &http.Client{Transport: &replayTransport{snapshot: s}}
+	cfg := pingone.NewConfiguration(nil)
+	cfg.Service = nil
+	cfg.HTTPClient = httpClient
+	cfg.Host = apiURL.Host
+	cfg.Scheme = apiURL.Scheme
+	apiClient, err := pingone.NewAPIClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize API client: %w", err)
+	}
+
+	return &Client{
+		apiClient:     apiClient,
+		httpClient:    httpClient,
+		apiBaseURL:    (&url.URL{Scheme: apiURL.Scheme, Host: apiURL.Host, Path: "/v1"}).String(),
+		pageSize:      manifest.PageSize,
+		snapshot:      s,
+		EnvironmentID: manifest.EnvironmentID,
+		Region:        manifest.Region,
+	}, nil
+}
+
+// recordingTransport writes the body of every successful API GET response to a snapshot
+type recordingTransport struct {
+	base     http.RoundTripper
+	snapshot *snapshot
+}
+
+// RoundTrip sends the request and records a 200 response before handing it to the caller
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK || !strings.HasPrefix(req.URL.Path, "/v1/") {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for snapshot: %w", err)
+	}
+	if err := t.snapshot.save(req.URL, body); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// replayTransport serves API GET requests from a snapshot
+type replayTransport struct {
+	snapshot *snapshot
+}
+
+// RoundTrip returns the recorded body for the request path and query as a 200 response
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	if req.Method != http.MethodGet {
+		return nil, fmt.Errorf("snapshot replay only serves GET requests, got %s %s", req.Method, req.URL.Path)
+	}
+
+	body, err := t.snapshot.load(snapshotRequestKey(req.URL))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// snapshotRequestKey identifies a request by its path and query; the host is ignored so a
+// snapshot replays regardless of the API URL it was recorded from
+func snapshotRequestKey(u *url.URL) string {
+	key := u.EscapedPath()
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+// snapshotFile returns the file holding the response for key, relative to the snapshot
+// directory: "/v1/environments/<id>/flows?limit=100" is stored in
+// "environments/<id>/flows@limit%3D100.json"
+func snapshotFile(key string) (string, error) {
+	path, query, _ := strings.Cut(strings.TrimPrefix(key, "/v1/"), "?")
+	file := path
+	if query != "" {
+		file += "@" + url.QueryEscape(query)
+	}
+	file += ".json"
+
+	if !filepath.IsLocal(filepath.FromSlash(file)) {
+		return "", fmt.Errorf("cannot store response for %s in snapshot", key)
+	}
+	return file, nil
+}
+
+// setPageSize sets the page size of subsequent list requests, written to the manifest with the
+// next recorded response
+func (s *snapshot) setPageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.manifest.PageSize = size
+}
+
+// load reads the recorded response body for key
+func (s *snapshot) load(key string) ([]byte, error) {
+	s.mu.Lock()
+	file, ok := s.files[key]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%s not found in snapshot %s", key, s.dir)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(file)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file for %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// save writes the response body of a request, with secrets redacted, and updates the manifest
+func (s *snapshot) save(requestURL *url.URL, body []byte) error {
+	key := snapshotRequestKey(requestURL)
+	file, err := snapshotFile(key)
+	if err != nil {
+		return err
+	}
+	data, err := redactSecrets(body)
+	if err != nil {
+		return fmt.Errorf("failed to record %s in snapshot: %w", key, err)
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(file))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory for %s: %w", key, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write snapshot for %s: %w", key, err)
+	}
+
+	s.files[key] = file
+	if s.manifest.APIURL == "" {
+		s.manifest.APIURL = requestURL.Scheme + "://" + requestURL.Host
+	}
+	return s.writeManifest()
+}
+
+// writeManifest writes the manifest with entries sorted by key. Callers must hold s.mu
+// once the snapshot is shared.
+func (s *snapshot) writeManifest() error {
+	entries := make([]SnapshotEntry, 0, len(s.files))
+	for key, file := range s.files {
+		entries = append(entries, SnapshotEntry{Key: key, File: file})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	s.manifest.Entries = entries
+
+	data, err := json.MarshalIndent(s.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, SnapshotManifestFile), append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+	return nil
+}
+
+// redactSecrets returns a JSON response body, indented, with the OAuth client secret and API
+// key value of every DaVinci application in it replaced by redactedValue. Numbers are kept
+// exactly as received.
+func redactSecrets(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	redactValue(value)
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// redactValue redacts application secrets anywhere in a decoded JSON value, including the
+// items of collection responses
+func redactValue(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redactField(v, "oauth", "clientSecret")
+		redactField(v, "apiKey", "value")
+		for _, child := range v {
+			redactValue(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactValue(child)
+		}
+	}
+}
+
+// redactField replaces the non-empty string obj[parent][field] with redactedValue
+func redactField(obj map[string]interface{}, parent, field string) {
+	nested, ok := obj[parent].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if secret, ok := nested[field].(string); ok && secret != "" {
+		nested[field] = redactedValue
+	}
+}
@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	snapshotTestApplicationID = "087ccb17aacec9279b4c4a4b60c283a8"
+	snapshotTestPolicyID      = "3f1c9d2e7a8b4c6d9e0f1a2b3c4d5e6f"
+	snapshotTestConnectorID   = "292873d5ceea806d81373ed0341b5c88"
+	snapshotTestFlowID        = "6bb77275122b92d9cf4f9cc43619c457"
+)
+
+// newFakeServerClient returns a client of a fake server serving the default dataset
+func newFakeServerClient(t *testing.T, opts fakeserver.Options) (*fakeserver.Server, *Client) {
+	t.Helper()
+	dataset, err := fakeserver.LoadDefaultDataset()
+	require.NoError(t, err)
+	server := fakeserver.New(dataset, opts)
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithOptions(context.Background(), server.EnvironmentID(), server.EnvironmentID(), "EU", fakeserver.ClientID, fakeserver.ClientSecret, ClientOptions{
+		APIURL:  server.URL,
+		AuthURL: server.URL,
+	})
+	require.NoError(t, err)
+	return server, client
+}
+
+// snapshotTestResponses holds the result of every List/Get call of a client
+type snapshotTestResponses struct {
+	flows        []FlowSummary
+	flow         *FlowDetail
+	variables    interface{}
+	connectors   []ConnectorInstanceSummary
+	connector    *ConnectorInstanceDetail
+	applications []map[string]interface{}
+	policies     []FlowPolicySummary
+	policy       *FlowPolicyDetail
+}
+
+// fetchSnapshotTestResponses calls every List/Get method covered by snapshots
+func fetchSnapshotTestResponses(t *testing.T, client *Client) snapshotTestResponses {
+	t.Helper()
+	ctx := context.Background()
+	var r snapshotTestResponses
+	var err error
+
+	r.flows, err = client.ListFlows(ctx)
+	require.NoError(t, err)
+	r.flow, err = client.GetFlow(ctx, snapshotTestFlowID)
+	require.NoError(t, err)
+	r.variables, err = client.ListVariables(ctx, client.EnvironmentID)
+	require.NoError(t, err)
+	r.connectors, err = client.ListConnectorInstances(ctx)
+	require.NoError(t, err)
+	r.connector, err = client.GetConnectorInstance(ctx, snapshotTestConnectorID)
+	require.NoError(t, err)
+	applications, err := client.ListApplications(ctx, client.EnvironmentID)
+	require.NoError(t, err)
+	for _, application := range applications {
+		data, err := application.MarshalJSON()
+		require.NoError(t, err)
+		var obj map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &obj))
+		r.applications = append(r.applications, obj)
+	}
+	r.policies, err = client.ListFlowPolicies(ctx)
+	require.NoError(t, err)
+	r.policy, err = client.GetFlowPolicy(ctx, snapshotTestApplicationID, snapshotTestPolicyID)
+	require.NoError(t, err)
+	return r
+}
+
+func TestSnapshotRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	// One item per page so pagination links are recorded and followed on replay
+	server, recorder := newFakeServerClient(t, fakeserver.Options{PageSize: 1})
+	require.NoError(t, recorder.RecordSnapshot(dir))
+	live := fetchSnapshotTestResponses(t, recorder)
+	envPath := "/v1/environments/" + server.EnvironmentID()
+
+	t.Run("Writes manifest with sorted entries", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(dir, SnapshotManifestFile))
+		require.NoError(t, err)
+
+		var manifest SnapshotManifest
+		require.NoError(t, json.Unmarshal(data, &manifest))
+		assert.Equal(t, server.EnvironmentID(), manifest.EnvironmentID)
+		assert.Equal(t, "EU", manifest.Region)
+
+		keys := make([]string, 0, len(manifest.Entries))
+		for _, entry := range manifest.Entries {
+			keys = append(keys, entry.Key)
+			assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(entry.File)))
+		}
+		assert.IsIncreasing(t, keys)
+		assert.Contains(t, keys, envPath+"/flows")
+		assert.Contains(t, keys, envPath+"/flows/"+snapshotTestFlowID)
+		assert.Contains(t, keys, envPath+"/variables?cursor=1&limit=1")
+		assert.FileExists(t, filepath.Join(dir, "environments", server.EnvironmentID(), "flows", snapshotTestFlowID+".json"))
+	})
+
+	t.Run("Writes files readable only by the user", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("file modes are not enforced on Windows")
+		}
+		for _, file := range []string{SnapshotManifestFile, filepath.Join("environments", server.EnvironmentID(), "davinciApplications.json")} {
+			info, err := os.Stat(filepath.Join(dir, file))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), file)
+		}
+	})
+
+	t.Run("Redacts application secrets", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(dir, "environments", server.EnvironmentID(), "davinciApplications.json"))
+		require.NoError(t, err)
+
+		assert.NotContains(t, string(data), live.applications[0]["oauth"].(map[string]interface{})["clientSecret"])
+		assert.NotContains(t, string(data), live.applications[0]["apiKey"].(map[string]interface{})["value"])
+		assert.Contains(t, string(data), `"clientSecret": "REDACTED"`)
+	})
+
+	t.Run("Replays responses through the normal decoding without credentials", func(t *testing.T) {
+		client, err := NewClientFromSnapshot(dir)
+		require.NoError(t, err)
+		assert.Equal(t, server.EnvironmentID(), client.EnvironmentID)
+		assert.Equal(t, "EU", client.Region)
+
+		replayed := fetchSnapshotTestResponses(t, client)
+		assert.Equal(t, live.flows, replayed.flows)
+		assert.Equal(t, live.flow, replayed.flow)
+		assert.Equal(t, live.variables, replayed.variables)
+		assert.Equal(t, live.connectors, replayed.connectors)
+		assert.Equal(t, live.connector, replayed.connector)
+		assert.Equal(t, live.policies, replayed.policies)
+		assert.Equal(t, live.policy, replayed.policy)
+
+		require.Len(t, replayed.applications, len(live.applications))
+		assert.Equal(t, "REDACTED", replayed.applications[0]["oauth"].(map[string]interface{})["clientSecret"])
+		assert.Equal(t, live.applications[0]["name"], replayed.applications[0]["name"])
+	})
+
+	t.Run("Returns error for requests missing from snapshot", func(t *testing.T) {
+		client, err := NewClientFromSnapshot(dir)
+		require.NoError(t, err)
+
+		_, err = client.GetFlow(context.Background(), "flow-2")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found in snapshot")
+	})
+}
+
+func TestSnapshotDoesNotRecordErrors(t *testing.T) {
+	dir := t.TempDir()
+	dataset, err := fakeserver.LoadDefaultDataset()
+	require.NoError(t, err)
+	_, client := newFakeServerClient(t, fakeserver.Options{Failures: map[string]int{
+		"/v1/environments/" + dataset.EnvironmentID + "/flows": http.StatusForbidden,
+	}})
+	client.SetRetryConfig(RetryConfig{MaxAttempts: 1})
+	require.NoError(t, client.RecordSnapshot(dir))
+
+	_, err = client.ListFlows(context.Background())
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "environments", dataset.EnvironmentID, "flows.json"))
+
+	data, err := os.ReadFile(filepath.Join(dir, SnapshotManifestFile))
+	require.NoError(t, err)
+	var manifest SnapshotManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Empty(t, manifest.Entries)
+}
+
+func TestNewClientFromSnapshot(t *testing.T) {
+	t.Run("Returns error when manifest is missing", func(t *testing.T) {
+		_, err := NewClientFromSnapshot(t.TempDir())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read snapshot manifest")
+	})
+
+	t.Run("Returns error for unsupported version", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, SnapshotManifestFile), []byte(`{"version": 99}`), 0644))
+
+		_, err := NewClientFromSnapshot(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported snapshot version")
+	})
+
+	t.Run("Returns error for empty directory argument", func(t *testing.T) {
+		_, err := NewClientFromSnapshot("")
+		require.Error(t, err)
+	})
+
+	t.Run("Returns error when recording from a snapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, SnapshotManifestFile), []byte(`{"version": 2, "region": "NA"}`), 0644))
+		client, err := NewClientFromSnapshot(dir)
+		require.NoError(t, err)
+
+		assert.Error(t, client.RecordSnapshot(t.TempDir()))
+	})
+}
+
+func TestSnapshotFile(t *testing.T) {
+	file, err := snapshotFile("/v1/environments/env/flows/a%2Fb")
+	require.NoError(t, err)
+	assert.Equal(t, "environments/env/flows/a%2Fb.json", file)
+
+	file, err = snapshotFile("/v1/environments/env/variables?cursor=1&limit=1")
+	require.NoError(t, err)
+	assert.Equal(t, "environments/env/variables@cursor%3D1%26limit%3D1.json", file)
+
+	_, err = snapshotFile("/v1/../../etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestRedactSecrets(t *testing.T) {
+	got, err := redactSecrets([]byte(`{"_embedded": {"davinciApplications": [
+		{"id": "app-1", "oauth": {"clientSecret": "s3cret", "scopes": ["openid"]}, "apiKey": {"enabled": true, "value": "k3y"}}
+	]}, "count": 12345678901234567890}`))
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(got), "s3cret")
+	assert.NotContains(t, string(got), "k3y")
+	assert.Contains(t, string(got), `"scopes": [`)
+	assert.Contains(t, string(got), "12345678901234567890")
+
+	_, err = redactSecrets([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestCacheKey(t *testing.T) {
+	assert.Equal(t, "flows", cacheKey("flows"))
+	assert.Equal(t, "flow_policies/app-1/policy-1", cacheKey("flow_policies", "app-1", "policy-1"))
+	assert.Equal(t, "flows/a%2Fb", cacheKey("flows", "a/b"))
+}
+
+func TestResponseCache(t *testing.T) {
+	client := &Client{EnvironmentID: "env", Region: "NA"}
+	client.EnableResponseCache()
+
+	calls := 0
+	fetch := func() ([]FlowSummary, error) {
+		calls++
+		return []FlowSummary{{FlowID: "flow-1", Name: "Login"}}, nil
+	}
+
+	first, err := cachedCall(client, cacheKey("flows"), fetch)
+	require.NoError(t, err)
+	second, err := cachedCall(client, cacheKey("flows"), fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, first, second)
+
+	t.Run("Returns independent copies", func(t *testing.T) {
+		second[0].Name = "Changed"
+		third, err := cachedCall(client, cacheKey("flows"), fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "Login", third[0].Name)
+	})
+
+	t.Run("Does not cache errors", func(t *testing.T) {
+		failures := 0
+		failing := func() ([]FlowSummary, error) {
+			failures++
+			return nil, errors.New("boom")
+		}
+		_, err := cachedCall(client, cacheKey("variables"), failing)
+		require.Error(t, err)
+		_, err = cachedCall(client, cacheKey("variables"), failing)
+		require.Error(t, err)
+		assert.Equal(t, 2, failures)
+	})
+}
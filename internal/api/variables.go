@@ -10,6 +10,13 @@ import (
 
 // ListVariables retrieves all variables for an environment
 func (c *Client) ListVariables(ctx context.Context, environmentID string) ([]pingone.DaVinciVariableResponse, error) {
+	return cachedCall(c, cacheKey("variables"), func() ([]pingone.DaVinciVariableResponse, error) {
+		return c.listVariables(ctx, environmentID)
+	})
+}
+
+// listVariables retrieves all variables for an environment
+func (c *Client) listVariables(ctx context.Context, environmentID string) ([]pingone.DaVinciVariableResponse, error) {
 	if environmentID == "" {
 		return nil, fmt.Errorf("environment ID is required")
 	}
@@ -19,7 +26,11 @@ func (c *Client) ListVariables(ctx context.Context, environmentID string) ([]pin
 		return nil, fmt.Errorf("invalid environment ID format: %w", err)
 	}
 
-	iterator := c.apiClient.DaVinciVariablesApi.GetVariables(ctx, envUUID).Execute()
+	request := c.apiClient.DaVinciVariablesApi.GetVariables(ctx, envUUID)
+	if c.pageSize > 0 {
+		request = request.Limit(int32(c.pageSize))
+	}
+	iterator := request.Execute()
 
 	var allVariables []pingone.DaVinciVariableResponse
 	for pageCursor, err := range iterator {
@@ -39,6 +50,13 @@ func (c *Client) ListVariables(ctx context.Context, environmentID string) ([]pin
 
 // GetVariable retrieves a specific variable by ID
 func (c *Client) GetVariable(ctx context.Context, environmentID, variableID string) (*pingone.DaVinciVariableResponse, error) {
+	return cachedCall(c, cacheKey("variables", variableID), func() (*pingone.DaVinciVariableResponse, error) {
+		return c.getVariable(ctx, environmentID, variableID)
+	})
+}
+
+// getVariable retrieves a specific variable by ID
+func (c *Client) getVariable(ctx context.Context, environmentID, variableID string) (*pingone.DaVinciVariableResponse, error) {
 	if environmentID == "" {
 		return nil, fmt.Errorf("environment ID is required")
 	}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
@@ -45,8 +46,26 @@ func ConvertApplicationWithEnvironmentAndGraph(appJSON []byte, environmentID str
 
 // generateApplicationHCL generates HCL for a DaVinci application
 func generateApplicationHCL(appData map[string]interface{}, environmentID string, graph *resolver.DependencyGraph) (string, error) {
-	var hcl strings.Builder
+	resource, err := applicationResource(appData, environmentID, graph)
+	if err != nil {
+		return "", err
+	}
+	return model.HCL(resource)
+}
+
+// ConvertApplicationToResource converts a DaVinci application to a resource with explicit
+// environment ID and optional dependency graph
+func ConvertApplicationToResource(appJSON []byte, environmentID string, graph *resolver.DependencyGraph) (*model.Resource, error) {
+	var appData map[string]interface{}
+	if err := json.Unmarshal(appJSON, &appData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal application JSON: %w", err)
+	}
+
+	return applicationResource(appData, environmentID, graph)
+}
 
+// applicationResource builds the resource of a DaVinci application
+func applicationResource(appData map[string]interface{}, environmentID string, graph *resolver.DependencyGraph) (*model.Resource, error) {
 	// Generate resource name - use registered name from graph if available to ensure uniqueness
 	var resourceName string
 	if graph != nil {
@@ -60,127 +79,139 @@ func generateApplicationHCL(appData map[string]interface{}, environmentID string
 		}
 	}
 
-	// Fallback: generate from application name if not in graph
+	// Fallback: generate from application name with the graph naming strategy if not in graph
 	if resourceName == "" {
-		resourceName = utils.SanitizeResourceName(getString(appData, "name"))
+		resourceName = graph.ResourceName(getString(appData, "id"), getString(appData, "name"))
 	}
 
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_application\" \"%s\" {\n", resourceName))
-
 	// Write environment_id - quote it if it doesn't start with "var."
-	if strings.HasPrefix(environmentID, "var.") {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %s\n\n", environmentID))
-	} else {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %q\n\n", environmentID))
+	environment, err := environmentValue(environmentID)
+	if err != nil {
+		return nil, err
 	}
 
+	app := model.NewResource("pingone_davinci_application", resourceName)
+	app.ID = getString(appData, "id")
+	app.Body.SetAttribute("environment_id", environment)
+	app.Body.AppendNewline()
+
 	// Required: name
 	if name := getString(appData, "name"); name != "" {
-		hcl.WriteString(fmt.Sprintf("  name           = %s\n", quoteString(name)))
+		app.Body.SetAttribute("name", model.String(name))
 	}
 
 	// Optional: api_key
 	if apiKey, ok := appData["apiKey"].(map[string]interface{}); ok {
-		hcl.WriteString("\n")
-		if err := writeAPIKeyBlock(&hcl, apiKey); err != nil {
-			return "", fmt.Errorf("failed to write api_key: %w", err)
-		}
+		app.Body.AppendNewline()
+		app.Body.SetAttribute("api_key", apiKeyValue(apiKey))
 	}
 
 	// Optional: oauth
 	if oauth, ok := appData["oauth"].(map[string]interface{}); ok {
-		hcl.WriteString("\n")
-		if err := writeOAuthBlock(&hcl, oauth); err != nil {
-			return "", fmt.Errorf("failed to write oauth: %w", err)
-		}
+		app.Body.AppendNewline()
+		app.Body.SetAttribute("oauth", oauthValue(oauth))
 	}
 
-	hcl.WriteString("}\n")
-	return hcl.String(), nil
+	return app, nil
 }
 
-// writeAPIKeyBlock writes the api_key attribute block
-func writeAPIKeyBlock(hcl *strings.Builder, apiKey map[string]interface{}) error {
-	hcl.WriteString("  api_key = {\n")
+// applicationOAuthVariables lists the OAuth settings of an application that differ between
+// environments, by payload key, attribute name and Terraform type
+var applicationOAuthVariables = []struct{ key, name, tfType string }{
+	{"redirectUris", "redirect_uris", "list(string)"},
+	{"logoutUris", "logout_uris", "list(string)"},
+	{"spjwksUrl", "sp_jwks_url", "string"},
+}
 
-	if enabled, ok := apiKey["enabled"].(bool); ok {
-		hcl.WriteString(fmt.Sprintf("    enabled = %t\n", enabled))
+// GetApplicationVariableEligibleAttributes extracts variable-eligible attributes from a DaVinci application
+// OAuth redirect and logout URIs and the SP JWKS URL become module variables
+func GetApplicationVariableEligibleAttributes(appJSON []byte, resourceName string) ([]VariableEligibleAttribute, error) {
+	var appData map[string]interface{}
+	if err := json.Unmarshal(appJSON, &appData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal application JSON: %w", err)
 	}
 
-	// Note: We intentionally don't output the actual API key value for security
-	// The Terraform resource will generate a new one
-
-	hcl.WriteString("  }\n")
-	return nil
-}
+	appName := getString(appData, "name")
+	if appName == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
 
-// writeOAuthBlock writes the oauth attribute block
-func writeOAuthBlock(hcl *strings.Builder, oauth map[string]interface{}) error {
-	hcl.WriteString("  oauth = {\n")
+	// Use provided resource name or sanitize from application name
+	if resourceName == "" {
+		resourceName = utils.DefaultNamingStrategy().ResourceName(getString(appData, "id"), appName)
+	}
 
-	// grant_types (array of strings)
-	if grantTypes, ok := oauth["grantTypes"].([]interface{}); ok && len(grantTypes) > 0 {
-		hcl.WriteString("    grant_types                   = [")
-		for i, gt := range grantTypes {
-			if i > 0 {
-				hcl.WriteString(", ")
-			}
-			hcl.WriteString(fmt.Sprintf("%q", gt))
-		}
-		hcl.WriteString("]\n")
+	oauth, ok := appData["oauth"].(map[string]interface{})
+	if !ok {
+		return nil, nil
 	}
 
-	// redirect_uris (array of strings)
-	if redirectUris, ok := oauth["redirectUris"].([]interface{}); ok && len(redirectUris) > 0 {
-		hcl.WriteString("    redirect_uris                 = [")
-		for i, uri := range redirectUris {
-			if i > 0 {
-				hcl.WriteString(", ")
-			}
-			hcl.WriteString(fmt.Sprintf("%q", uri))
+	var attributes []VariableEligibleAttribute
+	for _, setting := range applicationOAuthVariables {
+		value, tfType, ok := eligibleValue(oauth[setting.key])
+		if !ok || tfType != setting.tfType {
+			continue
 		}
-		hcl.WriteString("]\n")
+
+		attributes = append(attributes, VariableEligibleAttribute{
+			ResourceType:  "application",
+			ResourceName:  resourceName,
+			ResourceID:    getString(appData, "id"),
+			AttributePath: "oauth." + setting.name,
+			CurrentValue:  value,
+			VariableName:  fmt.Sprintf("davinci_application_%s_%s", strings.TrimPrefix(resourceName, "pingcli__"), setting.name),
+			VariableType:  tfType,
+			Description:   fmt.Sprintf("OAuth %s for %s application", setting.name, appName),
+		})
 	}
 
-	// logout_uris (array of strings)
-	if logoutUris, ok := oauth["logoutUris"].([]interface{}); ok && len(logoutUris) > 0 {
-		hcl.WriteString("    logout_uris                   = [")
-		for i, uri := range logoutUris {
-			if i > 0 {
-				hcl.WriteString(", ")
-			}
-			hcl.WriteString(fmt.Sprintf("%q", uri))
-		}
-		hcl.WriteString("]\n")
+	return attributes, nil
+}
+
+// apiKeyValue returns the api_key attribute object
+func apiKeyValue(apiKey map[string]interface{}) *model.Object {
+	object := &model.Object{}
+
+	if enabled, ok := apiKey["enabled"].(bool); ok {
+		object.Set("enabled", model.Bool(enabled))
 	}
 
-	// scopes (array of strings)
-	if scopes, ok := oauth["scopes"].([]interface{}); ok && len(scopes) > 0 {
-		hcl.WriteString("    scopes                        = [")
-		for i, scope := range scopes {
-			if i > 0 {
-				hcl.WriteString(", ")
-			}
-			hcl.WriteString(fmt.Sprintf("%q", scope))
+	// Note: We intentionally don't output the actual API key value for security
+	// The Terraform resource will generate a new one
+
+	return object
+}
+
+// oauthValue returns the oauth attribute object
+func oauthValue(oauth map[string]interface{}) *model.Object {
+	object := &model.Object{}
+
+	// grant_types, redirect_uris, logout_uris and scopes (arrays of strings)
+	for _, list := range []struct{ key, name string }{
+		{"grantTypes", "grant_types"},
+		{"redirectUris", "redirect_uris"},
+		{"logoutUris", "logout_uris"},
+		{"scopes", "scopes"},
+	} {
+		if items, ok := oauth[list.key].([]interface{}); ok && len(items) > 0 {
+			object.Set(list.name, stringList(items))
 		}
-		hcl.WriteString("]\n")
 	}
 
 	// enforce_signed_request_openid (boolean)
 	if enforceSignedRequest, ok := oauth["enforceSignedRequestOpenid"].(bool); ok {
-		hcl.WriteString(fmt.Sprintf("    enforce_signed_request_openid = %t\n", enforceSignedRequest))
+		object.Set("enforce_signed_request_openid", model.Bool(enforceSignedRequest))
 	}
 
 	// sp_jwks_openid (string, optional)
 	if spJwks := getString(oauth, "spJwksOpenid"); spJwks != "" {
-		hcl.WriteString(fmt.Sprintf("    sp_jwks_openid                = %s\n", quoteString(spJwks)))
+		object.Set("sp_jwks_openid", model.String(spJwks))
 	}
 
 	// sp_jwks_url (string, optional)
 	if spJwksUrl := getString(oauth, "spjwksUrl"); spJwksUrl != "" {
-		hcl.WriteString(fmt.Sprintf("    sp_jwks_url                   = %s\n", quoteString(spJwksUrl)))
+		object.Set("sp_jwks_url", model.String(spJwksUrl))
 	}
 
-	hcl.WriteString("  }\n")
-	return nil
+	return object
 }
@@ -36,7 +36,7 @@ func TestApplicationConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_application" "pingcli__My-0020-Application"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "My Application"`,
+				`name = "My Application"`,
 				`api_key = {`,
 				`enabled = true`,
 				`oauth = {`,
@@ -62,11 +62,11 @@ func TestApplicationConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_application" "pingcli__OAuth-0020-Only-0020-App"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "OAuth Only App"`,
+				`name = "OAuth Only App"`,
 				`oauth = {`,
-				`grant_types                   = ["authorizationCode"]`,
-				`redirect_uris                 = ["https://example.com/callback"]`,
-				`scopes                        = ["openid"]`,
+				`grant_types   = ["authorizationCode"]`,
+				`redirect_uris = ["https://example.com/callback"]`,
+				`scopes        = ["openid"]`,
 			},
 		},
 		{
@@ -82,7 +82,7 @@ func TestApplicationConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_application" "pingcli__API-0020-Key-0020-App"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "API Key App"`,
+				`name = "API Key App"`,
 				`api_key = {`,
 				`enabled = true`,
 			},
@@ -97,7 +97,7 @@ func TestApplicationConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_application" "pingcli__Minimal-0020-App"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "Minimal App"`,
+				`name = "Minimal App"`,
 			},
 		},
 		{
@@ -133,12 +133,12 @@ func TestApplicationConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_application" "pingcli__DaVinci-0020-API-0020-Protect-0020-Sample-0020-Application-beta"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "DaVinci API Protect Sample Application-beta"`,
+				`name = "DaVinci API Protect Sample Application-beta"`,
 				`api_key = {`,
 				`enabled = false`,
 				`oauth = {`,
-				`grant_types                   = ["authorizationCode"]`,
-				`scopes                        = ["openid", "profile"]`,
+				`grant_types = ["authorizationCode"]`,
+				`scopes      = ["openid", "profile"]`,
 			},
 		},
 	}
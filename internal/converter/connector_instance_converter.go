@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
@@ -35,65 +36,113 @@ func ConvertConnectorInstance(instanceJSON []byte) (string, error) {
 
 // ConvertConnectorInstanceWithOptions converts a connector instance with optional skip-dependencies flag
 func ConvertConnectorInstanceWithOptions(instanceJSON []byte, skipDependencies bool) (string, error) {
+	return ConvertConnectorInstanceWithResourceName(instanceJSON, skipDependencies, "")
+}
+
+// ConvertConnectorInstanceWithResourceName converts a connector instance using the given Terraform
+// resource name, e.g. the name registered in the dependency graph; an empty name is derived from the instance
+func ConvertConnectorInstanceWithResourceName(instanceJSON []byte, skipDependencies bool, resourceName string) (string, error) {
+	resource, err := ConvertConnectorInstanceToResource(instanceJSON, skipDependencies, resourceName)
+	if err != nil {
+		return "", err
+	}
+	return model.HCL(resource)
+}
+
+// ConvertConnectorInstanceToResource converts a connector instance to a resource using the
+// given Terraform resource name; an empty name is derived from the instance
+func ConvertConnectorInstanceToResource(instanceJSON []byte, skipDependencies bool, resourceName string) (*model.Resource, error) {
+	instance, err := parseConnectorInstance(instanceJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resource name using pingcli format
+	if resourceName == "" {
+		resourceName = utils.DefaultNamingStrategy().ResourceName(instance.ID, instance.Name)
+	}
+
+	// Masked secrets (API uses six stars "******") automatically use variables
+	return connectorInstanceResource(instance, skipDependencies, resourceName, func(key string, value interface{}) string {
+		if strVal, ok := value.(string); ok && strings.TrimSpace(strVal) == "******" {
+			return GenerateVariableName(resourceName, key)
+		}
+		return ""
+	})
+}
+
+// parseConnectorInstance parses and validates a connector instance payload
+func parseConnectorInstance(instanceJSON []byte) (ConnectorInstanceResponse, error) {
 	var instance ConnectorInstanceResponse
 	if err := json.Unmarshal(instanceJSON, &instance); err != nil {
-		return "", fmt.Errorf("failed to parse connector instance JSON: %w", err)
+		return instance, fmt.Errorf("failed to parse connector instance JSON: %w", err)
 	}
 
 	if instance.Name == "" {
-		return "", fmt.Errorf("connector instance name is required")
+		return instance, fmt.Errorf("connector instance name is required")
 	}
 
 	if instance.Connector.ID == "" {
-		return "", fmt.Errorf("connector.id is required")
+		return instance, fmt.Errorf("connector.id is required")
 	}
 
-	return generateConnectorInstanceHCL(instance, skipDependencies), nil
+	return instance, nil
 }
 
-// generateConnectorInstanceHCL generates the Terraform HCL for a connector instance
-func generateConnectorInstanceHCL(instance ConnectorInstanceResponse, skipDependencies bool) string {
-	var hcl strings.Builder
-
-	// Resource name using pingcli format
-	resourceName := utils.SanitizeResourceName(instance.Name)
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_connector_instance\" \"%s\" {\n", resourceName))
+// connectorInstanceResource builds the resource of a connector instance. variableFor returns
+// the input variable that supplies a property's value, or "" to write the value. Values of
+// secret properties are marked sensitive.
+func connectorInstanceResource(instance ConnectorInstanceResponse, skipDependencies bool, resourceName string, variableFor func(key string, value interface{}) string) (*model.Resource, error) {
+	resource := model.NewResource("pingone_davinci_connector_instance", resourceName)
+	resource.ID = instance.ID
+	body := &resource.Body
 
 	// Environment ID
 	if skipDependencies {
-		hcl.WriteString(fmt.Sprintf("  environment_id = \"%s\"\n", instance.Environment.ID))
+		body.SetAttribute("environment_id", model.String(instance.Environment.ID))
 	} else {
-		hcl.WriteString("  environment_id = var.pingone_environment_id\n")
+		environment, err := environmentValue("var.pingone_environment_id")
+		if err != nil {
+			return nil, err
+		}
+		body.SetAttribute("environment_id", environment)
 	}
 
-	hcl.WriteString("\n")
+	body.AppendNewline()
 
 	// Name
-	hcl.WriteString(fmt.Sprintf("  name           = \"%s\"\n", instance.Name))
+	body.SetAttribute("name", model.String(instance.Name))
 
-	hcl.WriteString("\n")
+	body.AppendNewline()
 
 	// Connector reference
-	hcl.WriteString("  connector = {\n")
-	hcl.WriteString(fmt.Sprintf("    id = \"%s\"\n", instance.Connector.ID))
-	hcl.WriteString("  }\n")
+	connector := &model.Object{}
+	connector.Set("id", model.String(instance.Connector.ID))
+	body.SetAttribute("connector", connector)
 
 	// Properties (if present)
 	if len(instance.Properties) > 0 {
-		hcl.WriteString("\n")
-		writePropertiesBlockWithAutoVars(&hcl, instance.Properties, resourceName)
-	}
+		properties, err := connectorPropertiesValue(instance.Properties, variableFor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write properties of connector instance %s: %w", instance.Name, err)
+		}
+		body.AppendNewline()
+		body.SetAttribute("properties", properties)
 
-	hcl.WriteString("}\n")
+		config := DefaultPropertyMappingConfig()
+		for key := range instance.Properties {
+			if config.IsSecret(key) {
+				resource.MarkSensitive(model.Path{"properties", key, "value"})
+			}
+		}
+	}
 
-	return hcl.String()
+	return resource, nil
 }
 
-// writePropertiesBlock writes the properties block with jsonencode, preserving type/value structure
-// writePropertiesBlockWithAutoVars writes properties and automatically uses variables for masked secrets
-func writePropertiesBlockWithAutoVars(hcl *strings.Builder, properties map[string]ConnectorPropertyValue, resourceName string) {
-	hcl.WriteString("  properties = jsonencode({\n")
-
+// connectorPropertiesValue returns the properties attribute with jsonencode, preserving the
+// type/value structure. Values supplied by a variable are written as "${var.<name>}".
+func connectorPropertiesValue(properties map[string]ConnectorPropertyValue, variableFor func(key string, value interface{}) string) (model.Value, error) {
 	// Sort keys for consistent output
 	keys := make([]string, 0, len(properties))
 	for k := range properties {
@@ -102,72 +151,34 @@ func writePropertiesBlockWithAutoVars(hcl *strings.Builder, properties map[strin
 	sort.Strings(keys)
 
 	// Write each property with nested type/value structure
-	for i, key := range keys {
+	object := &model.Object{}
+	for _, key := range keys {
 		prop := properties[key]
-
-		// Start property object
-		hcl.WriteString(fmt.Sprintf("      \"%s\": {\n", key))
+		property := &model.Object{}
 
 		// Write type field only when non-empty to match API omitEmpty behavior
 		if strings.TrimSpace(prop.Type) != "" {
-			hcl.WriteString(fmt.Sprintf("          \"type\": \"%s\",\n", prop.Type))
+			property.SetKey("type", model.String(prop.Type))
 		}
 
 		// Write value field
-		value := prop.Value
-
-		// Check if this is a masked secret (API uses six stars "******")
-		isMasked := false
-		if strVal, ok := value.(string); ok && strings.TrimSpace(strVal) == "******" {
-			isMasked = true
-		}
-
-		// Format the value
-		var formattedValue string
-		if isMasked {
-			// Generate variable name and use variable reference for masked secrets
-			varName := GenerateVariableName(resourceName, key)
-			formattedValue = fmt.Sprintf("\"${var.%s}\"", varName)
-		} else if value == nil {
-			formattedValue = "null"
+		var value model.Value
+		var err error
+		if varName := variableFor(key, prop.Value); varName != "" {
+			// Use variable reference with template syntax for jsonencode
+			value, err = model.VarInterpolation(varName)
 		} else {
-			// Format based on type and value
-			switch v := value.(type) {
-			case string:
-				formattedValue = fmt.Sprintf("\"%s\"", v)
-			case bool:
-				formattedValue = fmt.Sprintf("%t", v)
-			case float64:
-				// Check if it's an integer
-				if v == float64(int64(v)) {
-					formattedValue = fmt.Sprintf("%d", int64(v))
-				} else {
-					formattedValue = fmt.Sprintf("%f", v)
-				}
-			case map[string]interface{}:
-				// Complex nested object - marshal to JSON
-				jsonBytes, err := json.Marshal(v)
-				if err != nil {
-					formattedValue = fmt.Sprintf("\"%v\"", v)
-				} else {
-					formattedValue = string(jsonBytes)
-				}
-			default:
-				formattedValue = fmt.Sprintf("\"%v\"", v)
-			}
+			value, err = jsonValue(prop.Value)
 		}
-
-		hcl.WriteString(fmt.Sprintf("          \"value\": %s\n", formattedValue))
-
-		// Close property object
-		if i < len(keys)-1 {
-			hcl.WriteString("      },\n")
-		} else {
-			hcl.WriteString("      }\n")
+		if err != nil {
+			return nil, err
 		}
+		property.SetKey("value", value)
+
+		object.SetKey(key, property)
 	}
 
-	hcl.WriteString("  })\n")
+	return model.JSONEncode(object), nil
 }
 
 // GetConnectorInstanceVariableEligibleAttributes extracts variable-eligible properties from a connector instance
@@ -185,7 +196,7 @@ func GetConnectorInstanceVariableEligibleAttributes(instanceJSON []byte, resourc
 
 	// Use provided resource name or sanitize from instance name
 	if resourceName == "" {
-		resourceName = utils.SanitizeResourceName(instance.Name)
+		resourceName = utils.DefaultNamingStrategy().ResourceName(instance.ID, instance.Name)
 	}
 
 	var attributes []VariableEligibleAttribute
@@ -262,131 +273,29 @@ func GetConnectorInstanceVariableEligibleAttributes(instanceJSON []byte, resourc
 
 // GenerateConnectorInstanceHCLWithVariableReferences generates HCL with variable references for properties
 func GenerateConnectorInstanceHCLWithVariableReferences(instanceJSON []byte, skipDependencies bool, variableMap map[string]string) (string, error) {
-	var instance ConnectorInstanceResponse
-	if err := json.Unmarshal(instanceJSON, &instance); err != nil {
-		return "", fmt.Errorf("failed to parse connector instance JSON: %w", err)
-	}
-
-	if instance.Name == "" {
-		return "", fmt.Errorf("connector instance name is required")
-	}
+	return GenerateConnectorInstanceHCLWithResourceName(instanceJSON, skipDependencies, variableMap, "")
+}
 
-	if instance.Connector.ID == "" {
-		return "", fmt.Errorf("connector.id is required")
+// GenerateConnectorInstanceHCLWithResourceName generates HCL with variable references for properties
+// using the given Terraform resource name; an empty name is derived from the instance
+func GenerateConnectorInstanceHCLWithResourceName(instanceJSON []byte, skipDependencies bool, variableMap map[string]string, resourceName string) (string, error) {
+	instance, err := parseConnectorInstance(instanceJSON)
+	if err != nil {
+		return "", err
 	}
 
-	var hcl strings.Builder
-
 	// Resource name using pingcli format
-	resourceName := utils.SanitizeResourceName(instance.Name)
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_connector_instance\" \"%s\" {\n", resourceName))
-
-	// Environment ID
-	if skipDependencies {
-		hcl.WriteString(fmt.Sprintf("  environment_id = \"%s\"\n", instance.Environment.ID))
-	} else {
-		hcl.WriteString("  environment_id = var.pingone_environment_id\n")
-	}
-
-	hcl.WriteString("\n")
-
-	// Name
-	hcl.WriteString(fmt.Sprintf("  name           = \"%s\"\n", instance.Name))
-
-	hcl.WriteString("\n")
-
-	// Connector reference
-	hcl.WriteString("  connector = {\n")
-	hcl.WriteString(fmt.Sprintf("    id = \"%s\"\n", instance.Connector.ID))
-	hcl.WriteString("  }\n")
-
-	// Properties (if present) - with variable references
-	if len(instance.Properties) > 0 {
-		hcl.WriteString("\n")
-		writePropertiesBlockWithVariables(&hcl, instance.Properties, variableMap, resourceName)
-	}
-
-	hcl.WriteString("}\n")
-
-	return hcl.String(), nil
-}
-
-// writePropertiesBlockWithVariables writes the properties block with variable references where applicable
-// Properties maintain the type/value structure, with variables injected into the value field
-func writePropertiesBlockWithVariables(hcl *strings.Builder, properties map[string]ConnectorPropertyValue, variableMap map[string]string, resourceName string) {
-	hcl.WriteString("  properties = jsonencode({\n")
-
-	// Sort keys for consistent output
-	keys := make([]string, 0, len(properties))
-	for k := range properties {
-		keys = append(keys, k)
+	if resourceName == "" {
+		resourceName = utils.DefaultNamingStrategy().ResourceName(instance.ID, instance.Name)
 	}
-	sort.Strings(keys)
-
-	// Write each property with nested type/value structure
-	for i, key := range keys {
-		prop := properties[key]
-		value := prop.Value
-
-		// Check if this property has a variable mapping
-		// Variable map key format: "connection.resourceName.properties.propertyName"
-		propertyPath := fmt.Sprintf("connection.%s.properties.%s", resourceName, key)
-		varName, hasVariable := variableMap[propertyPath]
-
-		// Start property object
-		hcl.WriteString(fmt.Sprintf("      \"%s\": {\n", key))
-
-		// Write type field only when non-empty to match API omitEmpty behavior
-		if strings.TrimSpace(prop.Type) != "" {
-			hcl.WriteString(fmt.Sprintf("          \"type\": \"%s\",\n", prop.Type))
-		}
 
-		// Write value field
-		var formattedValue string
-		if hasVariable {
-			// Use variable reference with template syntax for jsonencode
-			formattedValue = fmt.Sprintf("\"${var.%s}\"", varName)
-		} else {
-			// Bug 09: Masked secrets should use variables when variableMap is provided
-			// Only generate TODO when no variable mapping exists
-			if value == nil {
-				formattedValue = "null"
-			} else {
-				// Format based on type
-				switch v := value.(type) {
-				case string:
-					formattedValue = fmt.Sprintf("\"%s\"", v)
-				case bool:
-					formattedValue = fmt.Sprintf("%t", v)
-				case float64:
-					if v == float64(int64(v)) {
-						formattedValue = fmt.Sprintf("%d", int64(v))
-					} else {
-						formattedValue = fmt.Sprintf("%f", v)
-					}
-				case map[string]interface{}:
-					// Complex nested object - marshal to JSON
-					jsonBytes, err := json.Marshal(v)
-					if err != nil {
-						formattedValue = fmt.Sprintf("\"%v\"", v)
-					} else {
-						formattedValue = string(jsonBytes)
-					}
-				default:
-					formattedValue = fmt.Sprintf("\"%v\"", v)
-				}
-			}
-		}
-
-		hcl.WriteString(fmt.Sprintf("          \"value\": %s\n", formattedValue))
-
-		// Close property object
-		if i < len(keys)-1 {
-			hcl.WriteString("      },\n")
-		} else {
-			hcl.WriteString("      }\n")
-		}
+	// Properties maintain the type/value structure, with variables injected into the value field
+	// Variable map key format: "connection.resourceName.properties.propertyName"
+	resource, err := connectorInstanceResource(instance, skipDependencies, resourceName, func(key string, value interface{}) string {
+		return variableMap[fmt.Sprintf("connection.%s.properties.%s", resourceName, key)]
+	})
+	if err != nil {
+		return "", err
 	}
-
-	hcl.WriteString("  })\n")
+	return model.HCL(resource)
 }
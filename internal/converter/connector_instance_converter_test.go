@@ -42,19 +42,19 @@ func TestConnectorInstanceConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_connector_instance" "pingcli__PingOne-0020-Protect"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "PingOne Protect"`,
+				`name = "PingOne Protect"`,
 				`connector = {`,
 				`id = "pingOneRiskConnector"`,
 				`properties = jsonencode({`,
-				`"clientId": {`,
-				`"type": "string"`,
-				`"value": "d2671735-e614-486c-9ae6-bdd72c5cd716"`,
-				`"clientSecret": {`,
-				`"value": "${var.davinci_connection_PingOne-0020-Protect_clientSecret}"`,
-				`"envId": {`,
-				`"value": "62f10a04-6c54-40c2-a97d-80a98522ff9a"`,
-				`"region": {`,
-				`"value": "NA"`,
+				`"clientId" = {`,
+				`"type"  = "string"`,
+				`"value" = "d2671735-e614-486c-9ae6-bdd72c5cd716"`,
+				`"clientSecret" = {`,
+				`"value" = "${var.davinci_connection_PingOne-0020-Protect_clientSecret}"`,
+				`"envId" = {`,
+				`"value" = "62f10a04-6c54-40c2-a97d-80a98522ff9a"`,
+				`"region" = {`,
+				`"value" = "NA"`,
 			},
 		},
 		{
@@ -74,15 +74,15 @@ func TestConnectorInstanceConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_connector_instance" "pingcli__HTTP-0020-With-0020-Empty-0020-Type"`,
 				`properties = jsonencode({`,
-				`"endpoint": {`,
+				`"endpoint" = {`,
 				// Ensure value present
-				`"value": "https://api.example.com"`,
+				`"value" = "https://api.example.com"`,
 			},
 			// Ensure type is omitted entirely when empty
 			negativeExpected: []string{
-				`"type": ""`,
-				`"type": "string"`,
-				`"type":`,
+				`"type"  = ""`,
+				`"type"  = "string"`,
+				`"type"  = `,
 			},
 		},
 		{
@@ -96,7 +96,7 @@ func TestConnectorInstanceConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_connector_instance" "pingcli__My-0020-Annotation"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "My Annotation"`,
+				`name = "My Annotation"`,
 				`connector = {`,
 				`id = "annotationConnector"`,
 			},
@@ -122,14 +122,14 @@ func TestConnectorInstanceConversion(t *testing.T) {
 			expected: []string{
 				`resource "pingone_davinci_connector_instance" "pingcli__External-0020-API"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "External API"`,
+				`name = "External API"`,
 				`connector = {`,
 				`id = "httpConnector"`,
 				`properties = jsonencode({`,
-				`"apiKey": {`,
-				`"value": "${var.davinci_connection_External-0020-API_apiKey}"`,
-				`"endpoint": {`,
-				`"value": "https://api.example.com"`,
+				`"apiKey" = {`,
+				`"value" = "${var.davinci_connection_External-0020-API_apiKey}"`,
+				`"endpoint" = {`,
+				`"value" = "https://api.example.com"`,
 			},
 		},
 	}
@@ -45,22 +45,22 @@ func TestConnectorInstancePropertiesFormatting(t *testing.T) {
 			}`,
 			expected: []string{
 				`properties = jsonencode({`,
-				`"clientId": {`,
-				`"type": "string"`,
-				`"value": "3642f58b-b0c2-4a35-b1b1-e24d051de546"`,
-				`"clientSecret": {`,
-				`"value": "${var.davinci_connection_PingOne_clientSecret}"`,
-				`"envId": {`,
-				`"value": "4111cd46-25bf-4a5b-8c74-184a9d0c1826"`,
-				`"region": {`,
-				`"value": "NA"`,
+				`"clientId" = {`,
+				`"type"  = "string"`,
+				`"value" = "3642f58b-b0c2-4a35-b1b1-e24d051de546"`,
+				`"clientSecret" = {`,
+				`"value" = "${var.davinci_connection_PingOne_clientSecret}"`,
+				`"envId" = {`,
+				`"value" = "4111cd46-25bf-4a5b-8c74-184a9d0c1826"`,
+				`"region" = {`,
+				`"value" = "NA"`,
 			},
 			notExpected: []string{
 				// Should NOT have flattened structure
 				`"clientId"     : "3642f58b-b0c2-4a35-b1b1-e24d051de546"`,
-				`"clientId": "3642f58b-b0c2-4a35-b1b1-e24d051de546"`,
+				`"clientId" = "3642f58b-b0c2-4a35-b1b1-e24d051de546"`,
 				// Should NOT have type and value on same line
-				`"type": "string", "value":`,
+				`"type"  = "string", "value" = `,
 			},
 		},
 		{
@@ -86,20 +86,20 @@ func TestConnectorInstancePropertiesFormatting(t *testing.T) {
 				}
 			}`,
 			expected: []string{
-				`"baseUrl": {`,
-				`"type": "string"`,
-				`"value": "https://api.example.com"`,
-				`"timeout": {`,
-				`"type": "number"`,
-				`"value": 30`,
-				`"enableSSL": {`,
-				`"type": "boolean"`,
-				`"value": true`,
+				`"baseUrl" = {`,
+				`"type"  = "string"`,
+				`"value" = "https://api.example.com"`,
+				`"timeout" = {`,
+				`"type"  = "number"`,
+				`"value" = 30`,
+				`"enableSSL" = {`,
+				`"type"  = "boolean"`,
+				`"value" = true`,
 			},
 			notExpected: []string{
-				`"baseUrl": "https://api.example.com"`,
-				`"timeout": 30`,
-				`"enableSSL": true`,
+				`"baseUrl" = "https://api.example.com"`,
+				`"timeout" = 30`,
+				`"enableSSL" = true`,
 			},
 		},
 	}
@@ -163,15 +163,15 @@ func TestConnectorInstancePropertiesWithVariables(t *testing.T) {
 	// Should have nested structure with variables in value fields
 	expectedPatterns := []string{
 		`properties = jsonencode({`,
-		`"clientId": {`,
-		`"type": "string"`,
-		`"value": "${var.davinci_connection_PingOne_clientId}"`,
-		`"clientSecret": {`,
-		`"value": "${var.davinci_connection_PingOne_clientSecret}"`, // Bug 09: Should use variable, not TODO
-		`"envId": {`,
-		`"value": "${var.davinci_connection_PingOne_envId}"`,
-		`"region": {`,
-		`"value": "${var.davinci_connection_PingOne_region}"`,
+		`"clientId" = {`,
+		`"type"  = "string"`,
+		`"value" = "${var.davinci_connection_PingOne_clientId}"`,
+		`"clientSecret" = {`,
+		`"value" = "${var.davinci_connection_PingOne_clientSecret}"`, // Bug 09: Should use variable, not TODO
+		`"envId" = {`,
+		`"value" = "${var.davinci_connection_PingOne_envId}"`,
+		`"region" = {`,
+		`"value" = "${var.davinci_connection_PingOne_region}"`,
 	}
 
 	for _, expected := range expectedPatterns {
@@ -180,8 +180,8 @@ func TestConnectorInstancePropertiesWithVariables(t *testing.T) {
 
 	// Should NOT have flattened variable references
 	notExpected := []string{
-		`"clientId": var.davinci_connection_PingOne_clientId`,
-		`"envId": var.davinci_connection_PingOne_envId`,
+		`"clientId" = var.davinci_connection_PingOne_clientId`,
+		`"envId" = var.davinci_connection_PingOne_envId`,
 	}
 
 	for _, ne := range notExpected {
@@ -220,11 +220,11 @@ func TestConnectorInstancePropertiesComplexValues(t *testing.T) {
 
 	// Should preserve type/value structure even for complex objects
 	expected := []string{
-		`"simpleString": {`,
-		`"type": "string"`,
-		`"value": "simple value"`,
-		`"customAuth": {`,
-		`"type": "object"`,
+		`"simpleString" = {`,
+		`"type"  = "string"`,
+		`"value" = "simple value"`,
+		`"customAuth" = {`,
+		`"type" = "object"`,
 		// Complex value should be JSON-encoded within the value field
 	}
 
@@ -280,9 +280,9 @@ func TestConnectorInstancePropertiesEmptyAndNil(t *testing.T) {
 			}`,
 			expectError: false,
 			expected: []string{
-				`"optionalField": {`,
-				`"type": "string"`,
-				`"value": null`,
+				`"optionalField" = {`,
+				`"type"  = "string"`,
+				`"value" = null`,
 			},
 		},
 	}
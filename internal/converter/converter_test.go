@@ -226,8 +226,8 @@ func TestFlowWithNodesAndEdges(t *testing.T) {
 		`"node1" = {`,
 		`id              = "node1"`,
 		`"node2" = {`,
-		`id              = "node2"`,
-		`node_type       = "EVAL"`,
+		`id        = "node2"`,
+		`node_type = "EVAL"`,
 		`edges = {`,
 		`"edge1" = {`,
 		`id     = "edge1"`,
@@ -599,7 +599,7 @@ func TestNodeWithMissingData(t *testing.T) {
 	}
 
 	// Should handle gracefully and include what's available
-	if !strings.Contains(result, `id              = "node1"`) {
+	if !strings.Contains(result, `id = "node1"`) {
 		t.Error("Output missing node id")
 	}
 }
@@ -656,7 +656,7 @@ func TestFlowWithoutGraphData(t *testing.T) {
 	if !strings.Contains(result, `resource "pingone_davinci_flow"`) {
 		t.Error("Output missing resource declaration")
 	}
-	if !strings.Contains(result, `name        = "No Graph Flow"`) {
+	if !strings.Contains(result, `name = "No Graph Flow"`) {
 		t.Error("Output missing flow name")
 	}
 }
@@ -686,7 +686,7 @@ func TestSpecialCharactersInFlowName(t *testing.T) {
 	}
 
 	// But the name attribute should preserve the original
-	if !strings.Contains(result, `name        = "Test!@#$%^&*()Flow<>?:{}[]"`) {
+	if !strings.Contains(result, `name = "Test!@#$%^&*()Flow<>?:{}[]"`) {
 		t.Error("Flow name not preserved in name attribute")
 	}
 }
@@ -860,7 +860,7 @@ func TestMultiFlowExport(t *testing.T) {
 		`name        = "Main Flow"`,
 		`description = "Parent flow"`,
 		`graph_data = {`,
-		`node_type       = "CONNECTION"`,
+		`node_type     = "CONNECTION"`,
 		`settings = {`,
 		`log_level`,
 	}
@@ -877,7 +877,7 @@ func TestMultiFlowExport(t *testing.T) {
 		`resource "pingone_davinci_flow" "pingcli__Subflow-0020-One"`,
 		`name        = "Subflow One"`,
 		`description = "First subflow"`,
-		`node_type       = "EVAL"`,
+		`node_type = "EVAL"`,
 	}
 
 	for _, expected := range expectedSubflowOneElements {
@@ -1042,7 +1042,7 @@ func TestSettingsJsLinksEmptyListPreserved(t *testing.T) {
 	if !strings.Contains(result, "settings = {") {
 		t.Fatalf("Output missing settings block.\nGot:\n%s", result)
 	}
-	if !strings.Contains(result, "js_links = []") {
+	if !strings.Contains(result, "js_links  = []") {
 		t.Errorf("js_links empty list not preserved.\nGot:\n%s", result)
 	}
 }
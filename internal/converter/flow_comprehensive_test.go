@@ -208,7 +208,7 @@ func TestComprehensiveFlowConversion(t *testing.T) {
 	assert.Contains(t, result, `input_schema = [`, "Missing input_schema")
 	assert.Contains(t, result, `trigger = {`, "Missing trigger block")
 	assert.Contains(t, result, `node_type       = "CONNECTION"`, "Missing CONNECTION node")
-	assert.Contains(t, result, `node_type       = "EVAL"`, "Missing EVAL node")
+	assert.Contains(t, result, `node_type = "EVAL"`, "Missing EVAL node")
 }
 
 // TestFlowConversion_NoEdges tests a flow with nodes but no edges
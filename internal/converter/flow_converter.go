@@ -3,13 +3,15 @@ package converter
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
-	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ConvertFlowToHCL converts a DaVinci flow JSON structure to Terraform HCL
@@ -17,8 +19,16 @@ import (
 // If skipDependencies is true, connection IDs will be left as hardcoded strings instead of Terraform references
 // graph parameter is optional; if provided, uses resolver for reference generation
 func ConvertFlowToHCL(flowData map[string]interface{}, environmentID string, skipDependencies bool, graph *resolver.DependencyGraph) (string, error) {
-	var hcl strings.Builder
+	resources, err := ConvertFlowToResources(flowData, environmentID, skipDependencies, graph)
+	if err != nil {
+		return "", err
+	}
+	return model.HCL(resources...)
+}
 
+// ConvertFlowToResources converts a DaVinci flow to its pingone_davinci_flow,
+// pingone_davinci_flow_enable and pingone_davinci_flow_deploy resources, which share a name
+func ConvertFlowToResources(flowData map[string]interface{}, environmentID string, skipDependencies bool, graph *resolver.DependencyGraph) ([]*model.Resource, error) {
 	// Generate resource name - use registered name from graph if available to ensure uniqueness
 	var resourceName string
 	if graph != nil {
@@ -32,62 +42,68 @@ func ConvertFlowToHCL(flowData map[string]interface{}, environmentID string, ski
 		}
 	}
 
-	// Fallback: generate from flow name if not in graph
+	// Fallback: generate from flow name with the graph naming strategy if not in graph
 	if resourceName == "" {
-		resourceName = utils.SanitizeResourceName(getString(flowData, "name"))
+		resourceName = graph.ResourceName(getString(flowData, "flowId"), getString(flowData, "name"))
 	}
 
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_flow\" \"%s\" {\n", resourceName))
-
-	// Handle environment_id - quote if it's a UUID string, otherwise use as-is (for var.environment_id)
-	if strings.HasPrefix(environmentID, "var.") {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %s\n\n", environmentID))
-	} else {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %q\n\n", environmentID))
+	environment, err := environmentValue(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	flowID, err := model.ResourceAttribute("pingone_davinci_flow", resourceName, "id")
+	if err != nil {
+		return nil, err
 	}
 
+	flowResource := model.NewResource("pingone_davinci_flow", resourceName)
+	flowResource.ID = getString(flowData, "flowId")
+	flow := &flowResource.Body
+
+	flow.SetAttribute("environment_id", environment)
+	flow.AppendNewline()
+
 	// Required: name
 	if name := getString(flowData, "name"); name != "" {
-		hcl.WriteString(fmt.Sprintf("  name        = %s\n", quoteString(name)))
+		flow.SetAttribute("name", model.String(name))
 	}
 
 	// Optional: description
 	if description := getString(flowData, "description"); description != "" {
-		hcl.WriteString(fmt.Sprintf("  description = %s\n", quoteString(description)))
+		flow.SetAttribute("description", model.String(description))
 	}
 
 	// Optional: color (supports both flowColor from UI export and color from API)
 	if color := getString(flowData, "flowColor"); color != "" {
-		hcl.WriteString(fmt.Sprintf("  color       = %s\n", quoteString(color)))
+		flow.SetAttribute("color", model.String(color))
 	} else if color := getString(flowData, "color"); color != "" {
-		hcl.WriteString(fmt.Sprintf("  color       = %s\n", quoteString(color)))
+		flow.SetAttribute("color", model.String(color))
 	}
 
 	// Settings block
 	if settings, ok := flowData["settings"].(map[string]interface{}); ok && len(settings) > 0 {
 		if filtered := filterFlowSettings(settings); len(filtered) > 0 {
-			hcl.WriteString("\n")
-			if err := writeSettingsBlock(&hcl, filtered); err != nil {
-				return "", fmt.Errorf("failed to write settings: %w", err)
-			}
+			flow.AppendNewline()
+			flow.SetAttribute("settings", settingsValue(filtered))
 		}
 	}
 
 	// Graph data block - complex nested structure
 	if graphData, ok := flowData["graphData"].(map[string]interface{}); ok {
-		hcl.WriteString("\n")
-		if err := writeGraphDataBlock(&hcl, graphData, skipDependencies, graph); err != nil {
-			return "", fmt.Errorf("failed to write graph_data: %w", err)
+		flow.AppendNewline()
+		from := resolver.ResourceRef{Type: "pingone_davinci_flow", ID: getString(flowData, "flowId"), Name: resourceName}
+		value, err := graphDataValue(graphData, from, skipDependencies, graph)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write graph_data: %w", err)
 		}
+		flow.SetAttribute("graph_data", value)
 	}
 
 	// Input schema list
 	inputSchemaEmitted := false
 	if inputSchema, ok := flowData["inputSchema"].([]interface{}); ok && len(inputSchema) > 0 {
-		hcl.WriteString("\n")
-		if err := writeInputSchemaBlock(&hcl, inputSchema); err != nil {
-			return "", fmt.Errorf("failed to write input_schema: %w", err)
-		}
+		flow.AppendNewline()
+		flow.SetAttribute("input_schema", inputSchemaValue(inputSchema))
 	} else if isc, ok := flowData["inputSchemaCompiled"].(map[string]interface{}); ok {
 		// Build input_schema from compiled schema. Some environments nest under "parameters",
 		// others place properties at the root. Support both.
@@ -108,7 +124,7 @@ func ConvertFlowToHCL(flowData map[string]interface{}, environmentID string, ski
 				}
 
 				// Fallback: derive input_schema by scanning graphData node trigger properties
-				if !strings.Contains(hcl.String(), "input_schema = [") {
+				if flow.Attribute("input_schema") == nil {
 					if graphData, ok := flowData["graphData"].(map[string]interface{}); ok {
 						if elements, ok := graphData["elements"].(map[string]interface{}); ok {
 							if nodes, ok := elements["nodes"].([]interface{}); ok {
@@ -174,10 +190,8 @@ func ConvertFlowToHCL(flowData map[string]interface{}, environmentID string, ski
 										derived = append(derived, item)
 									}
 									if len(derived) > 0 && !inputSchemaEmitted {
-										hcl.WriteString("\n")
-										if err := writeInputSchemaBlock(&hcl, derived); err != nil {
-											return "", fmt.Errorf("failed to write input_schema (graph fallback): %w", err)
-										}
+										flow.AppendNewline()
+										flow.SetAttribute("input_schema", inputSchemaValue(derived))
 										inputSchemaEmitted = true
 									}
 								}
@@ -221,39 +235,35 @@ func ConvertFlowToHCL(flowData map[string]interface{}, environmentID string, ski
 			}
 
 			if len(derived) > 0 && !inputSchemaEmitted {
-				hcl.WriteString("\n")
-				if err := writeInputSchemaBlock(&hcl, derived); err != nil {
-					return "", fmt.Errorf("failed to write input_schema (compiled): %w", err)
-				}
+				flow.AppendNewline()
+				flow.SetAttribute("input_schema", inputSchemaValue(derived))
 			}
 		}
 	}
 
 	// Output schema object
 	if outputSchema, ok := flowData["outputSchema"].(map[string]interface{}); ok && len(outputSchema) > 0 {
-		hcl.WriteString("\n")
-		if err := writeOutputSchemaBlock(&hcl, outputSchema); err != nil {
-			return "", fmt.Errorf("failed to write output_schema: %w", err)
+		flow.AppendNewline()
+		value, err := outputSchemaValue(outputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write output_schema: %w", err)
 		}
+		flow.SetAttribute("output_schema", value)
 	}
 
 	// Trigger block
 	if trigger, ok := flowData["trigger"].(map[string]interface{}); ok {
-		hcl.WriteString("\n")
-		if err := writeTriggerBlock(&hcl, trigger); err != nil {
-			return "", fmt.Errorf("failed to write trigger: %w", err)
-		}
+		flow.AppendNewline()
+		flow.SetAttribute("trigger", triggerValue(trigger))
 	}
 
-	hcl.WriteString("}\n")
-
 	// Generate auxiliary resources: pingone_davinci_flow_enable and pingone_davinci_flow_deploy
 	// Use same resource name to keep a consistent grouping.
 
 	// Resolve flow enabled status with conflict detection between export ('flowStatus') and API ('enabled').
 	enabledVal, enabledHasVal, err := resolveEnabled(flowData)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Flow ID for hardcoded path when skipDependencies is true
@@ -262,71 +272,73 @@ func ConvertFlowToHCL(flowData map[string]interface{}, environmentID string, ski
 		// Some API payloads use 'id'
 		hardFlowID = getString(flowData, "id")
 	}
+	if flowResource.ID == "" {
+		flowResource.ID = hardFlowID
+	}
 
-	// 1) flow_enable resource
-	hcl.WriteString("\n")
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_flow_enable\" \"%s\" {\n", resourceName))
-	if strings.HasPrefix(environmentID, "var.") {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %s\n", environmentID))
-	} else {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %q\n", environmentID))
+	flowEnabled, err := model.ResourceAttribute("pingone_davinci_flow", resourceName, "enabled")
+	if err != nil {
+		return nil, err
+	}
+	currentVersion, err := model.ResourceAttribute("pingone_davinci_flow", resourceName, "current_version")
+	if err != nil {
+		return nil, err
 	}
+
+	// 1) flow_enable resource
+	enableResource := model.NewResource("pingone_davinci_flow_enable", resourceName)
+	enableResource.ID = flowResource.ID
+	enable := &enableResource.Body
+	enable.SetAttribute("environment_id", environment)
 	if !skipDependencies {
-		hcl.WriteString(fmt.Sprintf("  flow_id        = pingone_davinci_flow.%s.id\n", resourceName))
+		enable.SetAttribute("flow_id", flowID)
 		// Prefer dependency reference to provider-computed attribute when not skipping dependencies
-		hcl.WriteString(fmt.Sprintf("  enabled        = pingone_davinci_flow.%s.enabled\n", resourceName))
+		enable.SetAttribute("enabled", flowEnabled)
 	} else {
 		// Hardcode values when skipping dependencies
 		if hardFlowID != "" {
-			hcl.WriteString(fmt.Sprintf("  flow_id        = %q\n", hardFlowID))
+			enable.SetAttribute("flow_id", model.String(hardFlowID))
 		} else {
 			// Fallback to dependency reference if flow ID is unavailable in payload
-			hcl.WriteString(fmt.Sprintf("  flow_id        = pingone_davinci_flow.%s.id\n", resourceName))
+			enable.SetAttribute("flow_id", flowID)
 		}
 		if enabledHasVal {
-			hcl.WriteString(fmt.Sprintf("  enabled        = %t\n", enabledVal))
+			enable.SetAttribute("enabled", model.Bool(enabledVal))
 		} else {
 			// Fallback to dependency reference if enabled cannot be resolved from payload
-			hcl.WriteString(fmt.Sprintf("  enabled        = pingone_davinci_flow.%s.enabled\n", resourceName))
+			enable.SetAttribute("enabled", flowEnabled)
 		}
 	}
-	hcl.WriteString("}\n")
 
 	// 2) flow_deploy resource
-	hcl.WriteString("\n")
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_flow_deploy\" \"%s\" {\n", resourceName))
-	if strings.HasPrefix(environmentID, "var.") {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %s\n", environmentID))
-	} else {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %q\n", environmentID))
-	}
+	deployResource := model.NewResource("pingone_davinci_flow_deploy", resourceName)
+	deployResource.ID = flowResource.ID
+	deploy := &deployResource.Body
+	deploy.SetAttribute("environment_id", environment)
+	triggerValues := &model.Object{}
 	if !skipDependencies {
-		hcl.WriteString(fmt.Sprintf("  flow_id        = pingone_davinci_flow.%s.id\n", resourceName))
-		hcl.WriteString("  deploy_trigger_values = {\n")
+		deploy.SetAttribute("flow_id", flowID)
 		// Use current_version for deploy trigger to align with provider expectations
-		hcl.WriteString(fmt.Sprintf("    \"deployed_version\" = pingone_davinci_flow.%s.current_version\n", resourceName))
-		hcl.WriteString("  }\n")
+		triggerValues.SetKey("deployed_version", currentVersion)
 	} else {
 		if hardFlowID != "" {
-			hcl.WriteString(fmt.Sprintf("  flow_id        = %q\n", hardFlowID))
+			deploy.SetAttribute("flow_id", model.String(hardFlowID))
 		} else {
-			hcl.WriteString(fmt.Sprintf("  flow_id        = pingone_davinci_flow.%s.id\n", resourceName))
+			deploy.SetAttribute("flow_id", flowID)
 		}
-		hcl.WriteString("  deploy_trigger_values = {\n")
 		// Prefer currentVersion from payload when skipping dependencies; coerce to integer when possible.
 		if cv, ok := flowData["currentVersion"].(float64); ok {
-			hcl.WriteString(fmt.Sprintf("    \"deployed_version\" = %d\n", int(cv)))
+			triggerValues.SetKey("deployed_version", model.Int(int64(cv)))
 		} else if cvi, ok := flowData["currentVersion"].(int); ok {
-			hcl.WriteString(fmt.Sprintf("    \"deployed_version\" = %d\n", cvi))
+			triggerValues.SetKey("deployed_version", model.Int(int64(cvi)))
 		} else {
 			// Fallback to dependency reference when payload lacks currentVersion
-			hcl.WriteString(fmt.Sprintf("    \"deployed_version\" = pingone_davinci_flow.%s.current_version\n", resourceName))
+			triggerValues.SetKey("deployed_version", currentVersion)
 		}
-		hcl.WriteString("  }\n")
 	}
-	hcl.WriteString("}\n")
+	deploy.SetAttribute("deploy_trigger_values", triggerValues)
 
-	return hcl.String(), nil
+	return []*model.Resource{flowResource, enableResource, deployResource}, nil
 }
 
 // toBool safely converts an interface to bool, handling nil and non-bool types
@@ -374,10 +386,37 @@ func resolveEnabled(flowData map[string]interface{}) (bool, bool, error) {
 	return false, false, nil
 }
 
-// writeSettingsBlock writes the settings nested block
-func writeSettingsBlock(hcl *strings.Builder, settings map[string]interface{}) error {
-	hcl.WriteString("  settings = {\n")
+// flowSettingsFieldNames maps flow setting JSON field names to HCL attribute names
+var flowSettingsFieldNames = map[string]string{
+	"csp":                             "csp",
+	"css":                             "css",
+	"cssLinks":                        "css_links",
+	"customErrorScreenBrandLogoUrl":   "custom_error_screen_brand_logo_url",
+	"customErrorShowFooter":           "custom_error_show_footer",
+	"customFaviconLink":               "custom_favicon_link",
+	"customLogoURLSelection":          "custom_logo_urlselection",
+	"customTitle":                     "custom_title",
+	"defaultErrorScreenBrandLogo":     "default_error_screen_brand_logo",
+	"flowHttpTimeoutInSeconds":        "flow_http_timeout_in_seconds",
+	"flowTimeoutInSeconds":            "flow_timeout_in_seconds",
+	"intermediateLoadingScreenCSS":    "intermediate_loading_screen_css",
+	"intermediateLoadingScreenHTML":   "intermediate_loading_screen_html",
+	"jsCustomFlowPlayer":              "js_custom_flow_player",
+	"jsLinks":                         "js_links",
+	"logLevel":                        "log_level",
+	"requireAuthenticationToInitiate": "require_authentication_to_initiate",
+	"scrubSensitiveInfo":              "scrub_sensitive_info",
+	"sensitiveInfoFields":             "sensitive_info_fields",
+	"useCSP":                          "use_csp",
+	"useCustomCSS":                    "use_custom_css",
+	"useCustomFlowPlayer":             "use_custom_flow_player",
+	"useCustomScript":                 "use_custom_script",
+	"useIntermediateLoadingScreen":    "use_intermediate_loading_screen",
+	"validateOnSave":                  "validate_on_save",
+}
 
+// settingsValue returns the settings object
+func settingsValue(settings map[string]interface{}) model.Value {
 	// Get keys and sort for consistent output
 	keys := make([]string, 0, len(settings))
 	for k := range settings {
@@ -390,94 +429,56 @@ func writeSettingsBlock(hcl *strings.Builder, settings map[string]interface{}) e
 	}
 	sort.Strings(keys)
 
-	// Map JSON field names to HCL attribute names
-	fieldNameMap := map[string]string{
-		"csp":                             "csp",
-		"css":                             "css",
-		"cssLinks":                        "css_links",
-		"customErrorScreenBrandLogoUrl":   "custom_error_screen_brand_logo_url",
-		"customErrorShowFooter":           "custom_error_show_footer",
-		"customFaviconLink":               "custom_favicon_link",
-		"customLogoURLSelection":          "custom_logo_urlselection",
-		"customTitle":                     "custom_title",
-		"defaultErrorScreenBrandLogo":     "default_error_screen_brand_logo",
-		"flowHttpTimeoutInSeconds":        "flow_http_timeout_in_seconds",
-		"flowTimeoutInSeconds":            "flow_timeout_in_seconds",
-		"intermediateLoadingScreenCSS":    "intermediate_loading_screen_css",
-		"intermediateLoadingScreenHTML":   "intermediate_loading_screen_html",
-		"jsCustomFlowPlayer":              "js_custom_flow_player",
-		"jsLinks":                         "js_links",
-		"logLevel":                        "log_level",
-		"requireAuthenticationToInitiate": "require_authentication_to_initiate",
-		"scrubSensitiveInfo":              "scrub_sensitive_info",
-		"sensitiveInfoFields":             "sensitive_info_fields",
-		"useCSP":                          "use_csp",
-		"useCustomCSS":                    "use_custom_css",
-		"useCustomFlowPlayer":             "use_custom_flow_player",
-		"useCustomScript":                 "use_custom_script",
-		"useIntermediateLoadingScreen":    "use_intermediate_loading_screen",
-		"validateOnSave":                  "validate_on_save",
-	}
-
+	object := &model.Object{}
 	for _, key := range keys {
 		value := settings[key]
-		hclKey := fieldNameMap[key]
+		hclKey := flowSettingsFieldNames[key]
 		if hclKey == "" {
 			hclKey = toSnakeCase(key)
 		}
 
 		// Special handling for js_links - array of objects
 		if key == "jsLinks" {
-			// If present but null, render as empty list [] to avoid diffs
-			if value == nil {
-				hcl.WriteString("    js_links = []\n")
+			jsLinks, _ := value.([]interface{})
+			// If present but null or empty, render as empty list [] to avoid diffs
+			if value == nil || (jsLinks != nil && len(jsLinks) == 0) {
+				object.Set(hclKey, &model.Tuple{})
 				continue
 			}
-			if jsLinks, ok := value.([]interface{}); ok && len(jsLinks) > 0 {
-				hcl.WriteString("    js_links = [\n")
-				for i, linkInterface := range jsLinks {
-					if link, ok := linkInterface.(map[string]interface{}); ok {
-						hcl.WriteString("      {\n")
-						// Write all required fields for js_links - these are always written even if empty
-						crossorigin := getString(link, "crossorigin")
-						hcl.WriteString(fmt.Sprintf("        crossorigin    = %s\n", quoteString(crossorigin)))
-
-						// defer is required and defaults to false if not present
-						deferVal := false
-						if val, ok := link["defer"].(bool); ok {
-							deferVal = val
-						}
-						hcl.WriteString(fmt.Sprintf("        defer          = %t\n", deferVal))
-
-						integrity := getString(link, "integrity")
-						hcl.WriteString(fmt.Sprintf("        integrity      = %s\n", quoteString(integrity)))
-
-						// label is optional but commonly used
-						if label := getString(link, "label"); label != "" {
-							hcl.WriteString(fmt.Sprintf("        label          = %s\n", quoteString(label)))
-						}
-
-						referrerpolicy := getString(link, "referrerpolicy")
-						hcl.WriteString(fmt.Sprintf("        referrerpolicy = %s\n", quoteString(referrerpolicy)))
-
-						linkType := getString(link, "type")
-						hcl.WriteString(fmt.Sprintf("        type           = %s\n", quoteString(linkType)))
+			if len(jsLinks) > 0 {
+				links := make([]model.Value, 0, len(jsLinks))
+				for _, linkInterface := range jsLinks {
+					link, ok := linkInterface.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					// Write all required fields for js_links - these are always written even if empty
+					linkObject := &model.Object{}
+					linkObject.Set("crossorigin", model.String(getString(link, "crossorigin")))
+
+					// defer is required and defaults to false if not present; exports may carry it as a string
+					deferVal := false
+					switch val := link["defer"].(type) {
+					case bool:
+						deferVal = val
+					case string:
+						deferVal, _ = strconv.ParseBool(val)
+					}
+					linkObject.Set("defer", model.Bool(deferVal))
+					linkObject.Set("integrity", model.String(getString(link, "integrity")))
 
-						// value is required
-						value := getString(link, "value")
-						hcl.WriteString(fmt.Sprintf("        value          = %s\n", quoteString(value)))
-						hcl.WriteString("      }")
-						if i < len(jsLinks)-1 {
-							hcl.WriteString(",")
-						}
-						hcl.WriteString("\n")
+					// label is optional but commonly used
+					if label := getString(link, "label"); label != "" {
+						linkObject.Set("label", model.String(label))
 					}
+					linkObject.Set("referrerpolicy", model.String(getString(link, "referrerpolicy")))
+					linkObject.Set("type", model.String(getString(link, "type")))
+
+					// value is required
+					linkObject.Set("value", model.String(getString(link, "value")))
+					links = append(links, linkObject)
 				}
-				hcl.WriteString("    ]\n")
-			}
-			// If jsLinks is an empty array, render as [] (explicit empty list)
-			if jsLinks, ok := value.([]interface{}); ok && len(jsLinks) == 0 {
-				hcl.WriteString("    js_links = []\n")
+				object.Set(hclKey, &model.Tuple{Elems: links})
 			}
 			continue
 		}
@@ -485,158 +486,151 @@ func writeSettingsBlock(hcl *strings.Builder, settings map[string]interface{}) e
 		// TODO: This seems unnecessary given general handling. May be better to ignore null.
 		// Emit explicit nulls for other settings keys when present
 		if value == nil {
-			hcl.WriteString(fmt.Sprintf("    %-36s = null\n", hclKey))
+			object.Set(hclKey, model.Null())
 			continue
 		}
 
 		switch v := value.(type) {
 		case string:
-			// Revert heredoc: emit quoted strings while avoiding double-escaping
-			// 1) Decode JSON-style escapes to raw characters (e.g., \n -> newline)
-			decoded := decodeJSONEscapes(v)
-			// 2) Prevent Terraform interpolation by escaping literal "${" as "$${"
-			safe := strings.ReplaceAll(decoded, "${", "$${")
-			// 3) Quote using strconv.Quote to produce single-escaped sequences
-			quoted := strconv.Quote(safe)
-			hcl.WriteString(fmt.Sprintf("    %-36s = %s\n", hclKey, quoted))
+			// Decode JSON-style escapes to raw characters (e.g., \n -> newline); the writer
+			// escapes them again, along with quotes and template sequences
+			object.Set(hclKey, model.String(decodeJSONEscapes(v)))
 		case float64:
-			hcl.WriteString(fmt.Sprintf("    %-36s = %d\n", hclKey, int(v)))
+			object.Set(hclKey, model.Int(int64(v)))
 		case bool:
-			hcl.WriteString(fmt.Sprintf("    %-36s = %t\n", hclKey, v))
+			object.Set(hclKey, model.Bool(v))
 		case []interface{}:
 			// Handle array fields like cssLinks, sensitiveInfoFields
-			hcl.WriteString(fmt.Sprintf("    %s = [", hclKey))
-			for i, item := range v {
-				if i > 0 {
-					hcl.WriteString(", ")
-				}
-				hcl.WriteString(quoteString(fmt.Sprintf("%v", item)))
-			}
-			hcl.WriteString("]\n")
+			object.Set(hclKey, stringList(v))
 		}
 	}
 
-	hcl.WriteString("  }\n")
-	return nil
+	return object
 }
 
-// writeGraphDataBlock writes the graph_data nested block
-func writeGraphDataBlock(hcl *strings.Builder, graphData map[string]interface{}, skipDependencies bool, graph *resolver.DependencyGraph) error {
-	hcl.WriteString("  graph_data = {\n")
+// graphDataValue returns the graph_data object
+// from identifies the flow being converted, for missing dependency reporting
+func graphDataValue(graphData map[string]interface{}, from resolver.ResourceRef, skipDependencies bool, graph *resolver.DependencyGraph) (model.Value, error) {
+	object := &model.Object{}
 
 	// Data object - include even if empty object {}
 	if data, ok := graphData["data"].(map[string]interface{}); ok {
-		// Special-case: empty object should render as jsonencode({}) on one line
-		if len(data) == 0 {
-			hcl.WriteString("    data = jsonencode({})\n")
-		} else {
-			hcl.WriteString("    data = jsonencode(")
-			// Use readable HCL map formatting inside jsonencode
-			writeJSONAsHCLMap(hcl, data, 4)
-			hcl.WriteString(")\n")
+		value, err := jsonValue(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write data: %w", err)
 		}
+		object.Set("data", model.JSONEncode(value))
 	}
 
 	// Elements (nodes and edges) - most complex part
 	if elements, ok := graphData["elements"].(map[string]interface{}); ok {
-		hcl.WriteString("    elements = {\n")
+		elementsObject := &model.Object{}
 
 		// Nodes
 		if nodes, ok := elements["nodes"].([]interface{}); ok {
-			// Deterministic ordering: sort by data.id (lexicographic) to avoid plan diffs
-			sortedNodes := make([]interface{}, 0, len(nodes))
-			sortedNodes = append(sortedNodes, nodes...)
-			sort.SliceStable(sortedNodes, func(i, j int) bool {
-				// Extract id fields safely
-				left, _ := sortedNodes[i].(map[string]interface{})
-				right, _ := sortedNodes[j].(map[string]interface{})
-				ldata, _ := left["data"].(map[string]interface{})
-				rdata, _ := right["data"].(map[string]interface{})
-				lid := getString(ldata, "id")
-				rid := getString(rdata, "id")
-				return lid < rid
-			})
-			if err := writeNodesBlock(hcl, sortedNodes, skipDependencies, graph); err != nil {
-				return fmt.Errorf("failed to write nodes: %w", err)
+			value, err := nodesValue(sortByDataID(nodes), from, skipDependencies, graph)
+			if err != nil {
+				return nil, fmt.Errorf("failed to write nodes: %w", err)
 			}
+			elementsObject.Set("nodes", value)
 		}
 
 		// Edges
 		if edges, ok := elements["edges"].([]interface{}); ok {
-			// Deterministic ordering: sort by data.id (lexicographic) to avoid plan diffs
-			sortedEdges := make([]interface{}, 0, len(edges))
-			sortedEdges = append(sortedEdges, edges...)
-			sort.SliceStable(sortedEdges, func(i, j int) bool {
-				left, _ := sortedEdges[i].(map[string]interface{})
-				right, _ := sortedEdges[j].(map[string]interface{})
-				ldata, _ := left["data"].(map[string]interface{})
-				rdata, _ := right["data"].(map[string]interface{})
-				lid := getString(ldata, "id")
-				rid := getString(rdata, "id")
-				return lid < rid
-			})
-			if err := writeEdgesBlock(hcl, sortedEdges); err != nil {
-				return fmt.Errorf("failed to write edges: %w", err)
-			}
+			elementsObject.Set("edges", edgesValue(sortByDataID(edges)))
 		}
 
-		hcl.WriteString("    }\n\n")
+		object.Set("elements", elementsObject)
 	}
 
 	// Pan object
 	if pan, ok := graphData["pan"].(map[string]interface{}); ok {
-		hcl.WriteString("    pan = {\n")
-		if x, ok := pan["x"].(float64); ok {
-			hcl.WriteString(fmt.Sprintf("      x = %g\n", x))
-		}
-		if y, ok := pan["y"].(float64); ok {
-			hcl.WriteString(fmt.Sprintf("      y = %g\n", y))
-		}
-		hcl.WriteString("    }\n\n")
+		object.Set("pan", positionValue(pan))
 	}
 
 	// Simple fields
 	if zoom, ok := graphData["zoom"].(float64); ok {
-		hcl.WriteString(fmt.Sprintf("    zoom                  = %d\n", int(zoom)))
+		object.Set("zoom", model.Int(int64(zoom)))
 	}
 	if minZoom, ok := graphData["minZoom"].(float64); ok {
-		hcl.WriteString(fmt.Sprintf("    min_zoom              = %g\n", minZoom))
+		object.Set("min_zoom", model.Number(minZoom))
 	}
 	if maxZoom, ok := graphData["maxZoom"].(float64); ok {
-		hcl.WriteString(fmt.Sprintf("    max_zoom              = %g\n", maxZoom))
+		object.Set("max_zoom", model.Number(maxZoom))
 	}
 	if zoomingEnabled, ok := graphData["zoomingEnabled"].(bool); ok {
-		hcl.WriteString(fmt.Sprintf("    zooming_enabled       = %t\n", zoomingEnabled))
+		object.Set("zooming_enabled", model.Bool(zoomingEnabled))
 	}
 	if panningEnabled, ok := graphData["panningEnabled"].(bool); ok {
-		hcl.WriteString(fmt.Sprintf("    panning_enabled       = %t\n", panningEnabled))
+		object.Set("panning_enabled", model.Bool(panningEnabled))
 	}
 	if userZoomingEnabled, ok := graphData["userZoomingEnabled"].(bool); ok {
-		hcl.WriteString(fmt.Sprintf("    user_zooming_enabled  = %t\n", userZoomingEnabled))
+		object.Set("user_zooming_enabled", model.Bool(userZoomingEnabled))
 	}
 	if userPanningEnabled, ok := graphData["userPanningEnabled"].(bool); ok {
-		hcl.WriteString(fmt.Sprintf("    user_panning_enabled  = %t\n", userPanningEnabled))
+		object.Set("user_panning_enabled", model.Bool(userPanningEnabled))
 	}
 	if boxSelectionEnabled, ok := graphData["boxSelectionEnabled"].(bool); ok {
-		hcl.WriteString(fmt.Sprintf("    box_selection_enabled = %t\n", boxSelectionEnabled))
+		object.Set("box_selection_enabled", model.Bool(boxSelectionEnabled))
 	}
 
 	// Renderer - uses jsonencode() because it's jsontypes.NormalizedType
 	if renderer, ok := graphData["renderer"].(map[string]interface{}); ok {
-		rendererJSON, err := json.Marshal(renderer)
+		value, err := jsonValue(renderer)
 		if err != nil {
-			return fmt.Errorf("failed to marshal renderer: %w", err)
+			return nil, fmt.Errorf("failed to write renderer: %w", err)
 		}
-		hcl.WriteString(fmt.Sprintf("\n    renderer = jsonencode(%s)\n", string(rendererJSON)))
+		object.Set("renderer", model.JSONEncode(value))
+	}
+
+	return object, nil
+}
+
+// sortByDataID returns a copy of graph elements sorted by data.id (lexicographic), a
+// deterministic ordering that avoids plan diffs
+func sortByDataID(elements []interface{}) []interface{} {
+	sorted := make([]interface{}, 0, len(elements))
+	sorted = append(sorted, elements...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		// Extract id fields safely
+		left, _ := sorted[i].(map[string]interface{})
+		right, _ := sorted[j].(map[string]interface{})
+		ldata, _ := left["data"].(map[string]interface{})
+		rdata, _ := right["data"].(map[string]interface{})
+		return getString(ldata, "id") < getString(rdata, "id")
+	})
+	return sorted
+}
+
+// positionValue returns an {x, y} object such as a node position or the graph pan
+func positionValue(position map[string]interface{}) model.Value {
+	object := &model.Object{}
+	if x, ok := position["x"].(float64); ok {
+		object.Set("x", model.Number(x))
+	}
+	if y, ok := position["y"].(float64); ok {
+		object.Set("y", model.Number(y))
 	}
+	return object
+}
 
-	hcl.WriteString("  }\n")
-	return nil
+// setElementAttributes sets the attributes shared by nodes and edges
+func setElementAttributes(object *model.Object, element map[string]interface{}) {
+	if group := getString(element, "group"); group != "" {
+		object.Set("group", model.String(group))
+	}
+	for _, name := range []string{"removed", "selected", "selectable", "locked", "grabbable", "pannable"} {
+		if value, ok := element[name].(bool); ok {
+			object.Set(name, model.Bool(value))
+		}
+	}
+	// Always include classes field (even if empty string)
+	object.Set("classes", model.String(getString(element, "classes")))
 }
 
-// writeNodesBlock writes the nodes map within elements
-func writeNodesBlock(hcl *strings.Builder, nodes []interface{}, skipDependencies bool, graph *resolver.DependencyGraph) error {
-	hcl.WriteString("      nodes = {\n")
+// nodesValue returns the nodes map within elements
+func nodesValue(nodes []interface{}, from resolver.ResourceRef, skipDependencies bool, graph *resolver.DependencyGraph) (model.Value, error) {
+	object := &model.Object{}
 
 	for i, nodeInterface := range nodes {
 		node, ok := nodeInterface.(map[string]interface{})
@@ -657,130 +651,280 @@ func writeNodesBlock(hcl *strings.Builder, nodes []interface{}, skipDependencies
 			nodeKey = fmt.Sprintf("node_%d", i)
 		}
 
-		hcl.WriteString(fmt.Sprintf("        %s = {\n", quoteString(nodeKey)))
-
+		nodeObject := &model.Object{}
 		if data, ok := node["data"].(map[string]interface{}); ok {
-			hcl.WriteString("          data = {\n")
+			dataObject := &model.Object{}
 
 			// Required: id and node_type
 			if id := getString(data, "id"); id != "" {
-				hcl.WriteString(fmt.Sprintf("            id              = %s\n", quoteString(id)))
+				dataObject.Set("id", model.String(id))
 			}
 			if nodeType := getString(data, "nodeType"); nodeType != "" {
-				hcl.WriteString(fmt.Sprintf("            node_type       = %s\n", quoteString(nodeType)))
+				dataObject.Set("node_type", model.String(nodeType))
 			}
 
 			// Optional: id_unique (from API field idUnique)
 			if idUnique := getString(data, "idUnique"); idUnique != "" {
-				hcl.WriteString(fmt.Sprintf("            id_unique       = %s\n", quoteString(idUnique)))
+				dataObject.Set("id_unique", model.String(idUnique))
 			}
 
 			// Optional fields - connection_id needs special handling
 			if connectionID := getString(data, "connectionId"); connectionID != "" {
 				if skipDependencies {
 					// Use hardcoded ID when skipping dependencies
-					hcl.WriteString(fmt.Sprintf("            connection_id   = %s\n", quoteString(connectionID)))
+					dataObject.Set("connection_id", model.String(connectionID))
 				} else {
 					// Generate Terraform reference using resolver if available
 					var ref string
 					if graph != nil {
-						var err error
-						ref, err = resolver.GenerateTerraformReference(graph, "pingone_davinci_connector_instance", connectionID, "id")
-						if err != nil {
-							// If reference generation fails, use TODO placeholder
-							ref = resolver.GenerateTODOPlaceholder("pingone_davinci_connector_instance", connectionID, err)
-						}
+						// Falls back to a TODO placeholder if the connector instance is not exported
+						location := fmt.Sprintf("graphData.elements.nodes[%s].data.connectionId", nodeKey)
+						ref = resolver.ResolveReference(graph, from, "pingone_davinci_connector_instance", connectionID, "id", "connectionId", location)
 					} else {
 						// Fallback to legacy logic if no graph provided
 						connectorID := getString(data, "connectorId")
 						ref = generateConnectionReference(connectorID, connectionID)
 					}
-					hcl.WriteString(fmt.Sprintf("            connection_id   = %s\n", ref))
+					value, err := model.ParseExpression(ref)
+					if err != nil {
+						return nil, fmt.Errorf("failed to write connection_id of node %s: %w", nodeKey, err)
+					}
+					dataObject.Set("connection_id", value)
 				}
 			}
 
 			if connectorID := getString(data, "connectorId"); connectorID != "" {
-				hcl.WriteString(fmt.Sprintf("            connector_id    = %s\n", quoteString(connectorID)))
+				dataObject.Set("connector_id", model.String(connectorID))
 			}
 			if name := getString(data, "name"); name != "" {
-				hcl.WriteString(fmt.Sprintf("            name            = %s\n", quoteString(name)))
+				dataObject.Set("name", model.String(name))
 			}
 			if label := getString(data, "label"); label != "" {
-				hcl.WriteString(fmt.Sprintf("            label           = %s\n", quoteString(label)))
+				dataObject.Set("label", model.String(label))
 			}
 			if status := getString(data, "status"); status != "" {
-				hcl.WriteString(fmt.Sprintf("            status          = %s\n", quoteString(status)))
+				dataObject.Set("status", model.String(status))
 			}
 			if capabilityName := getString(data, "capabilityName"); capabilityName != "" {
-				hcl.WriteString(fmt.Sprintf("            capability_name = %s\n", quoteString(capabilityName)))
+				dataObject.Set("capability_name", model.String(capabilityName))
 			}
 			if nodeTypeField := getString(data, "type"); nodeTypeField != "" {
-				hcl.WriteString(fmt.Sprintf("            type            = %s\n", quoteString(nodeTypeField)))
+				dataObject.Set("type", model.String(nodeTypeField))
 			}
 
 			// Properties - uses jsonencode() for readable HCL output
 			if properties, ok := data["properties"].(map[string]interface{}); ok {
-				hcl.WriteString("            properties = jsonencode(")
-				writeJSONAsHCLMap(hcl, properties, 12) // 12 spaces indent (3 levels of 4)
-				hcl.WriteString(")\n")
+				if !skipDependencies && graph != nil {
+					properties = resolveSubflowReferences(properties, nodeKey, from, graph)
+					location := fmt.Sprintf("graphData.elements.nodes[%s].data.properties", nodeKey)
+					properties = resolveVariableReferences(properties, location, from, graph)
+				}
+				value, err := jsonValue(properties)
+				if err != nil {
+					return nil, fmt.Errorf("failed to write properties of node %s: %w", nodeKey, err)
+				}
+				dataObject.Set("properties", model.JSONEncode(value))
 			}
 
-			hcl.WriteString("          }\n")
+			nodeObject.Set("data", dataObject)
 		}
 
 		// Position block - optional
 		if position, ok := node["position"].(map[string]interface{}); ok {
-			hcl.WriteString("          position = {\n")
-			if x, ok := position["x"].(float64); ok {
-				hcl.WriteString(fmt.Sprintf("            x = %g\n", x))
-			}
-			if y, ok := position["y"].(float64); ok {
-				hcl.WriteString(fmt.Sprintf("            y = %g\n", y))
-			}
-			hcl.WriteString("          }\n")
+			nodeObject.Set("position", positionValue(position))
 		}
 
 		// Other node attributes
-		if group := getString(node, "group"); group != "" {
-			hcl.WriteString(fmt.Sprintf("          group      = %s\n", quoteString(group)))
-		}
-		if removed, ok := node["removed"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          removed    = %t\n", removed))
+		setElementAttributes(nodeObject, node)
+
+		object.SetKey(nodeKey, nodeObject)
+	}
+
+	return object, nil
+}
+
+// hclExpression is a Terraform expression, such as a reference, that jsonValue writes as an
+// expression rather than a string. comment is an optional line comment written after it.
+type hclExpression struct {
+	expr    string
+	comment string
+}
+
+// newHCLExpression splits a resolver reference or TODO placeholder into expression and comment
+func newHCLExpression(s string) hclExpression {
+	expr, comment, _ := strings.Cut(s, " # ")
+	return hclExpression{expr: expr, comment: comment}
+}
+
+// resolveSubflowReferences returns a copy of node properties whose subFlowId points at the
+// subflow's Terraform reference instead of its ID, or a TODO placeholder when the subflow is
+// not exported. A pinned subFlowVersionId is kept with a TODO, as the version numbers of the
+// subflow differ between environments; -1 (latest) is kept as is.
+//
+// subFlowId is either {"value": {"label": <name>, "value": <flowId>}} or the bare flow ID.
+func resolveSubflowReferences(properties map[string]interface{}, nodeKey string, from resolver.ResourceRef, graph *resolver.DependencyGraph) map[string]interface{} {
+	location := fmt.Sprintf("graphData.elements.nodes[%s].data.properties.subFlowId", nodeKey)
+	resolved := maps.Clone(properties)
+
+	var subflowID string
+	switch subFlow := properties["subFlowId"].(type) {
+	case string:
+		subflowID = subFlow
+		if subflowID == "" {
+			return properties
 		}
-		if selected, ok := node["selected"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          selected   = %t\n", selected))
+		resolved["subFlowId"] = newHCLExpression(resolver.ResolveReference(graph, from, "pingone_davinci_flow", subflowID, "id", "subFlowId", location))
+	case map[string]interface{}:
+		selected, ok := subFlow["value"].(map[string]interface{})
+		if !ok {
+			return properties
 		}
-		if selectable, ok := node["selectable"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          selectable = %t\n", selectable))
+		if subflowID = getString(selected, "value"); subflowID == "" {
+			return properties
 		}
-		if locked, ok := node["locked"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          locked     = %t\n", locked))
+		location += ".value.value"
+		resolvedSelected := maps.Clone(selected)
+		resolvedSelected["value"] = newHCLExpression(resolver.ResolveReference(graph, from, "pingone_davinci_flow", subflowID, "id", "subFlowId", location))
+		resolvedSubFlow := maps.Clone(subFlow)
+		resolvedSubFlow["value"] = resolvedSelected
+		resolved["subFlowId"] = resolvedSubFlow
+	default:
+		return properties
+	}
+
+	to, err := graph.GetResource("pingone_davinci_flow", subflowID)
+	if err != nil {
+		return resolved
+	}
+	addDependencyOnce(graph, from, to, "subflow_id", location)
+
+	if version, ok := properties["subFlowVersionId"].(map[string]interface{}); ok && isPinnedFlowVersion(version["value"]) {
+		pinned, _ := json.Marshal(version["value"]) // A number or a string of digits
+		resolvedVersion := maps.Clone(version)
+		resolvedVersion["value"] = hclExpression{
+			expr:    string(pinned),
+			comment: fmt.Sprintf("TODO: Pinned subflow version; use pingone_davinci_flow.%s.current_version if the version differs in the target environment", to.Name),
 		}
-		if grabbable, ok := node["grabbable"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          grabbable  = %t\n", grabbable))
+		resolved["subFlowVersionId"] = resolvedVersion
+	}
+	return resolved
+}
+
+// isPinnedFlowVersion reports whether a subFlowVersionId value pins a version rather than
+// selecting the latest (-1)
+func isPinnedFlowVersion(value interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		return v >= 0
+	case string:
+		n, err := strconv.Atoi(v)
+		return err == nil && n >= 0
+	}
+	return false
+}
+
+// addDependencyOnce records a dependency edge unless the graph already has one for the same
+// field, as the flow closure records subflow edges before flows are converted
+func addDependencyOnce(graph *resolver.DependencyGraph, from, to resolver.ResourceRef, field, location string) {
+	for _, dep := range graph.GetDependencies(from.ID) {
+		if dep.From.Type == from.Type && dep.To.Type == to.Type && dep.To.ID == to.ID && dep.Field == field {
+			return
 		}
-		if pannable, ok := node["pannable"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          pannable   = %t\n", pannable))
+	}
+	graph.AddDependency(from, to, field, location)
+}
+
+// variableTemplate matches variable references in node property expressions:
+// {{global.variables.<name>...}} for variables of the flow's other contexts (flowInstance,
+// user, flow) and {{global.company.variables.<name>...}} for company variables
+var variableTemplate = regexp.MustCompile(`(\{\{global\.(company\.)?variables\.)([A-Za-z0-9_-]+)`)
+
+// resolveVariableReferences returns a copy of node properties with variable references pointing
+// at the variable resources: the variableId property, which selects a variable, becomes a
+// reference (or a TODO placeholder when the variable is not exported), and variable names in
+// expressions anywhere in the properties become interpolations of the variable's name. Names of
+// variables that are not exported are kept.
+func resolveVariableReferences(properties map[string]interface{}, location string, from resolver.ResourceRef, graph *resolver.DependencyGraph) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(properties))
+	for key, item := range properties {
+		itemLocation := location + "." + key
+		if variableID, ok := item.(string); ok && key == "variableId" && variableID != "" {
+			resolved[key] = newHCLExpression(resolver.ResolveReference(graph, from, "pingone_davinci_variable", variableID, "id", "variableId", itemLocation))
+			if to, err := graph.GetResource("pingone_davinci_variable", variableID); err == nil {
+				addDependencyOnce(graph, from, to, "variable_id", itemLocation)
+			}
+			continue
 		}
-		// Always include classes field (even if empty string)
-		classes := getString(node, "classes")
-		hcl.WriteString(fmt.Sprintf("          classes    = %s\n", quoteString(classes)))
+		resolved[key] = resolveVariableNames(item, itemLocation, from, graph)
+	}
+	return resolved
+}
 
-		hcl.WriteString("        }\n")
+// resolveVariableNames returns a copy of a node property value with the variable names in its
+// expressions interpolated
+func resolveVariableNames(value interface{}, location string, from resolver.ResourceRef, graph *resolver.DependencyGraph) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved[key] = resolveVariableNames(item, location+"."+key, from, graph)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved[i] = resolveVariableNames(item, fmt.Sprintf("%s[%d]", location, i), from, graph)
+		}
+		return resolved
+	case string:
+		return interpolateVariableNames(v, location, from, graph)
 	}
+	return value
+}
 
-	hcl.WriteString("      }\n")
-	return nil
+// interpolateVariableNames rewrites the variable names in a string's variable expressions to
+// interpolations of the variable resources' names, returning the string unchanged when none
+// of the variables is exported
+func interpolateVariableNames(s, location string, from resolver.ResourceRef, graph *resolver.DependencyGraph) interface{} {
+	var template hclTemplate
+	last := 0
+	for _, m := range variableTemplate.FindAllStringSubmatchIndex(s, -1) {
+		name := s[m[6]:m[7]]
+		variableContext, ok := referencedVariableContext(graph, name, m[4] >= 0)
+		if !ok {
+			continue
+		}
+		to, _ := graph.LookupVariable(name, variableContext)
+		addDependencyOnce(graph, from, to, "variable_name", location)
+
+		template = append(template, s[last:m[6]], hclExpression{expr: fmt.Sprintf("pingone_davinci_variable.%s.name", to.Name)})
+		last = m[7]
+	}
+	if last == 0 {
+		return s
+	}
+	return append(template, s[last:])
 }
 
-// writeEdgesBlock writes the edges map within elements
-func writeEdgesBlock(hcl *strings.Builder, edges []interface{}) error {
-	if len(edges) == 0 {
-		hcl.WriteString("      edges = {}\n")
-		return nil
+// referencedVariableContext returns the context of the exported variable a variable expression
+// refers to: company for {{global.company.variables.<name>}}, otherwise the context of the only
+// exported variable with the name outside the company context. ok is false when there is no
+// such variable, or several, which the name alone cannot tell apart.
+func referencedVariableContext(graph *resolver.DependencyGraph, name string, company bool) (string, bool) {
+	contexts := graph.VariableContexts(name)
+	if company {
+		return "company", slices.Contains(contexts, "company")
+	}
+	contexts = slices.DeleteFunc(contexts, func(context string) bool { return context == "company" })
+	if len(contexts) != 1 {
+		return "", false
 	}
+	return contexts[0], true
+}
 
-	hcl.WriteString("      edges = {\n")
+// edgesValue returns the edges map within elements
+func edgesValue(edges []interface{}) model.Value {
+	object := &model.Object{}
 
 	for i, edgeInterface := range edges {
 		edge, ok := edgeInterface.(map[string]interface{})
@@ -803,84 +947,51 @@ func writeEdgesBlock(hcl *strings.Builder, edges []interface{}) error {
 			edgeKey = fmt.Sprintf("edge_%d", i)
 		}
 
-		hcl.WriteString(fmt.Sprintf("        %s = {\n", quoteString(edgeKey)))
-
+		edgeObject := &model.Object{}
 		if data, ok := edge["data"].(map[string]interface{}); ok {
-			hcl.WriteString("          data = {\n")
+			dataObject := &model.Object{}
 
 			// Required: id, source, target
 			if id := getString(data, "id"); id != "" {
-				hcl.WriteString(fmt.Sprintf("            id     = %s\n", quoteString(id)))
+				dataObject.Set("id", model.String(id))
 			}
 			if source := getString(data, "source"); source != "" {
-				hcl.WriteString(fmt.Sprintf("            source = %s\n", quoteString(source)))
+				dataObject.Set("source", model.String(source))
 			}
 			if target := getString(data, "target"); target != "" {
-				hcl.WriteString(fmt.Sprintf("            target = %s\n", quoteString(target)))
+				dataObject.Set("target", model.String(target))
 			}
 
-			hcl.WriteString("          }\n")
+			edgeObject.Set("data", dataObject)
 		}
 
 		// Optional: position object (rarely used for edges but supported)
 		if position, ok := edge["position"].(map[string]interface{}); ok {
-			hcl.WriteString("          position = {\n")
-			if x, ok := position["x"].(float64); ok {
-				hcl.WriteString(fmt.Sprintf("            x = %g\n", x))
-			}
-			if y, ok := position["y"].(float64); ok {
-				hcl.WriteString(fmt.Sprintf("            y = %g\n", y))
-			}
-			hcl.WriteString("          }\n")
+			edgeObject.Set("position", positionValue(position))
 		}
 
 		// Optional edge attributes
-		if group := getString(edge, "group"); group != "" {
-			hcl.WriteString(fmt.Sprintf("          group      = %s\n", quoteString(group)))
-		}
-		if removed, ok := edge["removed"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          removed    = %t\n", removed))
-		}
-		if selected, ok := edge["selected"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          selected   = %t\n", selected))
-		}
-		if selectable, ok := edge["selectable"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          selectable = %t\n", selectable))
-		}
-		if locked, ok := edge["locked"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          locked     = %t\n", locked))
-		}
-		if grabbable, ok := edge["grabbable"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          grabbable  = %t\n", grabbable))
-		}
-		if pannable, ok := edge["pannable"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("          pannable   = %t\n", pannable))
-		}
-		// Always include classes field (even if empty string)
-		classes := getString(edge, "classes")
-		hcl.WriteString(fmt.Sprintf("          classes    = %s\n", quoteString(classes)))
+		setElementAttributes(edgeObject, edge)
 
-		hcl.WriteString("        }\n")
+		object.SetKey(edgeKey, edgeObject)
 	}
 
-	hcl.WriteString("      }\n")
-	return nil
+	return object
 }
 
-// writeInputSchemaBlock writes the input_schema list
-func writeInputSchemaBlock(hcl *strings.Builder, inputSchema []interface{}) error {
-	hcl.WriteString("  input_schema = [\n")
+// inputSchemaValue returns the input_schema list
+func inputSchemaValue(inputSchema []interface{}) model.Value {
+	items := make([]model.Value, 0, len(inputSchema))
 
-	for i, schemaInterface := range inputSchema {
+	for _, schemaInterface := range inputSchema {
 		schema, ok := schemaInterface.(map[string]interface{})
 		if !ok {
 			continue
 		}
 
-		hcl.WriteString("    {\n")
-
+		item := &model.Object{}
 		if propertyName := getString(schema, "propertyName"); propertyName != "" {
-			hcl.WriteString(fmt.Sprintf("      property_name           = %s\n", quoteString(propertyName)))
+			item.Set("property_name", model.String(propertyName))
 		}
 		// Normalize and ensure preferred_data_type is always set
 		preferredDataType := getString(schema, "preferredDataType")
@@ -906,96 +1017,78 @@ func writeInputSchemaBlock(hcl *strings.Builder, inputSchema []interface{}) erro
 		if preferredDataType == "" || !allowed[strings.ToLower(preferredDataType)] {
 			preferredDataType = "string"
 		}
-		hcl.WriteString(fmt.Sprintf("      preferred_data_type     = %s\n", quoteString(preferredDataType)))
+		item.Set("preferred_data_type", model.String(preferredDataType))
 		if preferredControlType := getString(schema, "preferredControlType"); preferredControlType != "" {
-			hcl.WriteString(fmt.Sprintf("      preferred_control_type  = %s\n", quoteString(preferredControlType)))
+			item.Set("preferred_control_type", model.String(preferredControlType))
 		}
 		if required, ok := schema["required"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("      required                = %t\n", required))
+			item.Set("required", model.Bool(required))
 		}
 		if isExpanded, ok := schema["isExpanded"].(bool); ok {
-			hcl.WriteString(fmt.Sprintf("      is_expanded             = %t\n", isExpanded))
+			item.Set("is_expanded", model.Bool(isExpanded))
 		}
 		// Always include description field (even if empty string)
-		description := getString(schema, "description")
-		hcl.WriteString(fmt.Sprintf("      description             = %s\n", quoteString(description)))
+		item.Set("description", model.String(getString(schema, "description")))
 
-		hcl.WriteString("    }")
-		// Add comma for all but last element
-		if i < len(inputSchema)-1 {
-			hcl.WriteString(",")
-		}
-		hcl.WriteString("\n")
+		items = append(items, item)
 	}
 
-	hcl.WriteString("  ]\n")
-	return nil
+	return &model.Tuple{Elems: items}
 }
 
-// writeOutputSchemaBlock writes the output_schema object
-func writeOutputSchemaBlock(hcl *strings.Builder, outputSchema map[string]interface{}) error {
-	hcl.WriteString("  output_schema = {\n")
+// outputSchemaValue returns the output_schema object
+func outputSchemaValue(outputSchema map[string]interface{}) (model.Value, error) {
+	object := &model.Object{}
 
 	// The output field typically contains a JSON object that should be encoded
 	if output, ok := outputSchema["output"]; ok {
-		// Convert output to JSON string
-		outputBytes, err := json.Marshal(output)
+		value, err := jsonValue(output)
 		if err != nil {
-			return fmt.Errorf("failed to marshal output schema: %w", err)
+			return nil, fmt.Errorf("failed to write output schema: %w", err)
 		}
-		hcl.WriteString(fmt.Sprintf("    output = jsonencode(%s)\n", string(outputBytes)))
+		object.Set("output", model.JSONEncode(value))
 	}
 
-	hcl.WriteString("  }\n")
-	return nil
+	return object, nil
 }
 
-// writeTriggerBlock writes the trigger nested block
-func writeTriggerBlock(hcl *strings.Builder, trigger map[string]interface{}) error {
-	hcl.WriteString("  trigger = {\n")
+// triggerValue returns the trigger object
+func triggerValue(trigger map[string]interface{}) model.Value {
+	object := &model.Object{}
 
 	if triggerType := getString(trigger, "type"); triggerType != "" {
-		hcl.WriteString(fmt.Sprintf("    type = %s\n", quoteString(triggerType)))
+		object.Set("type", model.String(triggerType))
 	}
 
 	if config, ok := trigger["configuration"].(map[string]interface{}); ok {
-		hcl.WriteString("    configuration = {\n")
+		configObject := &model.Object{}
 
-		// MFA configuration
-		if mfa, ok := config["mfa"].(map[string]interface{}); ok {
-			hcl.WriteString("      mfa = {\n")
-			if enabled, ok := mfa["enabled"].(bool); ok {
-				hcl.WriteString(fmt.Sprintf("        enabled     = %t\n", enabled))
+		// MFA and password configuration
+		for _, name := range []string{"mfa", "pwd"} {
+			if auth, ok := config[name].(map[string]interface{}); ok {
+				configObject.Set(name, triggerAuthValue(auth))
 			}
-			if time, ok := mfa["time"].(float64); ok {
-				hcl.WriteString(fmt.Sprintf("        time        = %d\n", int(time)))
-			}
-			if timeFormat := getString(mfa, "timeFormat"); timeFormat != "" {
-				hcl.WriteString(fmt.Sprintf("        time_format = %s\n", quoteString(timeFormat)))
-			}
-			hcl.WriteString("      }\n")
 		}
 
-		// Password configuration
-		if pwd, ok := config["pwd"].(map[string]interface{}); ok {
-			hcl.WriteString("      pwd = {\n")
-			if enabled, ok := pwd["enabled"].(bool); ok {
-				hcl.WriteString(fmt.Sprintf("        enabled     = %t\n", enabled))
-			}
-			if time, ok := pwd["time"].(float64); ok {
-				hcl.WriteString(fmt.Sprintf("        time        = %d\n", int(time)))
-			}
-			if timeFormat := getString(pwd, "timeFormat"); timeFormat != "" {
-				hcl.WriteString(fmt.Sprintf("        time_format = %s\n", quoteString(timeFormat)))
-			}
-			hcl.WriteString("      }\n")
-		}
-
-		hcl.WriteString("    }\n")
+		object.Set("configuration", configObject)
 	}
 
-	hcl.WriteString("  }\n")
-	return nil
+	return object
+}
+
+// triggerAuthValue returns the mfa or pwd object of a trigger configuration
+func triggerAuthValue(auth map[string]interface{}) model.Value {
+	object := &model.Object{}
+	if enabled, ok := auth["enabled"].(bool); ok {
+		object.Set("enabled", model.Bool(enabled))
+	}
+	if time, ok := auth["time"].(float64); ok {
+		object.Set("time", model.Int(int64(time)))
+	}
+	if timeFormat := getString(auth, "timeFormat"); timeFormat != "" {
+		object.Set("time_format", model.String(timeFormat))
+	}
+	return object
 }
 
 // Helper functions
@@ -1007,16 +1100,6 @@ func getString(m map[string]interface{}, key string) string {
 	return ""
 }
 
-func quoteString(s string) string {
-	// Escape special characters in HCL strings
-	s = strings.ReplaceAll(s, "\\", "\\\\")
-	s = strings.ReplaceAll(s, "\"", "\\\"")
-	s = strings.ReplaceAll(s, "\n", "\\n")
-	s = strings.ReplaceAll(s, "\r", "\\r")
-	s = strings.ReplaceAll(s, "\t", "\\t")
-	return fmt.Sprintf("%q", s)
-}
-
 func generateConnectionReference(connectorID, connectionID string) string {
 	// Generate Terraform reference for connection_id
 	// Format: pingone_davinci_connector_instance.<connector_id>_<connection_id>.id
@@ -1046,84 +1129,3 @@ func decodeJSONEscapes(s string) string {
 	}
 	return unquoted
 }
-
-// writeJSONAsHCLMap recursively writes a JSON object as an HCL map literal for use with jsonencode()
-// This generates readable HCL syntax instead of base64-encoded strings
-// indent specifies the indentation level (number of spaces)
-func writeJSONAsHCLMap(hcl *strings.Builder, value interface{}, indent int) {
-	indentStr := strings.Repeat(" ", indent)
-
-	switch v := value.(type) {
-	case map[string]interface{}:
-		// Write object as HCL map
-		hcl.WriteString("{\n")
-
-		// Sort keys for deterministic output
-		keys := make([]string, 0, len(v))
-		for k := range v {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-
-		for i, key := range keys {
-			hcl.WriteString(indentStr + "  ")
-			// Write key with quotes
-			hcl.WriteString(fmt.Sprintf("\"%s\" = ", key))
-			// Recursively write value
-			writeJSONAsHCLMap(hcl, v[key], indent+2)
-			// Add comma if not last element
-			if i < len(keys)-1 {
-				hcl.WriteString(",")
-			}
-			hcl.WriteString("\n")
-		}
-		hcl.WriteString(indentStr + "}")
-
-	case []interface{}:
-		// Write array as HCL list
-		hcl.WriteString("[\n")
-		for i, item := range v {
-			hcl.WriteString(indentStr + "  ")
-			writeJSONAsHCLMap(hcl, item, indent+2)
-			// Add comma if not last element
-			if i < len(v)-1 {
-				hcl.WriteString(",")
-			}
-			hcl.WriteString("\n")
-		}
-		hcl.WriteString(indentStr + "]")
-
-	case string:
-		// Use Go's strconv.Quote for proper JSON-compatible string escaping
-		// This handles all special characters including quotes, newlines, Unicode, etc.
-		// Only escape Terraform interpolation sequences: replace "${" with "$${"
-		// Do NOT alter standalone "$" or existing "$$" to preserve content fidelity.
-		quoted := strconv.Quote(v)
-		escaped := strings.ReplaceAll(quoted, "${", "$${")
-		hcl.WriteString(escaped)
-
-	case float64:
-		// Check if it's an integer value
-		if v == float64(int64(v)) {
-			hcl.WriteString(fmt.Sprintf("%d", int64(v)))
-		} else {
-			hcl.WriteString(fmt.Sprintf("%g", v))
-		}
-
-	case bool:
-		hcl.WriteString(fmt.Sprintf("%t", v))
-
-	case nil:
-		hcl.WriteString("null")
-
-	default:
-		// Fallback - convert to string
-		hcl.WriteString(fmt.Sprintf("\"%v\"", v))
-	}
-}
-
-// escapeHCLString escapes special characters in strings for HCL syntax.
-// Currently unused; keep a stub to avoid unused lint errors when referenced later.
-// func escapeHCLString(s string) string {
-//     return s
-// }
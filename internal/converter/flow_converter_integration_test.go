@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	hclv2 "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
 	"github.com/stretchr/testify/require"
 )
@@ -214,3 +216,204 @@ func TestFlowConverterNameSanitization(t *testing.T) {
 	// Should contain hex-encoded space (-0020-) and exclamation (-0021-)
 	require.Contains(t, hcl, "pingcli__My-0020-HTTP-0020-Connector-0021-")
 }
+
+// subflowFlowJSON is a parent flow with three subflow nodes, one pinned to version 3 and one
+// holding the bare subflow ID
+const subflowFlowJSON = `{
+	"flowId": "parent-flow",
+	"name": "Parent Flow",
+	"graphData": {
+		"elements": {
+			"nodes": [
+				{
+					"data": {
+						"id": "node1",
+						"nodeType": "CONNECTION",
+						"connectorId": "flowConnector",
+						"name": "Flow Conductor",
+						"properties": {
+							"subFlowId": {"value": {"label": "MFA Subflow", "value": "subflow-123"}},
+							"subFlowVersionId": {"value": -1}
+						}
+					}
+				},
+				{
+					"data": {
+						"id": "node2",
+						"nodeType": "CONNECTION",
+						"connectorId": "flowConnector",
+						"name": "Flow Conductor",
+						"properties": {
+							"subFlowId": {"value": {"label": "Missing Subflow", "value": "subflow-999"}},
+							"subFlowVersionId": {"value": 3},
+							"nodeTitle": {"value": "After"}
+						}
+					}
+				},
+				{
+					"data": {
+						"id": "node3",
+						"nodeType": "CONNECTION",
+						"connectorId": "flowConnector",
+						"name": "Flow Conductor",
+						"properties": {
+							"subFlowId": "subflow-888",
+							"subFlowVersionId": {"value": -1}
+						}
+					}
+				}
+			],
+			"edges": []
+		}
+	}
+}`
+
+// TestFlowConverterSubflowReferences tests subflow IDs in node properties resolve to flow references
+func TestFlowConverterSubflowReferences(t *testing.T) {
+	var flowData map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(subflowFlowJSON), &flowData))
+
+	graph := resolver.NewDependencyGraph()
+	graph.AddResource("pingone_davinci_flow", "parent-flow", "parent_flow")
+	graph.AddResource("pingone_davinci_flow", "subflow-123", "mfa_subflow")
+	graph.AddResource("pingone_davinci_flow", "subflow-999", "missing_subflow")
+
+	hcl, err := ConvertFlowToHCL(flowData, "var.pingone_environment_id", false, graph)
+	require.NoError(t, err)
+	require.Contains(t, hcl, `"value" = pingone_davinci_flow.mfa_subflow.id`)
+	require.Contains(t, hcl, `"value" = pingone_davinci_flow.missing_subflow.id`)
+	require.NotContains(t, hcl, "subflow-123")
+
+	// Latest stays -1; a pinned version is kept with a TODO to follow the referenced flow
+	require.Contains(t, hcl, `"value" = -1`)
+	require.Contains(t, hcl, `"value" = 3 # TODO: Pinned subflow version; use pingone_davinci_flow.missing_subflow.current_version if the version differs in the target environment`)
+	require.NotContains(t, hcl, `= pingone_davinci_flow.missing_subflow.current_version`)
+
+	// Edges are recorded once, however often the flow is converted
+	_, err = ConvertFlowToHCL(flowData, "var.pingone_environment_id", false, graph)
+	require.NoError(t, err)
+	deps := graph.GetDependencies("parent-flow")
+	require.Len(t, deps, 2)
+	require.Equal(t, "subflow_id", deps[0].Field)
+	require.Equal(t, "mfa_subflow", deps[0].To.Name)
+	require.Equal(t, "graphData.elements.nodes[node1].data.properties.subFlowId.value.value", deps[0].Location)
+}
+
+// TestFlowConverterMissingSubflow tests subflows missing from the graph get TODO placeholders
+func TestFlowConverterMissingSubflow(t *testing.T) {
+	var flowData map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(subflowFlowJSON), &flowData))
+
+	graph := resolver.NewDependencyGraph()
+	graph.AddResource("pingone_davinci_flow", "parent-flow", "parent_flow")
+
+	hcl, err := ConvertFlowToHCL(flowData, "var.pingone_environment_id", false, graph)
+	require.NoError(t, err)
+	require.Contains(t, hcl, `"value" = "" # TODO: Reference to pingone_davinci_flow subflow-999`)
+	require.Contains(t, hcl, `"subFlowId" = "" # TODO: Reference to pingone_davinci_flow subflow-888`)
+	require.Contains(t, hcl, `"value" = 3`, "pinned version of a missing subflow is kept")
+	require.Empty(t, graph.GetDependencies("parent-flow"))
+
+	// The placeholder comment must not swallow the separator of the next entry
+	_, diags := hclsyntax.ParseConfig([]byte(hcl), "flow.tf", hclv2.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	// Skipping dependencies keeps the IDs
+	hcl, err = ConvertFlowToHCL(flowData, "var.pingone_environment_id", true, graph)
+	require.NoError(t, err)
+	require.Contains(t, hcl, `"value" = "subflow-123"`)
+}
+
+// TestFlowConverterVariableReferences tests variable references in node properties resolve to variable resources
+func TestFlowConverterVariableReferences(t *testing.T) {
+	flowJSON := `{
+		"flowId": "flow-1",
+		"name": "Variables Flow",
+		"graphData": {
+			"elements": {
+				"nodes": [
+					{
+						"data": {
+							"id": "node1",
+							"nodeType": "CONNECTION",
+							"connectorId": "variablesConnector",
+							"name": "Variables",
+							"properties": {
+								"variableId": "var-123",
+								"message": {"value": "Hi {{global.variables.firstName}} {{global.variables.lastName}} from {{global.company.variables.companyName}} at ${host}"},
+								"fields": [{"value": "{{global.variables.mf-configObject.config.mf-str-companyLogo}}"}],
+								"other": {"value": "{{global.variables.unknown}} {{global.variables.shared}}"},
+								"nested": {"variableId": "var-456"}
+							}
+						}
+					},
+					{
+						"data": {
+							"id": "node2",
+							"nodeType": "CONNECTION",
+							"connectorId": "variablesConnector",
+							"name": "Variables",
+							"properties": {
+								"variableId": "var-999"
+							}
+						}
+					}
+				],
+				"edges": []
+			}
+		}
+	}`
+
+	var flowData map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(flowJSON), &flowData))
+
+	graph := resolver.NewDependencyGraph()
+	graph.AddResource("pingone_davinci_flow", "flow-1", "variables_flow")
+	for _, v := range []struct{ id, name, context, resourceName string }{
+		{"var-123", "origin", "company", "origin_company"},
+		{"var-456", "firstName", "flowInstance", "first_name"},
+		{"var-789", "companyName", "company", "company_name"},
+		{"var-abc", "mf-configObject", "flowInstance", "config_object"},
+		{"var-def", "companyName", "flowInstance", "company_name_instance"},
+		{"var-ghi", "lastName", "user", "last_name"},
+		{"var-jkl", "shared", "flowInstance", "shared_instance"},
+		{"var-mno", "shared", "user", "shared_user"},
+	} {
+		graph.AddResource("pingone_davinci_variable", v.id, v.resourceName)
+		graph.AddVariableName(v.id, v.name, v.context)
+	}
+
+	hcl, err := ConvertFlowToHCL(flowData, "var.pingone_environment_id", false, graph)
+	require.NoError(t, err)
+	require.Contains(t, hcl, `"variableId" = pingone_davinci_variable.origin_company.id`)
+	// Variables outside the company context resolve to the context of the variable with the name
+	require.Contains(t, hcl, `"value" = "Hi {{global.variables.${pingone_davinci_variable.first_name.name}}} {{global.variables.${pingone_davinci_variable.last_name.name}}} from {{global.company.variables.${pingone_davinci_variable.company_name.name}}} at $${host}"`)
+	require.Contains(t, hcl, `"value" = "{{global.variables.${pingone_davinci_variable.config_object.name}.config.mf-str-companyLogo}}"`)
+	// Unknown names, and names shared by several contexts, are kept
+	require.Contains(t, hcl, `"value" = "{{global.variables.unknown}} {{global.variables.shared}}"`)
+	// Only the variableId property selects a variable
+	require.Contains(t, hcl, `"variableId" = "var-456"`)
+	require.Contains(t, hcl, `"variableId" = "" # TODO: Reference to pingone_davinci_variable var-999`)
+
+	_, diags := hclsyntax.ParseConfig([]byte(hcl), "flow.tf", hclv2.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	// One edge per referenced variable
+	targets := map[string]string{}
+	for _, dep := range graph.GetDependencies("flow-1") {
+		targets[dep.To.ID] = dep.Field
+	}
+	require.Equal(t, map[string]string{
+		"var-123": "variable_id",
+		"var-456": "variable_name",
+		"var-789": "variable_name",
+		"var-abc": "variable_name",
+		"var-ghi": "variable_name",
+	}, targets)
+
+	// Skipping dependencies keeps IDs and names
+	hcl, err = ConvertFlowToHCL(flowData, "var.pingone_environment_id", true, graph)
+	require.NoError(t, err)
+	require.Contains(t, hcl, `"variableId" = "var-123"`)
+	require.Contains(t, hcl, `{{global.variables.firstName}}`)
+}
@@ -246,7 +246,7 @@ func TestConvertFlowToHCL_EmitsIdUniqueInNodes(t *testing.T) {
 		t.Fatalf("ConvertFlowToHCL error: %v", err)
 	}
 
-	if !strings.Contains(hcl, "id_unique       = \"abc123uniq\"") {
+	if !strings.Contains(hcl, "id_unique  = \"abc123uniq\"") {
 		t.Fatalf("expected id_unique to be emitted, got:\n%s", hcl)
 	}
 }
@@ -65,3 +65,20 @@ func TestFlowWithJSLinksRequiredAttributes(t *testing.T) {
 		t.Logf("Generated HCL:\n%s", result)
 	}
 }
+
+// TestFlowWithJSLinksStringDefer verifies defer values exported as strings keep their value
+func TestFlowWithJSLinksStringDefer(t *testing.T) {
+	flow := map[string]interface{}{
+		"flowId": "flow-1",
+		"name":   "Flow",
+		"settings": map[string]interface{}{
+			"jsLinks": []interface{}{
+				map[string]interface{}{"defer": "true", "value": "https://example.com/a.js"},
+			},
+		},
+	}
+
+	result, err := ConvertFlowToHCL(flow, "var.pingone_environment_id", true, nil)
+	require.NoError(t, err)
+	assert.Contains(t, result, `defer          = true`, "defer \"true\" should be written as boolean true")
+}
@@ -33,9 +33,9 @@ func TestGraphElements_OrderDeterministicByID(t *testing.T) {
 	}
 
 	// Verify nodes appear sorted by id: nodeA, nodeB, nodeC
-	aIdx := strings.Index(hcl, "id              = \"nodeA\"")
-	bIdx := strings.Index(hcl, "id              = \"nodeB\"")
-	cIdx := strings.Index(hcl, "id              = \"nodeC\"")
+	aIdx := strings.Index(hcl, "id        = \"nodeA\"")
+	bIdx := strings.Index(hcl, "id        = \"nodeB\"")
+	cIdx := strings.Index(hcl, "id        = \"nodeC\"")
 	if aIdx == -1 || bIdx == -1 || cIdx == -1 {
 		t.Fatalf("Missing expected node IDs in HCL.\nHCL:\n%s", hcl)
 	}
@@ -1,156 +1,231 @@
 package converter
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/pingidentity/pingone-go-client/pingone"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ConvertFlowPolicyToTerraform converts a DaVinci flow policy to Terraform HCL format
 func ConvertFlowPolicyToTerraform(policy pingone.DaVinciFlowPolicyResponse, resourceName, applicationID, environmentID string, skipDeps bool, graph *resolver.DependencyGraph) (string, error) {
-	var hcl strings.Builder
+	resource, err := ConvertFlowPolicyToResource(policy, resourceName, applicationID, environmentID, skipDeps, graph)
+	if err != nil {
+		return "", err
+	}
+	return model.HCL(resource)
+}
+
+// flowPolicyPayload holds the flow policy fields read by the converter. Unlike
+// pingone.DaVinciFlowPolicyResponse it does not require read-only fields such as _links and
+// environment, so policies saved from the DaVinci UI or by hand convert too.
+type flowPolicyPayload struct {
+	ID                string                   `json:"id"`
+	Name              string                   `json:"name"`
+	Status            string                   `json:"status"`
+	Trigger           *flowPolicyTrigger       `json:"trigger,omitempty"`
+	FlowDistributions []flowPolicyDistribution `json:"flowDistributions"`
+}
+
+// flowPolicyTrigger is the trigger of a flow policy payload
+type flowPolicyTrigger struct {
+	Type          *string `json:"type,omitempty"`
+	Configuration *struct {
+		Mfa flowPolicyTimeout `json:"mfa"`
+		Pwd flowPolicyTimeout `json:"pwd"`
+	} `json:"configuration,omitempty"`
+}
+
+// flowPolicyTimeout is the MFA or password timeout of a flow policy trigger
+type flowPolicyTimeout struct {
+	Enabled    bool    `json:"enabled"`
+	Time       float32 `json:"time"`
+	TimeFormat string  `json:"timeFormat"`
+}
+
+// flowPolicyDistribution is one flow distribution of a flow policy payload
+type flowPolicyDistribution struct {
+	ID      string   `json:"id"`
+	Version float32  `json:"version"`
+	Weight  *float32 `json:"weight,omitempty"`
+}
+
+// parseFlowPolicy decodes a flow policy payload from an API response or saved JSON
+func parseFlowPolicy(policyJSON []byte) (flowPolicyPayload, error) {
+	var policy flowPolicyPayload
+	if err := json.Unmarshal(policyJSON, &policy); err != nil {
+		return flowPolicyPayload{}, fmt.Errorf("failed to parse flow policy JSON: %w", err)
+	}
+	return policy, nil
+}
+
+// ConvertFlowPolicyToResource converts a DaVinci flow policy to a resource
+func ConvertFlowPolicyToResource(policy pingone.DaVinciFlowPolicyResponse, resourceName, applicationID, environmentID string, skipDeps bool, graph *resolver.DependencyGraph) (*model.Resource, error) {
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal flow policy: %w", err)
+	}
+	return ConvertFlowPolicyJSONToResource(policyJSON, resourceName, applicationID, environmentID, skipDeps, graph)
+}
+
+// ConvertFlowPolicyJSONToResource converts a DaVinci flow policy payload to a resource. Only the
+// fields written to Terraform are read, so the payload does not need read-only API fields.
+func ConvertFlowPolicyJSONToResource(policyJSON []byte, resourceName, applicationID, environmentID string, skipDeps bool, graph *resolver.DependencyGraph) (*model.Resource, error) {
+	policy, err := parseFlowPolicy(policyJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	from := resolver.ResourceRef{Type: "pingone_davinci_application_flow_policy", ID: policy.ID, Name: resourceName}
 
 	// Create resource block
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_application_flow_policy\" \"%s\" {\n", resourceName))
+	resource := model.NewResource("pingone_davinci_application_flow_policy", resourceName)
+	resource.ID = policy.ID
+	body := &resource.Body
 
 	// Environment ID
-	if strings.HasPrefix(environmentID, "var.") {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %s\n", environmentID))
-	} else {
-		hcl.WriteString(fmt.Sprintf("  environment_id = %q\n", environmentID))
+	environment, err := environmentValue(environmentID)
+	if err != nil {
+		return nil, err
 	}
+	body.SetAttribute("environment_id", environment)
 
 	// Application ID - use graph for reference if available
+	var appRef model.Value
 	if skipDeps {
-		hcl.WriteString(fmt.Sprintf("  davinci_application_id = %q\n", applicationID))
+		appRef = model.String(applicationID)
 	} else {
 		if graph != nil {
-			appRef, err := resolver.GenerateTerraformReference(graph, "pingone_davinci_application", applicationID, "id")
-			if err != nil {
-				// Fallback to TODO placeholder if application not found in graph
-				hcl.WriteString(fmt.Sprintf("  davinci_application_id = \"\" # TODO: %s\n", err.Error()))
-			} else {
-				hcl.WriteString(fmt.Sprintf("  davinci_application_id = %s\n", appRef))
-			}
+			// Falls back to a TODO placeholder if the application is not exported
+			appRef, err = model.ParseExpression(resolver.ResolveReference(graph, from, "pingone_davinci_application", applicationID, "id", "applicationId", "application.id"))
 		} else {
 			// Fallback to legacy sanitized name
-			appResourceName := sanitizeResourceName(applicationID)
-			hcl.WriteString(fmt.Sprintf("  davinci_application_id = pingone_davinci_application.%s.id\n", appResourceName))
+			appRef, err = model.ResourceAttribute("pingone_davinci_application", sanitizeResourceName(applicationID), "id")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write davinci_application_id: %w", err)
 		}
 	}
+	body.SetAttribute("davinci_application_id", appRef)
 
-	// Name
-	if name, ok := policy.GetNameOk(); ok {
-		hcl.WriteString(fmt.Sprintf("  name           = %q\n", *name))
-	}
-
-	// Status
-	if status, ok := policy.GetStatusOk(); ok {
-		hcl.WriteString(fmt.Sprintf("  status         = %q\n", string(*status)))
-	}
+	// Name and status
+	body.SetAttribute("name", model.String(policy.Name))
+	body.SetAttribute("status", model.String(policy.Status))
 
 	// Trigger - emit only if present in API (omitEmpty behavior)
-	if trigger, ok := policy.GetTriggerOk(); ok && trigger != nil {
-		hcl.WriteString("\n")
-		hcl.WriteString("  trigger = {\n")
+	if trigger := policy.Trigger; trigger != nil {
+		triggerObject := &model.Object{}
 
 		// Type
-		if t, typeOk := trigger.GetTypeOk(); typeOk && t != nil {
-			hcl.WriteString(fmt.Sprintf("    type = %q\n", *t))
+		if trigger.Type != nil {
+			triggerObject.Set("type", model.String(*trigger.Type))
 		}
 
-		// Configuration - only if present
-		if config, configOk := trigger.GetConfigurationOk(); configOk && config != nil {
-			hcl.WriteString("\n")
-			hcl.WriteString("    configuration = {\n")
-
-			// Removed defaults; emit mfa only when present
-			// MFA configuration
-			if mfa, mfaOk := config.GetMfaOk(); mfaOk {
-				hcl.WriteString("      mfa = {\n")
-				if enabled, enabledOk := mfa.GetEnabledOk(); enabledOk {
-					hcl.WriteString(fmt.Sprintf("        enabled     = %t\n", *enabled))
-				}
-				if time, timeOk := mfa.GetTimeOk(); timeOk {
-					hcl.WriteString(fmt.Sprintf("        time        = %d\n", int64(*time)))
-				}
-				if timeFormat, formatOk := mfa.GetTimeFormatOk(); formatOk && timeFormat != nil {
-					hcl.WriteString(fmt.Sprintf("        time_format = %q\n", *timeFormat))
-				}
-				hcl.WriteString("      }\n")
-			}
-
-			// Removed defaults; emit pwd only when present
-			// Password configuration
-			if pwd, pwdOk := config.GetPwdOk(); pwdOk {
-				hcl.WriteString("\n")
-				hcl.WriteString("      pwd = {\n")
-				if enabled, enabledOk := pwd.GetEnabledOk(); enabledOk {
-					hcl.WriteString(fmt.Sprintf("        enabled     = %t\n", *enabled))
-				}
-				if time, timeOk := pwd.GetTimeOk(); timeOk {
-					hcl.WriteString(fmt.Sprintf("        time        = %d\n", int64(*time)))
-				}
-				if timeFormat, formatOk := pwd.GetTimeFormatOk(); formatOk && timeFormat != nil {
-					hcl.WriteString(fmt.Sprintf("        time_format = %q\n", *timeFormat))
-				}
-				hcl.WriteString("      }\n")
-			}
-
-			hcl.WriteString("    }\n")
+		// Configuration - only if present, with its MFA and password timeouts
+		if config := trigger.Configuration; config != nil {
+			configObject := &model.Object{}
+			configObject.Set("mfa", config.Mfa.value())
+			configObject.Set("pwd", config.Pwd.value())
+			triggerObject.Set("configuration", configObject)
 		}
 
-		hcl.WriteString("  }\n")
+		body.AppendNewline()
+		body.SetAttribute("trigger", triggerObject)
 	}
 
 	// Flow distributions
-	if distributions, ok := policy.GetFlowDistributionsOk(); ok && len(distributions) > 0 {
-		hcl.WriteString("\n")
-		hcl.WriteString("  flow_distributions = [\n")
+	if len(policy.FlowDistributions) > 0 {
+		items := make([]model.Value, 0, len(policy.FlowDistributions))
 
-		for _, dist := range distributions {
-			hcl.WriteString("    {\n")
+		for i, dist := range policy.FlowDistributions {
+			item := &model.Object{}
 
 			// Flow ID - use graph for reference if available
-			if flowID, ok := dist.GetIdOk(); ok {
-				if skipDeps {
-					hcl.WriteString(fmt.Sprintf("      id      = %q\n", *flowID))
-				} else {
-					if graph != nil {
-						flowRef, err := resolver.GenerateTerraformReference(graph, "pingone_davinci_flow", *flowID, "id")
-						if err != nil {
-							// Generate TODO placeholder for missing flow dependency
-							placeholder := resolver.GenerateTODOPlaceholder("pingone_davinci_flow", *flowID, err)
-							hcl.WriteString(fmt.Sprintf("      id      = %s\n", placeholder))
-						} else {
-							hcl.WriteString(fmt.Sprintf("      id      = %s\n", flowRef))
-						}
-					} else {
-						// Fallback: use raw UUID with comment
-						hcl.WriteString(fmt.Sprintf("      id      = %q # TODO: Replace with pingone_davinci_flow.<resource_name>.id\n", *flowID))
+			if skipDeps {
+				item.Set("id", model.String(dist.ID))
+			} else {
+				if graph != nil {
+					// Falls back to a TODO placeholder if the flow is not exported
+					location := fmt.Sprintf("flowDistributions[%d].id", i)
+					flowRef, err := model.ParseExpression(resolver.ResolveReference(graph, from, "pingone_davinci_flow", dist.ID, "id", "flowId", location))
+					if err != nil {
+						return nil, fmt.Errorf("failed to write flow distribution %d: %w", i, err)
 					}
+					item.Set("id", flowRef)
+				} else {
+					// Fallback: use raw UUID with comment
+					item.Set("id", model.WithComment(model.String(dist.ID), "TODO: Replace with pingone_davinci_flow.<resource_name>.id"))
 				}
 			}
 
 			// Version
-			if version, ok := dist.GetVersionOk(); ok {
-				hcl.WriteString(fmt.Sprintf("      version = %d\n", int64(*version)))
-			}
+			item.Set("version", model.Int(int64(dist.Version)))
 
 			// Weight (optional)
-			if weight, ok := dist.GetWeightOk(); ok {
-				hcl.WriteString(fmt.Sprintf("      weight  = %d\n", int64(*weight)))
+			if dist.Weight != nil {
+				item.Set("weight", model.Int(int64(*dist.Weight)))
 			}
 
-			hcl.WriteString("    },\n")
+			items = append(items, item)
 		}
 
-		hcl.WriteString("  ]\n")
+		body.AppendNewline()
+		body.SetAttribute("flow_distributions", &model.Tuple{Elems: items})
 	}
 
-	hcl.WriteString("}\n")
+	return resource, nil
+}
+
+// value returns the trigger timeout as an object
+func (t flowPolicyTimeout) value() *model.Object {
+	object := &model.Object{}
+	object.Set("enabled", model.Bool(t.Enabled))
+	object.Set("time", model.Int(int64(t.Time)))
+	object.Set("time_format", model.String(t.TimeFormat))
+	return object
+}
+
+// GetFlowPolicyVariableEligibleAttributes extracts variable-eligible attributes from a flow policy
+// The weights of its flow distributions become module variables, so traffic can be split
+// differently per environment
+func GetFlowPolicyVariableEligibleAttributes(policyJSON []byte, resourceName string) ([]VariableEligibleAttribute, error) {
+	policy, err := parseFlowPolicy(policyJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	policyName := policy.Name
+	if policyName == "" {
+		return nil, fmt.Errorf("flow policy name is required")
+	}
+
+	// Use provided resource name or sanitize from policy name
+	if resourceName == "" {
+		resourceName = utils.DefaultNamingStrategy().ResourceName(policy.ID, policyName)
+	}
+
+	var attributes []VariableEligibleAttribute
+	for i, dist := range policy.FlowDistributions {
+		if dist.Weight == nil {
+			continue
+		}
+
+		// Distributions are written in payload order, so the index locates the weight
+		attributes = append(attributes, VariableEligibleAttribute{
+			ResourceType:  "flow_policy",
+			ResourceName:  resourceName,
+			ResourceID:    policy.ID,
+			AttributePath: fmt.Sprintf("flow_distributions.%d.weight", i),
+			CurrentValue:  int64(*dist.Weight),
+			VariableName:  fmt.Sprintf("davinci_flow_policy_%s_weight_%d", strings.TrimPrefix(resourceName, "pingcli__"), i),
+			VariableType:  "number",
+			Description:   fmt.Sprintf("Weight of flow %s in %s flow policy", dist.ID, policyName),
+		})
+	}
 
-	return hcl.String(), nil
+	return attributes, nil
 }
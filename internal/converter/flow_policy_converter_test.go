@@ -5,6 +5,7 @@ import (
     "testing"
 
     "github.com/pingidentity/pingone-go-client/pingone"
+    "github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
     "github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
 )
 
@@ -42,10 +43,10 @@ func TestFlowPolicyConversion_OmitsEmptyTrigger(t *testing.T) {
     // Should still include name, status, and flow_distributions
     expected := []string{
         "resource \"pingone_davinci_application_flow_policy\" \"dm_main_flow\"",
-        "environment_id = var.pingone_environment_id",
+        "environment_id         = var.pingone_environment_id",
         "davinci_application_id =", // Verify updated field name is present
-        "name           = \"OOTB - Device Management - Main Flow\"",
-        "status         = \"enabled\"",
+        "name                   = \"OOTB - Device Management - Main Flow\"",
+        "status                 = \"enabled\"",
         "flow_distributions = [",
     }
     for _, e := range expected {
@@ -54,3 +55,48 @@ func TestFlowPolicyConversion_OmitsEmptyTrigger(t *testing.T) {
         }
     }
 }
+
+// TestConvertFlowPolicyJSONToResource_WithoutReadOnlyFields ensures policy JSON saved without
+// _links or environment (e.g. from the DaVinci UI) converts
+func TestConvertFlowPolicyJSONToResource_WithoutReadOnlyFields(t *testing.T) {
+    policyJSON := []byte(`{
+        "id": "policy-1",
+        "name": "Login Policy",
+        "status": "enabled",
+        "trigger": {"type": "AUTHENTICATION", "configuration": {"mfa": {"enabled": true, "time": 8, "timeFormat": "hours"}, "pwd": {"enabled": false, "time": 0, "timeFormat": "hours"}}},
+        "flowDistributions": [{"id": "flow-1", "version": -1, "weight": 100}]
+    }`)
+
+    resource, err := ConvertFlowPolicyJSONToResource(policyJSON, "login_policy", "app-1", "env-1", true, nil)
+    if err != nil {
+        t.Fatalf("ConvertFlowPolicyJSONToResource() error: %v", err)
+    }
+    if resource.ID != "policy-1" {
+        t.Fatalf("resource ID = %q, want policy-1", resource.ID)
+    }
+
+    hcl, err := model.HCL(resource)
+    if err != nil {
+        t.Fatalf("model.HCL() error: %v", err)
+    }
+    expected := []string{
+        "name                   = \"Login Policy\"",
+        "type = \"AUTHENTICATION\"",
+        "time_format = \"hours\"",
+        "id      = \"flow-1\"",
+        "weight  = 100",
+    }
+    for _, e := range expected {
+        if !strings.Contains(hcl, e) {
+            t.Fatalf("Missing expected fragment: %s\nGot:\n%s", e, hcl)
+        }
+    }
+
+    attrs, err := GetFlowPolicyVariableEligibleAttributes(policyJSON, "login_policy")
+    if err != nil {
+        t.Fatalf("GetFlowPolicyVariableEligibleAttributes() error: %v", err)
+    }
+    if len(attrs) != 1 || attrs[0].CurrentValue != int64(100) {
+        t.Fatalf("unexpected attributes: %+v", attrs)
+    }
+}
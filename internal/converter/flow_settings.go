@@ -1,11 +1,14 @@
 package converter
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"strings"
 	"sync"
 
 	"github.com/pingidentity/pingone-go-client/pingone"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 var (
@@ -81,3 +84,62 @@ func filterFlowSettings(settings map[string]interface{}) map[string]interface{}
 	}
 	return filtered
 }
+
+// flowSettingVariables lists the flow settings holding environment-specific URLs. Flows have
+// no custom domain setting: a custom domain belongs to the PingOne environment, and URLs on it
+// are held by the settings listed here.
+var flowSettingVariables = []string{
+	"cssLinks",
+	"customErrorScreenBrandLogoUrl",
+	"customFaviconLink",
+	"customLogoURLSelection",
+}
+
+// GetFlowVariableEligibleAttributes extracts variable-eligible settings from a flow
+// Settings holding environment-specific URLs, such as CSS links and logo URLs, become module variables
+func GetFlowVariableEligibleAttributes(flowJSON []byte, resourceName string) ([]VariableEligibleAttribute, error) {
+	var flowData map[string]interface{}
+	if err := json.Unmarshal(flowJSON, &flowData); err != nil {
+		return nil, fmt.Errorf("failed to parse flow JSON: %w", err)
+	}
+
+	flowName := getString(flowData, "name")
+	if flowName == "" {
+		return nil, fmt.Errorf("flow name is required")
+	}
+
+	// Use provided resource name or sanitize from flow name
+	if resourceName == "" {
+		resourceName = utils.DefaultNamingStrategy().ResourceName(getString(flowData, "flowId"), flowName)
+	}
+
+	// Only settings the converter writes can be replaced
+	settings, _ := flowData["settings"].(map[string]interface{})
+	settings = filterFlowSettings(settings)
+
+	var attributes []VariableEligibleAttribute
+	for _, key := range flowSettingVariables {
+		value, tfType, ok := eligibleValue(settings[key])
+		if !ok {
+			continue
+		}
+		// Strings are written with their JSON escapes decoded
+		if s, isString := value.(string); isString {
+			value = decodeJSONEscapes(s)
+		}
+
+		hclKey := flowSettingsFieldNames[key]
+		attributes = append(attributes, VariableEligibleAttribute{
+			ResourceType:  "flow",
+			ResourceName:  resourceName,
+			ResourceID:    getString(flowData, "flowId"),
+			AttributePath: "settings." + hclKey,
+			CurrentValue:  value,
+			VariableName:  fmt.Sprintf("davinci_flow_%s_%s", strings.TrimPrefix(resourceName, "pingcli__"), hclKey),
+			VariableType:  tfType,
+			Description:   fmt.Sprintf("%s setting for %s flow", hclKey, flowName),
+		})
+	}
+
+	return attributes, nil
+}
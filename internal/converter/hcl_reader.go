@@ -0,0 +1,613 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/modulereader"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// The reader is the reverse of the converters: it parses generated (or hand-edited) HCL and
+// reconstructs the API payloads the converters consume, so JSON -> HCL -> JSON round trips
+// can be checked and HCL can be fed to tooling that works on API payloads.
+//
+// References are resolved where the HCL allows it: "<type>.<name>.id" becomes the ID of the
+// import block for that resource and pingone_davinci_variable.<name>.name becomes the
+// variable's name. Other references, such as var.pingone_environment_id, read back as their
+// interpolation, e.g. "${var.pingone_environment_id}". Values the HCL does not carry (TODO
+// placeholders, masked secrets, read-only fields) cannot be reconstructed, and defaults the
+// converters write, such as settings.logLevel, read back as explicit values.
+
+// ParsedResource is a resource read back from HCL
+type ParsedResource struct {
+	Type    string
+	Name    string
+	Payload map[string]interface{} // API payload in the shape the converter of Type consumes
+}
+
+// Address returns the Terraform address of the resource
+func (r ParsedResource) Address() string {
+	return r.Type + "." + r.Name
+}
+
+// JSON returns the payload as JSON, e.g. for ConvertVariable
+func (r ParsedResource) JSON() ([]byte, error) {
+	return json.Marshal(r.Payload)
+}
+
+// ParseHCL reads the DaVinci resources of a single HCL file, in file order
+func ParseHCL(src []byte, filename string) ([]ParsedResource, error) {
+	file, err := modulereader.ParseFile(src, filename)
+	if err != nil {
+		return nil, err
+	}
+	reader := newHCLReader()
+	reader.addFile(file)
+	return reader.resources()
+}
+
+// ParseModuleDir reads the DaVinci resources of every .tf file in dir, in file name and file
+// order. Import blocks are also read from the root module in the parent directory, where the
+// generator writes them, to resolve resource IDs. Modules in JSON syntax (.tf.json,
+// .tfvars.json) are rejected rather than read without those files.
+func ParseModuleDir(dir string) ([]ParsedResource, error) {
+	files, err := modulereader.Read(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := newHCLReader()
+	for _, file := range files.Files {
+		reader.addFile(file)
+	}
+	for _, file := range files.RootFiles {
+		reader.addImports(file)
+	}
+	return reader.resources()
+}
+
+// readResourceTypes are the resource types the reader reconstructs payloads for
+var readResourceTypes = map[string]bool{
+	"pingone_davinci_flow":                    true,
+	"pingone_davinci_variable":                true,
+	"pingone_davinci_connector_instance":      true,
+	"pingone_davinci_application":             true,
+	"pingone_davinci_application_flow_policy": true,
+}
+
+// hclReader collects the resource and import blocks of one or more files
+type hclReader struct {
+	blocks      []*hclsyntax.Block          // Resource blocks of readResourceTypes, in file order
+	flowBlocks  map[string]*hclsyntax.Block // flow_enable and flow_deploy blocks, keyed by address
+	resourceIDs map[string]string           // Resource address -> ID, from import blocks
+}
+
+func newHCLReader() *hclReader {
+	return &hclReader{flowBlocks: make(map[string]*hclsyntax.Block), resourceIDs: make(map[string]string)}
+}
+
+// addFile collects the resource and import blocks of a file
+func (r *hclReader) addFile(file *modulereader.File) {
+	for _, block := range file.Resources() {
+		switch resourceType := block.Labels[0]; {
+		case readResourceTypes[resourceType]:
+			r.blocks = append(r.blocks, block)
+		case resourceType == "pingone_davinci_flow_enable", resourceType == "pingone_davinci_flow_deploy":
+			r.flowBlocks[resourceType+"."+block.Labels[1]] = block
+		}
+	}
+	r.addImports(file)
+}
+
+// addImports records the resource IDs of import blocks. Import IDs end with the resource ID,
+// e.g. <environment_id>/<flow_id>; addresses in the root module drop the module prefix.
+func (r *hclReader) addImports(file *modulereader.File) {
+	for _, block := range file.Blocks("import") {
+		to, ok := block.Body.Attributes["to"]
+		if !ok {
+			continue
+		}
+		traversal, diags := hcl.AbsTraversalForExpr(to.Expr)
+		if diags.HasErrors() {
+			continue
+		}
+		names, ok := traversalNames(traversal)
+		for ok && len(names) > 2 && names[0] == "module" {
+			names = names[2:]
+		}
+		if !ok || len(names) != 2 {
+			continue
+		}
+
+		id, ok := block.Body.Attributes["id"]
+		if !ok {
+			continue
+		}
+		value, diags := id.Expr.Value(nil)
+		if diags.HasErrors() || value.Type() != cty.String || value.IsNull() {
+			continue
+		}
+		importID := value.AsString()
+		r.resourceIDs[names[0]+"."+names[1]] = importID[strings.LastIndex(importID, "/")+1:]
+	}
+}
+
+// resources reconstructs the payload of every collected resource block
+func (r *hclReader) resources() ([]ParsedResource, error) {
+	resources := make([]ParsedResource, 0, len(r.blocks))
+	for _, block := range r.blocks {
+		resource := ParsedResource{Type: block.Labels[0], Name: block.Labels[1]}
+		attrs, err := r.evalBody(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", resource.Address(), err)
+		}
+
+		switch resource.Type {
+		case "pingone_davinci_flow":
+			resource.Payload, err = r.readFlow(resource.Name, attrs)
+		case "pingone_davinci_variable":
+			resource.Payload, err = readVariable(attrs)
+		case "pingone_davinci_connector_instance":
+			resource.Payload, err = readConnectorInstance(attrs)
+		case "pingone_davinci_application":
+			resource.Payload = readApplication(attrs)
+		case "pingone_davinci_application_flow_policy":
+			resource.Payload = readFlowPolicy(attrs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", resource.Address(), err)
+		}
+
+		if id := r.resourceIDs[resource.Address()]; id != "" {
+			resource.Payload[payloadIDKey(resource.Type)] = id
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// payloadIDKey returns the payload field holding the ID of a resource type
+func payloadIDKey(resourceType string) string {
+	if resourceType == "pingone_davinci_flow" {
+		return "flowId"
+	}
+	return "id"
+}
+
+// evalBody evaluates the attributes of a resource body
+func (r *hclReader) evalBody(body *hclsyntax.Body) (map[string]interface{}, error) {
+	attrs := make(map[string]interface{}, len(body.Attributes))
+	for name, attr := range body.Attributes {
+		value, err := r.eval(attr.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate %s: %w", name, err)
+		}
+		attrs[name] = value
+	}
+	return attrs, nil
+}
+
+// eval evaluates an expression with jsonencode() and the references it contains
+func (r *hclReader) eval(expr hclsyntax.Expression) (interface{}, error) {
+	variables, err := r.referenceValues(expr.Variables())
+	if err != nil {
+		return nil, err
+	}
+	ctx := &hcl.EvalContext{
+		Functions: map[string]function.Function{"jsonencode": stdlib.JSONEncodeFunc},
+		Variables: variables,
+	}
+	value, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%s", diags.Error())
+	}
+	return ctyToInterface(value)
+}
+
+// referenceValues returns the root variables that give each traversal its resolved value
+func (r *hclReader) referenceValues(traversals []hcl.Traversal) (map[string]cty.Value, error) {
+	tree := make(map[string]interface{})
+	for _, traversal := range traversals {
+		names, ok := traversalNames(traversal)
+		if !ok || len(names) < 2 {
+			return nil, fmt.Errorf("unsupported reference at %s", traversal.SourceRange())
+		}
+		node := tree
+		for _, name := range names[:len(names)-1] {
+			child, ok := node[name].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[name] = child
+			}
+			node = child
+		}
+		node[names[len(names)-1]] = r.resolveReference(names)
+	}
+
+	variables := make(map[string]cty.Value, len(tree))
+	for name, node := range tree {
+		variables[name] = referenceTreeValue(node)
+	}
+	return variables, nil
+}
+
+// referenceTreeValue converts a tree of resolved references to nested objects
+func referenceTreeValue(node interface{}) cty.Value {
+	children, ok := node.(map[string]interface{})
+	if !ok {
+		return node.(cty.Value)
+	}
+	attrs := make(map[string]cty.Value, len(children))
+	for name, child := range children {
+		attrs[name] = referenceTreeValue(child)
+	}
+	return cty.ObjectVal(attrs)
+}
+
+// resolveReference returns the value of a reference given as attribute names
+func (r *hclReader) resolveReference(names []string) cty.Value {
+	if len(names) == 3 {
+		address := names[0] + "." + names[1]
+		switch {
+		case names[2] == "id":
+			if id, ok := r.resourceIDs[address]; ok {
+				return cty.StringVal(id)
+			}
+		case names[0] == "pingone_davinci_variable" && names[2] == "name":
+			if name, ok := r.literalAttribute(address, "name"); ok {
+				return cty.StringVal(name)
+			}
+		}
+	}
+	return cty.StringVal("${" + strings.Join(names, ".") + "}")
+}
+
+// literalAttribute returns a string attribute of a collected resource that needs no context
+func (r *hclReader) literalAttribute(address, attribute string) (string, bool) {
+	for _, block := range r.blocks {
+		if block.Labels[0]+"."+block.Labels[1] != address {
+			continue
+		}
+		attr, ok := block.Body.Attributes[attribute]
+		if !ok {
+			return "", false
+		}
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() || value.Type() != cty.String || value.IsNull() {
+			return "", false
+		}
+		return value.AsString(), true
+	}
+	return "", false
+}
+
+// traversalNames returns the attribute names of a traversal, or false if it has other steps
+// such as indexes
+func traversalNames(traversal hcl.Traversal) ([]string, bool) {
+	names := make([]string, 0, len(traversal))
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, s.Name)
+		case hcl.TraverseAttr:
+			names = append(names, s.Name)
+		default:
+			return nil, false
+		}
+	}
+	return names, true
+}
+
+// ctyToInterface converts a value to the types encoding/json decodes JSON to
+func ctyToInterface(value cty.Value) (interface{}, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+	if !value.IsWhollyKnown() {
+		return nil, fmt.Errorf("value is not known")
+	}
+
+	valueType := value.Type()
+	switch {
+	case valueType == cty.String:
+		return value.AsString(), nil
+	case valueType == cty.Bool:
+		return value.True(), nil
+	case valueType == cty.Number:
+		f, _ := value.AsBigFloat().Float64()
+		return f, nil
+	case valueType.IsObjectType() || valueType.IsMapType():
+		object := make(map[string]interface{}, value.LengthInt())
+		for it := value.ElementIterator(); it.Next(); {
+			key, item := it.Element()
+			converted, err := ctyToInterface(item)
+			if err != nil {
+				return nil, err
+			}
+			object[key.AsString()] = converted
+		}
+		return object, nil
+	case valueType.IsTupleType() || valueType.IsListType() || valueType.IsSetType():
+		list := make([]interface{}, 0, value.LengthInt())
+		for it := value.ElementIterator(); it.Next(); {
+			_, item := it.Element()
+			converted, err := ctyToInterface(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %s", valueType.FriendlyName())
+}
+
+// decodeJSONEncoded decodes the string a jsonencode() attribute evaluates to; other values
+// are returned unchanged
+func decodeJSONEncoded(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return decoded, nil
+}
+
+// toCamelCase converts an HCL attribute name to its API field name, e.g. id_unique -> idUnique
+func toCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// camelKeys returns a copy of value with object keys converted to API field names; renames
+// lists the names toCamelCase does not produce
+func camelKeys(value interface{}, renames map[string]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			name, ok := renames[key]
+			if !ok {
+				name = toCamelCase(key)
+			}
+			object[name] = camelKeys(item, renames)
+		}
+		return object
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = camelKeys(item, renames)
+		}
+		return list
+	}
+	return value
+}
+
+// readFlow reconstructs a flow from its attributes and its flow_enable and flow_deploy resources
+func (r *hclReader) readFlow(name string, attrs map[string]interface{}) (map[string]interface{}, error) {
+	flow := make(map[string]interface{})
+	for _, key := range []string{"name", "description", "color"} {
+		if value, ok := attrs[key]; ok {
+			flow[key] = value
+		}
+	}
+
+	if settings, ok := attrs["settings"].(map[string]interface{}); ok {
+		renames := make(map[string]string, len(flowSettingsFieldNames))
+		for field, hclKey := range flowSettingsFieldNames {
+			renames[hclKey] = field
+		}
+		flow["settings"] = camelKeys(settings, renames)
+	}
+
+	if graphData, ok := attrs["graph_data"].(map[string]interface{}); ok {
+		decoded, err := readGraphData(graphData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read graph_data: %w", err)
+		}
+		flow["graphData"] = decoded
+	}
+
+	if inputSchema, ok := attrs["input_schema"].([]interface{}); ok {
+		flow["inputSchema"] = camelKeys(inputSchema, nil)
+	}
+
+	if outputSchema, ok := attrs["output_schema"].(map[string]interface{}); ok {
+		decoded := make(map[string]interface{})
+		if output, ok := outputSchema["output"]; ok {
+			value, err := decodeJSONEncoded(output)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read output_schema: %w", err)
+			}
+			decoded["output"] = value
+		}
+		flow["outputSchema"] = decoded
+	}
+
+	if trigger, ok := attrs["trigger"].(map[string]interface{}); ok {
+		flow["trigger"] = camelKeys(trigger, nil)
+	}
+
+	// The flow ID, enabled state and current version are only written to the auxiliary
+	// resources, and only as values when dependencies are skipped
+	if block, ok := r.flowBlocks["pingone_davinci_flow_enable."+name]; ok {
+		enable, err := r.evalBody(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pingone_davinci_flow_enable.%s: %w", name, err)
+		}
+		if flowID, ok := enable["flow_id"].(string); ok && !strings.HasPrefix(flowID, "${") {
+			flow["flowId"] = flowID
+		}
+		if enabled, ok := enable["enabled"].(bool); ok {
+			flow["enabled"] = enabled
+		}
+	}
+	if block, ok := r.flowBlocks["pingone_davinci_flow_deploy."+name]; ok {
+		deploy, err := r.evalBody(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pingone_davinci_flow_deploy.%s: %w", name, err)
+		}
+		if values, ok := deploy["deploy_trigger_values"].(map[string]interface{}); ok {
+			if version, ok := values["deployed_version"].(float64); ok {
+				flow["currentVersion"] = version
+			}
+		}
+	}
+
+	return flow, nil
+}
+
+// readGraphData reconstructs graph data, turning the nodes and edges maps back into lists
+// ordered by ID
+func readGraphData(graphData map[string]interface{}) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{}, len(graphData))
+	for key, value := range graphData {
+		switch key {
+		case "data", "renderer":
+			object, err := decodeJSONEncoded(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", key, err)
+			}
+			decoded[key] = object
+		case "elements":
+			elements, _ := value.(map[string]interface{})
+			decodedElements := make(map[string]interface{}, len(elements))
+			for _, kind := range []string{"nodes", "edges"} {
+				byID, ok := elements[kind].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				list, err := readGraphElements(byID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read %s: %w", kind, err)
+				}
+				decodedElements[kind] = list
+			}
+			decoded["elements"] = decodedElements
+		default:
+			decoded[toCamelCase(key)] = camelKeys(value, nil)
+		}
+	}
+	return decoded, nil
+}
+
+// readGraphElements reconstructs the node or edge list from the map keyed by element ID
+func readGraphElements(byID map[string]interface{}) ([]interface{}, error) {
+	keys := make([]string, 0, len(byID))
+	for key := range byID {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		element, ok := byID[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var properties interface{}
+		data, _ := element["data"].(map[string]interface{})
+		if value, ok := data["properties"]; ok {
+			var err error
+			if properties, err = decodeJSONEncoded(value); err != nil {
+				return nil, fmt.Errorf("failed to read properties of %s: %w", key, err)
+			}
+		}
+
+		decoded := camelKeys(element, nil).(map[string]interface{})
+		if properties != nil {
+			decoded["data"].(map[string]interface{})["properties"] = properties
+		}
+		list = append(list, decoded)
+	}
+	return list, nil
+}
+
+// readVariable reconstructs a VariableResponse payload
+func readVariable(attrs map[string]interface{}) (map[string]interface{}, error) {
+	variable := make(map[string]interface{})
+	for key, value := range attrs {
+		switch key {
+		case "environment_id":
+			variable["environment"] = map[string]interface{}{"id": value}
+		case "value":
+			// The value object sets one key named after the value's type
+			object, ok := value.(map[string]interface{})
+			if !ok || len(object) != 1 {
+				return nil, fmt.Errorf("value must set exactly one type")
+			}
+			for _, item := range object {
+				variable["value"] = item
+			}
+		default:
+			variable[toCamelCase(key)] = value
+		}
+	}
+	return variable, nil
+}
+
+// readConnectorInstance reconstructs a ConnectorInstanceResponse payload
+func readConnectorInstance(attrs map[string]interface{}) (map[string]interface{}, error) {
+	instance := make(map[string]interface{})
+	for key, value := range attrs {
+		switch key {
+		case "environment_id":
+			instance["environment"] = map[string]interface{}{"id": value}
+		case "properties":
+			properties, err := decodeJSONEncoded(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read properties: %w", err)
+			}
+			instance["properties"] = properties
+		default:
+			instance[key] = value
+		}
+	}
+	return instance, nil
+}
+
+// readApplication reconstructs an application payload
+func readApplication(attrs map[string]interface{}) map[string]interface{} {
+	app := make(map[string]interface{})
+	for key, value := range attrs {
+		if key == "environment_id" {
+			app["environment"] = map[string]interface{}{"id": value}
+			continue
+		}
+		app[toCamelCase(key)] = camelKeys(value, map[string]string{"sp_jwks_url": "spjwksUrl"})
+	}
+	return app
+}
+
+// readFlowPolicy reconstructs a DaVinciFlowPolicyResponse payload. Read-only fields such as
+// _links are not in the HCL, so the payload has the response's field names but does not
+// unmarshal into one.
+func readFlowPolicy(attrs map[string]interface{}) map[string]interface{} {
+	policy := make(map[string]interface{})
+	for key, value := range attrs {
+		switch key {
+		case "environment_id":
+			policy["environment"] = map[string]interface{}{"id": value}
+		case "davinci_application_id":
+			policy["application"] = map[string]interface{}{"id": value}
+		default:
+			policy[toCamelCase(key)] = camelKeys(value, nil)
+		}
+	}
+	return policy
+}
@@ -0,0 +1,395 @@
+package converter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pingidentity/pingone-go-client/pingone"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+)
+
+// parseSingle reads the only resource of generated HCL
+func parseSingle(t *testing.T, hcl string) ParsedResource {
+	t.Helper()
+	resources, err := ParseHCL([]byte(hcl), "generated.tf")
+	if err != nil {
+		t.Fatalf("ParseHCL() error: %v\n%s", err, hcl)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d\n%s", len(resources), hcl)
+	}
+	return resources[0]
+}
+
+// decodeJSON decodes a JSON document the way the converters' callers do
+func decodeJSON(t *testing.T, data string) map[string]interface{} {
+	t.Helper()
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("invalid test JSON: %v", err)
+	}
+	return decoded
+}
+
+// assertPayload fails with both payloads as JSON when they differ
+func assertPayload(t *testing.T, got, want map[string]interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		wantJSON, _ := json.MarshalIndent(want, "", "  ")
+		t.Errorf("payload mismatch\ngot:  %s\nwant: %s", gotJSON, wantJSON)
+	}
+}
+
+// TestParseHCL_FlowRoundTrip verifies a flow converted with skipped dependencies reads back
+// to the same payload
+func TestParseHCL_FlowRoundTrip(t *testing.T) {
+	flow := decodeJSON(t, `{
+		"flowId": "flow-1",
+		"name": "Login \"main\" ${flow}",
+		"description": "Line one\nLine two",
+		"color": "#AFD5FF",
+		"enabled": true,
+		"currentVersion": 7,
+		"settings": {
+			"logLevel": 2,
+			"useCustomCSS": true,
+			"css": "body { color: red; }",
+			"cssLinks": ["https://example.com/a.css"],
+			"jsLinks": [{"crossorigin": "anonymous", "defer": true, "integrity": "sha-1", "label": "lib", "referrerpolicy": "no-referrer", "type": "text/javascript", "value": "https://example.com/a.js"}]
+		},
+		"graphData": {
+			"data": {},
+			"elements": {
+				"nodes": [
+					{
+						"data": {
+							"id": "node2",
+							"nodeType": "CONNECTION",
+							"idUnique": "unique-2",
+							"connectionId": "conn-1",
+							"connectorId": "httpConnector",
+							"name": "Http",
+							"label": "Http",
+							"status": "configured",
+							"capabilityName": "customHtmlMessage",
+							"type": "action",
+							"properties": {"message": {"value": "Hello {{name}}"}, "list": [1, "two", null]}
+						},
+						"position": {"x": 100.5, "y": -20},
+						"group": "nodes",
+						"removed": false,
+						"selected": false,
+						"selectable": true,
+						"locked": false,
+						"grabbable": true,
+						"pannable": false,
+						"classes": ""
+					},
+					{
+						"data": {"id": "node1", "nodeType": "EVAL", "properties": {}},
+						"position": {"x": 0, "y": 0},
+						"group": "nodes",
+						"classes": "start"
+					}
+				],
+				"edges": [
+					{
+						"data": {"id": "edge1", "source": "node1", "target": "node2"},
+						"position": {"x": 0, "y": 0},
+						"group": "edges",
+						"selectable": true,
+						"classes": ""
+					}
+				]
+			},
+			"pan": {"x": 10, "y": 20.5},
+			"zoom": 1,
+			"minZoom": 0.001,
+			"maxZoom": 100000,
+			"zoomingEnabled": true,
+			"panningEnabled": true,
+			"userZoomingEnabled": true,
+			"userPanningEnabled": true,
+			"boxSelectionEnabled": true,
+			"renderer": {"name": "null"}
+		},
+		"inputSchema": [
+			{"propertyName": "username", "preferredDataType": "string", "preferredControlType": "textField", "required": true, "isExpanded": false, "description": "User"}
+		],
+		"outputSchema": {"output": {"type": "object", "properties": {"ok": {"type": "boolean"}}}},
+		"trigger": {"type": "AUTHENTICATION", "configuration": {"mfa": {"enabled": true, "time": 5, "timeFormat": "MIN"}}}
+	}`)
+
+	hcl, err := ConvertFlowToHCL(flow, "env-1", true, nil)
+	if err != nil {
+		t.Fatalf("ConvertFlowToHCL() error: %v", err)
+	}
+	resource := parseSingle(t, hcl)
+	if resource.Type != "pingone_davinci_flow" {
+		t.Fatalf("expected pingone_davinci_flow, got %s", resource.Type)
+	}
+
+	// Nodes read back ordered by ID, as the converter writes them
+	expected := decodeJSON(t, mustJSON(t, flow))
+	nodes := expected["graphData"].(map[string]interface{})["elements"].(map[string]interface{})["nodes"].([]interface{})
+	nodes[0], nodes[1] = nodes[1], nodes[0]
+	assertPayload(t, resource.Payload, expected)
+}
+
+// TestParseHCL_VariableRoundTrip verifies a variable reads back to the same VariableResponse
+func TestParseHCL_VariableRoundTrip(t *testing.T) {
+	min, max := 1, 10
+	original := VariableResponse{
+		ID:          "var-1",
+		Name:        "retries",
+		DataType:    "number",
+		DisplayName: "Retries",
+		Context:     "flow",
+		Value:       float64(3),
+		Mutable:     true,
+		Min:         &min,
+		Max:         &max,
+		Flow: &struct {
+			ID string `json:"id"`
+		}{ID: "flow-1"},
+	}
+	original.Environment.ID = "env-1"
+	variableJSON, _ := json.Marshal(original)
+
+	hcl, err := ConvertVariableWithResourceName(variableJSON, true, "retries", nil)
+	if err != nil {
+		t.Fatalf("ConvertVariableWithResourceName() error: %v", err)
+	}
+	resource := parseSingle(t, hcl)
+
+	// The ID is only known from an import block
+	original.ID = ""
+	readJSON, err := resource.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error: %v", err)
+	}
+	var read VariableResponse
+	if err := json.Unmarshal(readJSON, &read); err != nil {
+		t.Fatalf("payload does not unmarshal into VariableResponse: %v", err)
+	}
+	if !reflect.DeepEqual(read, original) {
+		t.Errorf("variable mismatch\ngot:  %+v\nwant: %+v", read, original)
+	}
+}
+
+// TestParseHCL_ConnectorInstanceRoundTrip verifies connector properties read back unchanged
+func TestParseHCL_ConnectorInstanceRoundTrip(t *testing.T) {
+	instance := decodeJSON(t, `{
+		"environment": {"id": "env-1"},
+		"connector": {"id": "httpConnector"},
+		"name": "HTTP",
+		"properties": {
+			"url": {"type": "string", "value": "https://example.com/${path}"},
+			"headers": {"type": "array", "value": [{"name": "Accept", "value": "application/json"}]},
+			"timeout": {"value": 30}
+		}
+	}`)
+
+	hcl, err := ConvertConnectorInstanceWithResourceName([]byte(mustJSON(t, instance)), true, "http")
+	if err != nil {
+		t.Fatalf("ConvertConnectorInstanceWithResourceName() error: %v", err)
+	}
+	assertPayload(t, parseSingle(t, hcl).Payload, instance)
+}
+
+// TestParseHCL_ApplicationRoundTrip verifies an application reads back to the same payload
+func TestParseHCL_ApplicationRoundTrip(t *testing.T) {
+	app := decodeJSON(t, `{
+		"environment": {"id": "env-1"},
+		"name": "Web App",
+		"apiKey": {"enabled": true},
+		"oauth": {
+			"grantTypes": ["authorizationCode"],
+			"redirectUris": ["https://example.com/callback"],
+			"logoutUris": ["https://example.com/logout"],
+			"scopes": ["openid", "profile"],
+			"enforceSignedRequestOpenid": false,
+			"spJwksOpenid": "{\"keys\": []}",
+			"spjwksUrl": "https://example.com/jwks"
+		}
+	}`)
+
+	hcl, err := ConvertApplicationWithEnvironment([]byte(mustJSON(t, app)), "env-1")
+	if err != nil {
+		t.Fatalf("ConvertApplicationWithEnvironment() error: %v", err)
+	}
+	assertPayload(t, parseSingle(t, hcl).Payload, app)
+}
+
+// TestParseHCL_FlowPolicyRoundTrip verifies a flow policy reads back with the response's field names
+func TestParseHCL_FlowPolicyRoundTrip(t *testing.T) {
+	var policy pingone.DaVinciFlowPolicyResponse
+	policy.SetId("policy-1")
+	policy.SetName("Login Policy")
+	policy.SetStatus(pingone.DAVINCIFLOWPOLICYRESPONSESTATUS_ENABLED)
+	distribution := pingone.DaVinciFlowPolicyResponseFlowDistribution{Id: "flow-1", Version: 3}
+	distribution.SetWeight(100)
+	policy.SetFlowDistributions([]pingone.DaVinciFlowPolicyResponseFlowDistribution{distribution})
+
+	hcl, err := ConvertFlowPolicyToTerraform(policy, "login_policy", "app-1", "env-1", true, nil)
+	if err != nil {
+		t.Fatalf("ConvertFlowPolicyToTerraform() error: %v", err)
+	}
+
+	expected := decodeJSON(t, `{
+		"environment": {"id": "env-1"},
+		"application": {"id": "app-1"},
+		"name": "Login Policy",
+		"status": "enabled",
+		"flowDistributions": [{"id": "flow-1", "version": 3, "weight": 100}]
+	}`)
+	assertPayload(t, parseSingle(t, hcl).Payload, expected)
+}
+
+// TestParseModuleDir_ResolvesReferences verifies references read back as the IDs of the
+// root module's import blocks and variable names, and other references as interpolations
+func TestParseModuleDir_ResolvesReferences(t *testing.T) {
+	graph := resolver.NewDependencyGraph()
+	graph.AddResource("pingone_davinci_flow", "flow-1", "login")
+	graph.AddResource("pingone_davinci_flow", "flow-2", "subflow")
+	graph.AddResource("pingone_davinci_connector_instance", "conn-1", "http")
+	graph.AddResource("pingone_davinci_variable", "var-1", "color_company")
+	graph.AddVariableName("var-1", "color", "company")
+
+	flow := decodeJSON(t, `{
+		"flowId": "flow-1",
+		"name": "Login",
+		"graphData": {"elements": {"nodes": [{"data": {
+			"id": "node1",
+			"connectionId": "conn-1",
+			"properties": {
+				"message": {"value": "Color: {{global.company.variables.color}}"},
+				"subFlowId": {"value": {"label": "Subflow", "value": "flow-2"}}
+			}
+		}}]}}
+	}`)
+	flowHCL, err := ConvertFlowToHCL(flow, "var.pingone_environment_id", false, graph)
+	if err != nil {
+		t.Fatalf("ConvertFlowToHCL() error: %v", err)
+	}
+	variableHCL, err := ConvertVariableWithResourceName([]byte(`{"id": "var-1", "name": "color", "context": "company", "dataType": "string", "value": "blue", "mutable": true}`), false, "color_company", graph)
+	if err != nil {
+		t.Fatalf("ConvertVariableWithResourceName() error: %v", err)
+	}
+
+	root := t.TempDir()
+	child := filepath.Join(root, "modules", "davinci")
+	if err := os.MkdirAll(child, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(child, "pingone_davinci_flow.tf"):     flowHCL,
+		filepath.Join(child, "pingone_davinci_variable.tf"): variableHCL,
+		filepath.Join(root, "modules", "davinci-imports.tf"): `import {
+  to = module.davinci.pingone_davinci_flow.login
+  id = "env-1/flow-1"
+}
+
+import {
+  to = module.davinci.pingone_davinci_flow.subflow
+  id = "env-1/flow-2"
+}
+
+import {
+  to = module.davinci.pingone_davinci_connector_instance.http
+  id = "env-1/conn-1"
+}
+`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resources, err := ParseModuleDir(child)
+	if err != nil {
+		t.Fatalf("ParseModuleDir() error: %v", err)
+	}
+	if len(resources) != 2 || resources[0].Address() != "pingone_davinci_flow.login" || resources[1].Address() != "pingone_davinci_variable.color_company" {
+		t.Fatalf("unexpected resources: %+v", resources)
+	}
+
+	data := resources[0].Payload["graphData"].(map[string]interface{})["elements"].(map[string]interface{})["nodes"].([]interface{})[0].(map[string]interface{})["data"].(map[string]interface{})
+	if data["connectionId"] != "conn-1" {
+		t.Errorf("connectionId = %v, want conn-1", data["connectionId"])
+	}
+	properties := data["properties"].(map[string]interface{})
+	if got := properties["message"].(map[string]interface{})["value"]; got != "Color: {{global.company.variables.color}}" {
+		t.Errorf("message = %v, want the variable name restored", got)
+	}
+	if got := properties["subFlowId"].(map[string]interface{})["value"].(map[string]interface{})["value"]; got != "flow-2" {
+		t.Errorf("subFlowId = %v, want flow-2", got)
+	}
+	if got := resources[0].Payload["flowId"]; got != "flow-1" {
+		t.Errorf("flowId = %v, want flow-1", got)
+	}
+
+	// The variable has no import block and the environment is a module input
+	variable := resources[1].Payload
+	if _, ok := variable["id"]; ok {
+		t.Errorf("expected no variable ID, got %v", variable["id"])
+	}
+	if got := variable["environment"].(map[string]interface{})["id"]; got != "${var.pingone_environment_id}" {
+		t.Errorf("environment.id = %v, want the interpolated reference", got)
+	}
+}
+
+// TestParseHCL_Errors verifies invalid HCL and payloads are reported with the resource address
+func TestParseHCL_Errors(t *testing.T) {
+	invalid := map[string]string{
+		"syntax":        `resource "pingone_davinci_flow" "x" {`,
+		"json":          "resource \"pingone_davinci_connector_instance\" \"x\" {\n  properties = \"{\"\n}\n",
+		"value":         "resource \"pingone_davinci_variable\" \"x\" {\n  value = { string = \"a\", bool = true }\n}\n",
+		"index":         "resource \"pingone_davinci_application\" \"x\" {\n  name = var.names[0]\n}\n",
+		"function call": "resource \"pingone_davinci_application\" \"x\" {\n  name = file(\"name\")\n}\n",
+	}
+	for name, src := range invalid {
+		if _, err := ParseHCL([]byte(src), "invalid.tf"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// TestParseModuleDir_RejectsJSONSyntax verifies modules generated in JSON syntax are reported
+// instead of read without their .tf.json files
+func TestParseModuleDir_RejectsJSONSyntax(t *testing.T) {
+	for name, dir := range map[string]string{"child module": "davinci", "root module": ""} {
+		root := t.TempDir()
+		child := filepath.Join(root, "davinci")
+		if err := os.MkdirAll(child, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(child, "versions.tf"), []byte("terraform {}\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "pingone_davinci_flow.tf.json"), []byte(`{"resource": {}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := ParseModuleDir(child)
+		if err == nil || !strings.Contains(err.Error(), "pingone_davinci_flow.tf.json is in JSON syntax") {
+			t.Errorf("%s: ParseModuleDir() error = %v, want JSON syntax error", name, err)
+		}
+	}
+}
+
+// mustJSON encodes a test payload
+func mustJSON(t *testing.T, value interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to encode test payload: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,111 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+)
+
+// Converters build model resources rather than HCL text; the model's writer escapes string
+// values, writes references from traversals and formats the output like terraform fmt. The
+// helpers in this file turn payload values and resolver expressions into model values.
+
+// stringList returns a list of quoted strings, formatting non-string items with %v
+func stringList(items []interface{}) model.Value {
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		strs = append(strs, fmt.Sprintf("%v", item))
+	}
+	return model.StringList(strs)
+}
+
+// environmentValue returns the environment_id value: a variable reference such as
+// var.pingone_environment_id, or a quoted environment ID
+func environmentValue(environmentID string) (model.Value, error) {
+	if strings.HasPrefix(environmentID, "var.") {
+		return model.ParseExpression(environmentID)
+	}
+	return model.String(environmentID), nil
+}
+
+// hclTemplate is a quoted template whose parts are literal strings, references written as
+// hclExpression values, or parsed traversals
+type hclTemplate []interface{}
+
+// value returns the template as a model value
+func (t hclTemplate) value() (model.Value, error) {
+	template := make(model.Template, 0, len(t))
+	for _, part := range t {
+		switch p := part.(type) {
+		case string:
+			template = append(template, model.TemplatePart{Literal: p})
+		case hclExpression:
+			traversal, diags := hclsyntax.ParseTraversalAbs([]byte(p.expr), "", hcl.InitialPos)
+			if diags.HasErrors() {
+				return nil, fmt.Errorf("invalid reference %q: %s", p.expr, diags.Error())
+			}
+			template = append(template, model.TemplatePart{Reference: traversal})
+		case hcl.Traversal:
+			template = append(template, model.TemplatePart{Reference: p})
+		default:
+			return nil, fmt.Errorf("unsupported template part %T", part)
+		}
+	}
+	return template, nil
+}
+
+// jsonValue returns a decoded JSON value as a model value for use with jsonencode(): objects
+// become object constructors with quoted, sorted keys and arrays become tuples. hclExpression
+// and hclTemplate values are written as expressions.
+func jsonValue(value interface{}) (model.Value, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		object := &model.Object{}
+		for _, key := range keys {
+			item, err := jsonValue(v[key])
+			if err != nil {
+				return nil, err
+			}
+			object.SetKey(key, item)
+		}
+		return object, nil
+	case []interface{}:
+		elems := make([]model.Value, 0, len(v))
+		for _, item := range v {
+			elem, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return &model.Tuple{Elems: elems}, nil
+	case string:
+		return model.String(v), nil
+	case float64:
+		return model.Number(v), nil
+	case bool:
+		return model.Bool(v), nil
+	case nil:
+		return model.Null(), nil
+	case hclExpression:
+		expr := v.expr
+		if v.comment != "" {
+			expr += " # " + v.comment
+		}
+		return model.ParseExpression(expr)
+	case hclTemplate:
+		return v.value()
+	default:
+		return model.String(fmt.Sprintf("%v", v)), nil
+	}
+}
@@ -0,0 +1,255 @@
+package converter
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pingidentity/pingone-go-client/pingone"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// hostileFragments are pieces of strings that break HCL written by concatenation: quotes,
+// escapes, template sequences, comment markers, heredoc markers and braces
+var hostileFragments = []string{
+	`"`, `\`, `\\`, `\"`, `\n`, "\n", "\r\n", "\t",
+	"${", "%{", "$${", "%%{", "${var.secret}", "%{ if true }x%{ endif }", "$", "%", "{", "}",
+	"#", "//", "/*", "*/", "<<EOF", "EOF", "=", ",", "[", "]",
+	"héllo", "日本", " ", " ",
+	"{{global.variables.color}}", "plain",
+}
+
+// hostileString is a quick.Generator of strings built from hostile fragments
+type hostileString string
+
+// Generate implements quick.Generator
+func (hostileString) Generate(r *rand.Rand, size int) reflect.Value {
+	var s strings.Builder
+	for i := r.Intn(6) + 1; i > 0; i-- {
+		s.WriteString(hostileFragments[r.Intn(len(hostileFragments))])
+	}
+	return reflect.ValueOf(hostileString(s.String()))
+}
+
+// hclEvalContext evaluates generated attributes: jsonencode() and references to variables
+// named after their resource
+var hclEvalContext = &hcl.EvalContext{
+	Functions: map[string]function.Function{"jsonencode": stdlib.JSONEncodeFunc},
+	Variables: map[string]cty.Value{
+		"pingone_davinci_variable": cty.ObjectVal(map[string]cty.Value{
+			"color_company": cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("color")}),
+		}),
+	},
+}
+
+// evalAttribute parses generated HCL and evaluates an attribute of the first resource of the
+// given type, failing on any syntax or evaluation error
+func evalAttribute(t *testing.T, src, resourceType, attribute string) cty.Value {
+	t.Helper()
+	file, diags := hclsyntax.ParseConfig([]byte(src), "generated.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("generated HCL does not parse: %s\n%s", diags.Error(), src)
+	}
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" || block.Labels[0] != resourceType {
+			continue
+		}
+		attr, ok := block.Body.Attributes[attribute]
+		if !ok {
+			t.Fatalf("resource %s has no attribute %s\n%s", resourceType, attribute, src)
+		}
+		value, diags := attr.Expr.Value(hclEvalContext)
+		if diags.HasErrors() {
+			t.Fatalf("attribute %s does not evaluate: %s\n%s", attribute, diags.Error(), src)
+		}
+		return value
+	}
+	t.Fatalf("no resource %s\n%s", resourceType, src)
+	return cty.NilVal
+}
+
+// decodeJSONAttribute decodes the string produced by a jsonencode() attribute
+func decodeJSONAttribute(t *testing.T, value cty.Value) interface{} {
+	t.Helper()
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value.AsString()), &decoded); err != nil {
+		t.Fatalf("jsonencode() result is not JSON: %v", err)
+	}
+	return decoded
+}
+
+// checkProperty runs a property over generated hostile strings
+func checkProperty(t *testing.T, property interface{}) {
+	t.Helper()
+	if err := quick.Check(property, &quick.Config{MaxCount: 300, Rand: rand.New(rand.NewSource(1))}); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestHostileStrings_Flow checks flow attributes and node properties survive any string
+func TestHostileStrings_Flow(t *testing.T) {
+	checkProperty(t, func(name, description, label, property hostileString) bool {
+		properties := map[string]interface{}{
+			string(property): map[string]interface{}{"value": string(property)},
+			"list":           []interface{}{string(label), 1.5, true, nil},
+		}
+		flow := map[string]interface{}{
+			"flowId":      "flow-1",
+			"name":        string(name),
+			"description": string(description),
+			"graphData": map[string]interface{}{
+				"elements": map[string]interface{}{
+					"nodes": []interface{}{
+						map[string]interface{}{"data": map[string]interface{}{
+							"id":         string(label),
+							"label":      string(label),
+							"properties": properties,
+						}},
+					},
+				},
+			},
+		}
+		hcl, err := ConvertFlowToHCL(flow, "var.pingone_environment_id", true, nil)
+		if err != nil {
+			t.Fatalf("ConvertFlowToHCL() error: %v", err)
+		}
+
+		node := evalAttribute(t, hcl, "pingone_davinci_flow", "graph_data").GetAttr("elements").GetAttr("nodes").GetAttr(string(label)).GetAttr("data")
+		return evalAttribute(t, hcl, "pingone_davinci_flow", "name").AsString() == string(name) &&
+			evalAttribute(t, hcl, "pingone_davinci_flow", "description").AsString() == string(description) &&
+			node.GetAttr("label").AsString() == string(label) &&
+			reflect.DeepEqual(decodeJSONAttribute(t, node.GetAttr("properties")), properties)
+	})
+}
+
+// TestHostileStrings_FlowVariableInterpolation checks strings whose variable names are
+// interpolated evaluate to the original string
+func TestHostileStrings_FlowVariableInterpolation(t *testing.T) {
+	checkProperty(t, func(before, after hostileString) bool {
+		graph := resolver.NewDependencyGraph()
+		graph.AddResource("pingone_davinci_flow", "flow-1", "flow")
+		graph.AddResource("pingone_davinci_variable", "var-1", "color_company")
+		graph.AddVariableName("var-1", "color", "company")
+
+		value := string(before) + "{{global.company.variables.color}}" + string(after)
+		flow := map[string]interface{}{
+			"flowId": "flow-1",
+			"name":   "Flow",
+			"graphData": map[string]interface{}{
+				"elements": map[string]interface{}{
+					"nodes": []interface{}{
+						map[string]interface{}{"data": map[string]interface{}{
+							"id":         "node1",
+							"properties": map[string]interface{}{"message": map[string]interface{}{"value": value}},
+						}},
+					},
+				},
+			},
+		}
+		hcl, err := ConvertFlowToHCL(flow, "var.pingone_environment_id", false, graph)
+		if err != nil {
+			t.Fatalf("ConvertFlowToHCL() error: %v", err)
+		}
+		if !strings.Contains(hcl, "${pingone_davinci_variable.color_company.name}") {
+			t.Fatalf("expected interpolated variable name, got:\n%s", hcl)
+		}
+
+		node := evalAttribute(t, hcl, "pingone_davinci_flow", "graph_data").GetAttr("elements").GetAttr("nodes").GetAttr("node1").GetAttr("data")
+		properties := decodeJSONAttribute(t, node.GetAttr("properties")).(map[string]interface{})
+		return properties["message"].(map[string]interface{})["value"] == value
+	})
+}
+
+// TestHostileStrings_Variable checks variable attributes and values survive any string
+func TestHostileStrings_Variable(t *testing.T) {
+	checkProperty(t, func(name, displayName, value hostileString) bool {
+		variableJSON, _ := json.Marshal(map[string]interface{}{
+			"id":          "var-1",
+			"environment": map[string]interface{}{"id": "env-1"},
+			"name":        string(name),
+			"displayName": string(displayName),
+			"dataType":    "string",
+			"context":     "company",
+			"value":       string(value),
+			"mutable":     true,
+		})
+		hcl, err := ConvertVariableWithResourceName(variableJSON, true, "var_1", nil)
+		if err != nil {
+			t.Fatalf("ConvertVariableWithResourceName() error: %v", err)
+		}
+
+		return evalAttribute(t, hcl, "pingone_davinci_variable", "name").AsString() == string(name) &&
+			evalAttribute(t, hcl, "pingone_davinci_variable", "display_name").AsString() == string(displayName) &&
+			evalAttribute(t, hcl, "pingone_davinci_variable", "value").GetAttr("string").AsString() == string(value)
+	})
+}
+
+// TestHostileStrings_ConnectorInstance checks connector names and properties survive any string
+func TestHostileStrings_ConnectorInstance(t *testing.T) {
+	checkProperty(t, func(name, key, value hostileString) bool {
+		instanceJSON, _ := json.Marshal(map[string]interface{}{
+			"id":          "conn-1",
+			"environment": map[string]interface{}{"id": "env-1"},
+			"connector":   map[string]interface{}{"id": "httpConnector"},
+			"name":        string(name),
+			"properties": map[string]interface{}{
+				string(key): map[string]interface{}{"type": "string", "value": string(value)},
+			},
+		})
+		hcl, err := ConvertConnectorInstanceWithResourceName(instanceJSON, true, "conn_1")
+		if err != nil {
+			t.Fatalf("ConvertConnectorInstanceWithResourceName() error: %v", err)
+		}
+
+		properties := decodeJSONAttribute(t, evalAttribute(t, hcl, "pingone_davinci_connector_instance", "properties"))
+		expected := map[string]interface{}{
+			string(key): map[string]interface{}{"type": "string", "value": string(value)},
+		}
+		if strings.TrimSpace(string(value)) == "******" {
+			return true
+		}
+		return evalAttribute(t, hcl, "pingone_davinci_connector_instance", "name").AsString() == string(name) &&
+			reflect.DeepEqual(properties, expected)
+	})
+}
+
+// TestHostileStrings_Application checks application names and OAuth URIs survive any string
+func TestHostileStrings_Application(t *testing.T) {
+	checkProperty(t, func(name, uri hostileString) bool {
+		appJSON, _ := json.Marshal(map[string]interface{}{
+			"id":    "app-1",
+			"name":  string(name),
+			"oauth": map[string]interface{}{"redirectUris": []interface{}{string(uri)}, "spJwksOpenid": string(uri)},
+		})
+		hcl, err := ConvertApplicationWithEnvironment(appJSON, "env-1")
+		if err != nil {
+			t.Fatalf("ConvertApplicationWithEnvironment() error: %v", err)
+		}
+
+		oauth := evalAttribute(t, hcl, "pingone_davinci_application", "oauth")
+		return evalAttribute(t, hcl, "pingone_davinci_application", "name").AsString() == string(name) &&
+			oauth.GetAttr("redirect_uris").Index(cty.NumberIntVal(0)).AsString() == string(uri) &&
+			oauth.GetAttr("sp_jwks_openid").AsString() == string(uri)
+	})
+}
+
+// TestHostileStrings_FlowPolicy checks flow policy names survive any string
+func TestHostileStrings_FlowPolicy(t *testing.T) {
+	checkProperty(t, func(name hostileString) bool {
+		var policy pingone.DaVinciFlowPolicyResponse
+		policy.SetName(string(name))
+		hcl, err := ConvertFlowPolicyToTerraform(policy, "policy_1", "app-1", "env-1", true, nil)
+		if err != nil {
+			t.Fatalf("ConvertFlowPolicyToTerraform() error: %v", err)
+		}
+		return evalAttribute(t, hcl, "pingone_davinci_application_flow_policy", "name").AsString() == string(name)
+	})
+}
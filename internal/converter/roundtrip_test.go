@@ -0,0 +1,348 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pingidentity/pingone-go-client/pingone"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+)
+
+// The round-trip harness converts every JSON fixture under testdata to HCL, reads the HCL back
+// with ParseHCL and reports each fixture field that was lost or altered on the way. Fields the
+// converters drop on purpose are declared in roundTripAllowlist, so any other loss fails.
+
+// roundTripEnvironmentID is the environment of fixtures that do not carry one
+const roundTripEnvironmentID = "env-roundtrip"
+
+// roundTripAllowance declares fields of a resource type that are intentionally not round-tripped.
+// Path is matched per segment, where * matches any one segment, and covers the fields below it.
+// When Value is set, only fixture fields with that value are allowed to change.
+type roundTripAllowance struct {
+	ResourceType string
+	Path         string
+	Value        interface{}
+	Reason       string
+}
+
+// roundTripAllowlist lists the known, intentional losses
+var roundTripAllowlist = []roundTripAllowance{
+	// Read-only API fields and export metadata
+	{"*", "_links", nil, "read-only API links"},
+	{"*", "createdAt", nil, "read-only timestamp"},
+	{"*", "updatedAt", nil, "read-only timestamp"},
+	{"pingone_davinci_flow", "companyId", nil, "export metadata"},
+	{"pingone_davinci_flow", "customerId", nil, "export metadata"},
+	{"pingone_davinci_flow", "createdDate", nil, "export metadata"},
+	{"pingone_davinci_flow", "deployedDate", nil, "export metadata"},
+	{"pingone_davinci_flow", "savedDate", nil, "export metadata"},
+	{"pingone_davinci_flow", "updatedDate", nil, "export metadata"},
+	{"pingone_davinci_flow", "publishedVersion", nil, "export metadata"},
+	{"pingone_davinci_flow", "versionId", nil, "export metadata"},
+	{"pingone_davinci_flow", "authTokenExpireIds", nil, "export metadata"},
+	{"pingone_davinci_flow", "orx", nil, "export metadata"},
+	{"pingone_davinci_flow", "timeouts", nil, "export metadata"},
+	{"pingone_davinci_flow", "isInputSchemaSaved", nil, "export metadata"},
+	{"pingone_davinci_flow", "isOutputSchemaSaved", nil, "export metadata"},
+	{"pingone_davinci_flow", "connectorIds", nil, "derived from the graph by the API"},
+	{"pingone_davinci_flow", "inputSchemaCompiled", nil, "compiled by the API from the input schema"},
+	{"pingone_davinci_flow", "connections", nil, "exported as connector instance resources"},
+	{"pingone_davinci_flow", "variables", nil, "exported as variable resources"},
+	{"pingone_davinci_flow", "forms", nil, "forms are not managed by the flow resource"},
+	{"pingone_davinci_flow", "enabledGraphData", nil, "the deployed graph follows graph_data through pingone_davinci_flow_deploy"},
+
+	// Fields outside the PingOne flow request model
+	{"pingone_davinci_flow", "settings.debugMode", nil, "dropped by filterFlowSettings"},
+	{"pingone_davinci_flow", "graphData.elements.nodes.*.data.isDisabled", nil, "not a node data field of the flow request model"},
+	{"pingone_davinci_flow", "graphData.elements.edges.*.data.multiValueSourceId", nil, "not an edge data field of the flow request model"},
+
+	// Values the converters normalize or omit
+	{"pingone_davinci_flow", "description", "", "empty descriptions are omitted"},
+	{"pingone_davinci_flow", "settings.jsLinks.*.defer", "false", "written as a bool"},
+	{"pingone_davinci_variable", "mutable", false, "mutable is written as true when no value is written (provider requirement)"},
+
+	// Secrets are never written
+	{"pingone_davinci_variable", "value", "******", "masked secret values are not written"},
+	{"pingone_davinci_connector_instance", "properties.*.value", "******", "masked secrets are written as module variables"},
+	{"pingone_davinci_application", "apiKey.value", nil, "the API key is generated by the provider"},
+	{"pingone_davinci_application", "oauth.clientSecret", nil, "the client secret is generated by the provider"},
+}
+
+// normalizeExportFields renames the fields of flow exports that have an API counterpart, the
+// fields the reader reconstructs
+func normalizeExportFields(resourceType string, payload map[string]interface{}) {
+	if resourceType != "pingone_davinci_flow" {
+		return
+	}
+	if color, ok := payload["flowColor"]; ok {
+		payload["color"] = color
+		delete(payload, "flowColor")
+	}
+	if status, ok := payload["flowStatus"].(string); ok {
+		switch strings.ToLower(status) {
+		case "enabled":
+			payload["enabled"] = true
+			delete(payload, "flowStatus")
+		case "disabled":
+			payload["enabled"] = false
+			delete(payload, "flowStatus")
+		}
+	}
+}
+
+// roundTripFixture is a testdata JSON fixture and the resource type it is converted as
+type roundTripFixture struct {
+	Path         string
+	ResourceType string
+}
+
+// roundTripFixtures returns every JSON fixture under testdata. Fixtures named after a resource
+// type, e.g. pingone_davinci_variable-secret.json, are of that type; all others are flows.
+func roundTripFixtures(t *testing.T) []roundTripFixture {
+	t.Helper()
+	var fixtures []roundTripFixture
+	err := filepath.WalkDir("testdata", func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		resourceType := "pingone_davinci_flow"
+		if name := strings.TrimSuffix(filepath.Base(path), ".json"); strings.HasPrefix(name, "pingone_davinci_") {
+			resourceType, _, _ = strings.Cut(name, "-")
+		}
+		fixtures = append(fixtures, roundTripFixture{Path: path, ResourceType: resourceType})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+	return fixtures
+}
+
+// convertFixture converts a fixture with its resource type's converter, skipping dependencies
+// so IDs are written as values. It returns the HCL and the import ID of the resource.
+func convertFixture(resourceType string, data []byte) (string, string, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", "", err
+	}
+	environmentID := roundTripEnvironmentID
+	if environment, ok := payload["environment"].(map[string]interface{}); ok && getString(environment, "id") != "" {
+		environmentID = getString(environment, "id")
+	}
+	importID := environmentID + "/" + getString(payload, "id")
+
+	switch resourceType {
+	case "pingone_davinci_flow":
+		hcl, err := ConvertFlowToHCL(payload, environmentID, true, nil)
+		return hcl, environmentID + "/" + getString(payload, "flowId"), err
+	case "pingone_davinci_variable":
+		hcl, err := ConvertVariableWithResourceName(data, true, "", nil)
+		return hcl, importID, err
+	case "pingone_davinci_connector_instance":
+		hcl, err := ConvertConnectorInstanceWithResourceName(data, true, "")
+		return hcl, importID, err
+	case "pingone_davinci_application":
+		hcl, err := ConvertApplicationWithEnvironment(data, environmentID)
+		return hcl, importID, err
+	case "pingone_davinci_application_flow_policy":
+		var policy pingone.DaVinciFlowPolicyResponse
+		if err := json.Unmarshal(data, &policy); err != nil {
+			return "", "", err
+		}
+		applicationID := policy.GetApplication().Id
+		resourceName := (*resolver.DependencyGraph)(nil).ResourceName(policy.GetId(), policy.GetName())
+		hcl, err := ConvertFlowPolicyToTerraform(policy, resourceName, applicationID, environmentID, true, nil)
+		return hcl, environmentID + "/" + applicationID + "/" + policy.GetId(), err
+	}
+	return "", "", fmt.Errorf("no converter for %s", resourceType)
+}
+
+// readFixtureHCL reads generated HCL back with an import block for the resource, as the
+// module generator writes one for each resource
+func readFixtureHCL(hcl, importID string) (ParsedResource, error) {
+	resources, err := ParseHCL([]byte(hcl), "generated.tf")
+	if err != nil {
+		return ParsedResource{}, err
+	}
+	if len(resources) != 1 {
+		return ParsedResource{}, fmt.Errorf("expected 1 resource, got %d", len(resources))
+	}
+	hcl += fmt.Sprintf("\nimport {\n  to = %s\n  id = %q\n}\n", resources[0].Address(), importID)
+	resources, err = ParseHCL([]byte(hcl), "generated.tf")
+	if err != nil {
+		return ParsedResource{}, err
+	}
+	return resources[0], nil
+}
+
+// fieldChange is a fixture field that did not survive the round trip
+type fieldChange struct {
+	Path     string
+	Lost     bool // Otherwise altered
+	Original interface{}
+	Read     interface{}
+}
+
+func (c fieldChange) String() string {
+	if c.Lost {
+		return fmt.Sprintf("%s: lost (was %s)", c.Path, formatFieldValue(c.Original))
+	}
+	return fmt.Sprintf("%s: altered from %s to %s", c.Path, formatFieldValue(c.Original), formatFieldValue(c.Read))
+}
+
+func formatFieldValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	if len(data) > 80 {
+		return string(data[:77]) + "..."
+	}
+	return string(data)
+}
+
+// compareRoundTrip returns the leaf fields of original that are missing or different in read.
+// Fields read adds, such as defaults the converters write, are not changes.
+func compareRoundTrip(original, read interface{}) []fieldChange {
+	originalFields := make(map[string]interface{})
+	readFields := make(map[string]interface{})
+	flattenFields(original, "", originalFields)
+	flattenFields(read, "", readFields)
+
+	var changes []fieldChange
+	for path, value := range originalFields {
+		readValue, ok := readFields[path]
+		switch {
+		case !ok:
+			changes = append(changes, fieldChange{Path: path, Lost: true, Original: value})
+		case !reflect.DeepEqual(value, readValue):
+			changes = append(changes, fieldChange{Path: path, Original: value, Read: readValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// flattenFields adds the leaf values of a JSON value to out, keyed by dotted path. Graph
+// elements are keyed by their data.id, as the converters order them by ID; other list items by
+// index. Empty objects and lists are leaves.
+func flattenFields(value interface{}, path string, out map[string]interface{}) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			out[path] = v
+			return
+		}
+		for key, item := range v {
+			flattenFields(item, join(key), out)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out[path] = v
+			return
+		}
+		for i, item := range v {
+			key := fmt.Sprint(i)
+			if element, ok := item.(map[string]interface{}); ok {
+				if data, ok := element["data"].(map[string]interface{}); ok && getString(data, "id") != "" {
+					key = getString(data, "id")
+				}
+			}
+			flattenFields(item, join(key), out)
+		}
+	default:
+		out[path] = v
+	}
+}
+
+// allowance returns the allowlist entry covering a change, or -1
+func allowance(resourceType string, change fieldChange) int {
+	segments := strings.Split(change.Path, ".")
+	for i, allowed := range roundTripAllowlist {
+		if allowed.ResourceType != "*" && allowed.ResourceType != resourceType {
+			continue
+		}
+		if allowed.Value != nil && !reflect.DeepEqual(allowed.Value, change.Original) {
+			continue
+		}
+		pattern := strings.Split(allowed.Path, ".")
+		if len(pattern) > len(segments) {
+			continue
+		}
+		matched := true
+		for j, segment := range pattern {
+			if segment != "*" && segment != segments[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
+
+// TestRoundTripFidelity converts every fixture to HCL and back and fails on any loss that is
+// not allowlisted, and on allowlist entries no fixture needs
+func TestRoundTripFidelity(t *testing.T) {
+	fixtures := roundTripFixtures(t)
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures found in testdata")
+	}
+
+	used := make([]bool, len(roundTripAllowlist))
+	ran := 0
+	for _, fixture := range fixtures {
+		t.Run(strings.TrimPrefix(filepath.ToSlash(fixture.Path), "testdata/"), func(t *testing.T) {
+			ran++
+			data, err := os.ReadFile(fixture.Path)
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+			hcl, importID, err := convertFixture(fixture.ResourceType, data)
+			if err != nil {
+				t.Fatalf("failed to convert fixture: %v", err)
+			}
+			resource, err := readFixtureHCL(hcl, importID)
+			if err != nil {
+				t.Fatalf("failed to read generated HCL: %v\n%s", err, hcl)
+			}
+
+			var original map[string]interface{}
+			if err := json.Unmarshal(data, &original); err != nil {
+				t.Fatalf("invalid fixture JSON: %v", err)
+			}
+			normalizeExportFields(fixture.ResourceType, original)
+			for _, change := range compareRoundTrip(original, resource.Payload) {
+				if i := allowance(fixture.ResourceType, change); i >= 0 {
+					used[i] = true
+					continue
+				}
+				t.Errorf("%s", change)
+			}
+		})
+	}
+
+	// Entries are only known to be unused when every fixture was compared
+	if ran < len(fixtures) || t.Failed() {
+		return
+	}
+	for i, allowed := range roundTripAllowlist {
+		if !used[i] {
+			t.Errorf("allowlist entry %s %s is not needed by any fixture; remove it", allowed.ResourceType, allowed.Path)
+		}
+	}
+}
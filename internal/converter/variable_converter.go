@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
@@ -34,24 +36,42 @@ func ConvertVariable(variableJSON []byte) (string, error) {
 
 // ConvertVariableWithOptions converts a variable with optional skip-dependencies flag
 func ConvertVariableWithOptions(variableJSON []byte, skipDependencies bool) (string, error) {
+	return ConvertVariableWithResourceName(variableJSON, skipDependencies, "", nil)
+}
+
+// ConvertVariableWithResourceName converts a variable using the given Terraform resource name,
+// e.g. the name registered in the dependency graph; an empty name is derived from the variable.
+// graph is optional; if provided, the flow of a flow-context variable is referenced through it.
+func ConvertVariableWithResourceName(variableJSON []byte, skipDependencies bool, resourceName string, graph *resolver.DependencyGraph) (string, error) {
+	resource, err := ConvertVariableToResource(variableJSON, skipDependencies, resourceName, graph)
+	if err != nil {
+		return "", err
+	}
+	return model.HCL(resource)
+}
+
+// ConvertVariableToResource converts a variable to a resource using the given Terraform
+// resource name; an empty name is derived from the variable. graph is optional; if provided,
+// the flow of a flow-context variable is referenced through it.
+func ConvertVariableToResource(variableJSON []byte, skipDependencies bool, resourceName string, graph *resolver.DependencyGraph) (*model.Resource, error) {
 	var variable VariableResponse
 	if err := json.Unmarshal(variableJSON, &variable); err != nil {
-		return "", fmt.Errorf("failed to parse variable JSON: %w", err)
+		return nil, fmt.Errorf("failed to parse variable JSON: %w", err)
 	}
 
 	if variable.Name == "" {
-		return "", fmt.Errorf("variable name is required")
+		return nil, fmt.Errorf("variable name is required")
 	}
 
 	if variable.Context == "" {
-		return "", fmt.Errorf("variable context is required")
+		return nil, fmt.Errorf("variable context is required")
 	}
 
 	if variable.DataType == "" {
-		return "", fmt.Errorf("variable data_type is required")
+		return nil, fmt.Errorf("variable data_type is required")
 	}
 
-	return generateVariableHCL(variable, skipDependencies), nil
+	return variableResource(variable, skipDependencies, resourceName, graph)
 }
 
 // GetVariableEligibleAttributes extracts variable-eligible attributes from a DaVinci variable
@@ -68,7 +88,7 @@ func GetVariableEligibleAttributes(variableJSON []byte, resourceName string) ([]
 
 	// Use provided resource name or sanitize from variable name and context
 	if resourceName == "" {
-		resourceName = utils.SanitizeMultiKeyResourceName(variable.Name, variable.Context)
+		resourceName = utils.DefaultNamingStrategy().ResourceName(variable.ID, variable.Name, variable.Context)
 	}
 
 	var attributes []VariableEligibleAttribute
@@ -144,27 +164,27 @@ func GetVariableEligibleAttributes(variableJSON []byte, resourceName string) ([]
 	return attributes, nil
 }
 
-// generateVariableHCL generates the Terraform HCL for a variable
-func generateVariableHCL(variable VariableResponse, skipDependencies bool) string {
-	var hcl strings.Builder
-
+// variableResource builds the resource of a variable. Values of secret variables are marked
+// sensitive.
+func variableResource(variable VariableResponse, skipDependencies bool, resourceName string, graph *resolver.DependencyGraph) (*model.Resource, error) {
 	// Resource name using pingcli format with context suffix to prevent duplicates
-	resourceName := utils.SanitizeMultiKeyResourceName(variable.Name, variable.Context)
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_variable\" \"%s\" {\n", resourceName))
+	if resourceName == "" {
+		resourceName = utils.DefaultNamingStrategy().ResourceName(variable.ID, variable.Name, variable.Context)
+	}
 
-	// Environment ID
-	if skipDependencies {
-		hcl.WriteString(fmt.Sprintf("  environment_id = \"%s\"\n", variable.Environment.ID))
-	} else {
-		hcl.WriteString("  environment_id = var.pingone_environment_id\n")
+	resource := model.NewResource("pingone_davinci_variable", resourceName)
+	resource.ID = variable.ID
+	body := &resource.Body
+	if err := setVariableEnvironment(body, variable, skipDependencies); err != nil {
+		return nil, err
 	}
 
-	hcl.WriteString("\n")
+	body.AppendNewline()
 
 	// Required attributes
-	hcl.WriteString(fmt.Sprintf("  name           = \"%s\"\n", variable.Name))
-	hcl.WriteString(fmt.Sprintf("  context        = \"%s\"\n", variable.Context))
-	hcl.WriteString(fmt.Sprintf("  data_type      = \"%s\"\n", variable.DataType))
+	body.SetAttribute("name", model.String(variable.Name))
+	body.SetAttribute("context", model.String(variable.Context))
+	body.SetAttribute("data_type", model.String(variable.DataType))
 
 	// Determine if we'll actually write a value (needed for mutable logic)
 	// Special handling: for secret data types, if API returns masked value ("******"),
@@ -194,75 +214,111 @@ func generateVariableHCL(variable VariableResponse, skipDependencies bool) strin
 	if !willWriteValue && !mutable {
 		mutable = true // Provider requires mutable=true when value is not set
 	}
-	hcl.WriteString(fmt.Sprintf("  mutable        = %t\n", mutable))
+	body.SetAttribute("mutable", model.Bool(mutable))
 
 	// Note if we overrode mutable
 	if !variable.Mutable && mutable {
-		hcl.WriteString("  # NOTE: mutable overridden to true because no value is provided (provider requirement)\n")
+		body.AppendCommentAbout("mutable", "NOTE: mutable overridden to true because no value is provided (provider requirement)")
 	} // Optional display_name
 	if variable.DisplayName != "" {
-		hcl.WriteString(fmt.Sprintf("  display_name   = \"%s\"\n", variable.DisplayName))
+		body.SetAttribute("display_name", model.String(variable.DisplayName))
 	}
 
 	// Optional min/max (for number type)
-	if variable.Min != nil {
-		hcl.WriteString(fmt.Sprintf("  min            = %d\n", *variable.Min))
-	}
-	if variable.Max != nil {
-		hcl.WriteString(fmt.Sprintf("  max            = %d\n", *variable.Max))
-	}
+	setVariableRange(body, variable)
 
 	// Flow reference (for flow context)
 	if variable.Flow != nil {
-		hcl.WriteString("\n")
-		hcl.WriteString("  flow = {\n")
-		if skipDependencies {
-			hcl.WriteString(fmt.Sprintf("    id = \"%s\"\n", variable.Flow.ID))
-		} else {
-			// TODO: Part 4 - resolve flow reference
-			hcl.WriteString(fmt.Sprintf("    id = \"%s\"  # TODO: Replace with flow reference\n", variable.Flow.ID))
+		if err := setVariableFlow(body, variable, resourceName, skipDependencies, graph); err != nil {
+			return nil, err
 		}
-		hcl.WriteString("  }\n")
 	}
 
 	// Value block (type-specific)
 	// Only write value block if variable actually has a meaningful value
 	// Never output secret values for security
 	valueWritten := false
+	body.AppendNewline()
 	if hasValue {
-		hcl.WriteString("\n")
 		// Write value based on actual runtime type of value, independent of data_type
-		valueWritten = writeVariableValueBlockFromActual(&hcl, variable.Value)
-
-		// If no value was actually written, add TODO comment
-		if !valueWritten {
-			if dt == "secret" {
-				hcl.WriteString("  # TODO: Add secret value manually\n")
-				hcl.WriteString("  # value = {\n")
-				hcl.WriteString("  #   secret_string = \"your-secret-value\"\n")
-				hcl.WriteString("  # }\n")
-			} else {
-				hcl.WriteString(fmt.Sprintf("  # TODO: Add %s value\n", variable.DataType))
-				hcl.WriteString("  # Value omitted - will be set dynamically by flow execution\n")
-			}
+		var err error
+		valueWritten, err = setVariableValueFromActual(body, variable.Value)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		// For variables without values, add a TODO comment
-		hcl.WriteString("\n")
-		if variable.DataType == "secret" {
-			hcl.WriteString("  # TODO: Add secret value manually\n")
-			hcl.WriteString("  # value = {\n")
-			hcl.WriteString("  #   secret_string = \"your-secret-value\"\n")
-			hcl.WriteString("  # }\n")
+	}
+	// For variables without values, add a TODO comment
+	if !valueWritten {
+		if dt == "secret" {
+			body.AppendCommentAbout("value", "TODO: Add secret value manually")
+			body.AppendCommentAbout("value", "value = {")
+			body.AppendCommentAbout("value", `  secret_string = "your-secret-value"`)
+			body.AppendCommentAbout("value", "}")
 		} else {
-			hcl.WriteString(fmt.Sprintf("  # TODO: Add %s value\n", variable.DataType))
-			hcl.WriteString("  # Value omitted - will be set dynamically by flow execution\n")
+			body.AppendCommentAbout("value", fmt.Sprintf("TODO: Add %s value", variable.DataType))
+			body.AppendCommentAbout("value", "Value omitted - will be set dynamically by flow execution")
 		}
 	}
+	if dt == "secret" {
+		resource.MarkSensitive(model.Path{"value"})
+	}
 
-	hcl.WriteString("}\n")
+	return resource, nil
+}
+
+// setVariableEnvironment sets the environment_id of a variable: the variable's own environment
+// when skipping dependencies, otherwise the module's environment variable
+func setVariableEnvironment(body *model.Body, variable VariableResponse, skipDependencies bool) error {
+	if skipDependencies {
+		body.SetAttribute("environment_id", model.String(variable.Environment.ID))
+		return nil
+	}
+	environment, err := environmentValue("var.pingone_environment_id")
+	if err != nil {
+		return err
+	}
+	body.SetAttribute("environment_id", environment)
+	return nil
+}
+
+// setVariableRange sets the optional min and max of a variable
+func setVariableRange(body *model.Body, variable VariableResponse) {
+	if variable.Min != nil {
+		body.SetAttribute("min", model.Int(int64(*variable.Min)))
+	}
+	if variable.Max != nil {
+		body.SetAttribute("max", model.Int(int64(*variable.Max)))
+	}
+}
 
-	return hcl.String()
+// setVariableFlow sets the flow of a flow-context variable. With a graph the flow is
+// referenced, falling back to a TODO placeholder that says why the flow is missing; without
+// one the flow ID is kept with a TODO.
+func setVariableFlow(body *model.Body, variable VariableResponse, resourceName string, skipDependencies bool, graph *resolver.DependencyGraph) error {
+	flow := &model.Object{}
+	switch {
+	case skipDependencies:
+		flow.Set("id", model.String(variable.Flow.ID))
+	case graph != nil:
+		if resourceName == "" {
+			resourceName = graph.ResourceName(variable.ID, variable.Name, variable.Context)
+		}
+		from := resolver.ResourceRef{Type: "pingone_davinci_variable", ID: variable.ID, Name: resourceName}
+		ref := resolver.ResolveReference(graph, from, "pingone_davinci_flow", variable.Flow.ID, "id", "flow.id", "flow.id")
+		value, err := model.ParseExpression(ref)
+		if err != nil {
+			return fmt.Errorf("failed to write flow of variable %s: %w", variable.Name, err)
+		}
+		flow.Set("id", value)
+		if to, err := graph.GetResource("pingone_davinci_flow", variable.Flow.ID); err == nil {
+			addDependencyOnce(graph, from, to, "flow_id", "flow.id")
+		}
+	default:
+		flow.Set("id", model.WithComment(model.String(variable.Flow.ID), "TODO: Replace with flow reference"))
+	}
+	body.AppendNewline()
+	body.SetAttribute("flow", flow)
+	return nil
 }
 
 // isEmptyValue checks if a value is considered empty
@@ -298,51 +354,49 @@ func canWriteValueFromActual(value interface{}) bool {
 	}
 }
 
-// writeVariableValueBlock writes the value block based on data type
+// setVariableValueFromActual sets the value object based on the value's runtime type
 // Returns true if a value was written, false if nothing was written
-func writeVariableValueBlockFromActual(hcl *strings.Builder, value interface{}) bool {
-	var valueContent strings.Builder
-	hasContent := false
+func setVariableValueFromActual(body *model.Body, value interface{}) (bool, error) {
+	object := &model.Object{}
 
 	switch v := value.(type) {
 	case string:
 		if v != "" {
-			valueContent.WriteString(fmt.Sprintf("    string = \"%s\"\n", v))
-			hasContent = true
+			object.Set("string", model.String(v))
 		}
 	case bool:
-		valueContent.WriteString(fmt.Sprintf("    bool = %t\n", v))
-		hasContent = true
+		object.Set("bool", model.Bool(v))
 	case float64:
-		if v == float64(int64(v)) {
-			valueContent.WriteString(fmt.Sprintf("    float32 = %d\n", int64(v)))
-		} else {
-			valueContent.WriteString(fmt.Sprintf("    float32 = %f\n", v))
-		}
-		hasContent = true
+		object.Set("float32", model.Number(v))
 	case int:
-		valueContent.WriteString(fmt.Sprintf("    float32 = %d\n", v))
-		hasContent = true
+		object.Set("float32", model.Int(int64(v)))
 	case map[string]interface{}, []interface{}:
-		jsonBytes, err := json.Marshal(v)
-		if err == nil && len(jsonBytes) > 2 {
-			valueContent.WriteString(fmt.Sprintf("    json_object = %s\n", string(jsonBytes)))
-			hasContent = true
+		if canWriteValueFromActual(v) {
+			json, err := jsonValue(v)
+			if err != nil {
+				return false, fmt.Errorf("failed to write variable value: %w", err)
+			}
+			object.Set("json_object", model.JSONEncode(json))
 		}
 	}
 
-	if hasContent {
-		hcl.WriteString("  value = {\n")
-		hcl.WriteString(valueContent.String())
-		hcl.WriteString("  }\n")
+	if object.Len() == 0 {
+		return false, nil
 	}
-
-	return hasContent
+	body.SetAttribute("value", object)
+	return true, nil
 }
 
 // GenerateVariableHCLWithVariableReferences generates HCL with variable references instead of hardcoded values
 // This is used for module generation where values are parameterized
 func GenerateVariableHCLWithVariableReferences(variableJSON []byte, skipDependencies bool, variableName string) (string, error) {
+	return GenerateVariableHCLWithResourceName(variableJSON, skipDependencies, variableName, "", nil)
+}
+
+// GenerateVariableHCLWithResourceName generates HCL with variable references using the given
+// Terraform resource name; an empty name is derived from the variable. graph is optional; if
+// provided, the flow of a flow-context variable is referenced through it.
+func GenerateVariableHCLWithResourceName(variableJSON []byte, skipDependencies bool, variableName, resourceName string, graph *resolver.DependencyGraph) (string, error) {
 	var variable VariableResponse
 	if err := json.Unmarshal(variableJSON, &variable); err != nil {
 		return "", fmt.Errorf("failed to parse variable JSON: %w", err)
@@ -352,101 +406,80 @@ func GenerateVariableHCLWithVariableReferences(variableJSON []byte, skipDependen
 		return "", fmt.Errorf("variable name is required")
 	}
 
-	return generateVariableHCLWithVarReference(variable, skipDependencies, variableName), nil
+	return generateVariableHCLWithVarReference(variable, skipDependencies, variableName, resourceName, graph)
 }
 
 // generateVariableHCLWithVarReference generates HCL using var.{name} for the value attribute
-func generateVariableHCLWithVarReference(variable VariableResponse, skipDependencies bool, varName string) string {
-	var hcl strings.Builder
-
+func generateVariableHCLWithVarReference(variable VariableResponse, skipDependencies bool, varName, resourceName string, graph *resolver.DependencyGraph) (string, error) {
 	// Resource name using pingcli format with context suffix to prevent duplicates
-	resourceName := utils.SanitizeMultiKeyResourceName(variable.Name, variable.Context)
-	hcl.WriteString(fmt.Sprintf("resource \"pingone_davinci_variable\" \"%s\" {\n", resourceName))
+	if resourceName == "" {
+		resourceName = utils.DefaultNamingStrategy().ResourceName(variable.ID, variable.Name, variable.Context)
+	}
 
-	// Environment ID
-	if skipDependencies {
-		hcl.WriteString(fmt.Sprintf("  environment_id = \"%s\"\n", variable.Environment.ID))
-	} else {
-		hcl.WriteString("  environment_id = var.pingone_environment_id\n")
+	resource := model.NewResource("pingone_davinci_variable", resourceName)
+	resource.ID = variable.ID
+	body := &resource.Body
+	if err := setVariableEnvironment(body, variable, skipDependencies); err != nil {
+		return "", err
 	}
 
-	hcl.WriteString("\n")
+	body.AppendNewline()
 
 	// Required attributes (always hardcoded)
-	hcl.WriteString(fmt.Sprintf("  name           = \"%s\"\n", variable.Name))
-	hcl.WriteString(fmt.Sprintf("  context        = \"%s\"\n", variable.Context))
-	hcl.WriteString(fmt.Sprintf("  data_type      = \"%s\"\n", variable.DataType))
+	body.SetAttribute("name", model.String(variable.Name))
+	body.SetAttribute("context", model.String(variable.Context))
+	body.SetAttribute("data_type", model.String(variable.DataType))
 
 	// Optional display_name
 	if variable.DisplayName != "" {
-		hcl.WriteString(fmt.Sprintf("  display_name   = \"%s\"\n", variable.DisplayName))
+		body.SetAttribute("display_name", model.String(variable.DisplayName))
 	}
 
 	// Value - use variable reference instead of hardcoded value
 	hasValue := variable.Value != nil && !isEmptyValue(variable.Value)
 
 	if (hasValue) && varName != "" {
-		hcl.WriteString("\n")
+		ref, err := model.Var(varName)
+		if err != nil {
+			return "", err
+		}
+
 		// Infer the key from the actual value's runtime type (not data_type)
 		// Special-case: secrets should use secret_string key per provider schema
+		key := "string"
 		if variable.DataType == "secret" {
-			hcl.WriteString("  value = {\n")
-			hcl.WriteString(fmt.Sprintf("    secret_string = var.%s\n", varName))
-			hcl.WriteString("  }\n")
+			key = "secret_string"
 		} else {
-		switch v := variable.Value.(type) {
-		case string:
-			hcl.WriteString("  value = {\n")
-			hcl.WriteString(fmt.Sprintf("    string = var.%s\n", varName))
-			hcl.WriteString("  }\n")
-		case bool:
-			hcl.WriteString("  value = {\n")
-			hcl.WriteString(fmt.Sprintf("    bool = var.%s\n", varName))
-			hcl.WriteString("  }\n")
-		case float64:
-			hcl.WriteString("  value = {\n")
-			hcl.WriteString(fmt.Sprintf("    float32 = var.%s\n", varName))
-			hcl.WriteString("  }\n")
-		case int:
-			hcl.WriteString("  value = {\n")
-			hcl.WriteString(fmt.Sprintf("    float32 = var.%s\n", varName))
-			hcl.WriteString("  }\n")
-		case map[string]interface{}, []interface{}:
-			hcl.WriteString("  value = {\n")
-			hcl.WriteString(fmt.Sprintf("    json_object = var.%s\n", varName))
-			hcl.WriteString("  }\n")
-		default:
-			// Fallback to string typing for unknown types
-			_ = v
-			hcl.WriteString("  value = {\n")
-			hcl.WriteString(fmt.Sprintf("    string = var.%s\n", varName))
-			hcl.WriteString("  }\n")
-		}
+			switch variable.Value.(type) {
+			case bool:
+				key = "bool"
+			case float64, int:
+				key = "float32"
+			case map[string]interface{}, []interface{}:
+				key = "json_object"
+			default:
+				// Strings, and unknown types fall back to string typing
+			}
 		}
+
+		value := &model.Object{}
+		value.Set(key, ref)
+		body.AppendNewline()
+		body.SetAttribute("value", value)
 	}
 
 	// Mutable
-	hcl.WriteString(fmt.Sprintf("  mutable        = %t\n", variable.Mutable))
+	body.SetAttribute("mutable", model.Bool(variable.Mutable))
 
 	// Optional: Min/Max
-	if variable.Min != nil {
-		hcl.WriteString(fmt.Sprintf("  min            = %d\n", *variable.Min))
-	}
-	if variable.Max != nil {
-		hcl.WriteString(fmt.Sprintf("  max            = %d\n", *variable.Max))
-	}
+	setVariableRange(body, variable)
 
 	// Optional: Flow dependency
 	if variable.Flow != nil && variable.Flow.ID != "" {
-		hcl.WriteString("\n")
-		if skipDependencies {
-			hcl.WriteString(fmt.Sprintf("  flow_id = \"%s\"\n", variable.Flow.ID))
-		} else {
-			hcl.WriteString("  # flow_id will be resolved via dependency graph\n")
+		if err := setVariableFlow(body, variable, resourceName, skipDependencies, graph); err != nil {
+			return "", err
 		}
 	}
 
-	hcl.WriteString("}\n")
-
-	return hcl.String()
+	return model.HCL(resource)
 }
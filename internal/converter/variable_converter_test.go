@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
 )
 
 func TestConvertVariable_ValueTypingFromActualValue_StringFalseWithBooleanDataType(t *testing.T) {
@@ -32,7 +34,7 @@ func TestConvertVariable_ValueTypingFromActualValue_StringFalseWithBooleanDataTy
 		t.Errorf("expected string-typed value, got:\n%s", hcl)
 	}
 	// Ensure data_type still present
-	if !strings.Contains(hcl, "data_type      = \"boolean\"") {
+	if !strings.Contains(hcl, "data_type = \"boolean\"") {
 		t.Errorf("expected data_type to be preserved, got:\n%s", hcl)
 	}
 }
@@ -101,10 +103,73 @@ func TestGenerateVariableHCLWithVarReference_UsesActualTypeForKey(t *testing.T)
 		Max:      intPtr(2000),
 	}
 
-	hcl := generateVariableHCLWithVarReference(varResp, false, "davinci_variable_ciam_mobilePushOtpEnabled_company_value")
+	hcl, err := generateVariableHCLWithVarReference(varResp, false, "davinci_variable_ciam_mobilePushOtpEnabled_company_value", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	// Expect the value key to be string, not bool
 	if !strings.Contains(hcl, "value = {\n    string = var.davinci_variable_ciam_mobilePushOtpEnabled_company_value\n  }") {
 		t.Errorf("expected string key for var reference, got:\n%s", hcl)
 	}
 }
+
+func TestConvertVariable_FlowContextReferencesFlow(t *testing.T) {
+	variableJSON := []byte(`{
+		"id": "var-1",
+		"environment": {"id": "env-1"},
+		"name": "attempts",
+		"dataType": "number",
+		"context": "flow",
+		"mutable": true,
+		"flow": {"id": "flow-1"}
+	}`)
+
+	graph := resolver.NewDependencyGraph()
+	graph.AddResource("pingone_davinci_flow", "flow-1", "login")
+	graph.AddResource("pingone_davinci_variable", "var-1", "attempts_flow")
+
+	// Both generators reference the flow and record the edge once
+	hcl, err := ConvertVariableWithResourceName(variableJSON, false, "attempts_flow", graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	moduleHCL, err := GenerateVariableHCLWithResourceName(variableJSON, false, "attempts_value", "attempts_flow", graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, out := range []string{hcl, moduleHCL} {
+		if !strings.Contains(out, "  flow = {\n    id = pingone_davinci_flow.login.id\n  }\n") {
+			t.Errorf("expected flow reference, got:\n%s", out)
+		}
+	}
+	deps := graph.GetDependencies("var-1")
+	if len(deps) != 1 || deps[0].To.Name != "login" || deps[0].Field != "flow_id" {
+		t.Errorf("expected one flow_id dependency on login, got %v", deps)
+	}
+
+	// A flow missing from the graph keeps a TODO that says why
+	tracker := resolver.NewMissingDependencyTracker()
+	tracker.SetIncludedTypes([]string{"pingone_davinci_variable"})
+	missingGraph := resolver.NewDependencyGraph()
+	missingGraph.SetMissingTracker(tracker)
+	hcl, err = ConvertVariableWithResourceName(variableJSON, false, "attempts_flow", missingGraph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(hcl, `id = "" # TODO: Reference to (pingone_davinci_flow flow-1) was not included in export filters`) {
+		t.Errorf("expected reason-annotated TODO, got:\n%s", hcl)
+	}
+	if len(tracker.GetMissing()) != 1 {
+		t.Errorf("expected the missing flow to be recorded, got %v", tracker.GetMissing())
+	}
+
+	// Skipping dependencies keeps the ID
+	hcl, err = ConvertVariableWithResourceName(variableJSON, true, "attempts_flow", graph)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(hcl, `id = "flow-1"`) {
+		t.Errorf("expected hardcoded flow ID, got:\n%s", hcl)
+	}
+}
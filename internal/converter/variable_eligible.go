@@ -1,6 +1,12 @@
 package converter
 
-import "github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
+)
 
 // VariableEligibleAttribute represents a resource attribute that can become a module variable
 type VariableEligibleAttribute struct {
@@ -22,7 +28,7 @@ type VariableEligibleAttribute struct {
 	// VariableName is the computed module variable name (e.g., "davinci_variable_company_name_value")
 	VariableName string
 
-	// VariableType is the Terraform type ("string", "number", "bool")
+	// VariableType is the Terraform type ("string", "number", "bool", "list(string)")
 	VariableType string
 
 	// Description for the variable
@@ -55,6 +61,24 @@ type VariableExtractor interface {
 	GetVariableEligibleAttributes(resourceJSON []byte, resourceName string) ([]VariableEligibleAttribute, error)
 }
 
+// VariableExtractorFunc adapts an extraction function to the VariableExtractor interface
+type VariableExtractorFunc func(resourceJSON []byte, resourceName string) ([]VariableEligibleAttribute, error)
+
+// GetVariableEligibleAttributes calls f
+func (f VariableExtractorFunc) GetVariableEligibleAttributes(resourceJSON []byte, resourceName string) ([]VariableEligibleAttribute, error) {
+	return f(resourceJSON, resourceName)
+}
+
+// VariableExtractors maps the ResourceType of variable-eligible attributes to the extractor of
+// the resources holding them
+var VariableExtractors = map[string]VariableExtractor{
+	"variable":    VariableExtractorFunc(GetVariableEligibleAttributes),
+	"connection":  VariableExtractorFunc(GetConnectorInstanceVariableEligibleAttributes),
+	"flow":        VariableExtractorFunc(GetFlowVariableEligibleAttributes),
+	"application": VariableExtractorFunc(GetApplicationVariableEligibleAttributes),
+	"flow_policy": VariableExtractorFunc(GetFlowPolicyVariableEligibleAttributes),
+}
+
 // AttributeExtractionContext provides context for variable extraction decisions
 type AttributeExtractionContext struct {
 	// IncludeAllPrimitives extracts all primitive-type attributes as variables
@@ -141,3 +165,142 @@ func NewHCLVariableReplacer(attributes []VariableEligibleAttribute) *HCLVariable
 
 	return replacer
 }
+
+// variableReferenceResourceTypes maps the ResourceType of variable-eligible attributes to the
+// Terraform resource type whose values they replace
+var variableReferenceResourceTypes = map[string]string{
+	"variable":    "pingone_davinci_variable",
+	"connection":  "pingone_davinci_connector_instance",
+	"flow":        "pingone_davinci_flow",
+	"application": "pingone_davinci_application",
+	"flow_policy": "pingone_davinci_application_flow_policy",
+}
+
+// Target returns the address of the resource holding the attribute and the path of the value
+// its module variable replaces
+func (v *VariableEligibleAttribute) Target() (string, model.Path, error) {
+	resourceType, ok := variableReferenceResourceTypes[v.ResourceType]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported resource type %q for variable %s", v.ResourceType, v.VariableName)
+	}
+	address := resourceType + "." + v.ResourceName
+
+	switch v.ResourceType {
+	case "connection":
+		// Properties keep their type/value structure
+		property, ok := strings.CutPrefix(v.AttributePath, "properties.")
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported attribute path %q for variable %s", v.AttributePath, v.VariableName)
+		}
+		return address, model.Path{"properties", property, "value"}, nil
+	default:
+		return address, model.ParsePath(v.AttributePath), nil
+	}
+}
+
+// ApplyVariableReferences replaces the values of variable-eligible attributes in resources with
+// references to their module variables
+func ApplyVariableReferences(resources []*model.Resource, attributes []VariableEligibleAttribute) error {
+	byAddress := make(map[string]*model.Resource, len(resources))
+	for _, resource := range resources {
+		byAddress[resource.Address()] = resource
+	}
+
+	for _, attr := range attributes {
+		address, path, err := attr.Target()
+		if err != nil {
+			return err
+		}
+		resource := byAddress[address]
+		if resource == nil {
+			return fmt.Errorf("missing resource %s for variable %s", address, attr.VariableName)
+		}
+
+		switch attr.ResourceType {
+		case "variable":
+			err = applyVariableValueReference(resource, attr)
+		case "connection":
+			// Properties are written with jsonencode(), so the variable is interpolated
+			var ref model.Value
+			if ref, err = model.VarInterpolation(attr.VariableName); err == nil {
+				err = resource.Set(path, ref)
+			}
+		default:
+			var ref model.Value
+			if ref, err = model.Var(attr.VariableName); err == nil {
+				err = resource.Set(path, ref)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reference variable %s: %w", attr.VariableName, err)
+		}
+	}
+	return nil
+}
+
+// applyVariableValueReference sets the value of a DaVinci variable to the module variable,
+// keyed by the variable's type: secret_string for secrets, otherwise the key matching the
+// module variable's Terraform type
+func applyVariableValueReference(resource *model.Resource, attr VariableEligibleAttribute) error {
+	ref, err := model.Var(attr.VariableName)
+	if err != nil {
+		return err
+	}
+
+	key := "string"
+	switch {
+	case isStringLiteral(resource, "data_type", "secret"):
+		key = "secret_string"
+	case attr.VariableType == "bool":
+		key = "bool"
+	case attr.VariableType == "number":
+		key = "float32"
+	}
+
+	value := &model.Object{}
+	value.Set(key, ref)
+	resource.Body.SetAttribute("value", value)
+
+	// The variable now has a value, so mutable no longer has to be overridden to true
+	if resource.Body.HasCommentAbout("mutable") {
+		resource.Body.SetAttribute("mutable", model.Bool(false))
+	}
+	return nil
+}
+
+// isStringLiteral reports whether the attribute name of resource is the string literal want
+func isStringLiteral(resource *model.Resource, name, want string) bool {
+	value, ok := resource.Get(model.Path{name})
+	if !ok {
+		return false
+	}
+	s, ok := model.AsString(value)
+	return ok && s == want
+}
+
+// eligibleValue returns a payload value as the value of a module variable, with its Terraform
+// type. Empty strings and lists, and lists of anything but strings, are not eligible.
+func eligibleValue(value interface{}) (interface{}, string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, "string", v != ""
+	case bool:
+		return v, "bool", true
+	case float64:
+		return v, "number", true
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, "", false
+		}
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, "", false
+			}
+			items = append(items, s)
+		}
+		return items, "list(string)", true
+	}
+	return nil, "", false
+}
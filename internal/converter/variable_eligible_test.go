@@ -1,8 +1,12 @@
 package converter
 
 import (
+	"encoding/json"
+	"os"
 	"testing"
 
+	"github.com/pingidentity/pingone-go-client/pingone"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -147,12 +151,12 @@ func TestGenerateVariableHCLWithVariableReferences(t *testing.T) {
 			expectedHCL: []string{
 				`resource "pingone_davinci_variable" "pingcli__companyName_company"`,
 				`environment_id = var.pingone_environment_id`,
-				`name           = "companyName"`,
-				`context        = "company"`,
-				`data_type      = "string"`,
-				`display_name   = "Company Name"`,
+				`name         = "companyName"`,
+				`context      = "company"`,
+				`data_type    = "string"`,
+				`display_name = "Company Name"`,
 				`string = var.davinci_variable_companyName_value`,
-				`mutable        = true`,
+				`mutable = true`,
 			},
 			notExpected: []string{
 				`"Acme Corp"`, // Hardcoded value should not appear
@@ -172,7 +176,7 @@ func TestGenerateVariableHCLWithVariableReferences(t *testing.T) {
 			varName: "davinci_variable_sessionTimeout_value",
 			expectedHCL: []string{
 				`float32 = var.davinci_variable_sessionTimeout_value`,
-				`mutable        = false`,
+				`mutable = false`,
 			},
 			notExpected: []string{
 				`300`, // Hardcoded number should not appear as literal
@@ -208,7 +212,7 @@ func TestGenerateVariableHCLWithVariableReferences(t *testing.T) {
 			}`,
 			varName: "davinci_variable_samplesecretvar_company_value",
 			expectedHCL: []string{
-				`data_type      = "secret"`,
+				`data_type    = "secret"`,
 				`secret_string = var.davinci_variable_samplesecretvar_company_value`,
 			},
 			notExpected: []string{
@@ -435,6 +439,177 @@ func TestGenerateConnectorInstanceHCLWithVariableReferences(t *testing.T) {
 	// Should have proper structure
 	assert.Contains(t, hcl, `resource "pingone_davinci_connector_instance" "pingcli__httpConnector"`)
 	assert.Contains(t, hcl, "environment_id = var.pingone_environment_id")
-	assert.Contains(t, hcl, `name           = "httpConnector"`)
+	assert.Contains(t, hcl, `name = "httpConnector"`)
 	assert.Contains(t, hcl, "properties = jsonencode")
 }
+
+func TestApplyVariableReferences(t *testing.T) {
+	variableJSON := []byte(`{
+		"id": "var-123",
+		"environment": {"id": "env-123"},
+		"name": "companyName",
+		"dataType": "string",
+		"context": "company",
+		"value": "Acme Corp",
+		"mutable": true
+	}`)
+	instanceJSON := []byte(`{
+		"id": "conn-123",
+		"environment": {"id": "env-123"},
+		"connector": {"id": "httpConnector"},
+		"name": "httpConnector",
+		"properties": {
+			"baseUrl": {"type": "string", "value": "https://api.example.com"},
+			"timeout": {"type": "number", "value": 30}
+		}
+	}`)
+
+	variable, err := ConvertVariableToResource(variableJSON, false, "", nil)
+	require.NoError(t, err)
+	connector, err := ConvertConnectorInstanceToResource(instanceJSON, false, "")
+	require.NoError(t, err)
+
+	variableAttrs, err := GetVariableEligibleAttributes(variableJSON, variable.Name)
+	require.NoError(t, err)
+	connectorAttrs, err := GetConnectorInstanceVariableEligibleAttributes(instanceJSON, connector.Name)
+	require.NoError(t, err)
+	attributes := append(variableAttrs, connectorAttrs...)
+	require.NotEmpty(t, connectorAttrs)
+
+	resources := []*model.Resource{variable, connector}
+	require.NoError(t, ApplyVariableReferences(resources, attributes))
+
+	hcl, err := model.HCL(resources...)
+	require.NoError(t, err)
+	assert.Contains(t, hcl, "string = var."+variableAttrs[0].VariableName)
+	assert.NotContains(t, hcl, "Acme Corp")
+	assert.Contains(t, hcl, `"${var.`+connectorAttrs[0].VariableName+`}"`)
+	assert.NotContains(t, hcl, "https://api.example.com")
+
+	t.Run("Missing resource", func(t *testing.T) {
+		err := ApplyVariableReferences([]*model.Resource{connector}, variableAttrs)
+		assert.ErrorContains(t, err, "missing resource pingone_davinci_variable."+variable.Name)
+	})
+}
+
+func TestGetFlowVariableEligibleAttributes(t *testing.T) {
+	flowJSON := []byte(`{
+		"flowId": "flow-123",
+		"name": "Login",
+		"settings": {
+			"cssLinks": ["https://cdn.example.com/login.css"],
+			"customFaviconLink": "https://cdn.example.com/favicon.ico",
+			"customErrorScreenBrandLogoUrl": "",
+			"logLevel": 2
+		},
+		"graphData": {"elements": {"nodes": [], "edges": []}}
+	}`)
+
+	attrs, err := GetFlowVariableEligibleAttributes(flowJSON, "pingcli__Login")
+	require.NoError(t, err)
+	require.Len(t, attrs, 2)
+
+	assert.Equal(t, "flow", attrs[0].ResourceType)
+	assert.Equal(t, "settings.css_links", attrs[0].AttributePath)
+	assert.Equal(t, "davinci_flow_Login_css_links", attrs[0].VariableName)
+	assert.Equal(t, "list(string)", attrs[0].VariableType)
+	assert.Equal(t, []string{"https://cdn.example.com/login.css"}, attrs[0].CurrentValue)
+
+	assert.Equal(t, "settings.custom_favicon_link", attrs[1].AttributePath)
+	assert.Equal(t, "davinci_flow_Login_custom_favicon_link", attrs[1].VariableName)
+	assert.Equal(t, "string", attrs[1].VariableType)
+
+	// The settings become references in the flow resource
+	var flowData map[string]interface{}
+	require.NoError(t, json.Unmarshal(flowJSON, &flowData))
+	resources, err := ConvertFlowToResources(flowData, "var.pingone_environment_id", false, nil)
+	require.NoError(t, err)
+	require.NoError(t, ApplyVariableReferences(resources, attrs))
+
+	hcl, err := model.HCL(resources[0])
+	require.NoError(t, err)
+	assert.Contains(t, hcl, "= var.davinci_flow_Login_css_links\n")
+	assert.Contains(t, hcl, "= var.davinci_flow_Login_custom_favicon_link\n")
+	assert.NotContains(t, hcl, "https://cdn.example.com")
+}
+
+// TestFlowSettingVariables_AreFlowSettings verifies every flow setting extracted as a variable
+// is a setting of the flow settings model, so none is silently never found
+func TestFlowSettingVariables_AreFlowSettings(t *testing.T) {
+	allowed := getAllowedFlowSettingsKeys()
+	for _, key := range flowSettingVariables {
+		assert.Contains(t, allowed, key)
+		assert.Contains(t, flowSettingsFieldNames, key)
+	}
+}
+
+func TestGetApplicationVariableEligibleAttributes(t *testing.T) {
+	appJSON, err := os.ReadFile("testdata/api_responses/pingone_davinci_application.json")
+	require.NoError(t, err)
+
+	attrs, err := GetApplicationVariableEligibleAttributes(appJSON, "")
+	require.NoError(t, err)
+	require.Len(t, attrs, 1)
+
+	attr := attrs[0]
+	assert.Equal(t, "application", attr.ResourceType)
+	assert.Equal(t, "pingcli__reCAPTCHA-0020-Sample-0020-Application", attr.ResourceName)
+	assert.Equal(t, "oauth.redirect_uris", attr.AttributePath)
+	assert.Equal(t, "davinci_application_reCAPTCHA-0020-Sample-0020-Application_redirect_uris", attr.VariableName)
+	assert.Equal(t, "list(string)", attr.VariableType)
+	assert.Equal(t, []string{"https://example.com/callback"}, attr.CurrentValue)
+	assert.False(t, attr.Sensitive)
+
+	resource, err := ConvertApplicationToResource(appJSON, "var.pingone_environment_id", nil)
+	require.NoError(t, err)
+	require.NoError(t, ApplyVariableReferences([]*model.Resource{resource}, attrs))
+
+	hcl, err := model.HCL(resource)
+	require.NoError(t, err)
+	assert.Contains(t, hcl, "redirect_uris = var."+attr.VariableName)
+	assert.Contains(t, hcl, `scopes        = ["openid", "profile"]`)
+	assert.NotContains(t, hcl, "https://example.com/callback")
+
+	t.Run("Without OAuth", func(t *testing.T) {
+		attrs, err := GetApplicationVariableEligibleAttributes([]byte(`{"id": "app-1", "name": "App"}`), "")
+		require.NoError(t, err)
+		assert.Empty(t, attrs)
+	})
+}
+
+func TestGetFlowPolicyVariableEligibleAttributes(t *testing.T) {
+	policyJSON, err := os.ReadFile("testdata/api_responses/pingone_davinci_application_flow_policy.json")
+	require.NoError(t, err)
+
+	attrs, err := GetFlowPolicyVariableEligibleAttributes(policyJSON, "pingcli__Policy")
+	require.NoError(t, err)
+	require.Len(t, attrs, 1)
+
+	attr := attrs[0]
+	assert.Equal(t, "flow_policy", attr.ResourceType)
+	assert.Equal(t, "flow_distributions.0.weight", attr.AttributePath)
+	assert.Equal(t, "davinci_flow_policy_Policy_weight_0", attr.VariableName)
+	assert.Equal(t, "number", attr.VariableType)
+	assert.Equal(t, int64(100), attr.CurrentValue)
+
+	var policy pingone.DaVinciFlowPolicyResponse
+	require.NoError(t, json.Unmarshal(policyJSON, &policy))
+	resource, err := ConvertFlowPolicyToResource(policy, "pingcli__Policy", "app-1", "var.pingone_environment_id", true, nil)
+	require.NoError(t, err)
+	require.NoError(t, ApplyVariableReferences([]*model.Resource{resource}, attrs))
+
+	hcl, err := model.HCL(resource)
+	require.NoError(t, err)
+	assert.Contains(t, hcl, "weight  = var.davinci_flow_policy_Policy_weight_0")
+}
+
+func TestVariableExtractors(t *testing.T) {
+	// Every resource type that takes variable references has an extractor
+	for resourceType := range variableReferenceResourceTypes {
+		assert.Contains(t, VariableExtractors, resourceType)
+	}
+
+	attrs, err := VariableExtractors["flow_policy"].GetVariableEligibleAttributes([]byte(`{}`), "")
+	assert.Error(t, err)
+	assert.Empty(t, attrs)
+}
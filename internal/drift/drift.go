@@ -0,0 +1,188 @@
+// Copyright © 2025 Ping Identity Corporation
+
+// Package drift compares a previously generated Terraform module with a fresh export of the
+// same environment and reports the resources and attributes that changed in between.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeType describes how a resource or attribute differs between the module and the environment
+type ChangeType string
+
+const (
+	// Added resources or attributes exist in the environment but not in the module
+	Added ChangeType = "added"
+	// Removed resources or attributes exist in the module but no longer in the environment
+	Removed ChangeType = "removed"
+	// Changed resources or attributes exist in both with different values
+	Changed ChangeType = "changed"
+)
+
+// AttributeChange is a difference in one attribute path of a resource
+type AttributeChange struct {
+	Path   string     `json:"path"`
+	Change ChangeType `json:"change"`
+	Module string     `json:"module,omitempty"` // Value in the module
+	Live   string     `json:"live,omitempty"`   // Value in the environment
+}
+
+// ResourceDrift is a difference in one resource
+type ResourceDrift struct {
+	Address    string            `json:"address"`
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Change     ChangeType        `json:"change"`
+	Attributes []AttributeChange `json:"attributes,omitempty"`
+}
+
+// Report is the result of comparing a module with the environment
+type Report struct {
+	ModuleDir string          `json:"module_dir"`
+	Resources []ResourceDrift `json:"resources"`
+	Added     int             `json:"added"`
+	Removed   int             `json:"removed"`
+	Changed   int             `json:"changed"`
+	Unchanged int             `json:"unchanged"`
+}
+
+// HasDrift reports whether any resource differs
+func (r *Report) HasDrift() bool {
+	return len(r.Resources) > 0
+}
+
+// Compare returns the differences between the existing module and a module generated from
+// the live environment. Resources are matched by address and reported in address order.
+// Attributes that reference the same input variable are compared by the variable values when
+// both are known, so connector property and variable value edits are reported too.
+func Compare(existing, live *Module) *Report {
+	report := &Report{ModuleDir: existing.Dir, Resources: []ResourceDrift{}}
+
+	for _, address := range unionKeys(existing.Resources, live.Resources) {
+		moduleResource, inModule := existing.Resources[address]
+		liveResource, inLive := live.Resources[address]
+
+		switch {
+		case !inModule:
+			report.Added++
+			report.Resources = append(report.Resources, ResourceDrift{Address: address, Type: liveResource.Type, Name: liveResource.Name, Change: Added})
+		case !inLive:
+			report.Removed++
+			report.Resources = append(report.Resources, ResourceDrift{Address: address, Type: moduleResource.Type, Name: moduleResource.Name, Change: Removed})
+		default:
+			changes := compareAttributes(moduleResource, liveResource, existing.Values, live.Values)
+			if len(changes) == 0 {
+				report.Unchanged++
+				continue
+			}
+			report.Changed++
+			report.Resources = append(report.Resources, ResourceDrift{Address: address, Type: moduleResource.Type, Name: moduleResource.Name, Change: Changed, Attributes: changes})
+		}
+	}
+
+	return report
+}
+
+// compareAttributes returns the attribute differences of a resource in path order
+func compareAttributes(existing, live *Resource, existingValues, liveValues map[string]string) []AttributeChange {
+	var changes []AttributeChange
+	for _, path := range unionKeys(existing.Attributes, live.Attributes) {
+		moduleValue, inModule := existing.Attributes[path]
+		liveValue, inLive := live.Attributes[path]
+
+		switch {
+		case !inModule:
+			changes = append(changes, AttributeChange{Path: path, Change: Added, Live: liveValue.Text})
+		case !inLive:
+			changes = append(changes, AttributeChange{Path: path, Change: Removed, Module: moduleValue.Text})
+		case moduleValue.key != liveValue.key:
+			changes = append(changes, AttributeChange{Path: path, Change: Changed, Module: moduleValue.Text, Live: liveValue.Text})
+		default:
+			// Same expression: compare the values behind a shared variable reference
+			variable := moduleValue.variable()
+			if variable == "" {
+				continue
+			}
+			before, knownBefore := existingValues[variable]
+			after, knownAfter := liveValues[variable]
+			if knownBefore && knownAfter && before != after {
+				changes = append(changes, AttributeChange{Path: path, Change: Changed, Module: before, Live: after})
+			}
+		}
+	}
+	return changes
+}
+
+// Text renders the report for terminal output
+func (r *Report) Text() string {
+	var sb strings.Builder
+
+	if !r.HasDrift() {
+		sb.WriteString(fmt.Sprintf("No drift detected: %s matches the environment (%d resources)\n", r.ModuleDir, r.Unchanged))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Drift detected in %s: %d added, %d removed, %d changed, %d unchanged\n", r.ModuleDir, r.Added, r.Removed, r.Changed, r.Unchanged))
+	for _, resource := range r.Resources {
+		sb.WriteString("\n")
+		switch resource.Change {
+		case Added:
+			sb.WriteString(fmt.Sprintf("+ %s (in the environment, not in the module)\n", resource.Address))
+		case Removed:
+			sb.WriteString(fmt.Sprintf("- %s (in the module, no longer in the environment)\n", resource.Address))
+		default:
+			sb.WriteString(fmt.Sprintf("~ %s\n", resource.Address))
+		}
+
+		for _, attribute := range resource.Attributes {
+			switch attribute.Change {
+			case Added:
+				sb.WriteString(fmt.Sprintf("    + %s = %s\n", attribute.Path, truncate(attribute.Live)))
+			case Removed:
+				sb.WriteString(fmt.Sprintf("    - %s = %s\n", attribute.Path, truncate(attribute.Module)))
+			default:
+				sb.WriteString(fmt.Sprintf("    ~ %s: %s -> %s\n", attribute.Path, truncate(attribute.Module), truncate(attribute.Live)))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// JSON renders the report as indented JSON
+func (r *Report) JSON() ([]byte, error) {
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode drift report: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// maxTextValueLength limits the length of values in the text report; the JSON report has them in full
+const maxTextValueLength = 80
+
+func truncate(value string) string {
+	runes := []rune(value)
+	if len(runes) <= maxTextValueLength {
+		return value
+	}
+	return string(runes[:maxTextValueLength-3]) + "..."
+}
+
+// unionKeys returns the keys of both maps in sorted order
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
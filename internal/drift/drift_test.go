@@ -0,0 +1,211 @@
+package drift
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeModule writes a root module with the given files and a child module "child" with
+// the given module files, returning the child module directory
+func writeModule(t *testing.T, rootFiles, moduleFiles map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "child")
+	require.NoError(t, os.MkdirAll(moduleDir, 0755))
+	for name, content := range rootFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	for name, content := range moduleFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0644))
+	}
+	return moduleDir
+}
+
+const flowHCL = `resource "pingone_davinci_flow" "login" {
+  environment_id = var.pingone_environment_id
+  name           = "Login"
+
+  graph_data = {
+    elements = {
+      nodes = {
+        "n1" = {
+          data = {
+            id         = "n1"
+            properties = jsonencode({
+              "nodeTitle" : { "value" : "Start" }
+            })
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+const connectorHCL = `resource "pingone_davinci_connector_instance" "http" {
+  environment_id = var.pingone_environment_id
+  name           = "HTTP"
+  properties = jsonencode({
+    "clientId" : { "value" : "${var.davinci_connection_HTTP_clientId}" }
+  })
+}
+`
+
+const rootHCL = `module "example" {
+  source = "./child"
+
+  pingone_environment_id           = var.pingone_environment_id
+  davinci_connection_HTTP_clientId = var.davinci_connection_HTTP_clientId
+}
+`
+
+func TestReadModule(t *testing.T) {
+	moduleDir := writeModule(t,
+		map[string]string{
+			"module.tf":             rootHCL,
+			"terraform.auto.tfvars": "pingone_environment_id = \"env\"\ndavinci_connection_HTTP_clientId = \"abc\"\n",
+		},
+		map[string]string{"flow.tf": flowHCL, "connector.tf": connectorHCL},
+	)
+
+	module, err := ReadModule(moduleDir)
+	require.NoError(t, err)
+
+	require.Contains(t, module.Resources, "pingone_davinci_flow.login")
+	flow := module.Resources["pingone_davinci_flow.login"]
+	assert.Equal(t, `"Login"`, flow.Attributes["name"].Text)
+	assert.Equal(t, `"Start"`, flow.Attributes[`graph_data.elements.nodes.n1.data.properties.nodeTitle.value`].Text)
+
+	connector := module.Resources["pingone_davinci_connector_instance.http"]
+	require.NotNil(t, connector)
+	assert.Equal(t, "davinci_connection_HTTP_clientId", connector.Attributes["properties.clientId.value"].variable())
+
+	assert.Equal(t, map[string]string{
+		"pingone_environment_id":           `"env"`,
+		"davinci_connection_HTTP_clientId": `"abc"`,
+	}, module.Values)
+}
+
+func TestReadModule_SkipsEmptyValues(t *testing.T) {
+	moduleDir := writeModule(t,
+		map[string]string{"terraform.auto.tfvars": "secret = \"\"\nredirect_uris = []\nlogout_uris = [\"https://example.com\"]\n"},
+		map[string]string{"flow.tf": flowHCL},
+	)
+
+	module, err := ReadModule(moduleDir)
+	require.NoError(t, err)
+
+	assert.NotContains(t, module.Values, "secret")
+	assert.NotContains(t, module.Values, "redirect_uris")
+	assert.Contains(t, module.Values, "logout_uris")
+}
+
+func TestReadModule_Errors(t *testing.T) {
+	_, err := ReadModule(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no .tf files found")
+
+	moduleDir := writeModule(t, nil, map[string]string{"broken.tf": `resource "a" "b" {`})
+	_, err = ReadModule(moduleDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse")
+
+	t.Run("Rejects modules in JSON syntax", func(t *testing.T) {
+		moduleDir := writeModule(t, nil, map[string]string{"flow.tf": flowHCL, "connector.tf.json": `{"resource": {}}`})
+		_, err := ReadModule(moduleDir)
+		assert.ErrorContains(t, err, "connector.tf.json is in JSON syntax")
+
+		moduleDir = writeModule(t, map[string]string{"terraform.auto.tfvars.json": `{}`}, map[string]string{"flow.tf": flowHCL})
+		_, err = ReadModule(moduleDir)
+		assert.ErrorContains(t, err, "terraform.auto.tfvars.json is in JSON syntax")
+	})
+}
+
+func TestCompare(t *testing.T) {
+	tfvars := func(clientID string) string {
+		return "pingone_environment_id = \"env\"\ndavinci_connection_HTTP_clientId = \"" + clientID + "\"\n"
+	}
+	read := func(t *testing.T, values string, files map[string]string) *Module {
+		module, err := ReadModule(writeModule(t, map[string]string{"module.tf": rootHCL, "terraform.auto.tfvars": values}, files))
+		require.NoError(t, err)
+		return module
+	}
+
+	t.Run("No drift with different formatting", func(t *testing.T) {
+		existing := read(t, tfvars("abc"), map[string]string{"flow.tf": flowHCL, "connector.tf": connectorHCL})
+		reformatted := `resource "pingone_davinci_flow" "login" {
+  name = "Login"
+  environment_id = var.pingone_environment_id
+  graph_data = { elements = { nodes = { "n1" = { data = { id = "n1", properties = jsonencode({ "nodeTitle": { "value": "Start" } }) } } } } }
+}
+`
+		live := read(t, tfvars("abc"), map[string]string{"flow.tf": reformatted, "connector.tf": connectorHCL})
+
+		report := Compare(existing, live)
+		assert.False(t, report.HasDrift())
+		assert.Equal(t, 2, report.Unchanged)
+		assert.Contains(t, report.Text(), "No drift detected")
+	})
+
+	t.Run("Node property and connector value changes", func(t *testing.T) {
+		existing := read(t, tfvars("abc"), map[string]string{"flow.tf": flowHCL, "connector.tf": connectorHCL})
+		edited := `resource "pingone_davinci_flow" "login" {
+  environment_id = var.pingone_environment_id
+  name           = "Login"
+  graph_data = { elements = { nodes = { "n1" = { data = { id = "n1", properties = jsonencode({ "nodeTitle": { "value": "Begin" } }) } } } } }
+}
+`
+		live := read(t, tfvars("xyz"), map[string]string{"flow.tf": edited, "connector.tf": connectorHCL})
+
+		report := Compare(existing, live)
+		require.True(t, report.HasDrift())
+		assert.Equal(t, 2, report.Changed)
+		require.Len(t, report.Resources, 2)
+
+		connector := report.Resources[0]
+		assert.Equal(t, "pingone_davinci_connector_instance.http", connector.Address)
+		assert.Equal(t, []AttributeChange{{Path: "properties.clientId.value", Change: Changed, Module: `"abc"`, Live: `"xyz"`}}, connector.Attributes)
+
+		flow := report.Resources[1]
+		assert.Equal(t, "pingone_davinci_flow.login", flow.Address)
+		assert.Equal(t, []AttributeChange{{
+			Path:   `graph_data.elements.nodes.n1.data.properties.nodeTitle.value`,
+			Change: Changed,
+			Module: `"Start"`,
+			Live:   `"Begin"`,
+		}}, flow.Attributes)
+
+		text := report.Text()
+		assert.Contains(t, text, "0 added, 0 removed, 2 changed, 0 unchanged")
+		assert.Contains(t, text, `~ graph_data.elements.nodes.n1.data.properties.nodeTitle.value: "Start" -> "Begin"`)
+	})
+
+	t.Run("Unknown values are not drift", func(t *testing.T) {
+		existing := read(t, tfvars(""), map[string]string{"connector.tf": connectorHCL})
+		live := read(t, tfvars("xyz"), map[string]string{"connector.tf": connectorHCL})
+
+		assert.False(t, Compare(existing, live).HasDrift())
+	})
+
+	t.Run("Added and removed resources", func(t *testing.T) {
+		existing := read(t, tfvars("abc"), map[string]string{"flow.tf": flowHCL})
+		live := read(t, tfvars("abc"), map[string]string{"connector.tf": connectorHCL})
+
+		report := Compare(existing, live)
+		assert.Equal(t, 1, report.Added)
+		assert.Equal(t, 1, report.Removed)
+		assert.Equal(t, []ResourceDrift{
+			{Address: "pingone_davinci_connector_instance.http", Type: "pingone_davinci_connector_instance", Name: "http", Change: Added},
+			{Address: "pingone_davinci_flow.login", Type: "pingone_davinci_flow", Name: "login", Change: Removed},
+		}, report.Resources)
+
+		out, err := report.JSON()
+		require.NoError(t, err)
+		assert.Contains(t, string(out), `"address": "pingone_davinci_connector_instance.http"`)
+		assert.Contains(t, string(out), `"change": "added"`)
+	})
+}
@@ -0,0 +1,231 @@
+package drift
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/modulereader"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Module holds the resources of a generated child module and the known values of its input variables
+type Module struct {
+	Dir       string
+	Resources map[string]*Resource // Keyed by address, e.g. "pingone_davinci_flow.pingcli__Login"
+	Values    map[string]string    // Module input variable name -> normalized value expression
+}
+
+// Resource is a resource block flattened to attribute paths
+type Resource struct {
+	Type       string
+	Name       string
+	Attributes map[string]Value // Keyed by path, e.g. `graph_data.elements.nodes["n1"].data.name`
+}
+
+// Address returns the Terraform address of the resource
+func (r *Resource) Address() string {
+	return r.Type + "." + r.Name
+}
+
+// Value is a leaf expression of a resource attribute
+type Value struct {
+	key  string // Token stream used for comparison, independent of formatting
+	Text string // Source text with whitespace collapsed, for display
+}
+
+// varReference matches an expression that is only a variable reference, bare or interpolated
+var varReference = regexp.MustCompile(`^"?\$\{var\.([A-Za-z0-9_-]+)\}"?$|^var\.([A-Za-z0-9_-]+)$`)
+
+// variable returns the name of the variable the value references, or "" if it is not only a variable reference
+func (v Value) variable() string {
+	match := varReference.FindStringSubmatch(v.Text)
+	if match == nil {
+		return ""
+	}
+	return match[1] + match[2]
+}
+
+// ReadModule parses the resource blocks of every .tf file in dir. Input variable values are read
+// from the module block that sources dir in the parent directory and the *.tfvars files next to
+// it; empty strings are treated as unknown, as the generator writes them for values it cannot export.
+// Modules in JSON syntax (.tf.json, .tfvars.json) are rejected rather than read without those files.
+func ReadModule(dir string) (*Module, error) {
+	files, err := modulereader.Read(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	module := &Module{Dir: dir, Resources: make(map[string]*Resource), Values: make(map[string]string)}
+	for _, file := range files.Files {
+		for _, block := range file.Resources() {
+			resource := &Resource{Type: block.Labels[0], Name: block.Labels[1], Attributes: make(map[string]Value)}
+			flattenBody(block.Body, file.Src, "", resource.Attributes)
+			module.Resources[resource.Address()] = resource
+		}
+	}
+
+	values, err := readVariableValues(files)
+	if err != nil {
+		return nil, err
+	}
+	module.Values = values
+	return module, nil
+}
+
+// readVariableValues resolves the module's input variables from the root module in the parent directory
+func readVariableValues(files *modulereader.Module) (map[string]string, error) {
+	// Root variable values from *.tfvars
+	rootValues := make(map[string]string)
+	for _, file := range files.VarFiles {
+		for name, attr := range file.Body.Attributes {
+			rootValues[name] = normalize(attr.Expr, file.Src).Text
+		}
+	}
+
+	// Module arguments from the module block that sources dir. Without one, the generator
+	// convention of identical root and module variable names applies.
+	arguments, err := moduleArguments(files)
+	if err != nil {
+		return nil, err
+	}
+	if arguments == nil {
+		arguments = make(map[string]Value)
+		for name := range rootValues {
+			arguments[name] = Value{Text: "var." + name}
+		}
+	}
+
+	values := make(map[string]string)
+	for name, argument := range arguments {
+		value := argument.Text
+		if rootVariable := argument.variable(); rootVariable != "" {
+			resolved, ok := rootValues[rootVariable]
+			if !ok {
+				continue
+			}
+			value = resolved
+		}
+		// Empty strings and lists are values not filled in, such as secrets
+		if value != `""` && value != "[]" {
+			values[name] = value
+		}
+	}
+	return values, nil
+}
+
+// moduleArguments returns the arguments of the module block in the root module whose source is
+// the child module, or nil if there is none
+func moduleArguments(files *modulereader.Module) (map[string]Value, error) {
+	moduleDir, err := filepath.Abs(files.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files.RootFiles {
+		for _, block := range file.Blocks("module") {
+			source, ok := block.Body.Attributes["source"]
+			if !ok {
+				continue
+			}
+			value, diags := source.Expr.Value(nil)
+			if diags.HasErrors() || value.Type() != cty.String {
+				continue
+			}
+			sourceDir, err := filepath.Abs(filepath.Join(files.RootDir, value.AsString()))
+			if err != nil || sourceDir != moduleDir {
+				continue
+			}
+
+			arguments := make(map[string]Value)
+			for name, attr := range block.Body.Attributes {
+				if name != "source" {
+					arguments[name] = normalize(attr.Expr, file.Src)
+				}
+			}
+			return arguments, nil
+		}
+	}
+	return nil, nil
+}
+
+// flattenBody adds every leaf attribute of body to out, keyed by its path below prefix
+func flattenBody(body *hclsyntax.Body, src []byte, prefix string, out map[string]Value) {
+	for name, attr := range body.Attributes {
+		flattenExpr(attr.Expr, src, joinPath(prefix, name), out)
+	}
+	for _, block := range body.Blocks {
+		path := joinPath(prefix, block.Type)
+		for _, label := range block.Labels {
+			path += fmt.Sprintf("[%q]", label)
+		}
+		flattenBody(block.Body, src, path, out)
+	}
+}
+
+// flattenExpr descends into object constructors and jsonencode() calls so nested changes, such
+// as a single node property in graph_data, are reported at their own path. Other expressions,
+// including lists, are leaves.
+func flattenExpr(expr hclsyntax.Expression, src []byte, path string, out map[string]Value) {
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		if len(e.Items) == 0 {
+			out[path] = normalize(e, src)
+			return
+		}
+		for _, item := range e.Items {
+			flattenExpr(item.ValueExpr, src, joinPath(path, objectKey(item.KeyExpr, src)), out)
+		}
+	case *hclsyntax.FunctionCallExpr:
+		if e.Name == "jsonencode" && len(e.Args) == 1 {
+			flattenExpr(e.Args[0], src, path, out)
+			return
+		}
+		out[path] = normalize(e, src)
+	default:
+		out[path] = normalize(e, src)
+	}
+}
+
+// objectKey returns the name of an object key, whether bare or quoted
+func objectKey(expr hclsyntax.Expression, src []byte) string {
+	if keyword := hcl.ExprAsKeyword(expr); keyword != "" {
+		return keyword
+	}
+	if value, diags := expr.Value(nil); !diags.HasErrors() && value.Type() == cty.String {
+		return value.AsString()
+	}
+	return normalize(expr, src).Text
+}
+
+// joinPath appends a key to a path, quoting keys that are not identifiers
+func joinPath(prefix, key string) string {
+	if !hclsyntax.ValidIdentifier(key) {
+		return prefix + fmt.Sprintf("[%q]", key)
+	}
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// normalize returns the comparison key and display text of an expression
+func normalize(expr hclsyntax.Expression, src []byte) Value {
+	rng := expr.Range()
+	text := src[rng.Start.Byte:rng.End.Byte]
+
+	tokens, _ := hclsyntax.LexExpression(text, rng.Filename, rng.Start)
+	var key strings.Builder
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenNewline, hclsyntax.TokenComment, hclsyntax.TokenEOF:
+			continue
+		}
+		key.Write(token.Bytes)
+		key.WriteByte(0)
+	}
+
+	return Value{key: key.String(), Text: strings.Join(strings.Fields(string(text)), " ")}
+}
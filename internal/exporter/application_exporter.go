@@ -8,89 +8,90 @@ import (
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
-	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ExportApplications exports all DaVinci applications from the API to HCL format
 func ExportApplications(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph) (string, error) {
-	hcl, _, err := ExportApplicationsWithImports(ctx, client, skipDeps, graph, nil)
+	hcl, _, err := ExportApplicationsWithImports(ctx, client, skipDeps, graph, nil, nil)
 	return hcl, err
 }
 
 // ExportApplicationsWithImports exports applications with optional import blocks
 // Returns HCL string and import blocks for module generation
-func ExportApplicationsWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator) (string, []RawImportBlock, error) {
+// Applications rejected by filter (nil exports all) are recorded on the graph's missing dependency tracker
+func ExportApplicationsWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator, filter *ResourceFilter) (string, []RawImportBlock, error) {
 	if client == nil {
 		return "", nil, fmt.Errorf("client cannot be nil")
 	}
 
-	// Get all applications from API
-	applications, err := client.ListApplications(ctx, client.EnvironmentID)
+	resources, _, err := newAPIConversion(client, skipDeps, graph, importGen, filter).convertApplications(ctx)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to list applications: %w", err)
+		return "", nil, err
 	}
 
-	if len(applications) == 0 {
-		return "", nil, nil
+	hcl, err := applicationsHCL(resources)
+	if err != nil {
+		return "", nil, err
+	}
+	return hcl, rawImportBlocks(resources), nil
+}
+
+// applicationsHCL renders application resources
+func applicationsHCL(resources []*model.Resource) (string, error) {
+	hcl, err := resourcesHCL(resources)
+	if err != nil {
+		return "", fmt.Errorf("failed to write applications: %w", err)
+	}
+	return hcl, nil
+}
+
+// convertApplications converts the selected applications to resources and extracts their
+// variable-eligible attributes
+func (c *conversion) convertApplications(ctx context.Context) ([]*model.Resource, []converter.VariableEligibleAttribute, error) {
+	graph := c.graph
+
+	applications, err := c.selected(ctx, "pingone_davinci_application")
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// First pass: Register all applications in the dependency graph
 	for _, application := range applications {
-		appName := application.GetName()
-		appID := application.GetId()
-		sanitizedName := resolver.SanitizeName(appName, nil)
-		graph.AddResource("pingone_davinci_application", appID, sanitizedName)
+		sanitizedName := graph.ResourceName(application.ID, application.Name)
+		graph.AddResource("pingone_davinci_application", application.ID, sanitizedName)
 	}
 
-	var namedBlocks []utils.NamedHCL
-	var importBlocks []RawImportBlock
+	var resources []*model.Resource
+	var extractedVariables []converter.VariableEligibleAttribute
 
-	// Second pass: Convert each application to HCL
+	// Second pass: Convert each application to a resource
 	for _, application := range applications {
-		appID := application.GetId()
-
-		// Get the actual resource name from the graph (includes deduplication suffix if needed)
-		actualName, err := graph.GetReferenceName("pingone_davinci_application", appID)
+		appJSON, err := c.source.payload(ctx, application)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to get resource name for application %s: %w", appID, err)
+			return nil, nil, err
 		}
 
-		// Track import block separately if import generator provided
-		if importGen != nil {
-			importIDStr := fmt.Sprintf("%s/%s", client.EnvironmentID, appID)
-			importBlocks = append(importBlocks, RawImportBlock{
-				ResourceType: "pingone_davinci_application",
-				ResourceName: actualName,
-				ImportID:     importIDStr,
-			})
-		}
-
-		// Convert SDK response to JSON format expected by converter
-		appJSON, err := convertApplicationToJSON(&application)
+		// Convert using converter with environment ID and graph, which names the resource
+		// after the application's registered name
+		resource, err := converter.ConvertApplicationToResource(appJSON, c.envRef(), graph)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to convert application %s to JSON: %w", application.GetId(), err)
-		}
-
-		// Determine environment ID based on skipDeps flag
-		var environmentID string
-		if skipDeps {
-			environmentID = client.EnvironmentID // Will be quoted by converter
-		} else {
-			environmentID = "var.pingone_environment_id" // Will be written as-is by converter
+			return nil, nil, fmt.Errorf("failed to convert application %s to HCL: %w", application.ID, err)
 		}
 
-		// Convert to HCL using converter with environment ID and graph
-		hcl, err := converter.ConvertApplicationWithEnvironmentAndGraph(appJSON, environmentID, graph)
+		// Extract variable-eligible attributes for module generation
+		appAttrs, err := converter.GetApplicationVariableEligibleAttributes(appJSON, resource.Name)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to convert application %s to HCL: %w", application.GetId(), err)
+			return nil, nil, fmt.Errorf("failed to extract application attributes for %s: %w", application.ID, err)
 		}
+		extractedVariables = append(extractedVariables, appAttrs...)
 
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: "", HCL: hcl})
+		resource.ImportID = c.importID(application.ID)
+		resources = append(resources, resource)
 	}
 
-	// Sort by resource name to ensure deterministic output
-	return utils.JoinHCLBlocksSorted(namedBlocks), importBlocks, nil
+	return resources, extractedVariables, nil
 }
 
 // convertApplicationToJSON converts SDK DaVinciApplicationResponse to JSON format expected by converter
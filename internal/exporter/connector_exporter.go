@@ -9,153 +9,113 @@ import (
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
-	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ExportConnectorInstances retrieves connector instances from the API and converts them to Terraform HCL
 func ExportConnectorInstances(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph) (string, []converter.VariableEligibleAttribute, error) {
-	hcl, extracted, _, err := ExportConnectorInstancesWithImports(ctx, client, skipDeps, graph, nil)
+	hcl, extracted, _, err := ExportConnectorInstancesWithImports(ctx, client, skipDeps, graph, nil, nil)
 	return hcl, extracted, err
 }
 
-// ExportConnectorInstancesForModule exports connector instances with JSON data for module generation
-// Returns HCL, extracted variables, JSON map, resource names map, and import blocks
-func ExportConnectorInstancesForModule(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator) (string, []converter.VariableEligibleAttribute, map[string][]byte, map[string]string, []RawImportBlock, error) {
-	hcl, extracted, importBlocks, err := ExportConnectorInstancesWithImports(ctx, client, skipDeps, graph, importGen)
-	if err != nil {
-		return "", nil, nil, nil, nil, err
+// ExportConnectorInstancesWithImports exports connector instances with optional import blocks
+// Returns HCL string, extracted variable-eligible attributes, and import blocks for module generation
+// Instances rejected by filter (nil exports all) are recorded on the graph's missing dependency tracker
+func ExportConnectorInstancesWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator, filter *ResourceFilter) (string, []converter.VariableEligibleAttribute, []RawImportBlock, error) {
+	if client == nil {
+		return "", nil, nil, fmt.Errorf("API client is required")
 	}
 
-	// Re-fetch to get JSON and build maps (inefficient but keeps changes minimal)
-	instanceSummaries, err := client.ListConnectorInstances(ctx)
+	resources, extractedVariables, err := newAPIConversion(client, skipDeps, graph, importGen, filter).convertConnectorInstances(ctx)
 	if err != nil {
-		return "", nil, nil, nil, nil, fmt.Errorf("failed to re-fetch connector instances for JSON: %w", err)
+		return "", nil, nil, err
 	}
 
-	// Apply the same skip filter to avoid referencing instances not registered in the graph
-	filtered := make([]api.ConnectorInstanceSummary, 0, len(instanceSummaries))
-	for _, s := range instanceSummaries {
-		if shouldSkipConnector(s) {
-			continue
-		}
-		filtered = append(filtered, s)
+	hcl, err := connectorInstancesHCL(resources)
+	if err != nil {
+		return "", nil, nil, err
 	}
+	return hcl, extractedVariables, rawImportBlocks(resources), nil
+}
 
-	jsonMap := make(map[string][]byte)
-	namesMap := make(map[string]string)
-
-	for _, summary := range filtered {
-		// Get the actual resource name from the graph
-		actualName, err := graph.GetReferenceName("pingone_davinci_connector_instance", summary.InstanceID)
-		if err != nil {
-			return "", nil, nil, nil, nil, fmt.Errorf("failed to get resource name for connector instance %s: %w", summary.InstanceID, err)
-		}
-
-		instanceDetail, err := client.GetConnectorInstance(ctx, summary.InstanceID)
-		if err != nil {
-			return "", nil, nil, nil, nil, fmt.Errorf("failed to get connector instance %s: %w", summary.InstanceID, err)
-		}
-
-		instanceJSON, err := convertInstanceDetailToJSON(instanceDetail, client.EnvironmentID)
-		if err != nil {
-			return "", nil, nil, nil, nil, fmt.Errorf("failed to convert instance to JSON: %w", err)
-		}
-
-		jsonMap[summary.InstanceID] = instanceJSON
-		namesMap[summary.InstanceID] = actualName
+// connectorInstancesHCL renders connector instance resources
+func connectorInstancesHCL(resources []*model.Resource) (string, error) {
+	if len(resources) == 0 {
+		return "# No connector instances found in environment\n", nil
 	}
 
-	return hcl, extracted, jsonMap, namesMap, importBlocks, nil
+	hcl, err := resourcesHCL(resources)
+	if err != nil {
+		return "", fmt.Errorf("failed to write connector instances: %w", err)
+	}
+	return hcl, nil
 }
 
-// ExportConnectorInstancesWithImports exports connector instances with optional import blocks
-// Returns HCL string, extracted variable-eligible attributes, and import blocks for module generation
-func ExportConnectorInstancesWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator) (string, []converter.VariableEligibleAttribute, []RawImportBlock, error) {
-	if client == nil {
-		return "", nil, nil, fmt.Errorf("API client is required")
-	}
+// convertConnectorInstances converts the selected connector instances to resources and
+// extracts their variable-eligible attributes
+func (c *conversion) convertConnectorInstances(ctx context.Context) ([]*model.Resource, []converter.VariableEligibleAttribute, error) {
+	graph := c.graph
 
-	// Retrieve all connector instances from the environment
-	instanceSummaries, err := client.ListConnectorInstances(ctx)
+	instances, err := c.source.list(ctx, "pingone_davinci_connector_instance")
 	if err != nil {
-		return "", nil, nil, fmt.Errorf("failed to list connector instances: %w", err)
+		return nil, nil, err
 	}
 
-	// Filter out ignored connectors (e.g., skUserPool)
-	filtered := make([]api.ConnectorInstanceSummary, 0, len(instanceSummaries))
-	for _, s := range instanceSummaries {
-		if shouldSkipConnector(s) {
+	// Filter out ignored connectors (e.g., skUserPool) and instances rejected by the export filters
+	filtered := make([]sourceResource, 0, len(instances))
+	for _, instance := range instances {
+		summary := api.ConnectorInstanceSummary{InstanceID: instance.ID, Name: instance.Name, ConnectorID: instance.ConnectorID}
+		if shouldSkipConnector(summary) || !c.filter.selectResource(graph, "pingone_davinci_connector_instance", instance.ID, instance.Name) {
 			continue
 		}
-		filtered = append(filtered, s)
-	}
-
-	if len(filtered) == 0 {
-		return "# No connector instances found in environment\n", nil, nil, nil
+		filtered = append(filtered, instance)
 	}
 
 	// First pass: Register all connector instances in the dependency graph
-	for _, summary := range filtered {
-		sanitizedName := resolver.SanitizeName(summary.Name, nil)
-		graph.AddResource("pingone_davinci_connector_instance", summary.InstanceID, sanitizedName)
+	for _, instance := range filtered {
+		sanitizedName := graph.ResourceName(instance.ID, instance.Name)
+		graph.AddResource("pingone_davinci_connector_instance", instance.ID, sanitizedName)
 	}
 
-	var namedBlocks []utils.NamedHCL
+	var resources []*model.Resource
 	var extractedVariables []converter.VariableEligibleAttribute
-	var importBlocks []RawImportBlock
 
 	// Second pass: Retrieve detailed connector instance data and convert each instance
-	for _, summary := range filtered {
+	for _, instance := range filtered {
 		// Get the actual resource name from the graph (includes deduplication suffix if needed)
-		actualName, err := graph.GetReferenceName("pingone_davinci_connector_instance", summary.InstanceID)
-		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to get resource name for connector instance %s: %w", summary.InstanceID, err)
-		}
-
-		// Track import block separately if import generator provided
-		if importGen != nil {
-			// Skip import for special connector IDs that don't follow UUID format
-			// User Pool connector uses "defaultUserPool" which isn't a valid UUID
-			if !isSpecialConnectorID(summary.InstanceID) {
-				importIDStr := fmt.Sprintf("%s/%s", client.EnvironmentID, summary.InstanceID)
-				importBlocks = append(importBlocks, RawImportBlock{
-					ResourceType: "pingone_davinci_connector_instance",
-					ResourceName: actualName,
-					ImportID:     importIDStr,
-				})
-			}
-		}
-
-		instanceDetail, err := client.GetConnectorInstance(ctx, summary.InstanceID)
+		actualName, err := graph.GetReferenceName("pingone_davinci_connector_instance", instance.ID)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to get connector instance %s (%s): %w", summary.Name, summary.InstanceID, err)
+			return nil, nil, fmt.Errorf("failed to get resource name for connector instance %s: %w", instance.ID, err)
 		}
 
-		// Convert the instance detail to JSON for the converter
-		instanceJSON, err := convertInstanceDetailToJSON(instanceDetail, client.EnvironmentID)
+		instanceJSON, err := c.source.payload(ctx, instance)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to convert instance %s to JSON: %w", summary.Name, err)
+			return nil, nil, err
 		}
 
 		// Extract variable-eligible attributes for module generation
 		connectorAttrs, err := converter.GetConnectorInstanceVariableEligibleAttributes(instanceJSON, actualName)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to extract connector attributes for %s: %w", summary.Name, err)
+			return nil, nil, fmt.Errorf("failed to extract connector attributes for %s: %w", instance.Name, err)
 		}
 		extractedVariables = append(extractedVariables, connectorAttrs...)
 
-		// Convert to HCL using the existing converter
-		hcl, err := converter.ConvertConnectorInstanceWithOptions(instanceJSON, skipDeps)
+		resource, err := converter.ConvertConnectorInstanceToResource(instanceJSON, c.skipDeps, actualName)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to convert connector instance %s to HCL: %w", summary.Name, err)
+			return nil, nil, fmt.Errorf("failed to convert connector instance %s to HCL: %w", instance.Name, err)
+		}
+
+		// Skip import for special connector IDs that don't follow UUID format
+		// User Pool connector uses "defaultUserPool" which isn't a valid UUID
+		if !isSpecialConnectorID(instance.ID) {
+			resource.ImportID = c.importID(instance.ID)
 		}
 
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: "", HCL: hcl})
+		resources = append(resources, resource)
 	}
 
-	// Sort by resource name to ensure deterministic output
-	return utils.JoinHCLBlocksSorted(namedBlocks), extractedVariables, importBlocks, nil
+	return resources, extractedVariables, nil
 }
 
 // isSpecialConnectorID checks if a connector instance ID is a special case that doesn't follow UUID format
@@ -0,0 +1,159 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingidentity/pingcli/shared/grpc"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+)
+
+// conversion converts the resources of a source to Terraform resources. Each resource type is
+// converted in two passes: all resources are registered in the dependency graph first, so
+// references between them resolve regardless of order, then each one is converted.
+type conversion struct {
+	source        resourceSource
+	environmentID string // Prefix of import IDs; environment_id value when dependencies are skipped
+	skipDeps      bool
+	graph         *resolver.DependencyGraph
+	filter        *ResourceFilter                 // Optional; nil converts all resources
+	importGen     *importgen.ImportBlockGenerator // Optional; import IDs are only set when provided
+}
+
+// newAPIConversion creates a conversion of the resources of the client's environment
+func newAPIConversion(client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator, filter *ResourceFilter) *conversion {
+	return &conversion{
+		source:        apiSource{client: client},
+		environmentID: client.EnvironmentID,
+		skipDeps:      skipDeps,
+		graph:         graph,
+		filter:        filter,
+		importGen:     importGen,
+	}
+}
+
+// newDependencyGraph creates the dependency graph of an export, naming resources as configured
+func newDependencyGraph(opts ExportOptions) *resolver.DependencyGraph {
+	graph := resolver.NewDependencyGraph()
+	graph.SetNamingStrategy(opts.NamingStrategy)
+	graph.SetNameLock(opts.NameLock)
+	return graph
+}
+
+// envRef returns the environment_id value: a variable reference unless dependencies are
+// skipped and the environment is known
+func (c *conversion) envRef() string {
+	if c.skipDeps && c.environmentID != "" {
+		return c.environmentID
+	}
+	return "var.pingone_environment_id"
+}
+
+// importID returns the import ID of a resource, or "" when import blocks are not generated
+func (c *conversion) importID(ids ...string) string {
+	if c.importGen == nil {
+		return ""
+	}
+	importID := c.environmentID
+	for _, id := range ids {
+		importID += "/" + id
+	}
+	return importID
+}
+
+// selected lists the resources of a type accepted by the export filters. Rejected resources
+// are recorded on the graph's missing dependency tracker so references to them get
+// reason-specific TODOs.
+func (c *conversion) selected(ctx context.Context, resourceType string) ([]sourceResource, error) {
+	resources, err := c.source.list(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]sourceResource, 0, len(resources))
+	for _, resource := range resources {
+		if c.filter.selectResource(c.graph, resourceType, resource.ID, resource.Name) {
+			selected = append(selected, resource)
+		}
+	}
+	return selected, nil
+}
+
+// conversionStep converts the resources of one type
+type conversionStep struct {
+	resourceType string // Terraform type, e.g. "pingone_davinci_flow"
+	label        string // Plural name used in messages, e.g. "flows"
+	convert      func(*conversion, context.Context) ([]*model.Resource, []converter.VariableEligibleAttribute, error)
+	hcl          func([]*model.Resource) (string, error) // Renders the resources for a single-file export
+}
+
+// conversionSteps lists the resource types in dependency order
+var conversionSteps = []conversionStep{
+	{"pingone_davinci_variable", "variables", (*conversion).convertVariables, variablesHCL},
+	{"pingone_davinci_connector_instance", "connector instances", (*conversion).convertConnectorInstances, connectorInstancesHCL},
+	{"pingone_davinci_flow", "flows", (*conversion).convertFlows, flowsHCL},
+	{"pingone_davinci_application", "applications", (*conversion).convertApplications, applicationsHCL},
+	{"pingone_davinci_application_flow_policy", "flow policies", (*conversion).convertFlowPolicies, flowPoliciesHCL},
+}
+
+// run converts every resource type in dependency order, passing the result of each step to
+// done. Flows are registered first so flow-context variables can reference their flow.
+func (c *conversion) run(ctx context.Context, logger grpc.Logger, done func(step conversionStep, resources []*model.Resource, extracted []converter.VariableEligibleAttribute) error) error {
+	if _, err := c.registerFlows(ctx); err != nil {
+		return fmt.Errorf("failed to export flows: %w", err)
+	}
+
+	for _, step := range conversionSteps {
+		if err := logger.Message(fmt.Sprintf("Exporting %s...", step.label), nil); err != nil {
+			return fmt.Errorf("failed to log message: %w", err)
+		}
+		resources, extracted, err := step.convert(c, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", step.label, err)
+		}
+		if err := done(step, resources, extracted); err != nil {
+			return err
+		}
+		if err := logger.Message(fmt.Sprintf("✓ Exported %d %s", countResources(resources, step.resourceType), step.label), nil); err != nil {
+			return fmt.Errorf("failed to log message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// countResources returns the number of resources of a type
+func countResources(resources []*model.Resource, resourceType string) int {
+	count := 0
+	for _, resource := range resources {
+		if resource.Type == resourceType {
+			count++
+		}
+	}
+	return count
+}
+
+// validateGraph logs the issues found in the dependency graph as a warning
+func validateGraph(graph *resolver.DependencyGraph, logger grpc.Logger) error {
+	if err := graph.ValidateGraph(); err != nil {
+		if warnErr := logger.Warn(fmt.Sprintf("Dependency validation found issues: %v", err), nil); warnErr != nil {
+			return fmt.Errorf("failed to log warning: %w", warnErr)
+		}
+	}
+	return nil
+}
+
+// logMissingDependencies logs the summary of references to resources not exported, if any
+func logMissingDependencies(tracker *resolver.MissingDependencyTracker, logger grpc.Logger) error {
+	if len(tracker.GetMissing()) == 0 {
+		return nil
+	}
+	if err := logger.Message("\n"+tracker.GenerateSummaryReport(), nil); err != nil {
+		return fmt.Errorf("failed to log missing dependencies summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package exporter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+)
+
+// exportableTypes lists the resource types produced by an export, in dependency order
+var exportableTypes = []string{
+	"pingone_davinci_variable",
+	"pingone_davinci_connector_instance",
+	"pingone_davinci_flow",
+	"pingone_davinci_application",
+	"pingone_davinci_application_flow_policy",
+}
+
+// resourceTypeAliases maps the short type names accepted by the filter flags to Terraform types
+var resourceTypeAliases = map[string]string{
+	"variable":                "pingone_davinci_variable",
+	"variables":               "pingone_davinci_variable",
+	"connector_instance":      "pingone_davinci_connector_instance",
+	"connector_instances":     "pingone_davinci_connector_instance",
+	"connector":               "pingone_davinci_connector_instance",
+	"connectors":              "pingone_davinci_connector_instance",
+	"flow":                    "pingone_davinci_flow",
+	"flows":                   "pingone_davinci_flow",
+	"application":             "pingone_davinci_application",
+	"applications":            "pingone_davinci_application",
+	"flow_policy":             "pingone_davinci_application_flow_policy",
+	"flow_policies":           "pingone_davinci_application_flow_policy",
+	"application_flow_policy": "pingone_davinci_application_flow_policy",
+}
+
+// ResourceFilter selects which resources are exported.
+// A nil *ResourceFilter exports everything.
+type ResourceFilter struct {
+	includeTypes map[string]bool // empty means all types
+	excludeTypes map[string]bool
+	includeNames []namePattern // empty means all names
+	excludeNames []namePattern
+	excludeIDs   map[string]bool
+	only         *resolver.DependencyGraph // when set, only resources in this graph are exported
+}
+
+// namePattern matches resource names against a glob, or a regular expression when prefixed with "re:"
+type namePattern struct {
+	glob  string
+	regex *regexp.Regexp
+}
+
+// ResourceFilterOptions holds the raw filter flag values
+type ResourceFilterOptions struct {
+	IncludeTypes []string // Resource types to export (Terraform type or short name, e.g. "flow")
+	ExcludeTypes []string // Resource types to skip
+	IncludeNames []string // Name patterns to export (glob, or regex with "re:" prefix)
+	ExcludeNames []string // Name patterns to skip
+	ExcludeIDs   []string // Resource IDs to skip
+}
+
+// NewResourceFilter validates the filter options and compiles the name patterns.
+// Returns nil when no filter is configured.
+func NewResourceFilter(opts ResourceFilterOptions) (*ResourceFilter, error) {
+	if len(opts.IncludeTypes) == 0 && len(opts.ExcludeTypes) == 0 && len(opts.IncludeNames) == 0 &&
+		len(opts.ExcludeNames) == 0 && len(opts.ExcludeIDs) == 0 {
+		return nil, nil
+	}
+
+	f := &ResourceFilter{
+		includeTypes: make(map[string]bool),
+		excludeTypes: make(map[string]bool),
+		excludeIDs:   make(map[string]bool),
+	}
+
+	for _, t := range opts.IncludeTypes {
+		resourceType, err := normalizeResourceType(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --include-types value: %w", err)
+		}
+		f.includeTypes[resourceType] = true
+	}
+	for _, t := range opts.ExcludeTypes {
+		resourceType, err := normalizeResourceType(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --exclude-types value: %w", err)
+		}
+		f.excludeTypes[resourceType] = true
+	}
+
+	var err error
+	if f.includeNames, err = compileNamePatterns(opts.IncludeNames); err != nil {
+		return nil, fmt.Errorf("invalid --include-name value: %w", err)
+	}
+	if f.excludeNames, err = compileNamePatterns(opts.ExcludeNames); err != nil {
+		return nil, fmt.Errorf("invalid --exclude-name value: %w", err)
+	}
+
+	for _, id := range opts.ExcludeIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			f.excludeIDs[id] = true
+		}
+	}
+
+	return f, nil
+}
+
+// normalizeResourceType resolves a Terraform resource type or short alias
+func normalizeResourceType(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if resourceType, ok := resourceTypeAliases[value]; ok {
+		return resourceType, nil
+	}
+	for _, resourceType := range exportableTypes {
+		if value == resourceType {
+			return resourceType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown resource type %q", value)
+}
+
+// compileNamePatterns parses glob and "re:" regex patterns
+func compileNamePatterns(values []string) ([]namePattern, error) {
+	var patterns []namePattern
+	for _, value := range values {
+		if expr, ok := strings.CutPrefix(value, "re:"); ok {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %w", expr, err)
+			}
+			patterns = append(patterns, namePattern{regex: re})
+			continue
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", value, err)
+		}
+		patterns = append(patterns, namePattern{glob: value})
+	}
+	return patterns, nil
+}
+
+// match reports whether the pattern matches name
+func (p namePattern) match(name string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(name)
+	}
+	matched, _ := path.Match(p.glob, name)
+	return matched
+}
+
+// matchAny reports whether any of the patterns match name
+func matchAny(patterns []namePattern, name string) bool {
+	for _, p := range patterns {
+		if p.match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludesType reports whether resources of the given type are exported
+func (f *ResourceFilter) IncludesType(resourceType string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.includeTypes) > 0 && !f.includeTypes[resourceType] {
+		return false
+	}
+	return !f.excludeTypes[resourceType]
+}
+
+// IncludedTypes returns the exported resource types in dependency order
+func (f *ResourceFilter) IncludedTypes() []string {
+	types := make([]string, 0, len(exportableTypes))
+	for _, resourceType := range exportableTypes {
+		if f.IncludesType(resourceType) {
+			types = append(types, resourceType)
+		}
+	}
+	return types
+}
+
+// Includes reports whether a single resource is exported
+func (f *ResourceFilter) Includes(resourceType, id, name string) bool {
+	_, ok := f.missingReason(resourceType, id, name)
+	return ok
+}
+
+// missingReason evaluates the filter for one resource, returning why it is filtered out
+func (f *ResourceFilter) missingReason(resourceType, id, name string) (resolver.MissingReason, bool) {
+	if f == nil {
+		return 0, true
+	}
+	if f.excludeTypes[resourceType] || f.excludeIDs[id] || matchAny(f.excludeNames, name) {
+		return resolver.Excluded, false
+	}
+	if len(f.includeTypes) > 0 && !f.includeTypes[resourceType] {
+		return resolver.NotIncluded, false
+	}
+	if len(f.includeNames) > 0 && !matchAny(f.includeNames, name) {
+		return resolver.NotIncluded, false
+	}
+	if f.only != nil && !f.only.HasResource(resourceType, id) {
+		return resolver.NotIncluded, false
+	}
+	return 0, true
+}
+
+// restrictTo returns a copy of the filter that additionally only accepts resources in graph
+func (f *ResourceFilter) restrictTo(graph *resolver.DependencyGraph) *ResourceFilter {
+	restricted := &ResourceFilter{}
+	if f != nil {
+		*restricted = *f
+	}
+	restricted.only = graph
+	return restricted
+}
+
+// selectResource reports whether a resource is exported. Filtered-out resources are recorded on
+// the graph's missing dependency tracker so references to them get reason-specific TODOs.
+func (f *ResourceFilter) selectResource(graph *resolver.DependencyGraph, resourceType, id, name string) bool {
+	reason, ok := f.missingReason(resourceType, id, name)
+	if ok {
+		return true
+	}
+
+	if tracker := graph.MissingTracker(); tracker != nil {
+		tracker.SetResourceName(resourceType, id, name)
+		if reason == resolver.Excluded {
+			tracker.MarkExcluded(resourceType, id)
+		} else {
+			tracker.MarkNotIncluded(resourceType, id)
+		}
+	}
+	return false
+}
+
+// newMissingTracker creates a tracker for the given filter and attaches it to the graph
+func newMissingTracker(graph *resolver.DependencyGraph, filter *ResourceFilter) *resolver.MissingDependencyTracker {
+	tracker := resolver.NewMissingDependencyTracker()
+	tracker.SetIncludedTypes(filter.IncludedTypes())
+	graph.SetMissingTracker(tracker)
+	return tracker
+}
@@ -0,0 +1,146 @@
+package exporter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResourceFilter(t *testing.T) {
+	t.Run("Returns nil when no filter is set", func(t *testing.T) {
+		filter, err := NewResourceFilter(ResourceFilterOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, filter)
+		assert.True(t, filter.Includes("pingone_davinci_flow", "flow-1", "Login"))
+	})
+
+	t.Run("Accepts short and full type names", func(t *testing.T) {
+		filter, err := NewResourceFilter(ResourceFilterOptions{IncludeTypes: []string{"flow", "pingone_davinci_variable"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pingone_davinci_variable", "pingone_davinci_flow"}, filter.IncludedTypes())
+	})
+
+	t.Run("Returns error for unknown type", func(t *testing.T) {
+		_, err := NewResourceFilter(ResourceFilterOptions{ExcludeTypes: []string{"users"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--exclude-types")
+	})
+
+	t.Run("Returns error for invalid regex", func(t *testing.T) {
+		_, err := NewResourceFilter(ResourceFilterOptions{IncludeNames: []string{"re:("}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--include-name")
+	})
+
+	t.Run("Returns error for invalid glob", func(t *testing.T) {
+		_, err := NewResourceFilter(ResourceFilterOptions{ExcludeNames: []string{"[a-"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--exclude-name")
+	})
+}
+
+func TestResourceFilterIncludes(t *testing.T) {
+	filter, err := NewResourceFilter(ResourceFilterOptions{
+		IncludeTypes: []string{"flow", "connector_instance"},
+		IncludeNames: []string{"Login*", "re:^(?i)http"},
+		ExcludeNames: []string{"*Test*"},
+		ExcludeIDs:   []string{"flow-3"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		id           string
+		resName      string
+		expected     bool
+	}{
+		{"Glob include match", "pingone_davinci_flow", "flow-1", "Login Flow", true},
+		{"Regex include match", "pingone_davinci_connector_instance", "conn-1", "HTTP Connector", true},
+		{"Name not included", "pingone_davinci_flow", "flow-2", "Registration", false},
+		{"Excluded by name", "pingone_davinci_flow", "flow-4", "Login Test", false},
+		{"Excluded by ID", "pingone_davinci_flow", "flow-3", "Login", false},
+		{"Type not included", "pingone_davinci_variable", "var-1", "Login", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filter.Includes(tt.resourceType, tt.id, tt.resName))
+		})
+	}
+}
+
+func TestResourceFilterRecordsMissingReason(t *testing.T) {
+	filter, err := NewResourceFilter(ResourceFilterOptions{
+		ExcludeTypes: []string{"variable"},
+		IncludeNames: []string{"Login*", "Http"},
+	})
+	require.NoError(t, err)
+
+	graph := resolver.NewDependencyGraph()
+	tracker := newMissingTracker(graph, filter)
+
+	assert.False(t, filter.selectResource(graph, "pingone_davinci_variable", "var-1", "Login"))
+	assert.False(t, filter.selectResource(graph, "pingone_davinci_flow", "flow-1", "Registration"))
+	assert.True(t, filter.selectResource(graph, "pingone_davinci_flow", "flow-2", "Login"))
+
+	assert.Equal(t, resolver.Excluded, tracker.DetermineMissingReason("pingone_davinci_variable", "var-1", graph))
+	assert.Equal(t, resolver.NotIncluded, tracker.DetermineMissingReason("pingone_davinci_flow", "flow-1", graph))
+	assert.Equal(t, "Registration", tracker.GetResourceName("pingone_davinci_flow", "flow-1"))
+}
+
+// TestExportEnvironmentForModule_WithFilter verifies filtered resources become reason-specific TODOs
+func TestExportEnvironmentForModule_WithFilter(t *testing.T) {
+	dataset := &fakeserver.Dataset{
+		EnvironmentID:      "62f10a04-6c54-40c2-a97d-80a98522ff9a",
+		ConnectorInstances: jsonObjects(t, `[{"id": "conn-1", "name": "Http", "connector": {"id": "httpConnector"}, "properties": {}}]`),
+		Flows: jsonObjects(t, `[
+			{"id": "flow-1", "name": "Login", "enabled": true, "graphData": {"elements": {"nodes": [
+				{"data": {"id": "n1", "nodeType": "CONNECTION", "connectionId": "conn-1", "connectorId": "httpConnector", "name": "Http", "capabilityName": "customHtmlMessage"}}
+			]}}},
+			{"id": "flow-2", "name": "Test Login"}
+		]`),
+	}
+
+	t.Run("Excluded connector becomes an excluded TODO", func(t *testing.T) {
+		client := newDatasetClient(t, dataset)
+
+		filter, err := NewResourceFilter(ResourceFilterOptions{ExcludeNames: []string{"Test *"}, ExcludeIDs: []string{"conn-1"}})
+		require.NoError(t, err)
+
+		logger := &mockLogger{}
+		data, err := ExportEnvironmentForModule(context.Background(), client, ExportOptions{GenerateImports: true, Filter: filter}, logger)
+		require.NoError(t, err)
+
+		assert.NotContains(t, resourcesOfType(t, data, "pingone_davinci_connector_instance"), `resource "pingone_davinci_connector_instance"`)
+		assert.Empty(t, exportedOfType(data, "pingone_davinci_connector_instance"))
+		assert.Equal(t, 1, strings.Count(flowsOf(t, data), `resource "pingone_davinci_flow"`))
+		assert.Contains(t, flowsOf(t, data), `# TODO: Reference to "Http" (pingone_davinci_connector_instance conn-1) was excluded from export`)
+		for _, block := range rawImportBlocks(data.Resources) {
+			assert.NotContains(t, block.ImportID, "flow-2")
+		}
+
+		summary := strings.Join(logger.messages, "\n")
+		assert.Contains(t, summary, "Excluded Resources (1)")
+		assert.Contains(t, summary, `pingone_davinci_flow "pingcli__Login" (flow-1) → pingone_davinci_connector_instance "Http" (conn-1)`)
+	})
+
+	t.Run("Types outside include filter become not-included TODOs", func(t *testing.T) {
+		client := newDatasetClient(t, dataset)
+
+		filter, err := NewResourceFilter(ResourceFilterOptions{IncludeTypes: []string{"flow"}, IncludeNames: []string{"Login"}})
+		require.NoError(t, err)
+
+		logger := &mockLogger{}
+		data, err := ExportEnvironmentForModule(context.Background(), client, ExportOptions{Filter: filter}, logger)
+		require.NoError(t, err)
+
+		assert.Contains(t, flowsOf(t, data), "was not included in export filters")
+		assert.Contains(t, strings.Join(logger.messages, "\n"), "Not Included in Export (1)")
+	})
+}
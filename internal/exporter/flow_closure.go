@@ -0,0 +1,330 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingidentity/pingcli/shared/grpc"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+)
+
+// FlowClosure is the set of resources needed to manage a selection of flows:
+// the selected flows, everything they reference (connector instances, variables, subflows),
+// and the flow policies and applications that reference any flow in the set
+type FlowClosure struct {
+	// Graph holds the resources in the closure and the dependencies that pulled them in
+	Graph *resolver.DependencyGraph
+
+	// Entries lists the resources in discovery order with the reason each was pulled in
+	Entries []FlowClosureEntry
+}
+
+// FlowClosureEntry records why a resource is part of a flow closure
+type FlowClosureEntry struct {
+	Resource resolver.ResourceRef
+	Reason   string // e.g. "selected with --flow" or "connection_id of pingone_davinci_flow \"Login\""
+}
+
+// flowClosureWalker tracks the state of a closure walk
+type flowClosureWalker struct {
+	ctx     context.Context
+	client  *api.Client
+	closure *FlowClosure
+	names   map[string]string // type:id -> display name
+	queue   []string          // flow IDs waiting to be walked
+}
+
+// ResolveFlowClosure resolves the selected flows (by name or ID) and walks the flow dependency
+// schema (connection IDs, variable IDs, subflow IDs) and the reverse edges from flow policies and
+// their applications until no new resources are found
+func ResolveFlowClosure(ctx context.Context, client *api.Client, selectors []string) (*FlowClosure, error) {
+	if client == nil {
+		return nil, fmt.Errorf("API client is required")
+	}
+
+	flows, err := client.ListFlows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flows: %w", err)
+	}
+
+	w := &flowClosureWalker{
+		ctx:     ctx,
+		client:  client,
+		closure: &FlowClosure{Graph: resolver.NewDependencyGraph()},
+		names:   make(map[string]string),
+	}
+	for _, flow := range flows {
+		w.names[flowClosureKey("pingone_davinci_flow", flow.FlowID)] = flow.Name
+	}
+	if err := w.loadNames(); err != nil {
+		return nil, err
+	}
+
+	// Resolve selectors to root flows
+	for _, selector := range selectors {
+		flowID, err := matchFlowSelector(flows, selector)
+		if err != nil {
+			return nil, err
+		}
+		if w.add("pingone_davinci_flow", flowID, "selected with --flow") {
+			w.queue = append(w.queue, flowID)
+		}
+	}
+
+	policies, err := client.ListFlowPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flow policies: %w", err)
+	}
+	policyDeps := make(map[string][]resolver.Dependency, len(policies))
+
+	// Walk flows, then pull in policies that reference walked flows; repeat while
+	// policies pull in further flows through their distributions
+	for len(w.queue) > 0 {
+		if err := w.walkFlows(); err != nil {
+			return nil, err
+		}
+
+		for _, policy := range policies {
+			if w.closure.Graph.HasResource("pingone_davinci_application_flow_policy", policy.PolicyID) {
+				continue
+			}
+
+			deps, ok := policyDeps[policy.PolicyID]
+			if !ok {
+				if deps, err = w.policyDependencies(policy); err != nil {
+					return nil, err
+				}
+				policyDeps[policy.PolicyID] = deps
+			}
+
+			w.addPolicyIfReferenced(policy, deps)
+		}
+	}
+
+	return w.closure, nil
+}
+
+// matchFlowSelector returns the ID of the flow whose ID or name equals selector
+func matchFlowSelector(flows []api.FlowSummary, selector string) (string, error) {
+	var matches []api.FlowSummary
+	for _, flow := range flows {
+		if flow.FlowID == selector {
+			return flow.FlowID, nil
+		}
+		if flow.Name == selector {
+			matches = append(matches, flow)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("flow %q not found: use a flow name or ID", selector)
+	case 1:
+		return matches[0].FlowID, nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, m := range matches {
+			ids = append(ids, m.FlowID)
+		}
+		return "", fmt.Errorf("flow name %q is ambiguous (%s): use the flow ID instead", selector, strings.Join(ids, ", "))
+	}
+}
+
+// loadNames looks up connector instance and variable names for the closure report
+func (w *flowClosureWalker) loadNames() error {
+	instances, err := w.client.ListConnectorInstances(w.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list connector instances: %w", err)
+	}
+	for _, instance := range instances {
+		w.names[flowClosureKey("pingone_davinci_connector_instance", instance.InstanceID)] = instance.Name
+	}
+
+	variables, err := w.client.ListVariables(w.ctx, w.client.EnvironmentID)
+	if err != nil {
+		return fmt.Errorf("failed to list variables: %w", err)
+	}
+	for _, variable := range variables {
+		w.names[flowClosureKey("pingone_davinci_variable", variable.GetId().String())] = variable.GetName()
+	}
+	return nil
+}
+
+// walkFlows drains the flow queue, adding every resource referenced by each flow
+func (w *flowClosureWalker) walkFlows() error {
+	schema := optionalDependencySchema(resolver.GetFlowDependencySchema())
+
+	for len(w.queue) > 0 {
+		flowID := w.queue[0]
+		w.queue = w.queue[1:]
+
+		detail, err := w.client.GetFlow(w.ctx, flowID)
+		if err != nil {
+			return fmt.Errorf("failed to get flow %s: %w", flowID, err)
+		}
+		flowData, err := convertFlowDetailToMap(detail)
+		if err != nil {
+			return fmt.Errorf("failed to convert flow %s to map: %w", flowID, err)
+		}
+
+		deps, err := resolver.ParseResourceDependencies("pingone_davinci_flow", flowID, flowData, schema)
+		if err != nil {
+			return fmt.Errorf("failed to parse dependencies of flow %s: %w", flowID, err)
+		}
+
+		from := w.ref("pingone_davinci_flow", flowID)
+		for _, dep := range deps {
+			to := w.ref(dep.To.Type, dep.To.ID)
+			w.closure.Graph.AddDependency(from, to, dep.Field, dep.Location)
+
+			reason := fmt.Sprintf("%s of %s", dep.Field, w.describe(from))
+			if w.add(to.Type, to.ID, reason) && to.Type == "pingone_davinci_flow" {
+				w.queue = append(w.queue, to.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// policyDependencies fetches a flow policy and parses its flow and application references
+func (w *flowClosureWalker) policyDependencies(policy api.FlowPolicySummary) ([]resolver.Dependency, error) {
+	detail, err := w.client.GetFlowPolicy(w.ctx, policy.ApplicationID, policy.PolicyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow policy %s: %w", policy.PolicyID, err)
+	}
+
+	raw, err := json.Marshal(detail.RawResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal flow policy %s: %w", policy.PolicyID, err)
+	}
+	var policyData map[string]interface{}
+	if err := json.Unmarshal(raw, &policyData); err != nil {
+		return nil, fmt.Errorf("failed to parse flow policy %s: %w", policy.PolicyID, err)
+	}
+	policyData["applicationId"] = policy.ApplicationID
+
+	schema := optionalDependencySchema(resolver.GetFlowPolicyDependencySchema())
+	deps, err := resolver.ParseResourceDependencies("pingone_davinci_application_flow_policy", policy.PolicyID, policyData, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dependencies of flow policy %s: %w", policy.PolicyID, err)
+	}
+	return deps, nil
+}
+
+// addPolicyIfReferenced adds a policy, its application and its other flows to the closure
+// when any of its flow distributions points at a flow already in the closure
+func (w *flowClosureWalker) addPolicyIfReferenced(policy api.FlowPolicySummary, deps []resolver.Dependency) {
+	var trigger *resolver.Dependency
+	for i, dep := range deps {
+		if dep.To.Type == "pingone_davinci_flow" && w.closure.Graph.HasResource(dep.To.Type, dep.To.ID) {
+			trigger = &deps[i]
+			break
+		}
+	}
+	if trigger == nil {
+		return
+	}
+
+	w.names[flowClosureKey("pingone_davinci_application_flow_policy", policy.PolicyID)] = policy.Name
+	w.add("pingone_davinci_application_flow_policy", policy.PolicyID,
+		fmt.Sprintf("flow policy referencing %s", w.describe(w.ref(trigger.To.Type, trigger.To.ID))))
+
+	from := w.ref("pingone_davinci_application_flow_policy", policy.PolicyID)
+	for _, dep := range deps {
+		to := w.ref(dep.To.Type, dep.To.ID)
+		w.closure.Graph.AddDependency(from, to, dep.Field, dep.Location)
+
+		if w.add(to.Type, to.ID, fmt.Sprintf("%s of %s", dep.Field, w.describe(from))) && to.Type == "pingone_davinci_flow" {
+			w.queue = append(w.queue, to.ID)
+		}
+	}
+}
+
+// add registers a resource in the closure, returning false if it was already present
+func (w *flowClosureWalker) add(resourceType, id, reason string) bool {
+	if w.closure.Graph.HasResource(resourceType, id) {
+		return false
+	}
+	w.closure.Graph.AddResource(resourceType, id, w.names[flowClosureKey(resourceType, id)])
+	w.closure.Entries = append(w.closure.Entries, FlowClosureEntry{
+		Resource: w.ref(resourceType, id),
+		Reason:   reason,
+	})
+	return true
+}
+
+// ref builds a ResourceRef labelled with the resource's display name
+func (w *flowClosureWalker) ref(resourceType, id string) resolver.ResourceRef {
+	return resolver.ResourceRef{Type: resourceType, ID: id, Name: w.names[flowClosureKey(resourceType, id)]}
+}
+
+// describe formats a resource for the closure report
+func (w *flowClosureWalker) describe(ref resolver.ResourceRef) string {
+	if ref.Name != "" {
+		return fmt.Sprintf("%s %q", ref.Type, ref.Name)
+	}
+	return fmt.Sprintf("%s %s", ref.Type, ref.ID)
+}
+
+// flowClosureKey builds the name lookup key for a resource
+func flowClosureKey(resourceType, id string) string {
+	return resourceType + ":" + id
+}
+
+// optionalDependencySchema marks every field optional, so resources that simply lack a
+// reference (e.g. a flow without connector nodes) do not fail the walk
+func optionalDependencySchema(schema resolver.ResourceDependencySchema) resolver.ResourceDependencySchema {
+	fields := make([]resolver.FieldPath, len(schema.Fields))
+	for i, field := range schema.Fields {
+		field.IsOptional = true
+		fields[i] = field
+	}
+	schema.Fields = fields
+	return schema
+}
+
+// Report returns a human-readable summary of the closure and why each resource was included
+func (c *FlowClosure) Report() string {
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("Flow selection: %d resources in transitive closure\n", len(c.Entries)))
+	for _, entry := range c.Entries {
+		ref := entry.Resource
+		if ref.Name != "" {
+			report.WriteString(fmt.Sprintf("  • %s %q (%s): %s\n", ref.Type, ref.Name, ref.ID, entry.Reason))
+		} else {
+			report.WriteString(fmt.Sprintf("  • %s %s: %s\n", ref.Type, ref.ID, entry.Reason))
+		}
+	}
+
+	return report.String()
+}
+
+// applyFlowSelection restricts opts.Filter to the transitive closure of opts.Flows, if any
+func applyFlowSelection(ctx context.Context, client *api.Client, opts *ExportOptions, logger grpc.Logger) error {
+	if len(opts.Flows) == 0 {
+		return nil
+	}
+
+	if err := logger.Message(fmt.Sprintf("Resolving dependencies of selected flows: %s", strings.Join(opts.Flows, ", ")), nil); err != nil {
+		return fmt.Errorf("failed to log message: %w", err)
+	}
+
+	// The exporters fetch the same flows and policies again; serve them from memory
+	client.EnableResponseCache()
+
+	closure, err := ResolveFlowClosure(ctx, client, opts.Flows)
+	if err != nil {
+		return fmt.Errorf("failed to resolve flow selection: %w", err)
+	}
+
+	if err := logger.Message(closure.Report(), nil); err != nil {
+		return fmt.Errorf("failed to log message: %w", err)
+	}
+
+	opts.Filter = opts.Filter.restrictTo(closure.Graph)
+	return nil
+}
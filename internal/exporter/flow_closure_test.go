@@ -0,0 +1,121 @@
+package exporter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flowClosureDataset returns an environment with two independent flow trees:
+// Login -> (Http connector, companyBool variable by ID and name, Subflow) with policy "Login Policy" on "Web App",
+// and Other -> (Mail connector) with policy "Other Policy" on "Other App"
+func flowClosureDataset(t *testing.T) *fakeserver.Dataset {
+	t.Helper()
+	variableID := "229b519c-867d-4423-aeea-178c15c73d5f"
+
+	return &fakeserver.Dataset{
+		EnvironmentID: "62f10a04-6c54-40c2-a97d-80a98522ff9a",
+		Variables:     []map[string]interface{}{fixtureVariable(t)},
+		ConnectorInstances: jsonObjects(t, `[
+			{"id": "conn-1", "name": "Http", "connector": {"id": "httpConnector"}, "properties": {}},
+			{"id": "conn-2", "name": "Mail", "connector": {"id": "smtpConnector"}, "properties": {}}
+		]`),
+		Flows: jsonObjects(t, `[
+			{"id": "flow-1", "name": "Login", "graphData": {"elements": {"nodes": [
+				{"data": {"id": "n1", "nodeType": "CONNECTION", "connectionId": "conn-1", "connectorId": "httpConnector"}},
+				{"data": {"id": "n2", "nodeType": "CONNECTION", "properties": {"variableId": "`+variableID+`", "subFlowId": "flow-2", "message": {"value": "{{global.company.variables.companyBool}}"}}}}
+			]}}},
+			{"id": "flow-2", "name": "Subflow", "graphData": {"elements": {"nodes": []}}},
+			{"id": "flow-3", "name": "Other", "graphData": {"elements": {"nodes": [
+				{"data": {"id": "n1", "nodeType": "CONNECTION", "connectionId": "conn-2", "connectorId": "smtpConnector"}}
+			]}}}
+		]`),
+		Applications: jsonObjects(t, `[
+			{"id": "app-1", "name": "Web App", "apiKey": {"enabled": true, "value": "key-1"}, "oauth": {"clientSecret": "secret-1"}},
+			{"id": "app-2", "name": "Other App", "apiKey": {"enabled": true, "value": "key-2"}, "oauth": {"clientSecret": "secret-2"}}
+		]`),
+		FlowPolicies: jsonObjects(t, `[
+			{"id": "pol-1", "name": "Login Policy", "status": "enabled", "application": {"id": "app-1"}, "flowDistributions": [{"id": "flow-1", "version": -1, "weight": 100}]},
+			{"id": "pol-2", "name": "Other Policy", "status": "enabled", "application": {"id": "app-2"}, "flowDistributions": [{"id": "flow-3", "version": -1, "weight": 100}]}
+		]`),
+	}
+}
+
+func TestResolveFlowClosure(t *testing.T) {
+	client := newDatasetClient(t, flowClosureDataset(t))
+
+	t.Run("Walks forward and reverse edges from the selected flow", func(t *testing.T) {
+		closure, err := ResolveFlowClosure(context.Background(), client, []string{"Login"})
+		require.NoError(t, err)
+
+		var got []string
+		for _, entry := range closure.Entries {
+			got = append(got, entry.Resource.Type+":"+entry.Resource.ID)
+		}
+		assert.ElementsMatch(t, []string{
+			"pingone_davinci_flow:flow-1",
+			"pingone_davinci_connector_instance:conn-1",
+			"pingone_davinci_variable:229b519c-867d-4423-aeea-178c15c73d5f",
+			"pingone_davinci_flow:flow-2",
+			"pingone_davinci_application_flow_policy:pol-1",
+			"pingone_davinci_application:app-1",
+		}, got)
+
+		report := closure.Report()
+		assert.Contains(t, report, `pingone_davinci_flow "Login" (flow-1): selected with --flow`)
+		assert.Contains(t, report, `pingone_davinci_connector_instance "Http" (conn-1): connection_id of pingone_davinci_flow "Login"`)
+		assert.Contains(t, report, `pingone_davinci_flow "Subflow" (flow-2): subflow_id of pingone_davinci_flow "Login"`)
+		assert.Contains(t, report, `(pol-1): flow policy referencing pingone_davinci_flow "Login"`)
+		assert.Contains(t, report, `application_id of pingone_davinci_application_flow_policy "Login Policy"`)
+		assert.NotContains(t, report, "flow-3")
+	})
+
+	t.Run("Selects flows by ID", func(t *testing.T) {
+		closure, err := ResolveFlowClosure(context.Background(), client, []string{"flow-2"})
+		require.NoError(t, err)
+		require.Len(t, closure.Entries, 1)
+		assert.Equal(t, "Subflow", closure.Entries[0].Resource.Name)
+	})
+
+	t.Run("Returns error for unknown flow", func(t *testing.T) {
+		_, err := ResolveFlowClosure(context.Background(), client, []string{"Missing"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `flow "Missing" not found`)
+	})
+}
+
+func TestMatchFlowSelector(t *testing.T) {
+	flows := []api.FlowSummary{
+		{FlowID: "flow-1", Name: "Login"},
+		{FlowID: "flow-2", Name: "Login"},
+	}
+
+	_, err := matchFlowSelector(flows, "Login")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+
+	id, err := matchFlowSelector(flows, "flow-2")
+	require.NoError(t, err)
+	assert.Equal(t, "flow-2", id)
+}
+
+// TestExportEnvironmentForModule_FlowSelection verifies only the closure of the selected flows is exported
+func TestExportEnvironmentForModule_FlowSelection(t *testing.T) {
+	client := newDatasetClient(t, flowClosureDataset(t))
+
+	logger := &mockLogger{}
+	data, err := ExportEnvironmentForModule(context.Background(), client, ExportOptions{Flows: []string{"Login"}}, logger)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, strings.Count(flowsOf(t, data), `resource "pingone_davinci_flow"`))
+	assert.NotContains(t, flowsOf(t, data), `"Other"`)
+	assert.Contains(t, flowsOf(t, data), "pingone_davinci_connector_instance.pingcli__Http.id")
+	assert.Len(t, exportedOfType(data, "pingone_davinci_connector_instance"), 1)
+	assert.Len(t, exportedOfType(data, "pingone_davinci_variable"), 1)
+	assert.Contains(t, strings.Join(logger.messages, "\n"), "Flow selection: 6 resources in transitive closure")
+}
@@ -8,99 +8,132 @@ import (
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
-	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ExportFlows retrieves flows from the API and converts them to Terraform HCL
 func ExportFlows(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph) (string, error) {
-	hcl, _, err := ExportFlowsWithImports(ctx, client, skipDeps, graph, nil)
+	hcl, _, err := ExportFlowsWithImports(ctx, client, skipDeps, graph, nil, nil)
 	return hcl, err
 }
 
 // ExportFlowsWithImports exports flows with optional import blocks
 // Returns HCL string and import blocks for module generation
-func ExportFlowsWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator) (string, []RawImportBlock, error) {
+// Flows rejected by filter (nil exports all) are recorded on the graph's missing dependency tracker
+func ExportFlowsWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator, filter *ResourceFilter) (string, []RawImportBlock, error) {
 	if client == nil {
 		return "", nil, fmt.Errorf("API client is required")
 	}
 
-	// Retrieve all flows from the environment
-	flowSummaries, err := client.ListFlows(ctx)
+	resources, _, err := newAPIConversion(client, skipDeps, graph, importGen, filter).convertFlows(ctx)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to list flows: %w", err)
+		return "", nil, err
 	}
 
-	if len(flowSummaries) == 0 {
-		return "# No flows found in environment\n", nil, nil
+	hcl, err := flowsHCL(resources)
+	if err != nil {
+		return "", nil, err
 	}
+	return hcl, rawImportBlocks(resources), nil
+}
 
-	var namedBlocks []utils.NamedHCL
-	var importBlocks []RawImportBlock
+// flowsHCL renders flow resources
+func flowsHCL(resources []*model.Resource) (string, error) {
+	if len(resources) == 0 {
+		return "# No flows found in environment\n", nil
+	}
 
+	hcl, err := resourcesHCL(resources)
+	if err != nil {
+		return "", fmt.Errorf("failed to write flows: %w", err)
+	}
+	return hcl, nil
+}
+
+// convertFlows converts the selected flows to resources and extracts their variable-eligible
+// settings. Each flow becomes a flow, flow_enable and flow_deploy resource; flow_deploy has
+// nothing to import.
+func (c *conversion) convertFlows(ctx context.Context) ([]*model.Resource, []converter.VariableEligibleAttribute, error) {
 	// First pass: Register all flows in the dependency graph
-	for _, summary := range flowSummaries {
-		sanitizedName := resolver.SanitizeName(summary.Name, nil)
-		graph.AddResource("pingone_davinci_flow", summary.FlowID, sanitizedName)
+	flows, err := c.registerFlows(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	var resources []*model.Resource
+	var extractedVariables []converter.VariableEligibleAttribute
+
 	// Second pass: Retrieve detailed flow data and convert each flow
-	for _, summary := range flowSummaries {
-		// Get the actual resource name from the graph (includes deduplication suffix if needed)
-		actualName, err := graph.GetReferenceName("pingone_davinci_flow", summary.FlowID)
+	for _, flow := range flows {
+		flowJSON, err := c.source.payload(ctx, flow)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to get resource name for flow %s: %w", summary.FlowID, err)
+			return nil, nil, err
 		}
 
-		// Track import block separately if import generator provided
-		if importGen != nil {
-			importIDStr := fmt.Sprintf("%s/%s", client.EnvironmentID, summary.FlowID)
-			importBlocks = append(importBlocks, RawImportBlock{
-				ResourceType: "pingone_davinci_flow",
-				ResourceName: actualName,
-				ImportID:     importIDStr,
-			})
-
-			// Also include import block for the auxiliary flow_enable resource
-			importBlocks = append(importBlocks, RawImportBlock{
-				ResourceType: "pingone_davinci_flow_enable",
-				ResourceName: actualName,
-				ImportID:     importIDStr,
-			})
+		var flowData map[string]interface{}
+		if err := json.Unmarshal(flowJSON, &flowData); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse flow %s: %w", flow.ID, err)
 		}
 
-		flowDetail, err := client.GetFlow(ctx, summary.FlowID)
+		// Convert using the converter with dependency graph, which names the resources after
+		// the flow's registered name
+		flowResources, err := converter.ConvertFlowToResources(flowData, c.envRef(), c.skipDeps, c.graph)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to get flow %s (%s): %w", summary.Name, summary.FlowID, err)
+			return nil, nil, fmt.Errorf("failed to convert flow %s to HCL: %w", flow.Name, err)
 		}
 
-		// Convert the flow detail to the format expected by the converter
-		flowData, err := convertFlowDetailToMap(flowDetail)
+		// Extract variable-eligible settings for module generation
+		flowAttrs, err := converter.GetFlowVariableEligibleAttributes(flowJSON, flowResources[0].Name)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to convert flow %s to map: %w", summary.Name, err)
+			return nil, nil, fmt.Errorf("failed to extract flow attributes for %s: %w", flow.ID, err)
 		}
-
-		// Determine environment_id value based on skipDeps flag
-		envID := "var.pingone_environment_id"
-		if skipDeps {
-			envID = client.EnvironmentID
+		extractedVariables = append(extractedVariables, flowAttrs...)
+
+		// flow_enable shares the flow's import ID
+		for _, resource := range flowResources {
+			switch resource.Type {
+			case "pingone_davinci_flow", "pingone_davinci_flow_enable":
+				resource.ImportID = c.importID(flow.ID)
+			}
 		}
 
-		// Convert to HCL using the converter with dependency graph
-		hcl, err := converter.ConvertFlowToHCL(flowData, envID, skipDeps, graph)
-		if err != nil {
-			return "", nil, fmt.Errorf("failed to convert flow %s to HCL: %w", summary.Name, err)
-		}
+		resources = append(resources, flowResources...)
+	}
+
+	return resources, extractedVariables, nil
+}
 
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: "", HCL: hcl})
+// registerFlows registers the selected flows in the dependency graph and returns them.
+// Exports call it before converting variables so flow-context variables can reference their
+// flow; flows already in the graph keep their name.
+func (c *conversion) registerFlows(ctx context.Context) ([]sourceResource, error) {
+	flows, err := c.selected(ctx, "pingone_davinci_flow")
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort by resource name to ensure deterministic output
-	return utils.JoinHCLBlocksSorted(namedBlocks), importBlocks, nil
+	for _, flow := range flows {
+		if !c.graph.HasResource("pingone_davinci_flow", flow.ID) {
+			c.graph.AddResource("pingone_davinci_flow", flow.ID, c.graph.ResourceName(flow.ID, flow.Name))
+		}
+	}
+	return flows, nil
 }
 
-// convertFlowDetailToMap converts FlowDetail to map[string]interface{} for the converter
+// convertFlowDetailToMap converts FlowDetail to map[string]interface{} for the converter.
+// The full API response is used when available, so the converter sees the same payload as
+// when converting a saved flow.
 func convertFlowDetailToMap(flow *api.FlowDetail) (map[string]interface{}, error) {
+	if flow.Raw != nil {
+		flowMap := make(map[string]interface{}, len(flow.Raw)+1)
+		for key, value := range flow.Raw {
+			flowMap[key] = value
+		}
+		flowMap["flowId"] = flow.FlowID
+		return flowMap, nil
+	}
+
 	// Create a flow structure compatible with the converter's expected format
 	flowMap := map[string]interface{}{
 		"name":        flow.Name,
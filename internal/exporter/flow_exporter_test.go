@@ -42,6 +42,27 @@ func TestExportFlows(t *testing.T) {
 	})
 }
 
+func TestExportFlowsRecordsDependencies(t *testing.T) {
+	client := newDatasetClient(t, flowClosureDataset(t))
+
+	data, err := ExportEnvironmentForModule(context.Background(), client, ExportOptions{}, &mockLogger{})
+	require.NoError(t, err)
+
+	assert.Contains(t, flowsOf(t, data), `"variableId" = pingone_davinci_variable.pingcli__companyBool_company.id`)
+	assert.Contains(t, flowsOf(t, data), `"value" = "{{global.company.variables.${pingone_davinci_variable.pingcli__companyBool_company.name}}}"`)
+	assert.Contains(t, flowsOf(t, data), `"subFlowId"  = pingone_davinci_flow.pingcli__Subflow.id`)
+
+	fields := map[string]string{}
+	for _, dep := range data.DependencyGraph.GetDependencies("flow-1") {
+		fields[dep.Field] = dep.To.Name
+	}
+	assert.Equal(t, map[string]string{
+		"variable_id":   "pingcli__companyBool_company",
+		"variable_name": "pingcli__companyBool_company",
+		"subflow_id":    "pingcli__Subflow",
+	}, fields)
+}
+
 func TestExportFlowsJSON(t *testing.T) {
 	t.Run("Returns error when client is nil", func(t *testing.T) {
 		json, err := ExportFlowsJSON(context.Background(), nil)
@@ -8,81 +8,106 @@ import (
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
-	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ExportFlowPolicies exports all flow policies to Terraform HCL
 func ExportFlowPolicies(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph) (string, error) {
-	hcl, _, err := ExportFlowPoliciesWithImports(ctx, client, skipDeps, graph, nil)
+	hcl, _, err := ExportFlowPoliciesWithImports(ctx, client, skipDeps, graph, nil, nil)
 	return hcl, err
 }
 
 // ExportFlowPoliciesWithImports exports flow policies with optional import blocks
 // Returns HCL string and import blocks for module generation
-func ExportFlowPoliciesWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator) (string, []RawImportBlock, error) {
-	policies, err := client.ListFlowPolicies(ctx)
+// Policies rejected by filter (nil exports all) are skipped; nothing references them, so the
+// flow policy API is not called at all when the type is filtered out
+func ExportFlowPoliciesWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator, filter *ResourceFilter) (string, []RawImportBlock, error) {
+	if client == nil {
+		return "", nil, fmt.Errorf("client cannot be nil")
+	}
+
+	resources, _, err := newAPIConversion(client, skipDeps, graph, importGen, filter).convertFlowPolicies(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hcl, err := flowPoliciesHCL(resources)
+	if err != nil {
+		return "", nil, err
+	}
+	return hcl, rawImportBlocks(resources), nil
+}
+
+// flowPoliciesHCL renders flow policy resources under a header with their count
+func flowPoliciesHCL(resources []*model.Resource) (string, error) {
+	if len(resources) == 0 {
+		return "# No flow policies found\n\n", nil
+	}
+
+	hcl, err := resourcesHCL(resources)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to list flow policies: %w", err)
+		return "", fmt.Errorf("failed to write flow policies: %w", err)
+	}
+
+	header := fmt.Sprintf("# Flow Policies (%d total)\n\n", len(resources))
+	return header + hcl, nil
+}
+
+// convertFlowPolicies converts the selected flow policies to resources and extracts their
+// variable-eligible attributes
+func (c *conversion) convertFlowPolicies(ctx context.Context) ([]*model.Resource, []converter.VariableEligibleAttribute, error) {
+	if !c.filter.IncludesType("pingone_davinci_application_flow_policy") {
+		return nil, nil, nil
 	}
+	graph := c.graph
 
-	if len(policies) == 0 {
-		return "# No flow policies found\n\n", nil, nil
+	policies, err := c.selected(ctx, "pingone_davinci_application_flow_policy")
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// First pass: Register all flow policies in the dependency graph
 	for _, policy := range policies {
-		sanitizedName := resolver.SanitizeName(policy.Name, nil)
-		graph.AddResource("pingone_davinci_application_flow_policy", policy.PolicyID, sanitizedName)
+		sanitizedName := graph.ResourceName(policy.ID, policy.Name)
+		graph.AddResource("pingone_davinci_application_flow_policy", policy.ID, sanitizedName)
 	}
 
-	var namedBlocks []utils.NamedHCL
-	var importBlocks []RawImportBlock
+	var resources []*model.Resource
+	var extractedVariables []converter.VariableEligibleAttribute
 
-	// Second pass: Convert each flow policy to HCL
+	// Second pass: Convert each flow policy to a resource
 	for _, policy := range policies {
 		// Get the sanitized resource name from the graph
-		resourceName, err := graph.GetReferenceName("pingone_davinci_application_flow_policy", policy.PolicyID)
+		resourceName, err := graph.GetReferenceName("pingone_davinci_application_flow_policy", policy.ID)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to get resource name for flow policy %s: %w", policy.PolicyID, err)
-		}
-
-		// Track import block separately if import generator provided
-		// Note: Flow policy assignments have a special 3-part ID format
-		if importGen != nil {
-			// Build the 3-part import ID: env_id/app_id/policy_id
-			importIDStr := fmt.Sprintf("%s/%s/%s", client.EnvironmentID, policy.ApplicationID, policy.PolicyID)
-			importBlocks = append(importBlocks, RawImportBlock{
-				ResourceType: "pingone_davinci_application_flow_policy",
-				ResourceName: resourceName,
-				ImportID:     importIDStr,
-			})
+			return nil, nil, fmt.Errorf("failed to get resource name for flow policy %s: %w", policy.ID, err)
 		}
 
-		detail, err := client.GetFlowPolicy(ctx, policy.ApplicationID, policy.PolicyID)
+		policyJSON, err := c.source.payload(ctx, policy)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to get flow policy %s: %w", policy.PolicyID, err)
+			return nil, nil, err
 		}
 
-		// Get environment ID - pass raw string for var reference or quoted UUID
-		var environmentID string
-		if skipDeps {
-			environmentID = client.EnvironmentID // Will be quoted by converter
-		} else {
-			environmentID = "var.pingone_environment_id" // Will be written as-is by converter
+		resource, err := converter.ConvertFlowPolicyJSONToResource(policyJSON, resourceName, policy.ApplicationID, c.envRef(), c.skipDeps, graph)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert flow policy %s to Terraform: %w", policy.ID, err)
 		}
 
-		hcl, err := converter.ConvertFlowPolicyToTerraform(detail.RawResponse, resourceName, policy.ApplicationID, environmentID, skipDeps, graph)
+		// Extract variable-eligible attributes for module generation
+		policyAttrs, err := converter.GetFlowPolicyVariableEligibleAttributes(policyJSON, resourceName)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to convert flow policy %s to Terraform: %w", policy.PolicyID, err)
+			return nil, nil, fmt.Errorf("failed to extract flow policy attributes for %s: %w", policy.ID, err)
 		}
+		extractedVariables = append(extractedVariables, policyAttrs...)
+
+		// Note: Flow policies have a special 3-part ID format: env_id/app_id/policy_id
+		resource.ImportID = c.importID(policy.ApplicationID, policy.ID)
 
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: "", HCL: hcl})
+		resources = append(resources, resource)
 	}
 
-	// Sort by resource name to ensure deterministic output
-	header := fmt.Sprintf("# Flow Policies (%d total)\n\n", len(policies))
-	return header + utils.JoinHCLBlocksSorted(namedBlocks), importBlocks, nil
+	return resources, extractedVariables, nil
 }
 
 // ensureUniqueFlowPolicyResourceName ensures resource names are unique by appending suffixes
@@ -0,0 +1,288 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pingidentity/pingcli/shared/grpc"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+)
+
+// LocalResources holds DaVinci resource payloads loaded from local JSON files
+// (DaVinci UI flow exports, multi-flow exports or saved PingOne API responses)
+type LocalResources struct {
+	Flows        []map[string]interface{}
+	Variables    [][]byte
+	Connectors   [][]byte
+	Applications [][]byte
+	FlowPolicies [][]byte
+
+	// EnvironmentID is the first environment ID found in the payloads, if any
+	EnvironmentID string
+
+	seenFlows map[string]bool
+}
+
+// embeddedCollectionKeys lists HAL collection keys and the resource type they contain, in the
+// order they are loaded so the environment ID comes from the same collection every time
+var embeddedCollectionKeys = []struct {
+	key          string
+	resourceType string
+}{
+	{key: "flows", resourceType: "pingone_davinci_flow"},
+	{key: "variables", resourceType: "pingone_davinci_variable"},
+	{key: "connectorInstances", resourceType: "pingone_davinci_connector_instance"},
+	{key: "davinciApplications", resourceType: "pingone_davinci_application"},
+	{key: "flowPolicies", resourceType: "pingone_davinci_application_flow_policy"},
+}
+
+// LoadLocalResources reads DaVinci JSON from the given files and directories.
+// Directories are walked recursively and every *.json file is loaded.
+func LoadLocalResources(paths []string) (*LocalResources, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("at least one input path is required")
+	}
+
+	res := &LocalResources{seenFlows: make(map[string]bool)}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input %s: %w", path, err)
+		}
+
+		if !info.IsDir() {
+			if err := res.loadFile(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// filepath.WalkDir visits entries in lexical order, keeping output deterministic
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".json") {
+				return nil
+			}
+			return res.loadFile(p)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load directory %s: %w", path, err)
+		}
+	}
+
+	return res, nil
+}
+
+// Count returns the total number of loaded resources
+func (r *LocalResources) Count() int {
+	return len(r.Flows) + len(r.Variables) + len(r.Connectors) + len(r.Applications) + len(r.FlowPolicies)
+}
+
+// loadFile parses a single JSON file and adds its resources
+func (r *LocalResources) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	switch v := payload.(type) {
+	case []interface{}:
+		for i, item := range v {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s: element %d is not a JSON object", path, i)
+			}
+			if err := r.add(obj, ""); err != nil {
+				return fmt.Errorf("%s: element %d: %w", path, i, err)
+			}
+		}
+	case map[string]interface{}:
+		if err := r.addDocument(v); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("%s: expected a JSON object or array", path)
+	}
+
+	return nil
+}
+
+// addDocument handles the top-level forms: multi-flow exports ("flows" array),
+// HAL collections ("_embedded") and single resources
+func (r *LocalResources) addDocument(doc map[string]interface{}) error {
+	if flows, ok := doc["flows"].([]interface{}); ok {
+		for i, item := range flows {
+			flow, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("flows[%d] is not a JSON object", i)
+			}
+			if err := r.add(flow, "pingone_davinci_flow"); err != nil {
+				return fmt.Errorf("flows[%d]: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	if embedded, ok := doc["_embedded"].(map[string]interface{}); ok {
+		for _, collection := range embeddedCollectionKeys {
+			key := collection.key
+			items, ok := embedded[key].([]interface{})
+			if !ok {
+				continue
+			}
+			for i, item := range items {
+				obj, ok := item.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("_embedded.%s[%d] is not a JSON object", key, i)
+				}
+				if err := r.add(obj, collection.resourceType); err != nil {
+					return fmt.Errorf("_embedded.%s[%d]: %w", key, i, err)
+				}
+			}
+		}
+		return nil
+	}
+
+	return r.add(doc, "")
+}
+
+// add classifies a single resource object (unless resourceType is given) and stores it
+func (r *LocalResources) add(obj map[string]interface{}, resourceType string) error {
+	if resourceType == "" {
+		resourceType = classifyLocalResource(obj)
+	}
+	if resourceType == "" {
+		return fmt.Errorf("unrecognized DaVinci resource JSON (expected a flow, variable, connector instance, application or flow policy)")
+	}
+
+	if r.EnvironmentID == "" {
+		if env, ok := obj["environment"].(map[string]interface{}); ok {
+			if id, ok := env["id"].(string); ok {
+				r.EnvironmentID = id
+			}
+		}
+	}
+
+	if resourceType == "pingone_davinci_flow" {
+		flowID := localFlowID(obj)
+		if flowID != "" {
+			if r.seenFlows[flowID] {
+				return nil
+			}
+			r.seenFlows[flowID] = true
+		}
+		r.Flows = append(r.Flows, obj)
+		return nil
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource: %w", err)
+	}
+
+	switch resourceType {
+	case "pingone_davinci_variable":
+		r.Variables = append(r.Variables, raw)
+	case "pingone_davinci_connector_instance":
+		r.Connectors = append(r.Connectors, raw)
+	case "pingone_davinci_application":
+		r.Applications = append(r.Applications, raw)
+	case "pingone_davinci_application_flow_policy":
+		r.FlowPolicies = append(r.FlowPolicies, raw)
+	}
+
+	return nil
+}
+
+// classifyLocalResource determines the resource type of a JSON object from its fields
+func classifyLocalResource(obj map[string]interface{}) string {
+	has := func(key string) bool {
+		_, ok := obj[key]
+		return ok
+	}
+
+	switch {
+	case has("flowId") || has("graphData"):
+		return "pingone_davinci_flow"
+	case has("flowDistributions"):
+		return "pingone_davinci_application_flow_policy"
+	case has("dataType") && has("context"):
+		return "pingone_davinci_variable"
+	case has("connector"):
+		return "pingone_davinci_connector_instance"
+	case has("oauth") || has("apiKey"):
+		return "pingone_davinci_application"
+	default:
+		return ""
+	}
+}
+
+// localFlowID returns the flow ID from a DaVinci export ("flowId") or API payload ("id")
+func localFlowID(flow map[string]interface{}) string {
+	if id, ok := flow["flowId"].(string); ok && id != "" {
+		return id
+	}
+	if id, ok := flow["id"].(string); ok {
+		return id
+	}
+	return ""
+}
+
+// ExportLocalResourcesForModule converts locally loaded resources into ExportedData through
+// the same conversion as ExportEnvironmentForModule; only the source of the payloads differs.
+// environmentID overrides the ID detected from the payloads; import blocks are only
+// generated when an environment ID is known.
+func ExportLocalResourcesForModule(res *LocalResources, environmentID string, opts ExportOptions, logger grpc.Logger) (*ExportedData, error) {
+	if res == nil {
+		return nil, fmt.Errorf("local resources are required")
+	}
+	if len(opts.Flows) > 0 {
+		return nil, fmt.Errorf("flow selection is not supported for local resources")
+	}
+	if environmentID == "" {
+		environmentID = res.EnvironmentID
+	}
+
+	data := &ExportedData{EnvironmentID: environmentID}
+
+	var importGen *importgen.ImportBlockGenerator
+	if opts.GenerateImports {
+		if environmentID == "" {
+			if err := logger.Warn("No environment ID found in input; import blocks will not be generated", nil); err != nil {
+				return nil, fmt.Errorf("failed to log warning: %w", err)
+			}
+		} else {
+			importGen = importgen.NewImportBlockGenerator()
+		}
+	}
+
+	if err := logger.Message(fmt.Sprintf("Converting %d local DaVinci resources for module generation...", res.Count()), nil); err != nil {
+		return nil, fmt.Errorf("failed to log message: %w", err)
+	}
+
+	c := &conversion{
+		source:        localSource{resources: res},
+		environmentID: environmentID,
+		skipDeps:      opts.SkipDependencies,
+		graph:         newDependencyGraph(opts),
+		filter:        opts.Filter,
+		importGen:     importGen,
+	}
+	if err := exportForModule(context.Background(), c, data, logger); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,263 @@
+package exporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const converterTestdata = "../converter/testdata"
+
+func TestLoadLocalResources(t *testing.T) {
+	t.Run("Loads a single DaVinci flow export", func(t *testing.T) {
+		res, err := LoadLocalResources([]string{filepath.Join(converterTestdata, "simple-flow.json")})
+		require.NoError(t, err)
+		assert.Len(t, res.Flows, 1)
+		assert.Equal(t, 1, res.Count())
+	})
+
+	t.Run("Loads a multi-flow export", func(t *testing.T) {
+		flow1, err := os.ReadFile(filepath.Join(converterTestdata, "simple-flow.json"))
+		require.NoError(t, err)
+		flow2, err := os.ReadFile(filepath.Join(converterTestdata, "simple-flow-2.json"))
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "multi.json")
+		multi := `{"companyId": "x", "flows": [` + string(flow1) + `,` + string(flow2) + `]}`
+		require.NoError(t, os.WriteFile(path, []byte(multi), 0644))
+
+		res, err := LoadLocalResources([]string{path})
+		require.NoError(t, err)
+		assert.Len(t, res.Flows, 2)
+	})
+
+	t.Run("Loads a directory of API responses", func(t *testing.T) {
+		res, err := LoadLocalResources([]string{filepath.Join(converterTestdata, "api_responses")})
+		require.NoError(t, err)
+		assert.Len(t, res.Flows, 1)
+		assert.Len(t, res.Variables, 2)
+		assert.Len(t, res.Connectors, 1)
+		assert.Len(t, res.Applications, 1)
+		assert.Len(t, res.FlowPolicies, 1)
+		assert.Equal(t, "62f10a04-6c54-40c2-a97d-80a98522ff9a", res.EnvironmentID)
+	})
+
+	t.Run("Deduplicates flows with the same ID", func(t *testing.T) {
+		path := filepath.Join(converterTestdata, "simple-flow.json")
+		res, err := LoadLocalResources([]string{path, path})
+		require.NoError(t, err)
+		assert.Len(t, res.Flows, 1)
+	})
+
+	t.Run("Loads HAL collections", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "apps.json")
+		collection := `{"_embedded": {"davinciApplications": [
+			{"id": "app-1", "name": "App One", "oauth": {}},
+			{"id": "app-2", "name": "App Two", "apiKey": {}}
+		]}}`
+		require.NoError(t, os.WriteFile(path, []byte(collection), 0644))
+
+		res, err := LoadLocalResources([]string{path})
+		require.NoError(t, err)
+		assert.Len(t, res.Applications, 2)
+	})
+
+	t.Run("Takes environment ID from HAL collections in a fixed order", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "collections.json")
+		collection := `{"_embedded": {
+			"variables": [{"id": "var-1", "name": "color", "context": "company", "environment": {"id": "env-variables"}}],
+			"flows": [{"id": "flow-1", "name": "Login", "environment": {"id": "env-flows"}}]
+		}}`
+		require.NoError(t, os.WriteFile(path, []byte(collection), 0644))
+
+		for range 10 {
+			res, err := LoadLocalResources([]string{path})
+			require.NoError(t, err)
+			assert.Equal(t, "env-flows", res.EnvironmentID)
+		}
+	})
+
+	t.Run("Returns error for unrecognized JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "unknown.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"foo": "bar"}`), 0644))
+
+		_, err := LoadLocalResources([]string{path})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unrecognized DaVinci resource JSON")
+	})
+
+	t.Run("Returns error when no paths given", func(t *testing.T) {
+		_, err := LoadLocalResources(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestExportLocalResourcesForModule(t *testing.T) {
+	t.Run("Converts API responses with import blocks", func(t *testing.T) {
+		res, err := LoadLocalResources([]string{filepath.Join(converterTestdata, "api_responses")})
+		require.NoError(t, err)
+
+		data, err := ExportLocalResourcesForModule(res, "", ExportOptions{GenerateImports: true}, &mockLogger{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "62f10a04-6c54-40c2-a97d-80a98522ff9a", data.EnvironmentID)
+		assert.Contains(t, flowsOf(t, data), `resource "pingone_davinci_flow"`)
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_variable"), `resource "pingone_davinci_variable"`)
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_connector_instance"), `resource "pingone_davinci_connector_instance"`)
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_application"), `resource "pingone_davinci_application"`)
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_application_flow_policy"), `resource "pingone_davinci_application_flow_policy"`)
+		assert.Len(t, exportedOfType(data, "pingone_davinci_variable"), 2)
+		assert.Len(t, exportedOfType(data, "pingone_davinci_flow"), 1)
+
+		// Two variable imports, flow and flow_enable imports, and one import for each other resource
+		require.Len(t, rawImportBlocks(data.Resources), 7)
+		policyImports := 0
+		for _, block := range rawImportBlocks(data.Resources) {
+			assert.True(t, strings.HasPrefix(block.ImportID, data.EnvironmentID+"/"), block.ImportID)
+			if strings.HasSuffix(block.ImportID, "/3f1c9d2e7a8b4c6d9e0f1a2b3c4d5e6f") {
+				policyImports++
+			}
+		}
+		assert.Equal(t, 1, policyImports, "the flow policy is imported once")
+	})
+
+	t.Run("Matches an export of the same payloads from the API", func(t *testing.T) {
+		dataset, err := fakeserver.LoadDefaultDataset()
+		require.NoError(t, err)
+		server := fakeserver.New(dataset, fakeserver.Options{})
+		t.Cleanup(server.Close)
+		client, err := api.NewClientWithOptions(context.Background(), server.EnvironmentID(), server.EnvironmentID(), "NA", fakeserver.ClientID, fakeserver.ClientSecret, api.ClientOptions{
+			APIURL:  server.URL,
+			AuthURL: server.URL,
+		})
+		require.NoError(t, err)
+
+		for _, skipDeps := range []bool{false, true} {
+			opts := ExportOptions{GenerateImports: true, SkipDependencies: skipDeps}
+			fromAPI, err := ExportEnvironmentForModule(context.Background(), client, opts, &mockLogger{})
+			require.NoError(t, err)
+
+			res, err := LoadLocalResources([]string{fakeserver.FixtureDir()})
+			require.NoError(t, err)
+			fromJSON, err := ExportLocalResourcesForModule(res, "", opts, &mockLogger{})
+			require.NoError(t, err)
+
+			apiHCL, err := model.HCL(fromAPI.Resources...)
+			require.NoError(t, err)
+			jsonHCL, err := model.HCL(fromJSON.Resources...)
+			require.NoError(t, err)
+			assert.Equal(t, apiHCL, jsonHCL)
+			assert.Equal(t, rawImportBlocks(fromAPI.Resources), rawImportBlocks(fromJSON.Resources))
+			assert.ElementsMatch(t, fromAPI.ExtractedVariables, fromJSON.ExtractedVariables)
+		}
+	})
+
+	t.Run("Skips import blocks without environment ID", func(t *testing.T) {
+		res, err := LoadLocalResources([]string{filepath.Join(converterTestdata, "simple-flow.json")})
+		require.NoError(t, err)
+
+		logger := &mockLogger{}
+		data, err := ExportLocalResourcesForModule(res, "", ExportOptions{GenerateImports: true}, logger)
+		require.NoError(t, err)
+
+		assert.Empty(t, rawImportBlocks(data.Resources))
+		assert.NotEmpty(t, logger.warnings)
+		assert.Contains(t, flowsOf(t, data), "var.pingone_environment_id")
+	})
+
+	t.Run("Environment ID override is used for imports", func(t *testing.T) {
+		res, err := LoadLocalResources([]string{filepath.Join(converterTestdata, "flow-directory")})
+		require.NoError(t, err)
+
+		data, err := ExportLocalResourcesForModule(res, "env-override", ExportOptions{GenerateImports: true}, &mockLogger{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "env-override", data.EnvironmentID)
+		require.NotEmpty(t, rawImportBlocks(data.Resources))
+		assert.True(t, strings.HasPrefix(rawImportBlocks(data.Resources)[0].ImportID, "env-override/"))
+	})
+
+	t.Run("Converts flow policies using the application ID in the payload", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "app.json"), []byte(`{"id": "app-1", "name": "My App", "oauth": {}}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "policy.json"), []byte(`{
+			"id": "policy-1",
+			"name": "My Policy",
+			"status": "enabled",
+			"application": {"id": "app-1"},
+			"flowDistributions": [{"id": "flow-1", "version": -1, "weight": 100}]
+		}`), 0644))
+
+		res, err := LoadLocalResources([]string{dir})
+		require.NoError(t, err)
+
+		data, err := ExportLocalResourcesForModule(res, "", ExportOptions{}, &mockLogger{})
+		require.NoError(t, err)
+
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_application_flow_policy"), "pingone_davinci_application.pingcli__My-0020-App.id")
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_application_flow_policy"), "TODO")
+	})
+
+	t.Run("Extracts module variables from applications and flow policies", func(t *testing.T) {
+		res, err := LoadLocalResources([]string{filepath.Join(converterTestdata, "api_responses")})
+		require.NoError(t, err)
+
+		data, err := ExportLocalResourcesForModule(res, "", ExportOptions{}, &mockLogger{})
+		require.NoError(t, err)
+
+		config := module.ModuleConfig{OutputDir: t.TempDir(), ModuleDirName: "ping-export-module", ModuleName: "ping-export", IncludeValues: true}
+		structure, err := ConvertExportedDataToModuleStructure(data, config)
+		require.NoError(t, err)
+		require.NoError(t, module.NewGenerator(config).Generate(structure))
+
+		app, err := os.ReadFile(filepath.Join(config.OutputDir, "ping-export-module", "pingone_davinci_application.tf"))
+		require.NoError(t, err)
+		assert.Contains(t, string(app), "redirect_uris = var.davinci_application_reCAPTCHA-0020-Sample-0020-Application_redirect_uris")
+
+		policy, err := os.ReadFile(filepath.Join(config.OutputDir, "ping-export-module", "pingone_davinci_application_flow_policy.tf"))
+		require.NoError(t, err)
+		assert.Contains(t, string(policy), "= var.davinci_flow_policy_")
+
+		tfvars, err := os.ReadFile(filepath.Join(config.OutputDir, "ping-export-terraform.auto.tfvars"))
+		require.NoError(t, err)
+		assert.Contains(t, string(tfvars), `davinci_application_reCAPTCHA-0020-Sample-0020-Application_redirect_uris = ["https://example.com/callback"]`)
+		assert.Regexp(t, `davinci_flow_policy_\S+_weight_0 = 100`, string(tfvars))
+	})
+
+	t.Run("References the flow of flow-context variables", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "flow.json"), []byte(`{"flowId": "flow-1", "name": "Login", "graphData": {"elements": {"nodes": []}}}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "variables.json"), []byte(`[
+			{"id": "var-1", "name": "attempts", "dataType": "number", "context": "flow", "mutable": true, "flow": {"id": "flow-1"}},
+			{"id": "var-2", "name": "orphan", "dataType": "number", "context": "flow", "mutable": true, "flow": {"id": "flow-9"}}
+		]`), 0644))
+
+		res, err := LoadLocalResources([]string{dir})
+		require.NoError(t, err)
+
+		// Variables are converted before flows
+		data, err := ExportLocalResourcesForModule(res, "", ExportOptions{}, &mockLogger{})
+		require.NoError(t, err)
+
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_variable"), "id = pingone_davinci_flow.pingcli__Login.id")
+		assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_variable"), `id = "" # TODO: Reference to (pingone_davinci_flow flow-9) not found in environment`)
+		deps := data.DependencyGraph.GetDependencies("var-1")
+		require.Len(t, deps, 1)
+		assert.Equal(t, "pingcli__Login", deps[0].To.Name)
+
+		structure, err := ConvertExportedDataToModuleStructure(data, module.ModuleConfig{ModuleName: "ping-export"})
+		require.NoError(t, err)
+		hcl, err := model.HCL(structure.Resources...)
+		require.NoError(t, err)
+		assert.Contains(t, hcl, "id = pingone_davinci_flow.pingcli__Login.id")
+	})
+}
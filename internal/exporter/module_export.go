@@ -8,6 +8,7 @@ import (
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
@@ -20,21 +21,46 @@ type RawImportBlock struct {
 	ImportID     string // The import ID (e.g., "env-id/var-id")
 }
 
+// resourcesHCL renders resources sorted by resource name, keeping the resources converted from
+// one object together, e.g. a flow with its flow_enable and flow_deploy resources
+func resourcesHCL(resources []*model.Resource) (string, error) {
+	var namedBlocks []utils.NamedHCL
+	for start := 0; start < len(resources); {
+		end := start + 1
+		for end < len(resources) && resources[end].Name == resources[start].Name {
+			end++
+		}
+		hcl, err := model.HCL(resources[start:end]...)
+		if err != nil {
+			return "", err
+		}
+		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: resources[start].Name, HCL: hcl})
+		start = end
+	}
+	return utils.JoinHCLBlocksSorted(namedBlocks), nil
+}
+
+// rawImportBlocks returns the import blocks of the resources that have an import ID
+func rawImportBlocks(resources []*model.Resource) []RawImportBlock {
+	var importBlocks []RawImportBlock
+	for _, resource := range resources {
+		if resource.ImportID == "" {
+			continue
+		}
+		importBlocks = append(importBlocks, RawImportBlock{
+			ResourceType: resource.Type,
+			ResourceName: resource.Name,
+			ImportID:     resource.ImportID,
+		})
+	}
+	return importBlocks
+}
+
 // ExportedData contains structured export data for module generation
 type ExportedData struct {
-	// HCL sections by resource type (will be regenerated with variable references for modules)
-	VariablesHCL    string
-	ConnectorsHCL   string
-	FlowsHCL        string
-	ApplicationsHCL string
-	FlowPoliciesHCL string
-
-	// Raw JSON data for regeneration with variable references
-	// Maps resource ID to its JSON representation
-	VariablesJSON  map[string][]byte // Variable ID -> JSON
-	ConnectorsJSON map[string][]byte // Connector instance ID -> JSON
-	FlowsJSON      map[string][]byte // Flow ID -> JSON
-	ResourceNames  map[string]string // Resource ID -> sanitized resource name
+	// Resources in export order: variables, connector instances, flows, applications and flow
+	// policies. Import IDs are set when import blocks are generated.
+	Resources []*model.Resource
 
 	// Metadata
 	EnvironmentID   string
@@ -43,20 +69,13 @@ type ExportedData struct {
 
 	// Variable-eligible attributes extracted from resources
 	ExtractedVariables []converter.VariableEligibleAttribute
-
-	// Import blocks for root module (separate from resource HCL)
-	ImportBlocks []RawImportBlock
 }
 
 // ExportEnvironmentForModule exports DaVinci resources in a structure suitable for module generation
 func ExportEnvironmentForModule(ctx context.Context, client *api.Client, opts ExportOptions, logger grpc.Logger) (*ExportedData, error) {
 	data := &ExportedData{
-		EnvironmentID:  client.EnvironmentID,
-		Region:         client.Region,
-		VariablesJSON:  make(map[string][]byte),
-		ConnectorsJSON: make(map[string][]byte),
-		FlowsJSON:      make(map[string][]byte),
-		ResourceNames:  make(map[string]string),
+		EnvironmentID: client.EnvironmentID,
+		Region:        client.Region,
 	}
 
 	// Initialize import block generator if needed
@@ -65,153 +84,78 @@ func ExportEnvironmentForModule(ctx context.Context, client *api.Client, opts Ex
 		importGen = importgen.NewImportBlockGenerator()
 	}
 
-	// Initialize dependency graph
-	graph := resolver.NewDependencyGraph()
-	data.DependencyGraph = graph
-
-	// Track which resource types are included
-	missingTracker := resolver.NewMissingDependencyTracker()
-	includedTypes := []string{
-		"pingone_davinci_variable",
-		"pingone_davinci_connector_instance",
-		"pingone_davinci_flow",
-		"pingone_davinci_application",
-		"pingone_davinci_application_flow_policy",
+	// Restrict the export to the selected flows and their dependencies
+	if err := applyFlowSelection(ctx, client, &opts, logger); err != nil {
+		return nil, err
+	}
+	if err := prefetchResources(ctx, client, opts, logger); err != nil {
+		return nil, err
 	}
-	missingTracker.SetIncludedTypes(includedTypes)
 
 	// Log export start
 	if err := logger.Message("Exporting DaVinci resources for module generation...", nil); err != nil {
 		return nil, fmt.Errorf("failed to log message: %w", err)
 	}
 
-	// Export each resource type
-
-	// 1. Variables
-	if err := logger.Message("Fetching variables...", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
-	variablesHCL, variablesExtracted, variablesJSON, variableNames, variableImports, err := ExportVariablesForModule(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		return nil, fmt.Errorf("failed to export variables: %w", err)
-	}
-	data.VariablesHCL = variablesHCL
-	data.VariablesJSON = variablesJSON
-	// Store variable names in ResourceNames map
-	for id, name := range variableNames {
-		data.ResourceNames[id] = name
-	}
-	data.ExtractedVariables = append(data.ExtractedVariables, variablesExtracted...)
-	data.ImportBlocks = append(data.ImportBlocks, variableImports...)
-	if err := logger.Message("✓ Variables exported", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
-
-	// 2. Connector Instances
-	if err := logger.Message("Fetching connector instances...", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
-	connectorsHCL, connectorsExtracted, connectorsJSON, connectorNames, connectorImports, err := ExportConnectorInstancesForModule(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		return nil, fmt.Errorf("failed to export connector instances: %w", err)
-	}
-	data.ConnectorsHCL = connectorsHCL
-	data.ConnectorsJSON = connectorsJSON
-	// Merge connector names into ResourceNames map
-	for id, name := range connectorNames {
-		data.ResourceNames[id] = name
-	}
-	data.ExtractedVariables = append(data.ExtractedVariables, connectorsExtracted...)
-	data.ImportBlocks = append(data.ImportBlocks, connectorImports...)
-	if err := logger.Message("✓ Connector instances exported", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
+	graph := newDependencyGraph(opts)
+	c := newAPIConversion(client, opts.SkipDependencies, graph, importGen, opts.Filter)
+	if err := exportForModule(ctx, c, data, logger); err != nil {
+		return nil, err
 	}
+	return data, nil
+}
 
-	// 3. Flows
-	if err := logger.Message("Fetching flows...", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
-	flows, flowImports, err := ExportFlowsWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		return nil, fmt.Errorf("failed to export flows: %w", err)
-	}
-	data.FlowsHCL = flows
-	data.ImportBlocks = append(data.ImportBlocks, flowImports...)
-	if err := logger.Message("✓ Flows exported", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
+// exportForModule converts the resources of a source into data. Exports from the API and
+// conversions of saved JSON both go through it.
+func exportForModule(ctx context.Context, c *conversion, data *ExportedData, logger grpc.Logger) error {
+	data.DependencyGraph = c.graph
 
-	// 4. Applications
-	if err := logger.Message("Fetching applications...", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
-	applications, appImports, err := ExportApplicationsWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		return nil, fmt.Errorf("failed to export applications: %w", err)
-	}
-	data.ApplicationsHCL = applications
-	data.ImportBlocks = append(data.ImportBlocks, appImports...)
-	if err := logger.Message("✓ Applications exported", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
+	// Track which resource types and resources are included
+	missingTracker := newMissingTracker(c.graph, c.filter)
 
-	// 5. Flow Policies
-	if err := logger.Message("Fetching flow policies...", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
-	}
-	flowPolicies, policyImports, err := ExportFlowPoliciesWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
+	err := c.run(ctx, logger, func(_ conversionStep, resources []*model.Resource, extracted []converter.VariableEligibleAttribute) error {
+		data.Resources = append(data.Resources, resources...)
+		data.ExtractedVariables = append(data.ExtractedVariables, extracted...)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to export flow policies: %w", err)
-	}
-	data.FlowPoliciesHCL = flowPolicies
-	data.ImportBlocks = append(data.ImportBlocks, policyImports...)
-	if err := logger.Message("✓ Flow policies exported", nil); err != nil {
-		return nil, fmt.Errorf("failed to log message: %w", err)
+		return err
 	}
 
-	// Validate dependency graph
-	if err := graph.ValidateGraph(); err != nil {
-		if warnErr := logger.Warn(fmt.Sprintf("Dependency validation found issues: %v", err), nil); warnErr != nil {
-			return nil, fmt.Errorf("failed to log warning: %w", warnErr)
-		}
+	if err := validateGraph(c.graph, logger); err != nil {
+		return err
 	}
-
-	return data, nil
+	return logMissingDependencies(missingTracker, logger)
 }
 
 // ConvertExportedDataToModuleStructure converts ExportedData to module.ModuleStructure
-// Regenerates HCL with variable references for module resources
+// Resource values that became module variables are replaced with references to them
 func ConvertExportedDataToModuleStructure(data *ExportedData, config module.ModuleConfig) (*module.ModuleStructure, error) {
-	// Build variable map from extracted variables
-	variableMap := buildVariableMap(data.ExtractedVariables)
-
-	// Regenerate HCL with variable references
-	// For now, use skipDependencies=false (module mode always uses var.environment_id)
-	regeneratedVariablesHCL, err := regenerateVariablesHCL(data, variableMap, false)
-	if err != nil {
-		return nil, fmt.Errorf("failed to regenerate variables HCL: %w", err)
+	if err := converter.ApplyVariableReferences(data.Resources, data.ExtractedVariables); err != nil {
+		return nil, fmt.Errorf("failed to apply variable references: %w", err)
 	}
 
-	regeneratedConnectorsHCL, err := regenerateConnectorsHCL(data, variableMap, false)
-	if err != nil {
-		return nil, fmt.Errorf("failed to regenerate connectors HCL: %w", err)
+	structure := &module.ModuleStructure{
+		Config:    config,
+		Resources: data.Resources,
 	}
 
-	structure := &module.ModuleStructure{
-		Config: config,
-		Resources: module.ModuleResources{
-			FlowsHCL:        data.FlowsHCL, // Flows don't have variable extraction yet
-			ConnectionsHCL:  regeneratedConnectorsHCL,
-			VariablesHCL:    regeneratedVariablesHCL,
-			ApplicationsHCL: data.ApplicationsHCL, // Applications don't have variable extraction yet
-			FlowPoliciesHCL: data.FlowPoliciesHCL, // Policies don't have variable extraction yet
-		},
+	byAddress := make(map[string]*model.Resource, len(data.Resources))
+	for _, resource := range data.Resources {
+		byAddress[resource.Address()] = resource
 	}
 
-	// Convert extracted variable-eligible attributes to module variables
+	// Convert extracted variable-eligible attributes to module variables; a variable replacing
+	// a value marked sensitive is sensitive too
 	variables := make([]module.Variable, 0, len(data.ExtractedVariables))
 	for _, attr := range data.ExtractedVariables {
-		variables = append(variables, attr.ToModuleVariable())
+		variable := attr.ToModuleVariable()
+		if address, path, err := attr.Target(); err == nil {
+			if resource, ok := byAddress[address]; ok && resource.Sensitive(path) {
+				variable.Sensitive = true
+			}
+		}
+		variables = append(variables, variable)
 	}
 	structure.Variables = variables
 
@@ -219,10 +163,10 @@ func ConvertExportedDataToModuleStructure(data *ExportedData, config module.Modu
 	outputs := generateOutputsFromGraph(data.DependencyGraph)
 	structure.Outputs = outputs
 
-	// Transform raw import blocks to module-scoped import blocks
+	// Build module-scoped import blocks from the resources' import IDs
 	// Import blocks must reference module.{module_name}.{resource_type}.{resource_name}
-	importBlocks := make([]module.ImportBlock, 0, len(data.ImportBlocks))
-	for _, raw := range data.ImportBlocks {
+	var importBlocks []module.ImportBlock
+	for _, raw := range rawImportBlocks(data.Resources) {
 		importBlocks = append(importBlocks, module.ImportBlock{
 			To: fmt.Sprintf("module.%s.%s.%s", config.ModuleName, raw.ResourceType, raw.ResourceName),
 			ID: raw.ImportID,
@@ -233,168 +177,6 @@ func ConvertExportedDataToModuleStructure(data *ExportedData, config module.Modu
 	return structure, nil
 }
 
-// buildVariableMap creates a map from attribute path to variable name
-// Format: "resourceType.resourceName.attributePath" -> "variable_name"
-func buildVariableMap(extracted []converter.VariableEligibleAttribute) map[string]string {
-	varMap := make(map[string]string)
-
-	for _, attr := range extracted {
-		// Build key: "resourceType.resourceName.attributePath"
-		key := fmt.Sprintf("%s.%s.%s", attr.ResourceType, attr.ResourceName, attr.AttributePath)
-		varMap[key] = attr.VariableName
-	}
-
-	return varMap
-}
-
-// regenerateVariablesHCL regenerates DaVinci variable resources with variable references
-func regenerateVariablesHCL(data *ExportedData, variableMap map[string]string, skipDeps bool) (string, error) {
-	// Extract variable resources that need regeneration
-	variableAttrs := filterVariableAttributes(data.ExtractedVariables)
-
-	if len(variableAttrs) == 0 {
-		// No variables to regenerate, return original
-		return data.VariablesHCL, nil
-	}
-
-	// Regenerate each variable resource with variable references
-	var namedBlocks []utils.NamedHCL
-	processedIDs := make(map[string]bool)
-
-	for _, attr := range variableAttrs {
-		// Use the ResourceID from the attribute directly
-		variableID := attr.ResourceID
-
-		if variableID == "" {
-			return "", fmt.Errorf("missing resource ID for variable %s", attr.ResourceName)
-		}
-
-		// Skip if already processed
-		if processedIDs[variableID] {
-			continue
-		}
-		processedIDs[variableID] = true
-
-		// Get the JSON for this variable
-		variableJSON, ok := data.VariablesJSON[variableID]
-		if !ok {
-			return "", fmt.Errorf("missing JSON for variable %s (ID: %s)", attr.ResourceName, variableID)
-		}
-
-		// Get the variable name for this attribute (the module variable name)
-		// The variableName is already stored in the attribute
-		variableName := attr.VariableName
-
-		// Regenerate HCL with variable references
-		hcl, err := converter.GenerateVariableHCLWithVariableReferences(variableJSON, skipDeps, variableName)
-		if err != nil {
-			return "", fmt.Errorf("failed to regenerate variable %s: %w", attr.ResourceName, err)
-		}
-
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: attr.ResourceName, HCL: hcl})
-	}
-
-	// Also include variables that weren't extracted (no variable-eligible attributes)
-	for variableID, variableJSON := range data.VariablesJSON {
-		if processedIDs[variableID] {
-			continue
-		}
-
-		// Generate without variable references (normal conversion)
-		hcl, err := converter.ConvertVariableWithOptions(variableJSON, skipDeps)
-		if err != nil {
-			return "", fmt.Errorf("failed to convert variable %s: %w", variableID, err)
-		}
-
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: variableID, HCL: hcl})
-	}
-
-	return utils.JoinHCLBlocksSorted(namedBlocks), nil
-}
-
-// regenerateConnectorsHCL regenerates connector instance resources with variable references
-func regenerateConnectorsHCL(data *ExportedData, variableMap map[string]string, skipDeps bool) (string, error) {
-	// Extract connector resources that need regeneration
-	connectorAttrs := filterConnectorAttributes(data.ExtractedVariables)
-
-	if len(connectorAttrs) == 0 {
-		// No connectors to regenerate, return original
-		return data.ConnectorsHCL, nil
-	}
-
-	// Regenerate each connector resource with variable references
-	var namedBlocks []utils.NamedHCL
-	processedIDs := make(map[string]bool)
-
-	for _, attr := range connectorAttrs {
-		// Use the ResourceID from the attribute directly
-		connectorID := attr.ResourceID
-
-		if connectorID == "" {
-			return "", fmt.Errorf("missing resource ID for connector %s", attr.ResourceName)
-		}
-
-		// Skip if already processed
-		if processedIDs[connectorID] {
-			continue
-		}
-		processedIDs[connectorID] = true
-
-		// Get the JSON for this connector
-		connectorJSON, ok := data.ConnectorsJSON[connectorID]
-		if !ok {
-			return "", fmt.Errorf("missing JSON for connector %s (ID: %s)", attr.ResourceName, connectorID)
-		}
-
-		// Regenerate HCL with variable references
-		hcl, err := converter.GenerateConnectorInstanceHCLWithVariableReferences(connectorJSON, skipDeps, variableMap)
-		if err != nil {
-			return "", fmt.Errorf("failed to regenerate connector %s: %w", attr.ResourceName, err)
-		}
-
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: attr.ResourceName, HCL: hcl})
-	}
-
-	// Also include connectors that weren't extracted (no variable-eligible attributes)
-	for connectorID, connectorJSON := range data.ConnectorsJSON {
-		if processedIDs[connectorID] {
-			continue
-		}
-
-		// Generate without variable references (normal conversion)
-		hcl, err := converter.ConvertConnectorInstanceWithOptions(connectorJSON, skipDeps)
-		if err != nil {
-			return "", fmt.Errorf("failed to convert connector %s: %w", connectorID, err)
-		}
-
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: connectorID, HCL: hcl})
-	}
-
-	return utils.JoinHCLBlocksSorted(namedBlocks), nil
-}
-
-// filterConnectorAttributes filters extracted attributes for connector instances
-func filterConnectorAttributes(extracted []converter.VariableEligibleAttribute) []converter.VariableEligibleAttribute {
-	var result []converter.VariableEligibleAttribute
-	for _, attr := range extracted {
-		if attr.ResourceType == "connection" {
-			result = append(result, attr)
-		}
-	}
-	return result
-}
-
-// filterVariableAttributes filters extracted attributes for DaVinci variables
-func filterVariableAttributes(extracted []converter.VariableEligibleAttribute) []converter.VariableEligibleAttribute {
-	var result []converter.VariableEligibleAttribute
-	for _, attr := range extracted {
-		if attr.ResourceType == "variable" {
-			result = append(result, attr)
-		}
-	}
-	return result
-}
-
 // NOTE: generateVariablesFromGraph was deprecated and unused; removed to satisfy lint.
 
 // generateOutputsFromGraph generates output definitions from the dependency graph
@@ -1,8 +1,16 @@
 package exporter
 
 import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/fakeserver"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,27 +29,54 @@ func TestRawImportBlock_Structure(t *testing.T) {
 	assert.Equal(t, "env-123/var-456", block.ImportID)
 }
 
-// TestExportedData_ImportBlocks verifies ImportBlocks field exists in ExportedData
-func TestExportedData_ImportBlocks(t *testing.T) {
-	data := &ExportedData{
-		ImportBlocks: []RawImportBlock{
-			{
-				ResourceType: "pingone_davinci_variable",
-				ResourceName: "test_var",
-				ImportID:     "env-id/var-id",
-			},
-			{
-				ResourceType: "pingone_davinci_flow",
-				ResourceName: "test_flow",
-				ImportID:     "env-id/flow-id",
-			},
-		},
+// exportedOfType returns the exported resources of the given types
+func exportedOfType(data *ExportedData, resourceTypes ...string) []*model.Resource {
+	var resources []*model.Resource
+	for _, resource := range data.Resources {
+		if slices.Contains(resourceTypes, resource.Type) {
+			resources = append(resources, resource)
+		}
 	}
+	return resources
+}
 
-	assert.Len(t, data.ImportBlocks, 2)
-	assert.Equal(t, "pingone_davinci_variable", data.ImportBlocks[0].ResourceType)
-	assert.Equal(t, "test_var", data.ImportBlocks[0].ResourceName)
-	assert.Equal(t, "env-id/var-id", data.ImportBlocks[0].ImportID)
+// resourcesOfType renders the exported resources of the given types, as the exporter would
+// write them to a single file
+func resourcesOfType(t *testing.T, data *ExportedData, resourceTypes ...string) string {
+	t.Helper()
+	hcl, err := resourcesHCL(exportedOfType(data, resourceTypes...))
+	require.NoError(t, err)
+	return hcl
+}
+
+// flowsOf renders the exported flow resources with their flow_enable and flow_deploy resources
+func flowsOf(t *testing.T, data *ExportedData) string {
+	return resourcesOfType(t, data, "pingone_davinci_flow", "pingone_davinci_flow_enable", "pingone_davinci_flow_deploy")
+}
+
+// TestRawImportBlocks verifies that import blocks are built from the resources with an import ID
+func TestRawImportBlocks(t *testing.T) {
+	variable := model.NewResource("pingone_davinci_variable", "test_var")
+	variable.ImportID = "env-id/var-id"
+	flow := model.NewResource("pingone_davinci_flow", "test_flow")
+	flow.ImportID = "env-id/flow-id"
+	deploy := model.NewResource("pingone_davinci_flow_deploy", "test_flow")
+
+	importBlocks := rawImportBlocks([]*model.Resource{variable, flow, deploy})
+
+	require.Len(t, importBlocks, 2)
+	assert.Equal(t, "pingone_davinci_variable", importBlocks[0].ResourceType)
+	assert.Equal(t, "test_var", importBlocks[0].ResourceName)
+	assert.Equal(t, "env-id/var-id", importBlocks[0].ImportID)
+	assert.Equal(t, "pingone_davinci_flow", importBlocks[1].ResourceType)
+}
+
+// importTestResource returns a resource with an environment ID and an import ID
+func importTestResource(resourceType, name, importID string) *model.Resource {
+	resource := model.NewResource(resourceType, name)
+	resource.ImportID = importID
+	resource.Body.SetAttribute("environment_id", model.String("env-123"))
+	return resource
 }
 
 // TestConvertExportedDataToModuleStructure_TransformsImportBlocks verifies
@@ -49,31 +84,10 @@ func TestExportedData_ImportBlocks(t *testing.T) {
 func TestConvertExportedDataToModuleStructure_TransformsImportBlocks(t *testing.T) {
 	// Arrange
 	data := &ExportedData{
-		VariablesHCL:    "# Variables HCL\n",
-		ConnectorsHCL:   "# Connectors HCL\n",
-		FlowsHCL:        "# Flows HCL\n",
-		ApplicationsHCL: "# Applications HCL\n",
-		FlowPoliciesHCL: "# Flow Policies HCL\n",
-		VariablesJSON:   make(map[string][]byte),
-		ConnectorsJSON:  make(map[string][]byte),
-		FlowsJSON:       make(map[string][]byte),
-		ResourceNames:   make(map[string]string),
-		ImportBlocks: []RawImportBlock{
-			{
-				ResourceType: "pingone_davinci_variable",
-				ResourceName: "company_name",
-				ImportID:     "env-123/var-456",
-			},
-			{
-				ResourceType: "pingone_davinci_flow",
-				ResourceName: "main_flow",
-				ImportID:     "env-123/flow-789",
-			},
-			{
-				ResourceType: "pingone_davinci_application_flow_policy",
-				ResourceName: "policy_name",
-				ImportID:     "env-123/app-456/policy-789",
-			},
+		Resources: []*model.Resource{
+			importTestResource("pingone_davinci_variable", "company_name", "env-123/var-456"),
+			importTestResource("pingone_davinci_flow", "main_flow", "env-123/flow-789"),
+			importTestResource("pingone_davinci_application_flow_policy", "policy_name", "env-123/app-456/policy-789"),
 		},
 	}
 
@@ -92,6 +106,7 @@ func TestConvertExportedDataToModuleStructure_TransformsImportBlocks(t *testing.
 	// Assert
 	require.NoError(t, err)
 	require.NotNil(t, structure)
+	assert.Equal(t, data.Resources, structure.Resources)
 
 	// Verify import blocks are transformed
 	require.Len(t, structure.ImportBlocks, 3)
@@ -114,16 +129,10 @@ func TestConvertExportedDataToModuleStructure_TransformsImportBlocks(t *testing.
 func TestConvertExportedDataToModuleStructure_NoImportBlocks(t *testing.T) {
 	// Arrange
 	data := &ExportedData{
-		VariablesHCL:    "# Variables HCL\n",
-		ConnectorsHCL:   "# Connectors HCL\n",
-		FlowsHCL:        "# Flows HCL\n",
-		ApplicationsHCL: "# Applications HCL\n",
-		FlowPoliciesHCL: "# Flow Policies HCL\n",
-		VariablesJSON:   make(map[string][]byte),
-		ConnectorsJSON:  make(map[string][]byte),
-		FlowsJSON:       make(map[string][]byte),
-		ResourceNames:   make(map[string]string),
-		ImportBlocks:    []RawImportBlock{}, // Empty import blocks
+		Resources: []*model.Resource{
+			importTestResource("pingone_davinci_variable", "company_name", ""),
+			importTestResource("pingone_davinci_flow", "main_flow", ""),
+		},
 	}
 
 	config := module.ModuleConfig{
@@ -142,3 +151,73 @@ func TestConvertExportedDataToModuleStructure_NoImportBlocks(t *testing.T) {
 	require.NotNil(t, structure)
 	assert.Empty(t, structure.ImportBlocks)
 }
+
+// newDatasetClient returns a client of a fake server serving dataset
+func newDatasetClient(t *testing.T, dataset *fakeserver.Dataset) *api.Client {
+	t.Helper()
+	server := fakeserver.New(dataset, fakeserver.Options{})
+	t.Cleanup(server.Close)
+
+	client, err := api.NewClientWithOptions(context.Background(), server.EnvironmentID(), server.EnvironmentID(), "NA", fakeserver.ClientID, fakeserver.ClientSecret, api.ClientOptions{
+		APIURL:  server.URL,
+		AuthURL: server.URL,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+// jsonObjects parses a JSON array of objects, for writing datasets as JSON
+func jsonObjects(t *testing.T, array string) []map[string]interface{} {
+	t.Helper()
+	var objects []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(array), &objects))
+	return objects
+}
+
+// fixtureVariable reads the variable saved in the converter's API response fixtures
+func fixtureVariable(t *testing.T) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(converterTestdata, "api_responses", "pingone_davinci_variable.json"))
+	require.NoError(t, err)
+	return jsonObjects(t, "["+string(data)+"]")[0]
+}
+
+// TestExportEnvironmentForModule_FromSnapshot verifies an export replayed from a recorded snapshot
+// matches the export it was recorded from
+func TestExportEnvironmentForModule_FromSnapshot(t *testing.T) {
+	envID := "62f10a04-6c54-40c2-a97d-80a98522ff9a"
+	dataset := &fakeserver.Dataset{
+		EnvironmentID:      envID,
+		Variables:          []map[string]interface{}{fixtureVariable(t)},
+		ConnectorInstances: jsonObjects(t, `[{"id": "conn-1", "name": "Http", "connector": {"id": "httpConnector"}, "properties": {}}]`),
+		Flows: jsonObjects(t, `[{"id": "flow-1", "name": "Login", "enabled": true, "graphData": {"elements": {"nodes": [
+			{"data": {"id": "n1", "nodeType": "CONNECTION", "connectionId": "conn-1", "connectorId": "httpConnector", "name": "Http", "capabilityName": "customHtmlMessage"}}
+		]}}}]`),
+	}
+
+	dir := t.TempDir()
+	recorder := newDatasetClient(t, dataset)
+	require.NoError(t, recorder.RecordSnapshot(dir))
+	recorded, err := ExportEnvironmentForModule(context.Background(), recorder, ExportOptions{GenerateImports: true}, &mockLogger{})
+	require.NoError(t, err)
+
+	client, err := api.NewClientFromSnapshot(dir)
+	require.NoError(t, err)
+
+	data, err := ExportEnvironmentForModule(context.Background(), client, ExportOptions{GenerateImports: true}, &mockLogger{})
+	require.NoError(t, err)
+
+	assert.Equal(t, envID, data.EnvironmentID)
+	assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_variable"), `resource "pingone_davinci_variable"`)
+	assert.Contains(t, resourcesOfType(t, data, "pingone_davinci_connector_instance"), `resource "pingone_davinci_connector_instance"`)
+	assert.Contains(t, flowsOf(t, data), `resource "pingone_davinci_flow"`)
+	assert.Contains(t, flowsOf(t, data), "pingone_davinci_connector_instance.pingcli__Http.id")
+	assert.NotEmpty(t, rawImportBlocks(data.Resources))
+
+	recordedHCL, err := model.HCL(recorded.Resources...)
+	require.NoError(t, err)
+	replayedHCL, err := model.HCL(data.Resources...)
+	require.NoError(t, err)
+	assert.Equal(t, recordedHCL, replayedHCL)
+	assert.Equal(t, rawImportBlocks(recorded.Resources), rawImportBlocks(data.Resources))
+}
@@ -7,14 +7,23 @@ import (
 
 	"github.com/pingidentity/pingcli/shared/grpc"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ExportOptions contains options for environment export
 type ExportOptions struct {
 	SkipDependencies bool
 	GenerateImports  bool
+	Filter           *ResourceFilter      // Optional; nil exports all resources
+	Flows            []string             // Optional flow names or IDs; only their transitive closure is exported
+	Concurrency      int                  // Maximum parallel API requests; 0 or 1 fetches serially
+	NameLock         *resolver.NameLock   // Optional; locked resource names are used instead of sanitized names
+	NamingStrategy   utils.NamingStrategy // Optional; nil derives resource names with the pingcli strategy
 }
 
 // ExportEnvironment exports all DaVinci resources from an environment in dependency order
@@ -57,115 +66,51 @@ func ExportEnvironmentWithOptions(ctx context.Context, client *api.Client, opts
 		hcl.WriteString("\n")
 	}
 
-	// Initialize dependency graph and missing dependency tracker
-	graph := resolver.NewDependencyGraph()
-	missingTracker := resolver.NewMissingDependencyTracker()
-
-	// Track which resource types are included in this export
-	includedTypes := []string{
-		"pingone_davinci_variable",
-		"pingone_davinci_connector_instance",
-		"pingone_davinci_flow",
-		"pingone_davinci_application",
-		"pingone_davinci_application_flow_policy",
+	// Restrict the export to the selected flows and their dependencies
+	if err := applyFlowSelection(ctx, client, &opts, logger); err != nil {
+		return "", err
 	}
-	missingTracker.SetIncludedTypes(includedTypes)
-
-	// Log export start
-	if err := logger.Message("Exporting DaVinci resources...", nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
+	if err := prefetchResources(ctx, client, opts, logger); err != nil {
+		return "", err
 	}
 
-	// Export resources in dependency order, building the graph as we go
+	// Initialize dependency graph and missing dependency tracker
+	// (tracks which resource types and resources are included in this export)
+	graph := newDependencyGraph(opts)
+	missingTracker := newMissingTracker(graph, opts.Filter)
 
-	// 1. Variables
-	if err := logger.Message("Fetching variables...", nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	variables, _, _, err := ExportVariablesWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		if logErr := logger.PluginError("Failed to export variables", map[string]string{"error": err.Error()}); logErr != nil {
-			return "", fmt.Errorf("failed to log error: %w", logErr)
-		}
-		return "", fmt.Errorf("failed to export variables: %w", err)
-	}
-	varCount := strings.Count(variables, "resource \"pingone_davinci_variable\"")
-	if err := logger.Message(fmt.Sprintf("✓ Found %d variables", varCount), nil); err != nil {
+	// Log export start
+	if err := logger.Message("Exporting DaVinci resources...", nil); err != nil {
 		return "", fmt.Errorf("failed to log message: %w", err)
 	}
-	hcl.WriteString(variables)
-	hcl.WriteString("\n")
 
-	// 2. Connector Instances
-	if err := logger.Message("Fetching connector instances...", nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	connectors, _, _, err := ExportConnectorInstancesWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		if logErr := logger.PluginError("Failed to export connector instances", map[string]string{"error": err.Error()}); logErr != nil {
-			return "", fmt.Errorf("failed to log error: %w", logErr)
+	// Export resources in dependency order, building the graph as we go; each type is
+	// written as its own section
+	totalResources := 0
+	sections := 0
+	c := newAPIConversion(client, opts.SkipDependencies, graph, importGen, opts.Filter)
+	err := c.run(ctx, logger, func(step conversionStep, resources []*model.Resource, _ []converter.VariableEligibleAttribute) error {
+		section, err := step.hcl(resources)
+		if err != nil {
+			return err
 		}
-		return "", fmt.Errorf("failed to export connector instances: %w", err)
-	}
-	connCount := strings.Count(connectors, "resource \"pingone_davinci_connector_instance\"")
-	if err := logger.Message(fmt.Sprintf("✓ Found %d connector instances", connCount), nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	hcl.WriteString(connectors)
-	hcl.WriteString("\n")
-
-	// 3. Flows
-	if err := logger.Message("Fetching flows...", nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	flows, _, err := ExportFlowsWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		if logErr := logger.PluginError("Failed to export flows", map[string]string{"error": err.Error()}); logErr != nil {
-			return "", fmt.Errorf("failed to log error: %w", logErr)
+		totalResources += countResources(resources, step.resourceType)
+		if section == "" {
+			return nil
 		}
-		return "", fmt.Errorf("failed to export flows: %w", err)
-	}
-	flowCount := strings.Count(flows, "resource \"pingone_davinci_flow\"")
-	if err := logger.Message(fmt.Sprintf("✓ Found %d flows", flowCount), nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	hcl.WriteString(flows)
-	hcl.WriteString("\n")
-
-	// 4. Applications
-	if err := logger.Message("Fetching applications...", nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	applications, _, err := ExportApplicationsWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
-	if err != nil {
-		if logErr := logger.PluginError("Failed to export applications", map[string]string{"error": err.Error()}); logErr != nil {
-			return "", fmt.Errorf("failed to log error: %w", logErr)
+		if sections > 0 {
+			hcl.WriteString("\n")
 		}
-		return "", fmt.Errorf("failed to export applications: %w", err)
-	}
-	appCount := strings.Count(applications, "resource \"pingone_davinci_application\"")
-	if err := logger.Message(fmt.Sprintf("✓ Found %d applications", appCount), nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	hcl.WriteString(applications)
-	hcl.WriteString("\n")
-
-	// 5. Flow Policies
-	if err := logger.Message("Fetching flow policies...", nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
-	}
-	flowPolicies, _, err := ExportFlowPoliciesWithImports(ctx, client, opts.SkipDependencies, graph, importGen)
+		hcl.WriteString(section)
+		sections++
+		return nil
+	})
 	if err != nil {
-		if logErr := logger.PluginError("Failed to export flow policies", map[string]string{"error": err.Error()}); logErr != nil {
+		if logErr := logger.PluginError("Failed to export resources", map[string]string{"error": err.Error()}); logErr != nil {
 			return "", fmt.Errorf("failed to log error: %w", logErr)
 		}
-		return "", fmt.Errorf("failed to export flow policies: %w", err)
-	}
-	policyCount := strings.Count(flowPolicies, "resource \"pingone_davinci_application_flow_policy\"")
-	if err := logger.Message(fmt.Sprintf("✓ Found %d flow policies", policyCount), nil); err != nil {
-		return "", fmt.Errorf("failed to log message: %w", err)
+		return "", err
 	}
-	hcl.WriteString(flowPolicies)
 
 	// Get the final HCL output. Each exporter already sorts blocks per type.
 	finalHCL := hcl.String()
@@ -176,10 +121,8 @@ func ExportEnvironmentWithOptions(ctx context.Context, client *api.Client, opts
 	}
 
 	// Validate dependency graph
-	if err := graph.ValidateGraph(); err != nil {
-		if warnErr := logger.Warn(fmt.Sprintf("Dependency validation found issues: %v", err), nil); warnErr != nil {
-			return "", fmt.Errorf("failed to log warning: %w", warnErr)
-		}
+	if err := validateGraph(graph, logger); err != nil {
+		return "", err
 	}
 
 	// Count TODO comments in generated HCL
@@ -206,15 +149,11 @@ func ExportEnvironmentWithOptions(ctx context.Context, client *api.Client, opts
 	}
 
 	// Log missing dependencies summary if any
-	if len(missingTracker.GetMissing()) > 0 {
-		summaryReport := missingTracker.GenerateSummaryReport()
-		if err := logger.Message("\n"+summaryReport, nil); err != nil {
-			return "", fmt.Errorf("failed to log missing dependencies summary: %w", err)
-		}
+	if err := logMissingDependencies(missingTracker, logger); err != nil {
+		return "", err
 	}
 
 	// Log completion
-	totalResources := varCount + connCount + flowCount + appCount + policyCount
 	if err := logger.Message(fmt.Sprintf("\n✓ Export complete - %d resources generated", totalResources), map[string]string{
 		"resources": fmt.Sprintf("%d", totalResources),
 		"todos":     fmt.Sprintf("%d", todoCount),
@@ -233,13 +172,17 @@ func generateProviderConfig(region string) string {
 	hcl.WriteString("  required_providers {\n")
 	hcl.WriteString("    pingone = {\n")
 	hcl.WriteString("      source  = \"pingidentity/pingone\"\n")
-	hcl.WriteString("      version = \"1.16.0-beta\"\n")
+	hcl.WriteString(fmt.Sprintf("      version = %q\n", module.ProviderVersion))
 	hcl.WriteString("    }\n")
 	hcl.WriteString("  }\n")
 	hcl.WriteString("}\n")
 	hcl.WriteString("\n")
 	hcl.WriteString("provider \"pingone\" {\n")
-	hcl.WriteString(fmt.Sprintf("  region = %q\n", region))
+	if r, ok := api.LookupRegion(region); ok {
+		hcl.WriteString(fmt.Sprintf("  region_code = %q\n", r.ProviderRegionCode))
+	} else {
+		hcl.WriteString(fmt.Sprintf("  # region_code = \"\" # Unknown region %q (valid regions: %s)\n", region, api.ValidRegionsString()))
+	}
 	hcl.WriteString("  # Configure authentication via environment variables:\n")
 	hcl.WriteString("  # PINGONE_CLIENT_ID\n")
 	hcl.WriteString("  # PINGONE_CLIENT_SECRET\n")
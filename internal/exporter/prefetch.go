@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pingidentity/pingcli/shared/grpc"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+)
+
+// prefetchResources fetches the list and detail responses used by the exporters with up to
+// opts.Concurrency requests in flight, across and within resource types. Responses are kept in
+// the client's response cache, so the exporters still register and convert resources serially
+// and produce the same output as a serial run.
+func prefetchResources(ctx context.Context, client *api.Client, opts ExportOptions, logger grpc.Logger) error {
+	if opts.Concurrency <= 1 {
+		return nil
+	}
+	client.EnableResponseCache()
+
+	if err := logger.Message(fmt.Sprintf("Fetching resources with concurrency %d...", opts.Concurrency), nil); err != nil {
+		return fmt.Errorf("failed to log message: %w", err)
+	}
+
+	filter := opts.Filter
+	var (
+		flows     []api.FlowSummary
+		instances []api.ConnectorInstanceSummary
+		policies  []api.FlowPolicySummary
+	)
+
+	// Lists for every included resource type
+	var lists []func(context.Context) error
+	if filter.IncludesType("pingone_davinci_variable") {
+		lists = append(lists, func(ctx context.Context) error {
+			_, err := client.ListVariables(ctx, client.EnvironmentID)
+			return err
+		})
+	}
+	if filter.IncludesType("pingone_davinci_connector_instance") {
+		lists = append(lists, func(ctx context.Context) (err error) {
+			instances, err = client.ListConnectorInstances(ctx)
+			return err
+		})
+	}
+	if filter.IncludesType("pingone_davinci_flow") {
+		lists = append(lists, func(ctx context.Context) (err error) {
+			flows, err = client.ListFlows(ctx)
+			return err
+		})
+	}
+	if filter.IncludesType("pingone_davinci_application") {
+		lists = append(lists, func(ctx context.Context) error {
+			_, err := client.ListApplications(ctx, client.EnvironmentID)
+			return err
+		})
+	}
+	if filter.IncludesType("pingone_davinci_application_flow_policy") {
+		lists = append(lists, func(ctx context.Context) (err error) {
+			policies, err = client.ListFlowPolicies(ctx)
+			return err
+		})
+	}
+	if err := runConcurrently(ctx, opts.Concurrency, lists); err != nil {
+		return fmt.Errorf("failed to prefetch resource lists: %w", err)
+	}
+
+	// Details of every resource that will be exported
+	var details []func(context.Context) error
+	for _, summary := range instances {
+		if shouldSkipConnector(summary) || !filter.Includes("pingone_davinci_connector_instance", summary.InstanceID, summary.Name) {
+			continue
+		}
+		instanceID := summary.InstanceID
+		details = append(details, func(ctx context.Context) error {
+			_, err := client.GetConnectorInstance(ctx, instanceID)
+			return err
+		})
+	}
+	for _, summary := range flows {
+		if !filter.Includes("pingone_davinci_flow", summary.FlowID, summary.Name) {
+			continue
+		}
+		flowID := summary.FlowID
+		details = append(details, func(ctx context.Context) error {
+			_, err := client.GetFlow(ctx, flowID)
+			return err
+		})
+	}
+	for _, policy := range policies {
+		if !filter.Includes("pingone_davinci_application_flow_policy", policy.PolicyID, policy.Name) {
+			continue
+		}
+		applicationID, policyID := policy.ApplicationID, policy.PolicyID
+		details = append(details, func(ctx context.Context) error {
+			_, err := client.GetFlowPolicy(ctx, applicationID, policyID)
+			return err
+		})
+	}
+	if err := runConcurrently(ctx, opts.Concurrency, details); err != nil {
+		return fmt.Errorf("failed to prefetch resource details: %w", err)
+	}
+
+	return nil
+}
+
+// runConcurrently runs tasks on a pool of at most concurrency goroutines and returns the first
+// error. Remaining tasks are skipped and the context passed to running tasks is canceled once
+// a task fails.
+func runConcurrently(ctx context.Context, concurrency int, tasks []func(context.Context) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, task := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(task func(context.Context) error) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := task(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(task)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
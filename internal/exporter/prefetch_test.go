@@ -0,0 +1,89 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	t.Run("Bounds tasks in flight", func(t *testing.T) {
+		var inFlight, maxInFlight, completed int32
+		var mu sync.Mutex
+
+		tasks := make([]func(context.Context) error, 20)
+		for i := range tasks {
+			tasks[i] = func(ctx context.Context) error {
+				n := atomic.AddInt32(&inFlight, 1)
+				mu.Lock()
+				if n > maxInFlight {
+					maxInFlight = n
+				}
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				atomic.AddInt32(&completed, 1)
+				return nil
+			}
+		}
+
+		require.NoError(t, runConcurrently(context.Background(), 4, tasks))
+		assert.Equal(t, int32(20), completed)
+		assert.LessOrEqual(t, maxInFlight, int32(4))
+		assert.Greater(t, maxInFlight, int32(1))
+	})
+
+	t.Run("Returns first error and skips remaining tasks", func(t *testing.T) {
+		var started int32
+		tasks := make([]func(context.Context) error, 50)
+		for i := range tasks {
+			tasks[i] = func(ctx context.Context) error {
+				atomic.AddInt32(&started, 1)
+				return errors.New("boom")
+			}
+		}
+
+		err := runConcurrently(context.Background(), 1, tasks)
+		require.Error(t, err)
+		assert.Equal(t, "boom", err.Error())
+		assert.Less(t, started, int32(50))
+	})
+
+	t.Run("Runs no tasks", func(t *testing.T) {
+		assert.NoError(t, runConcurrently(context.Background(), 8, nil))
+	})
+}
+
+// TestExportEnvironmentForModule_ConcurrencyMatchesSerial verifies a concurrent export
+// produces exactly the same output as a serial one
+func TestExportEnvironmentForModule_ConcurrencyMatchesSerial(t *testing.T) {
+	dataset := flowClosureDataset(t)
+
+	export := func(concurrency int) *ExportedData {
+		client := newDatasetClient(t, dataset)
+		data, err := ExportEnvironmentForModule(context.Background(), client, ExportOptions{
+			GenerateImports: true,
+			Concurrency:     concurrency,
+		}, &mockLogger{})
+		require.NoError(t, err)
+		return data
+	}
+
+	serial := export(1)
+	concurrent := export(8)
+
+	assert.Equal(t, resourcesOfType(t, serial, "pingone_davinci_variable"), resourcesOfType(t, concurrent, "pingone_davinci_variable"))
+	assert.Equal(t, resourcesOfType(t, serial, "pingone_davinci_connector_instance"), resourcesOfType(t, concurrent, "pingone_davinci_connector_instance"))
+	assert.Equal(t, flowsOf(t, serial), flowsOf(t, concurrent))
+	assert.Equal(t, resourcesOfType(t, serial, "pingone_davinci_application"), resourcesOfType(t, concurrent, "pingone_davinci_application"))
+	assert.Equal(t, resourcesOfType(t, serial, "pingone_davinci_application_flow_policy"), resourcesOfType(t, concurrent, "pingone_davinci_application_flow_policy"))
+	assert.Equal(t, rawImportBlocks(serial.Resources), rawImportBlocks(concurrent.Resources))
+	assert.NotEmpty(t, flowsOf(t, concurrent))
+}
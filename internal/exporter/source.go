@@ -0,0 +1,258 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+)
+
+// resourceSource supplies the DaVinci resource payloads an export converts. An export from the
+// PingOne API and a conversion of saved JSON differ only in their source; resources are
+// filtered, registered and converted the same way for both.
+type resourceSource interface {
+	// list returns the resources of a type in source order
+	list(ctx context.Context, resourceType string) ([]sourceResource, error)
+
+	// payload returns the JSON payload of a listed resource in the form the converters read.
+	// Payloads are fetched after filtering, so rejected resources are never fetched.
+	payload(ctx context.Context, resource sourceResource) ([]byte, error)
+}
+
+// sourceResource identifies one resource of a source
+type sourceResource struct {
+	Type          string
+	ID            string
+	Name          string
+	Context       string // Variable context, e.g. "company" or "flow"
+	ConnectorID   string // Connector of a connector instance
+	ApplicationID string // Application a flow policy belongs to
+
+	raw []byte // Payload, when listing already returned it
+}
+
+// apiSource reads resources from the PingOne API
+type apiSource struct {
+	client *api.Client
+}
+
+// list returns the resources of a type from the API list endpoints
+func (s apiSource) list(ctx context.Context, resourceType string) ([]sourceResource, error) {
+	client := s.client
+	var resources []sourceResource
+
+	switch resourceType {
+	case "pingone_davinci_variable":
+		variables, err := client.ListVariables(ctx, client.EnvironmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list variables: %w", err)
+		}
+		for i := range variables {
+			// Convert SDK response to JSON format expected by converter
+			raw, err := convertVariableToJSON(&variables[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert variable %s to JSON: %w", variables[i].GetId(), err)
+			}
+			resources = append(resources, sourceResource{
+				Type:    resourceType,
+				ID:      variables[i].GetId().String(),
+				Name:    variables[i].GetName(),
+				Context: variables[i].GetContext(),
+				raw:     raw,
+			})
+		}
+
+	case "pingone_davinci_connector_instance":
+		summaries, err := client.ListConnectorInstances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list connector instances: %w", err)
+		}
+		for _, summary := range summaries {
+			resources = append(resources, sourceResource{
+				Type:        resourceType,
+				ID:          summary.InstanceID,
+				Name:        summary.Name,
+				ConnectorID: summary.ConnectorID,
+			})
+		}
+
+	case "pingone_davinci_flow":
+		summaries, err := client.ListFlows(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list flows: %w", err)
+		}
+		for _, summary := range summaries {
+			resources = append(resources, sourceResource{Type: resourceType, ID: summary.FlowID, Name: summary.Name})
+		}
+
+	case "pingone_davinci_application":
+		applications, err := client.ListApplications(ctx, client.EnvironmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+		for i := range applications {
+			// Convert SDK response to JSON format expected by converter
+			raw, err := convertApplicationToJSON(&applications[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert application %s to JSON: %w", applications[i].GetId(), err)
+			}
+			resources = append(resources, sourceResource{
+				Type: resourceType,
+				ID:   applications[i].GetId(),
+				Name: applications[i].GetName(),
+				raw:  raw,
+			})
+		}
+
+	case "pingone_davinci_application_flow_policy":
+		policies, err := client.ListFlowPolicies(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list flow policies: %w", err)
+		}
+		for _, policy := range policies {
+			resources = append(resources, sourceResource{
+				Type:          resourceType,
+				ID:            policy.PolicyID,
+				Name:          policy.Name,
+				ApplicationID: policy.ApplicationID,
+			})
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+
+	return resources, nil
+}
+
+// payload retrieves the detail of a connector instance, flow or flow policy; variables and
+// applications are complete in their list response
+func (s apiSource) payload(ctx context.Context, resource sourceResource) ([]byte, error) {
+	if resource.raw != nil {
+		return resource.raw, nil
+	}
+	client := s.client
+
+	switch resource.Type {
+	case "pingone_davinci_connector_instance":
+		detail, err := client.GetConnectorInstance(ctx, resource.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get connector instance %s (%s): %w", resource.Name, resource.ID, err)
+		}
+		return convertInstanceDetailToJSON(detail, client.EnvironmentID)
+
+	case "pingone_davinci_flow":
+		detail, err := client.GetFlow(ctx, resource.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow %s (%s): %w", resource.Name, resource.ID, err)
+		}
+		flowData, err := convertFlowDetailToMap(detail)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert flow %s to map: %w", resource.Name, err)
+		}
+		return json.Marshal(flowData)
+
+	case "pingone_davinci_application_flow_policy":
+		detail, err := client.GetFlowPolicy(ctx, resource.ApplicationID, resource.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flow policy %s: %w", resource.ID, err)
+		}
+		return json.Marshal(detail.RawResponse)
+
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", resource.Type)
+	}
+}
+
+// localSource reads resources loaded from saved JSON files
+type localSource struct {
+	resources *LocalResources
+}
+
+// list returns the loaded resources of a type with their payloads
+func (s localSource) list(_ context.Context, resourceType string) ([]sourceResource, error) {
+	var resources []sourceResource
+
+	switch resourceType {
+	case "pingone_davinci_flow":
+		for i, flow := range s.resources.Flows {
+			flowID := localFlowID(flow)
+			if flowID == "" {
+				return nil, fmt.Errorf("flow %d has no flowId", i)
+			}
+			name, _ := flow["name"].(string)
+			raw, err := json.Marshal(flow)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal flow %s: %w", flowID, err)
+			}
+			resources = append(resources, sourceResource{Type: resourceType, ID: flowID, Name: name, raw: raw})
+		}
+		return resources, nil
+
+	case "pingone_davinci_variable":
+		return localSourceResources(resourceType, s.resources.Variables)
+	case "pingone_davinci_connector_instance":
+		return localSourceResources(resourceType, s.resources.Connectors)
+	case "pingone_davinci_application":
+		return localSourceResources(resourceType, s.resources.Applications)
+	case "pingone_davinci_application_flow_policy":
+		return localSourceResources(resourceType, s.resources.FlowPolicies)
+	default:
+		return nil, fmt.Errorf("unsupported resource type %s", resourceType)
+	}
+}
+
+// payload returns the saved payload of a resource
+func (s localSource) payload(_ context.Context, resource sourceResource) ([]byte, error) {
+	return resource.raw, nil
+}
+
+// localSourceResources identifies saved payloads of a type. The owning application of a flow
+// policy is read from "application.id" or "applicationId".
+func localSourceResources(resourceType string, raws [][]byte) ([]sourceResource, error) {
+	resources := make([]sourceResource, 0, len(raws))
+	for _, raw := range raws {
+		obj, id, name, err := localResourceIdentity(raw)
+		if err != nil {
+			return nil, err
+		}
+		resource := sourceResource{Type: resourceType, ID: id, Name: name, raw: raw}
+
+		switch resourceType {
+		case "pingone_davinci_variable":
+			resource.Context, _ = obj["context"].(string)
+		case "pingone_davinci_connector_instance":
+			if connector, ok := obj["connector"].(map[string]interface{}); ok {
+				resource.ConnectorID, _ = connector["id"].(string)
+			}
+		case "pingone_davinci_application_flow_policy":
+			resource.ApplicationID, _ = obj["applicationId"].(string)
+			if app, ok := obj["application"].(map[string]interface{}); ok {
+				if v, ok := app["id"].(string); ok {
+					resource.ApplicationID = v
+				}
+			}
+			if resource.ApplicationID == "" {
+				return nil, fmt.Errorf("flow policy %s has no application ID", id)
+			}
+		}
+
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// localResourceIdentity extracts the "id" and "name" fields of a resource payload
+func localResourceIdentity(raw []byte) (map[string]interface{}, string, string, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, "", "", fmt.Errorf("failed to parse resource JSON: %w", err)
+	}
+	id, _ := obj["id"].(string)
+	name, _ := obj["name"].(string)
+	if id == "" {
+		return nil, "", "", fmt.Errorf("resource %q has no id", name)
+	}
+	return obj, id, name, nil
+}
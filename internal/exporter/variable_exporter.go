@@ -8,129 +8,95 @@ import (
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/converter"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/importgen"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/resolver"
-	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ExportVariables exports all variables from the API to HCL format
 func ExportVariables(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph) (string, []converter.VariableEligibleAttribute, error) {
-	hcl, extracted, _, err := ExportVariablesWithImports(ctx, client, skipDeps, graph, nil)
+	hcl, extracted, _, err := ExportVariablesWithImports(ctx, client, skipDeps, graph, nil, nil)
 	return hcl, extracted, err
 }
 
-// ExportVariablesForModule exports variables with JSON data for module generation
-// Returns HCL, extracted variables, JSON map, resource names map, and import blocks
-func ExportVariablesForModule(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator) (string, []converter.VariableEligibleAttribute, map[string][]byte, map[string]string, []RawImportBlock, error) {
-	hcl, extracted, importBlocks, err := ExportVariablesWithImports(ctx, client, skipDeps, graph, importGen)
-	if err != nil {
-		return "", nil, nil, nil, nil, err
+// ExportVariablesWithImports exports all variables with optional import blocks
+// Returns HCL string, extracted variable-eligible attributes, and import blocks for module generation
+// Variables rejected by filter (nil exports all) are recorded on the graph's missing dependency tracker
+func ExportVariablesWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator, filter *ResourceFilter) (string, []converter.VariableEligibleAttribute, []RawImportBlock, error) {
+	if client == nil {
+		return "", nil, nil, fmt.Errorf("client cannot be nil")
 	}
 
-	// Re-fetch to get JSON (this is inefficient but keeps changes minimal)
-	variables, err := client.ListVariables(ctx, client.EnvironmentID)
+	resources, extractedVariables, err := newAPIConversion(client, skipDeps, graph, importGen, filter).convertVariables(ctx)
 	if err != nil {
-		return "", nil, nil, nil, nil, fmt.Errorf("failed to re-fetch variables for JSON: %w", err)
+		return "", nil, nil, err
 	}
 
-	jsonMap := make(map[string][]byte)
-	namesMap := make(map[string]string)
-
-	for _, variable := range variables {
-		variableJSON, err := convertVariableToJSON(&variable)
-		if err != nil {
-			return "", nil, nil, nil, nil, fmt.Errorf("failed to convert variable to JSON: %w", err)
-		}
-
-		variableID := variable.GetId().String()
-		jsonMap[variableID] = variableJSON
-
-		// Get actual resource name from graph
-		actualName, err := graph.GetReferenceName("pingone_davinci_variable", variableID)
-		if err != nil {
-			return "", nil, nil, nil, nil, fmt.Errorf("failed to get resource name for variable %s: %w", variableID, err)
-		}
-		namesMap[variableID] = actualName
+	hcl, err := variablesHCL(resources)
+	if err != nil {
+		return "", nil, nil, err
 	}
-
-	return hcl, extracted, jsonMap, namesMap, importBlocks, nil
+	return hcl, extractedVariables, rawImportBlocks(resources), nil
 }
 
-// ExportVariablesWithImports exports all variables with optional import blocks
-// Returns HCL string, extracted variable-eligible attributes, and import blocks for module generation
-func ExportVariablesWithImports(ctx context.Context, client *api.Client, skipDeps bool, graph *resolver.DependencyGraph, importGen *importgen.ImportBlockGenerator) (string, []converter.VariableEligibleAttribute, []RawImportBlock, error) {
-	if client == nil {
-		return "", nil, nil, fmt.Errorf("client cannot be nil")
-	}
-
-	// Get all variables from API
-	variables, err := client.ListVariables(ctx, client.EnvironmentID)
+// variablesHCL renders variable resources
+func variablesHCL(resources []*model.Resource) (string, error) {
+	hcl, err := resourcesHCL(resources)
 	if err != nil {
-		return "", nil, nil, fmt.Errorf("failed to list variables: %w", err)
+		return "", fmt.Errorf("failed to write variables: %w", err)
 	}
+	return hcl, nil
+}
+
+// convertVariables converts the selected variables to resources and extracts their
+// variable-eligible attributes
+func (c *conversion) convertVariables(ctx context.Context) ([]*model.Resource, []converter.VariableEligibleAttribute, error) {
+	graph := c.graph
 
-	if len(variables) == 0 {
-		return "", nil, nil, nil
+	variables, err := c.selected(ctx, "pingone_davinci_variable")
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// First pass: Register all variables in the dependency graph
 	for _, variable := range variables {
-		variableName := variable.GetName()
-		variableContext := variable.GetContext()
-		variableID := variable.GetId()
-		sanitizedName := utils.SanitizeMultiKeyResourceName(variableName, variableContext)
-		graph.AddResource("pingone_davinci_variable", variableID.String(), sanitizedName)
+		sanitizedName := graph.ResourceName(variable.ID, variable.Name, variable.Context)
+		graph.AddResource("pingone_davinci_variable", variable.ID, sanitizedName)
+		graph.AddVariableName(variable.ID, variable.Name, variable.Context)
 	}
 
-	var namedBlocks []utils.NamedHCL
+	var resources []*model.Resource
 	var extractedVariables []converter.VariableEligibleAttribute
-	var importBlocks []RawImportBlock
 
-	// Second pass: Convert each variable to HCL with optional import blocks
+	// Second pass: Convert each variable to a resource
 	for _, variable := range variables {
-		variableID := variable.GetId().String()
-
 		// Get the actual resource name from the graph (includes deduplication suffix if needed)
-		actualName, err := graph.GetReferenceName("pingone_davinci_variable", variableID)
+		actualName, err := graph.GetReferenceName("pingone_davinci_variable", variable.ID)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to get resource name for variable %s: %w", variableID, err)
-		}
-
-		// Track import block separately if import generator provided
-		if importGen != nil {
-			// Build the import ID directly
-			importIDStr := fmt.Sprintf("%s/%s", client.EnvironmentID, variableID)
-
-			importBlocks = append(importBlocks, RawImportBlock{
-				ResourceType: "pingone_davinci_variable",
-				ResourceName: actualName,
-				ImportID:     importIDStr,
-			})
+			return nil, nil, fmt.Errorf("failed to get resource name for variable %s: %w", variable.ID, err)
 		}
 
-		// Convert SDK response to JSON format expected by converter
-		variableJSON, err := convertVariableToJSON(&variable)
+		variableJSON, err := c.source.payload(ctx, variable)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to convert variable %s to JSON: %w", variable.GetId(), err)
+			return nil, nil, err
 		}
 
 		// Extract variable-eligible attributes for module generation
 		variableAttrs, err := converter.GetVariableEligibleAttributes(variableJSON, actualName)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to extract variable attributes for %s: %w", variable.GetId(), err)
+			return nil, nil, fmt.Errorf("failed to extract variable attributes for %s: %w", variable.ID, err)
 		}
 		extractedVariables = append(extractedVariables, variableAttrs...)
 
-		// Convert to HCL using existing converter
-		hcl, err := converter.ConvertVariableWithOptions(variableJSON, skipDeps)
+		resource, err := converter.ConvertVariableToResource(variableJSON, c.skipDeps, actualName, graph)
 		if err != nil {
-			return "", nil, nil, fmt.Errorf("failed to convert variable %s to HCL: %w", variable.GetId(), err)
+			return nil, nil, fmt.Errorf("failed to convert variable %s to HCL: %w", variable.ID, err)
 		}
+		resource.ImportID = c.importID(variable.ID)
 
-		namedBlocks = append(namedBlocks, utils.NamedHCL{Name: "", HCL: hcl})
+		resources = append(resources, resource)
 	}
 
-	// Sort by resource name to ensure deterministic output
-	return utils.JoinHCLBlocksSorted(namedBlocks), extractedVariables, importBlocks, nil
+	return resources, extractedVariables, nil
 }
 
 // convertVariableToJSON converts SDK DaVinciVariableResponse to JSON format expected by converter
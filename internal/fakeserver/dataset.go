@@ -0,0 +1,145 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Dataset holds the API resources served by a fake server. Each resource is the JSON object
+// returned by the API when fetching it by ID; the environment relationship and HAL links may
+// be omitted, as the server adds them.
+type Dataset struct {
+	EnvironmentID      string
+	Flows              []map[string]interface{}
+	Variables          []map[string]interface{}
+	ConnectorInstances []map[string]interface{}
+	Applications       []map[string]interface{}
+	FlowPolicies       []map[string]interface{} // Owning application in "application.id"
+}
+
+// fixturePrefixes maps fixture file name prefixes to the dataset slice they populate.
+// Longer prefixes come first so application flow policies are not read as applications.
+var fixturePrefixes = []struct {
+	prefix string
+	slice  func(*Dataset) *[]map[string]interface{}
+}{
+	{"pingone_davinci_application_flow_policy", func(d *Dataset) *[]map[string]interface{} { return &d.FlowPolicies }},
+	{"pingone_davinci_application", func(d *Dataset) *[]map[string]interface{} { return &d.Applications }},
+	{"pingone_davinci_connector_instance", func(d *Dataset) *[]map[string]interface{} { return &d.ConnectorInstances }},
+	{"pingone_davinci_flow", func(d *Dataset) *[]map[string]interface{} { return &d.Flows }},
+	{"pingone_davinci_variable", func(d *Dataset) *[]map[string]interface{} { return &d.Variables }},
+}
+
+// FixtureDir returns the directory of saved API responses the default dataset is read from
+func FixtureDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "converter", "testdata", "api_responses")
+}
+
+// LoadDefaultDataset loads the dataset from FixtureDir
+func LoadDefaultDataset() (*Dataset, error) {
+	return LoadDataset(FixtureDir())
+}
+
+// LoadDataset loads every pingone_davinci_<resource type>*.json file in dir, in file name
+// order. Other files are ignored. The environment ID is read from the first resource that
+// has one.
+func LoadDataset(dir string) (*Dataset, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "pingone_davinci_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+	sort.Strings(paths)
+
+	dataset := &Dataset{}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		for _, fixture := range fixturePrefixes {
+			if !strings.HasPrefix(name, fixture.prefix) {
+				continue
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read fixture %s: %w", name, err)
+			}
+			var resource map[string]interface{}
+			if err := json.Unmarshal(content, &resource); err != nil {
+				return nil, fmt.Errorf("failed to parse fixture %s: %w", name, err)
+			}
+
+			slice := fixture.slice(dataset)
+			*slice = append(*slice, resource)
+			if dataset.EnvironmentID == "" {
+				dataset.EnvironmentID = relationshipID(resource, "environment")
+			}
+			break
+		}
+	}
+
+	if dataset.EnvironmentID == "" {
+		return nil, fmt.Errorf("no fixture in %s has an environment ID", dir)
+	}
+	return dataset, nil
+}
+
+// flowPoliciesFor returns the flow policies owned by an application
+func (d *Dataset) flowPoliciesFor(applicationID string) []map[string]interface{} {
+	var policies []map[string]interface{}
+	for _, policy := range d.FlowPolicies {
+		if relationshipID(policy, "application") == applicationID {
+			policies = append(policies, policy)
+		}
+	}
+	return policies
+}
+
+// flowSummaries returns the list representation of flows
+func flowSummaries(flows []map[string]interface{}) []map[string]interface{} {
+	summaries := make([]map[string]interface{}, 0, len(flows))
+	for _, flow := range flows {
+		summary := map[string]interface{}{"id": flowID(flow)}
+		for _, key := range []string{"name", "description"} {
+			if v, ok := flow[key]; ok {
+				summary[key] = v
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// findByID returns the resource whose ID (read with id) matches, or nil
+func findByID(resources []map[string]interface{}, want string, id func(map[string]interface{}) string) map[string]interface{} {
+	for _, resource := range resources {
+		if id(resource) == want {
+			return resource
+		}
+	}
+	return nil
+}
+
+func resourceID(resource map[string]interface{}) string {
+	id, _ := resource["id"].(string)
+	return id
+}
+
+// flowID reads the flow ID, which DaVinci flow exports hold in "flowId"
+func flowID(flow map[string]interface{}) string {
+	if id, ok := flow["flowId"].(string); ok && id != "" {
+		return id
+	}
+	return resourceID(flow)
+}
+
+// relationshipID reads the ID of a relationship such as "environment": {"id": "..."}
+func relationshipID(resource map[string]interface{}, key string) string {
+	relationship, _ := resource[key].(map[string]interface{})
+	id, _ := relationship["id"].(string)
+	return id
+}
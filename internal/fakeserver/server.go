@@ -0,0 +1,313 @@
+// Copyright © 2025 Ping Identity Corporation
+
+// Package fakeserver provides an in-process stand-in for the PingOne DaVinci API. It serves
+// flows, variables, connector instances, applications and flow policies from JSON fixtures,
+// plus a client credentials token endpoint, so exports can be tested without credentials.
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+)
+
+// Credentials accepted by the token endpoint and the access token it issues
+const (
+	ClientID     = "fake-client-id"
+	ClientSecret = "fake-client-secret"
+	AccessToken  = "fake-access-token"
+)
+
+// Options configures the behavior of a fake server
+type Options struct {
+	// PageSize is the number of items per page for list requests without a limit query
+	// parameter. 0 serves every item on one page.
+	PageSize int
+
+	// Failures maps a request path (e.g. "/v1/environments/<id>/connectorInstances") to the
+	// HTTP status returned for every request to it
+	Failures map[string]int
+
+	// FailureSequences maps a request path to the HTTP statuses returned, in order, for the
+	// first requests to it; later requests are served normally. Use it to simulate transient
+	// errors such as a 503 followed by a successful retry.
+	FailureSequences map[string][]int
+}
+
+// Server is a running fake PingOne API. Point api.Client at it with the APIURL and AuthURL
+// client options set to Server.URL.
+type Server struct {
+	*httptest.Server
+	dataset *Dataset
+	opts    Options
+
+	mu            sync.Mutex
+	requests      []string
+	tokenRequests int
+	pathRequests  map[string]int // Requests per path, for FailureSequences
+}
+
+// New starts a fake server serving dataset. The caller must call Close when done.
+func New(dataset *Dataset, opts Options) *Server {
+	s := &Server{dataset: dataset, opts: opts, pathRequests: make(map[string]int)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /{authEnvID}/as/token", s.handleToken)
+
+	api := func(pattern string, handler http.HandlerFunc) {
+		mux.Handle("GET /v1/environments/{envID}"+pattern, s.authorize(handler))
+	}
+	api("/flows", s.listHandler("flows", func(*http.Request) []map[string]interface{} { return flowSummaries(s.dataset.Flows) }))
+	api("/flows/{id}", s.getHandler("flows", func(r *http.Request) map[string]interface{} {
+		return findByID(s.dataset.Flows, r.PathValue("id"), flowID)
+	}))
+	api("/variables", s.listHandler("variables", func(*http.Request) []map[string]interface{} { return s.dataset.Variables }))
+	api("/variables/{id}", s.getHandler("variables", func(r *http.Request) map[string]interface{} {
+		return findByID(s.dataset.Variables, r.PathValue("id"), resourceID)
+	}))
+	api("/connectorInstances", s.listHandler("connectorInstances", func(*http.Request) []map[string]interface{} { return s.dataset.ConnectorInstances }))
+	api("/connectorInstances/{id}", s.getHandler("connectorInstances", func(r *http.Request) map[string]interface{} {
+		return findByID(s.dataset.ConnectorInstances, r.PathValue("id"), resourceID)
+	}))
+	api("/davinciApplications", s.listHandler("davinciApplications", func(*http.Request) []map[string]interface{} { return s.dataset.Applications }))
+	api("/davinciApplications/{id}", s.getHandler("davinciApplications", func(r *http.Request) map[string]interface{} {
+		return findByID(s.dataset.Applications, r.PathValue("id"), resourceID)
+	}))
+	api("/davinciApplications/{appID}/flowPolicies", s.listHandler("flowPolicies", func(r *http.Request) []map[string]interface{} {
+		return s.dataset.flowPoliciesFor(r.PathValue("appID"))
+	}))
+	api("/davinciApplications/{appID}/flowPolicies/{id}", s.getHandler("flowPolicies", func(r *http.Request) map[string]interface{} {
+		return findByID(s.dataset.flowPoliciesFor(r.PathValue("appID")), r.PathValue("id"), resourceID)
+	}))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// EnvironmentID returns the environment the server holds resources for
+func (s *Server) EnvironmentID() string {
+	return s.dataset.EnvironmentID
+}
+
+// Requests returns every API request served so far as "<method> <path>?<query>", in order.
+// Token requests are counted separately by TokenRequests.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// TokenRequests returns the number of successful token requests
+func (s *Server) TokenRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenRequests
+}
+
+// handleToken issues AccessToken for a client credentials grant with ClientID and ClientSecret
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" {
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != ClientID || clientSecret != ClientSecret || r.PathValue("authEnvID") != s.dataset.EnvironmentID {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	s.mu.Lock()
+	s.tokenRequests++
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": AccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+// authorize records the request and rejects it unless it carries AccessToken, targets the
+// dataset environment and has no configured failure or pending failure in its sequence
+func (s *Server) authorize(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		attempt := s.pathRequests[r.URL.Path]
+		s.pathRequests[r.URL.Path]++
+		s.mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer "+AccessToken {
+			writeAPIError(w, http.StatusUnauthorized, "The request could not be completed. You do not have access to this resource.")
+			return
+		}
+		if status, ok := s.opts.Failures[r.URL.Path]; ok {
+			writeAPIError(w, status, fmt.Sprintf("Simulated %d response.", status))
+			return
+		}
+		if sequence := s.opts.FailureSequences[r.URL.Path]; attempt < len(sequence) {
+			status := sequence[attempt]
+			writeAPIError(w, status, fmt.Sprintf("Simulated %d response.", status))
+			return
+		}
+		if r.PathValue("envID") != s.dataset.EnvironmentID {
+			writeAPIError(w, http.StatusNotFound, "The requested environment does not exist.")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// listHandler serves a paged HAL collection of the items returned by items under embeddedKey
+func (s *Server) listHandler(embeddedKey string, items func(*http.Request) []map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		all := items(r)
+
+		query := r.URL.Query()
+		limit := s.opts.PageSize
+		if v := query.Get("limit"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 1 {
+				writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("Invalid limit: %s", v))
+				return
+			}
+			limit = parsed
+		}
+		if limit < 1 {
+			limit = len(all)
+		}
+		cursor := 0
+		if v := query.Get("cursor"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 0 || parsed > len(all) {
+				writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("Invalid cursor: %s", v))
+				return
+			}
+			cursor = parsed
+		}
+
+		end := min(cursor+limit, len(all))
+		page := make([]map[string]interface{}, 0, end-cursor)
+		for _, item := range all[cursor:end] {
+			page = append(page, s.withAPIFields(item, embeddedKey, s.URL+r.URL.Path+"/"+resourceID(item)))
+		}
+
+		links := map[string]interface{}{
+			"self":        href(s.URL + r.URL.RequestURI()),
+			"environment": href(s.URL + "/v1/environments/" + s.dataset.EnvironmentID),
+		}
+		if end < len(all) {
+			links["next"] = href(fmt.Sprintf("%s%s?cursor=%d&limit=%d", s.URL, r.URL.Path, end, limit))
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"_embedded": map[string]interface{}{embeddedKey: page},
+			"_links":    links,
+			"count":     len(all),
+			"size":      len(page),
+		})
+	}
+}
+
+// getHandler serves the item of collection returned by find, or 404 when it returns nil
+func (s *Server) getHandler(collection string, find func(*http.Request) map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		item := find(r)
+		if item == nil {
+			writeAPIError(w, http.StatusNotFound, "The requested resource object cannot be found.")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.withAPIFields(item, collection, s.URL+r.URL.Path))
+	}
+}
+
+// resourceLinks lists the HAL links the API returns with the resources of each collection,
+// besides self and environment. Flows are served as saved.
+var resourceLinks = map[string][]string{
+	"variables":           nil,
+	"connectorInstances":  {"connectorInstance.clone"},
+	"davinciApplications": {"flowPolicies", "davinciApplication.rotateKey", "davinciApplication.rotateSecret"},
+	"flowPolicies":        {"davinciApplication"},
+}
+
+// withAPIFields adds the environment relationship and HAL links the API returns with every
+// resource when the dataset omits them, so datasets only need the fields under test
+func (s *Server) withAPIFields(resource map[string]interface{}, collection, self string) map[string]interface{} {
+	links, ok := resourceLinks[collection]
+	_, hasEnvironment := resource["environment"]
+	_, hasLinks := resource["_links"]
+	if !ok || (hasEnvironment && hasLinks) {
+		return resource
+	}
+
+	filled := make(map[string]interface{}, len(resource)+2)
+	for key, value := range resource {
+		filled[key] = value
+	}
+	if !hasEnvironment {
+		filled["environment"] = map[string]interface{}{"id": s.dataset.EnvironmentID}
+	}
+	if !hasLinks {
+		hal := map[string]interface{}{
+			"self":        href(self),
+			"environment": href(s.URL + "/v1/environments/" + s.dataset.EnvironmentID),
+		}
+		for _, name := range links {
+			hal[name] = href(self)
+		}
+		filled["_links"] = hal
+	}
+	return filled
+}
+
+func href(url string) map[string]string {
+	return map[string]string{"href": url}
+}
+
+// writeAPIError writes an error in the PingOne API error format
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"id":      "fake-" + strconv.Itoa(status),
+		"code":    apiErrorCode(status),
+		"message": message,
+	})
+}
+
+func apiErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "INVALID_REQUEST"
+	case http.StatusUnauthorized:
+		return "ACCESS_FAILED"
+	case http.StatusForbidden:
+		return "ACCESS_FAILED"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		return "REQUEST_LIMITED"
+	default:
+		return "UNEXPECTED_ERROR"
+	}
+}
+
+// writeOAuthError writes an error in the OAuth 2.0 token endpoint error format
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,152 @@
+package fakeserver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, opts Options) (*Server, *api.Client) {
+	t.Helper()
+	dataset, err := LoadDefaultDataset()
+	require.NoError(t, err)
+
+	server := New(dataset, opts)
+	t.Cleanup(server.Close)
+
+	client, err := api.NewClientWithOptions(context.Background(), server.EnvironmentID(), server.EnvironmentID(), "NA", ClientID, ClientSecret, api.ClientOptions{
+		APIURL:  server.URL,
+		AuthURL: server.URL,
+	})
+	require.NoError(t, err)
+	return server, client
+}
+
+func TestLoadDefaultDataset(t *testing.T) {
+	dataset, err := LoadDefaultDataset()
+	require.NoError(t, err)
+
+	assert.Equal(t, "62f10a04-6c54-40c2-a97d-80a98522ff9a", dataset.EnvironmentID)
+	assert.Len(t, dataset.Flows, 1)
+	assert.Len(t, dataset.Variables, 2)
+	assert.Len(t, dataset.ConnectorInstances, 1)
+	assert.Len(t, dataset.Applications, 1)
+	assert.Len(t, dataset.FlowPolicies, 1)
+}
+
+func TestServer_ServesEveryResource(t *testing.T) {
+	server, client := newTestServer(t, Options{})
+	ctx := context.Background()
+
+	flows, err := client.ListFlows(ctx)
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, "PingOne reCAPTCHA v3 subflow", flows[0].Name)
+
+	flow, err := client.GetFlow(ctx, flows[0].FlowID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, flow.GraphData)
+
+	variables, err := client.ListVariables(ctx, client.EnvironmentID)
+	require.NoError(t, err)
+	assert.Len(t, variables, 2)
+
+	variable, err := client.GetVariable(ctx, client.EnvironmentID, "229b519c-867d-4423-aeea-178c15c73d5f")
+	require.NoError(t, err)
+	assert.Equal(t, "companyBool", variable.GetName())
+
+	instances, err := client.ListConnectorInstances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+
+	instance, err := client.GetConnectorInstance(ctx, instances[0].InstanceID)
+	require.NoError(t, err)
+	assert.Equal(t, "pingOneRiskConnector", instance.ConnectorID)
+
+	applications, err := client.ListApplications(ctx, client.EnvironmentID)
+	require.NoError(t, err)
+	require.Len(t, applications, 1)
+
+	policies, err := client.ListFlowPolicies(ctx)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, applications[0].GetId(), policies[0].ApplicationID)
+
+	policy, err := client.GetFlowPolicy(ctx, policies[0].ApplicationID, policies[0].PolicyID)
+	require.NoError(t, err)
+	assert.Equal(t, "reCAPTCHA Policy", policy.Name)
+
+	assert.Equal(t, 1, server.TokenRequests(), "token is reused across requests")
+}
+
+func TestServer_Pagination(t *testing.T) {
+	server, client := newTestServer(t, Options{PageSize: 1})
+
+	variables, err := client.ListVariables(context.Background(), client.EnvironmentID)
+	require.NoError(t, err)
+	assert.Len(t, variables, 2)
+
+	path := "/v1/environments/" + server.EnvironmentID() + "/variables"
+	assert.Equal(t, []string{"GET " + path, "GET " + path + "?cursor=1&limit=1"}, server.Requests())
+}
+
+func TestServer_Errors(t *testing.T) {
+	t.Run("Configured failures", func(t *testing.T) {
+		server, client := newTestServer(t, Options{Failures: map[string]int{
+			"/v1/environments/62f10a04-6c54-40c2-a97d-80a98522ff9a/flows": http.StatusForbidden,
+		}})
+		client.SetRetryConfig(api.RetryConfig{MaxAttempts: 1})
+
+		_, err := client.ListFlows(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API returned status 403")
+		assert.Len(t, server.Requests(), 1)
+	})
+
+	t.Run("Configured failure sequences", func(t *testing.T) {
+		server, client := newTestServer(t, Options{FailureSequences: map[string][]int{
+			"/v1/environments/62f10a04-6c54-40c2-a97d-80a98522ff9a/flows": {http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+		}})
+		client.SetRetryConfig(api.RetryConfig{MaxAttempts: 1})
+
+		for range 2 {
+			_, err := client.ListFlows(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "API returned status 503")
+		}
+		_, err := client.ListFlows(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, server.Requests(), 3)
+	})
+
+	t.Run("Unknown resources", func(t *testing.T) {
+		_, client := newTestServer(t, Options{})
+
+		_, err := client.GetFlow(context.Background(), "missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API returned status 404")
+	})
+
+	t.Run("Invalid credentials", func(t *testing.T) {
+		dataset, err := LoadDefaultDataset()
+		require.NoError(t, err)
+		server := New(dataset, Options{})
+		defer server.Close()
+
+		client, err := api.NewClientWithOptions(context.Background(), server.EnvironmentID(), server.EnvironmentID(), "NA", ClientID, "wrong", api.ClientOptions{
+			APIURL:  server.URL,
+			AuthURL: server.URL,
+		})
+		require.NoError(t, err)
+
+		_, err = client.ListFlows(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_client")
+		assert.Zero(t, server.TokenRequests())
+		assert.Empty(t, server.Requests())
+	})
+}
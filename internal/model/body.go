@@ -0,0 +1,121 @@
+package model
+
+import "strings"
+
+// Body is the content of a block or file: attributes, nested blocks, blank lines and line
+// comments, in the order they are written
+type Body struct {
+	items []bodyItem
+}
+
+// Attribute is an attribute of a block
+type Attribute struct {
+	Name  string
+	Value Value
+}
+
+// Block is a block of a configuration file other than a resource, e.g. a variable, module or
+// import block, or a block nested in another, e.g. a validation block
+type Block struct {
+	Type   string
+	Labels []string
+	Body   Body
+}
+
+// address names the block in errors: the address of a resource, otherwise the type and labels
+func (b *Block) address() string {
+	if b.Type == "resource" {
+		return strings.Join(b.Labels, ".")
+	}
+	return strings.Join(append([]string{b.Type}, b.Labels...), ".")
+}
+
+// bodyItem is an attribute, a nested block, a blank line or a line comment. A comment about an attribute
+// explains why the attribute is missing or has its value; it goes away when the attribute is
+// set again.
+type bodyItem struct {
+	attribute *Attribute
+	block     *Block
+	comment   string
+	about     string
+	newline   bool
+}
+
+// Attributes returns the attributes in order
+func (b *Body) Attributes() []*Attribute {
+	var attributes []*Attribute
+	for _, item := range b.items {
+		if item.attribute != nil {
+			attributes = append(attributes, item.attribute)
+		}
+	}
+	return attributes
+}
+
+// Attribute returns the attribute name, or nil
+func (b *Body) Attribute(name string) *Attribute {
+	for _, item := range b.items {
+		if item.attribute != nil && item.attribute.Name == name {
+			return item.attribute
+		}
+	}
+	return nil
+}
+
+// SetAttribute sets the value of the attribute name and removes the comments about it. A
+// missing attribute takes the place of the first comment about it, or is appended.
+func (b *Body) SetAttribute(name string, value Value) {
+	attr := b.Attribute(name)
+	if attr != nil {
+		attr.Value = value
+	}
+
+	items := make([]bodyItem, 0, len(b.items)+1)
+	for _, item := range b.items {
+		if item.about == name && item.attribute == nil {
+			if attr == nil {
+				attr = &Attribute{Name: name, Value: value}
+				items = append(items, bodyItem{attribute: attr})
+			}
+			continue
+		}
+		items = append(items, item)
+	}
+	if attr == nil {
+		items = append(items, bodyItem{attribute: &Attribute{Name: name, Value: value}})
+	}
+	b.items = items
+}
+
+// HasCommentAbout reports whether the body has a comment about the attribute name
+func (b *Body) HasCommentAbout(name string) bool {
+	for _, item := range b.items {
+		if item.about == name && item.attribute == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendBlock appends a nested block without attributes and returns it
+func (b *Body) AppendBlock(blockType string, labels ...string) *Block {
+	block := &Block{Type: blockType, Labels: labels}
+	b.items = append(b.items, bodyItem{block: block})
+	return block
+}
+
+// AppendNewline appends a blank line
+func (b *Body) AppendNewline() {
+	b.items = append(b.items, bodyItem{newline: true})
+}
+
+// AppendComment appends a line comment
+func (b *Body) AppendComment(text string) {
+	b.items = append(b.items, bodyItem{comment: text})
+}
+
+// AppendCommentAbout appends a line comment about the attribute name, such as a TODO standing
+// in for a value that could not be exported
+func (b *Body) AppendCommentAbout(name, text string) {
+	b.items = append(b.items, bodyItem{comment: text, about: name})
+}
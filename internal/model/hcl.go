@@ -0,0 +1,234 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Resources are written with hclwrite rather than by concatenating strings, so string values
+// are always escaped (quotes, backslashes, control characters and the "${" and "%{" template
+// sequences), references are written from traversals and the output is formatted like
+// terraform fmt.
+
+// HCL returns resources in native syntax, separated by blank lines
+func HCL(resources ...*Resource) (string, error) {
+	return FileHCL(resourceFile(resources))
+}
+
+// FileHCL returns the content of a configuration or variable definitions file in native syntax
+func FileHCL(file *Body) (string, error) {
+	out := hclwrite.NewFile()
+	if err := writeBody(out.Body(), file); err != nil {
+		return "", err
+	}
+	return string(hclwrite.Format(out.Bytes())), nil
+}
+
+// resourceFile returns a file body holding resource blocks, separated by blank lines
+func resourceFile(resources []*Resource) *Body {
+	file := &Body{}
+	for i, resource := range resources {
+		if i > 0 {
+			file.AppendNewline()
+		}
+		block := file.AppendBlock("resource", resource.Type, resource.Name)
+		block.Body = resource.Body
+	}
+	return file
+}
+
+// writeBody appends the items of body to out
+func writeBody(out *hclwrite.Body, body *Body) error {
+	for _, item := range body.items {
+		switch {
+		case item.attribute != nil:
+			tokens, err := valueTokens(item.attribute.Value)
+			if err != nil {
+				return fmt.Errorf("attribute %s: %w", item.attribute.Name, err)
+			}
+			out.SetAttributeRaw(item.attribute.Name, tokens)
+		case item.block != nil:
+			block := out.AppendNewBlock(item.block.Type, item.block.Labels)
+			if err := writeBody(block.Body(), &item.block.Body); err != nil {
+				return fmt.Errorf("failed to write %s: %w", item.block.address(), err)
+			}
+		case item.newline:
+			out.AppendNewline()
+		default:
+			out.AppendUnstructuredTokens(hclwrite.Tokens{{
+				Type:  hclsyntax.TokenComment,
+				Bytes: []byte("# " + commentText(item.comment) + "\n"),
+			}})
+		}
+	}
+	return nil
+}
+
+// commentText keeps a comment on one line, so values quoted in it cannot end the comment
+func commentText(text string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+}
+
+// valueTokens returns the tokens of value
+func valueTokens(value Value) (hclwrite.Tokens, error) {
+	switch v := value.(type) {
+	case Literal:
+		return literalTokens(v.Value), nil
+	case Reference:
+		return hclwrite.TokensForTraversal(v.Traversal), nil
+	case Template:
+		return templateTokens(v), nil
+	case *Object:
+		return objectTokens(v)
+	case *Tuple:
+		elems := make([]hclwrite.Tokens, 0, len(v.Elems))
+		for _, elem := range v.Elems {
+			tokens, err := valueTokens(elem)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, tokens)
+		}
+		return tupleTokens(elems), nil
+	case *Call:
+		args := make([]hclwrite.Tokens, 0, len(v.Args))
+		for _, arg := range v.Args {
+			tokens, err := valueTokens(arg)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, tokens)
+		}
+		return hclwrite.TokensForFunctionCall(v.Name, args...), nil
+	case Expression:
+		return expressionTokens(v), nil
+	case Commented:
+		tokens, err := valueTokens(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComment, Bytes: []byte("# " + commentText(v.Comment))}), nil
+	case nil:
+		return nil, fmt.Errorf("missing value")
+	}
+	return nil, fmt.Errorf("unsupported value %T", value)
+}
+
+// literalTokens returns a literal. Numbers that are not integers are written like
+// encoding/json writes them, e.g. 0.5 or 1e-50.
+func literalTokens(value cty.Value) hclwrite.Tokens {
+	if value.IsKnown() && !value.IsNull() && value.Type() == cty.Number {
+		if _, ok := integer(value); !ok {
+			f, _ := value.AsBigFloat().Float64()
+			if literal, err := json.Marshal(f); err == nil {
+				return hclwrite.Tokens{{Type: hclsyntax.TokenNumberLit, Bytes: literal}}
+			}
+		}
+	}
+	return hclwrite.TokensForValue(value)
+}
+
+// expressionTokens returns the tokens of an expression's source, keeping the space between
+// tokens that are apart in the source
+func expressionTokens(expr Expression) hclwrite.Tokens {
+	lexed, _ := hclsyntax.LexExpression([]byte(expr.Source), "", hcl.InitialPos)
+	tokens := make(hclwrite.Tokens, 0, len(lexed))
+	for i, token := range lexed {
+		if token.Type == hclsyntax.TokenEOF {
+			break
+		}
+		spaces := 0
+		if i > 0 && token.Range.Start.Byte > lexed[i-1].Range.End.Byte {
+			spaces = 1
+		}
+		tokens = append(tokens, &hclwrite.Token{Type: token.Type, Bytes: token.Bytes, SpacesBefore: spaces})
+	}
+	return tokens
+}
+
+// templateTokens returns a quoted template, escaping its literal parts
+func templateTokens(template Template) hclwrite.Tokens {
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)}}
+	for _, part := range template {
+		if part.Reference != nil {
+			tokens = appendInterpolation(tokens, part.Reference)
+			continue
+		}
+		// The literal tokens of a quoted string, without its quotes
+		quoted := hclwrite.TokensForValue(cty.StringVal(part.Literal))
+		tokens = append(tokens, quoted[1:len(quoted)-1]...)
+	}
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)})
+}
+
+// appendInterpolation appends a ${...} interpolation of traversal to tokens
+func appendInterpolation(tokens hclwrite.Tokens, traversal hcl.Traversal) hclwrite.Tokens {
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateInterp, Bytes: []byte("${")})
+	tokens = append(tokens, hclwrite.TokensForTraversal(traversal)...)
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateSeqEnd, Bytes: []byte("}")})
+}
+
+// objectTokens returns an object constructor; an object without attributes is written as {}
+func objectTokens(object *Object) (hclwrite.Tokens, error) {
+	if len(object.Attrs) == 0 {
+		return hclwrite.Tokens{
+			{Type: hclsyntax.TokenOBrace, Bytes: []byte("{")},
+			{Type: hclsyntax.TokenCBrace, Bytes: []byte("}")},
+		}, nil
+	}
+	attrs := make([]hclwrite.ObjectAttrTokens, 0, len(object.Attrs))
+	for _, attr := range object.Attrs {
+		value, err := valueTokens(attr.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", attr.Key, err)
+		}
+		name := hclwrite.TokensForIdentifier(attr.Key)
+		if attr.Quoted {
+			name = hclwrite.TokensForValue(cty.StringVal(attr.Key))
+		}
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{Name: name, Value: value})
+	}
+	return hclwrite.TokensForObject(attrs), nil
+}
+
+// tupleTokens returns a tuple constructor. Tuples of single-line elements stay on one line;
+// otherwise each element gets its own line, with line comments after the separating comma.
+func tupleTokens(elems []hclwrite.Tokens) hclwrite.Tokens {
+	multiline := false
+	for _, elem := range elems {
+		for _, token := range elem {
+			if token.Type == hclsyntax.TokenNewline || token.Type == hclsyntax.TokenComment {
+				multiline = true
+			}
+		}
+	}
+	if !multiline {
+		return hclwrite.TokensForTuple(elems)
+	}
+
+	tokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")},
+		{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+	}
+	for i, elem := range elems {
+		var comment *hclwrite.Token
+		if last := len(elem) - 1; last >= 0 && elem[last].Type == hclsyntax.TokenComment {
+			elem, comment = elem[:last], elem[last]
+		}
+		tokens = append(tokens, elem...)
+		if i < len(elems)-1 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+		if comment != nil {
+			tokens = append(tokens, comment)
+		}
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+	}
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHCL verifies resources are written formatted, with comments, blank lines and every kind
+// of value
+func TestHCL(t *testing.T) {
+	env, err := Var("pingone_environment_id")
+	require.NoError(t, err)
+	label, err := ParseExpression(`"Color is ${pingone_davinci_variable.color.name}!"`)
+	require.NoError(t, err)
+
+	properties := &Object{}
+	properties.SetKey("node-1", String("a"))
+	properties.Set("list", &Tuple{Elems: []Value{Number(1.5), Bool(true), Null()}})
+
+	flow := NewResource("pingone_davinci_flow", "main")
+	flow.Body.SetAttribute("environment_id", env)
+	flow.Body.SetAttribute("name", String(`Uses ${literal} and "quotes"`))
+	flow.Body.SetAttribute("flow_id", WithComment(String(""), "TODO: Replace with flow reference"))
+	flow.Body.AppendNewline()
+	flow.Body.AppendComment("Graph")
+	flow.Body.SetAttribute("label", label)
+	flow.Body.SetAttribute("properties", JSONEncode(properties))
+	flow.Body.SetAttribute("empty", &Object{})
+
+	enable := NewResource("pingone_davinci_flow_enable", "main")
+	enable.Body.SetAttribute("enabled", Bool(true))
+
+	got, err := HCL(flow, enable)
+	require.NoError(t, err)
+	assert.Equal(t, `resource "pingone_davinci_flow" "main" {
+  environment_id = var.pingone_environment_id
+  name           = "Uses $${literal} and \"quotes\""
+  flow_id        = "" # TODO: Replace with flow reference
+
+  # Graph
+  label = "Color is ${pingone_davinci_variable.color.name}!"
+  properties = jsonencode({
+    "node-1" = "a"
+    list     = [1.5, true, null]
+  })
+  empty = {}
+}
+
+resource "pingone_davinci_flow_enable" "main" {
+  enabled = true
+}
+`, got)
+
+	_, diags := hclsyntax.ParseConfig([]byte(got), "main.tf", hcl.InitialPos)
+	assert.False(t, diags.HasErrors(), diags.Error())
+}
+
+// TestHCL_CommentsStayOnOneLine verifies a newline in a comment cannot end it
+func TestHCL_CommentsStayOnOneLine(t *testing.T) {
+	resource := NewResource("pingone_davinci_variable", "x")
+	resource.Body.AppendComment("TODO: value was\n\"injected\" = true")
+	resource.Body.SetAttribute("value", WithComment(String(""), "note\r\nbroken = 1"))
+
+	got, err := HCL(resource)
+	require.NoError(t, err)
+	assert.Contains(t, got, `# TODO: value was "injected" = true`)
+	assert.Contains(t, got, `value = "" # note broken = 1`)
+}
+
+// TestHCL_MissingValue verifies an attribute without a value is an error
+func TestHCL_MissingValue(t *testing.T) {
+	resource := NewResource("pingone_davinci_variable", "x")
+	resource.Body.SetAttribute("value", nil)
+
+	_, err := HCL(resource)
+	assert.ErrorContains(t, err, "pingone_davinci_variable.x")
+}
+
+// TestFileHCL verifies files are written with nested blocks, comments and expressions
+func TestFileHCL(t *testing.T) {
+	condition, err := NativeExpression(`var.count>=0`)
+	require.NoError(t, err)
+	to, err := ParseExpression("module.ping-export.pingone_davinci_flow.main")
+	require.NoError(t, err)
+
+	file := &Body{}
+	file.AppendComment("Variables")
+	variable := file.AppendBlock("variable", "count")
+	variable.Body.SetAttribute("type", Expression{Source: "number"})
+	variable.Body.SetAttribute("description", String(`The "count"`))
+	validation := variable.Body.AppendBlock("validation")
+	validation.Body.SetAttribute("condition", condition)
+	file.AppendNewline()
+	imported := file.AppendBlock("import")
+	imported.Body.SetAttribute("to", to)
+
+	got, err := FileHCL(file)
+	require.NoError(t, err)
+	assert.Equal(t, `# Variables
+variable "count" {
+  type        = number
+  description = "The \"count\""
+  validation {
+    condition = var.count >= 0
+  }
+}
+
+import {
+  to = module.ping-export.pingone_davinci_flow.main
+}
+`, got)
+
+	_, diags := hclsyntax.ParseConfig([]byte(got), "main.tf", hcl.InitialPos)
+	assert.False(t, diags.HasErrors(), diags.Error())
+}
@@ -0,0 +1,405 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Files are written in Terraform JSON syntax from the model rather than by converting native
+// syntax, so nothing is lost on the way. In JSON syntax strings are templates: literal strings
+// are escaped and references are interpolated. Line comments, which JSON lacks, become "//"
+// members of the body holding them, which Terraform ignores; a comment on a nested value is
+// prefixed with the path of the value. Blocks become objects nested by type and labels; the
+// blocks sharing a type and labels become an array.
+
+// jsonExpressionAttributes are the attributes, by block type, whose JSON value is an
+// expression written as a string instead of a string template, e.g. the type of a variable
+var jsonExpressionAttributes = map[string]map[string]bool{
+	"variable": {"type": true},
+	"import":   {"to": true},
+	"moved":    {"from": true, "to": true},
+}
+
+// JSON returns resources in Terraform JSON syntax, indented, with a trailing newline
+func JSON(resources ...*Resource) ([]byte, error) {
+	return FileJSON(resourceFile(resources))
+}
+
+// FileJSON returns the content of a configuration file in Terraform JSON syntax, indented,
+// with a trailing newline
+func FileJSON(file *Body) ([]byte, error) {
+	return jsonWriter{}.file(file)
+}
+
+// VariableValuesJSON returns the content of a variable definitions file (.tfvars) in JSON
+// syntax. Its values are literal: strings are not templates, and must be constant.
+func VariableValuesJSON(file *Body) ([]byte, error) {
+	return jsonWriter{literal: true}.file(file)
+}
+
+// jsonWriter writes values as JSON, as string templates or, when literal is set, as
+// literal values
+type jsonWriter struct {
+	literal bool
+}
+
+// file writes the body of a file as an indented JSON object
+func (w jsonWriter) file(file *Body) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := w.writeBody(&buf, file, ""); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteString("\n")
+	return out.Bytes(), nil
+}
+
+// writeBody writes the attributes and nested blocks of a body of the given block type as an
+// object, preceded by its comments
+func (w jsonWriter) writeBody(buf *bytes.Buffer, body *Body, blockType string) error {
+	var comments []string
+	var attributes []*Attribute
+	var values []Value
+	blocks := &jsonBlockGroup{}
+	for _, item := range body.items {
+		switch {
+		case item.attribute != nil:
+			attributes = append(attributes, item.attribute)
+			values = append(values, stripComments(item.attribute.Value, Path{item.attribute.Name}, &comments))
+		case item.block != nil:
+			if err := blocks.add(append([]string{item.block.Type}, item.block.Labels...), item.block); err != nil {
+				return err
+			}
+		case !item.newline:
+			comments = append(comments, commentText(item.comment))
+		}
+	}
+
+	buf.WriteString("{")
+	members := 0
+	if len(comments) > 0 {
+		buf.WriteString(`"//":`)
+		if len(comments) == 1 {
+			writeJSONString(buf, comments[0])
+		} else {
+			buf.WriteString("[")
+			for i, comment := range comments {
+				if i > 0 {
+					buf.WriteString(",")
+				}
+				writeJSONString(buf, comment)
+			}
+			buf.WriteString("]")
+		}
+		members++
+	}
+	for i, attr := range attributes {
+		if members > 0 {
+			buf.WriteString(",")
+		}
+		members++
+		writeJSONString(buf, attr.Name)
+		buf.WriteString(":")
+		if jsonExpressionAttributes[blockType][attr.Name] {
+			source, err := expressionSource(values[i])
+			if err != nil {
+				return fmt.Errorf("attribute %s: %w", attr.Name, err)
+			}
+			writeJSONString(buf, source)
+			continue
+		}
+		if err := w.writeValue(buf, values[i]); err != nil {
+			return fmt.Errorf("attribute %s: %w", attr.Name, err)
+		}
+	}
+	for _, key := range blocks.keys {
+		if members > 0 {
+			buf.WriteString(",")
+		}
+		members++
+		writeJSONString(buf, key)
+		buf.WriteString(":")
+		if err := w.writeBlocks(buf, blocks.groups[key]); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("}")
+	return nil
+}
+
+// jsonBlockGroup holds the blocks sharing a type and labels, or the groups nested under it by
+// the next label
+type jsonBlockGroup struct {
+	keys   []string
+	groups map[string]*jsonBlockGroup
+	blocks []*Block
+}
+
+// add adds a block under the path of its type and labels
+func (g *jsonBlockGroup) add(path []string, block *Block) error {
+	if len(path) == 0 {
+		if g.groups != nil {
+			return fmt.Errorf("%s is both a block and a block label", block.address())
+		}
+		g.blocks = append(g.blocks, block)
+		return nil
+	}
+	if len(g.blocks) > 0 {
+		return fmt.Errorf("%s is both a block and a block label", block.address())
+	}
+	if g.groups == nil {
+		g.groups = make(map[string]*jsonBlockGroup)
+	}
+	child, ok := g.groups[path[0]]
+	if !ok {
+		child = &jsonBlockGroup{}
+		g.groups[path[0]] = child
+		g.keys = append(g.keys, path[0])
+	}
+	return child.add(path[1:], block)
+}
+
+// writeBlocks writes a group: one block as its body, several as an array of bodies, and
+// nested groups as an object keyed by label
+func (w jsonWriter) writeBlocks(buf *bytes.Buffer, group *jsonBlockGroup) error {
+	if group.groups == nil {
+		if len(group.blocks) > 1 {
+			buf.WriteString("[")
+		}
+		for i, block := range group.blocks {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := w.writeBody(buf, &block.Body, block.Type); err != nil {
+				return fmt.Errorf("failed to write %s: %w", block.address(), err)
+			}
+		}
+		if len(group.blocks) > 1 {
+			buf.WriteString("]")
+		}
+		return nil
+	}
+
+	buf.WriteString("{")
+	for i, key := range group.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		writeJSONString(buf, key)
+		buf.WriteString(":")
+		if err := w.writeBlocks(buf, group.groups[key]); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("}")
+	return nil
+}
+
+// expressionSource returns the native source of a reference or expression, for an attribute
+// whose JSON value is an expression
+func expressionSource(value Value) (string, error) {
+	switch v := value.(type) {
+	case Reference:
+		return string(hclwrite.TokensForTraversal(v.Traversal).Bytes()), nil
+	case Expression:
+		return v.Source, nil
+	case nil:
+		return "", fmt.Errorf("missing value")
+	}
+	return "", fmt.Errorf("unsupported value %T: expected a reference or expression", value)
+}
+
+// stripComments returns value without its comments, appending each comment to comments,
+// prefixed with the path of the value it follows
+func stripComments(value Value, path Path, comments *[]string) Value {
+	switch v := value.(type) {
+	case Commented:
+		inner := stripComments(v.Value, path, comments)
+		*comments = append(*comments, path.String()+": "+commentText(v.Comment))
+		return inner
+	case *Object:
+		object := &Object{Attrs: make([]ObjectAttr, len(v.Attrs))}
+		for i, attr := range v.Attrs {
+			object.Attrs[i] = attr
+			object.Attrs[i].Value = stripComments(attr.Value, append(path[:len(path):len(path)], attr.Key), comments)
+		}
+		return object
+	case *Tuple:
+		tuple := &Tuple{Elems: make([]Value, len(v.Elems))}
+		for i, elem := range v.Elems {
+			tuple.Elems[i] = stripComments(elem, append(path[:len(path):len(path)], strconv.Itoa(i)), comments)
+		}
+		return tuple
+	case *Call:
+		call := &Call{Name: v.Name, Args: make([]Value, len(v.Args))}
+		for i, arg := range v.Args {
+			call.Args[i] = stripComments(arg, path, comments)
+		}
+		return call
+	}
+	return value
+}
+
+// writeValue writes a value without comments as a JSON expression. Function calls and
+// expressions are interpolated in native syntax, as Terraform JSON has no other form for them.
+// Literal values must be constant.
+func (w jsonWriter) writeValue(buf *bytes.Buffer, value Value) error {
+	switch value.(type) {
+	case Reference, Template, *Call, Expression:
+		if w.literal {
+			return fmt.Errorf("value is not constant")
+		}
+	}
+
+	switch v := value.(type) {
+	case Literal:
+		return w.writeLiteral(buf, v.Value)
+	case Reference:
+		writeJSONString(buf, interpolation(v.Traversal))
+	case Template:
+		var sb strings.Builder
+		for _, part := range v {
+			if part.Reference != nil {
+				sb.WriteString(interpolation(part.Reference))
+				continue
+			}
+			sb.WriteString(escapeTemplate(part.Literal))
+		}
+		writeJSONString(buf, sb.String())
+	case *Object:
+		buf.WriteString("{")
+		for i, attr := range v.Attrs {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			writeJSONString(buf, w.escape(attr.Key))
+			buf.WriteString(":")
+			if err := w.writeValue(buf, attr.Value); err != nil {
+				return fmt.Errorf("%s: %w", attr.Key, err)
+			}
+		}
+		buf.WriteString("}")
+	case *Tuple:
+		buf.WriteString("[")
+		for i, elem := range v.Elems {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := w.writeValue(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	case *Call:
+		tokens, err := valueTokens(v)
+		if err != nil {
+			return err
+		}
+		writeJSONString(buf, "${"+string(hclwrite.Format(tokens.Bytes()))+"}")
+	case Expression:
+		writeJSONString(buf, "${"+v.Source+"}")
+	case nil:
+		return fmt.Errorf("missing value")
+	default:
+		return fmt.Errorf("unsupported value %T", value)
+	}
+	return nil
+}
+
+// writeLiteral writes a constant. Numbers are written like literalTokens writes them.
+func (w jsonWriter) writeLiteral(buf *bytes.Buffer, value cty.Value) error {
+	if value.IsNull() {
+		buf.WriteString("null")
+		return nil
+	}
+	if !value.IsWhollyKnown() {
+		return fmt.Errorf("value is not known")
+	}
+
+	valueType := value.Type()
+	switch {
+	case valueType == cty.String:
+		writeJSONString(buf, w.escape(value.AsString()))
+	case valueType == cty.Number:
+		if i, ok := integer(value); ok {
+			buf.WriteString(strconv.FormatInt(i, 10))
+			return nil
+		}
+		f, _ := value.AsBigFloat().Float64()
+		literal, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		buf.Write(literal)
+	case valueType == cty.Bool:
+		buf.WriteString(strconv.FormatBool(value.True()))
+	case valueType.IsObjectType() || valueType.IsMapType():
+		buf.WriteString("{")
+		i := 0
+		for it := value.ElementIterator(); it.Next(); i++ {
+			key, element := it.Element()
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			writeJSONString(buf, w.escape(key.AsString()))
+			buf.WriteString(":")
+			if err := w.writeLiteral(buf, element); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}")
+	case valueType.IsTupleType() || valueType.IsListType() || valueType.IsSetType():
+		buf.WriteString("[")
+		i := 0
+		for it := value.ElementIterator(); it.Next(); i++ {
+			_, element := it.Element()
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := w.writeLiteral(buf, element); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	default:
+		return fmt.Errorf("unsupported literal of type %s", valueType.FriendlyName())
+	}
+	return nil
+}
+
+// interpolation returns the ${...} interpolation of a reference
+func interpolation(traversal hcl.Traversal) string {
+	return "${" + string(hclwrite.TokensForTraversal(traversal).Bytes()) + "}"
+}
+
+// escape escapes a string written as a string template; literal values are written as is
+func (w jsonWriter) escape(s string) string {
+	if w.literal {
+		return s
+	}
+	return escapeTemplate(s)
+}
+
+// escapeTemplate escapes the template sequences of a literal string
+func escapeTemplate(s string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s)
+}
+
+// writeJSONString writes a JSON string, leaving HTML characters unescaped
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s) // Encoding a string cannot fail
+	buf.Truncate(buf.Len() - 1)
+}
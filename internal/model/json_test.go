@@ -0,0 +1,169 @@
+package model
+
+import (
+	"testing"
+
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJSON verifies resources are written in Terraform JSON syntax, with comments as "//"
+// members and every kind of value
+func TestJSON(t *testing.T) {
+	env, err := Var("pingone_environment_id")
+	require.NoError(t, err)
+	label, err := ParseExpression(`"Color is ${pingone_davinci_variable.color.name}!"`)
+	require.NoError(t, err)
+
+	item := &Object{}
+	item.Set("id", WithComment(String("flow-1"), "TODO: Replace with pingone_davinci_flow.<resource_name>.id"))
+	properties := &Object{}
+	properties.SetKey("node-1", String("a"))
+	properties.Set("list", &Tuple{Elems: []Value{Number(1.5), Bool(true), Null()}})
+
+	flow := NewResource("pingone_davinci_flow", "main")
+	flow.Body.SetAttribute("environment_id", env)
+	flow.Body.SetAttribute("name", String(`Uses ${literal} and "quotes" <html>`))
+	flow.Body.SetAttribute("flow_id", WithComment(String(""), "TODO: Replace with flow reference"))
+	flow.Body.AppendNewline()
+	flow.Body.AppendComment("Graph")
+	flow.Body.SetAttribute("label", label)
+	flow.Body.SetAttribute("distributions", &Tuple{Elems: []Value{item}})
+	flow.Body.SetAttribute("properties", JSONEncode(properties))
+	flow.Body.SetAttribute("empty", &Object{})
+
+	enable := NewResource("pingone_davinci_flow_enable", "main")
+	enable.Body.SetAttribute("enabled", Bool(true))
+
+	got, err := JSON(flow, enable)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "resource": {
+    "pingone_davinci_flow": {
+      "main": {
+        "//": [
+          "flow_id: TODO: Replace with flow reference",
+          "Graph",
+          "distributions.0.id: TODO: Replace with pingone_davinci_flow.<resource_name>.id"
+        ],
+        "environment_id": "${var.pingone_environment_id}",
+        "name": "Uses $${literal} and \"quotes\" <html>",
+        "flow_id": "",
+        "label": "Color is ${pingone_davinci_variable.color.name}!",
+        "distributions": [
+          {
+            "id": "flow-1"
+          }
+        ],
+        "properties": "${jsonencode({\n  \"node-1\" = \"a\"\n  list     = [1.5, true, null]\n})}",
+        "empty": {}
+      }
+    },
+    "pingone_davinci_flow_enable": {
+      "main": {
+        "enabled": true
+      }
+    }
+  }
+}
+`, string(got))
+
+	_, diags := hcljson.Parse(got, "main.tf.json")
+	assert.False(t, diags.HasErrors(), diags.Error())
+}
+
+// TestJSON_CommentsInsideFunctionCalls verifies comments inside a function call are moved out
+// of its interpolation, where they would comment out the rest of the expression
+func TestJSON_CommentsInsideFunctionCalls(t *testing.T) {
+	properties := &Object{}
+	properties.Set("id", WithComment(String("flow-1"), "TODO: Replace with flow reference"))
+	resource := NewResource("pingone_davinci_flow", "x")
+	resource.Body.SetAttribute("properties", JSONEncode(properties))
+
+	got, err := JSON(resource)
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `"//": "properties.id: TODO: Replace with flow reference"`)
+	assert.Contains(t, string(got), `"properties": "${jsonencode({\n  id = \"flow-1\"\n})}"`)
+}
+
+// TestJSON_MissingValue verifies an attribute without a value is an error
+func TestJSON_MissingValue(t *testing.T) {
+	resource := NewResource("pingone_davinci_variable", "x")
+	resource.Body.SetAttribute("value", nil)
+
+	_, err := JSON(resource)
+	assert.ErrorContains(t, err, "pingone_davinci_variable.x")
+}
+
+// TestFileJSON verifies blocks are nested by type and labels, blocks sharing them become an
+// array and expression attributes are written without interpolation
+func TestFileJSON(t *testing.T) {
+	condition, err := NativeExpression(`var.count >= 0`)
+	require.NoError(t, err)
+
+	file := &Body{}
+	file.AppendComment("Variables")
+	variable := file.AppendBlock("variable", "count")
+	variable.Body.SetAttribute("type", Expression{Source: "number"})
+	validation := variable.Body.AppendBlock("validation")
+	validation.Body.SetAttribute("condition", condition)
+	for _, name := range []string{"main", "other"} {
+		to, err := ParseExpression("module.ping-export.pingone_davinci_flow." + name)
+		require.NoError(t, err)
+		imported := file.AppendBlock("import")
+		imported.Body.SetAttribute("to", to)
+		imported.Body.SetAttribute("id", String("env/${id}"))
+	}
+
+	got, err := FileJSON(file)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "//": "Variables",
+  "variable": {
+    "count": {
+      "type": "number",
+      "validation": {
+        "condition": "${var.count >= 0}"
+      }
+    }
+  },
+  "import": [
+    {
+      "to": "module.ping-export.pingone_davinci_flow.main",
+      "id": "env/$${id}"
+    },
+    {
+      "to": "module.ping-export.pingone_davinci_flow.other",
+      "id": "env/$${id}"
+    }
+  ]
+}
+`, string(got))
+
+	_, diags := hcljson.Parse(got, "main.tf.json")
+	assert.False(t, diags.HasErrors(), diags.Error())
+}
+
+// TestVariableValuesJSON verifies variable definition values are written as literal values,
+// and only constants are accepted
+func TestVariableValuesJSON(t *testing.T) {
+	file := &Body{}
+	file.SetAttribute("name", String("Uses ${literal}"))
+	file.SetAttribute("secret", WithComment(String(""), "Secret value - provide manually"))
+
+	got, err := VariableValuesJSON(file)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "//": "secret: Secret value - provide manually",
+  "name": "Uses ${literal}",
+  "secret": ""
+}
+`, string(got))
+
+	env, err := Var("pingone_environment_id")
+	require.NoError(t, err)
+	file.SetAttribute("environment_id", env)
+	_, err = VariableValuesJSON(file)
+	assert.ErrorContains(t, err, "attribute environment_id: value is not constant")
+}
@@ -0,0 +1,220 @@
+// Package model is the in-memory form of the Terraform configuration generated from DaVinci
+// API payloads. Converters build resources from payloads, rewrites such as variable extraction
+// change their values, and writers render them, so no step has to read or edit HCL text.
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Resource is a Terraform resource block
+type Resource struct {
+	// Type is the resource type, e.g. "pingone_davinci_flow"
+	Type string
+
+	// Name is the resource name, unique per type
+	Name string
+
+	// ID is the API ID of the exported object, if known
+	ID string
+
+	// ImportID is the ID of the import block for the resource; empty for no import block
+	ImportID string
+
+	// Body holds the attributes of the resource
+	Body Body
+
+	sensitive map[string]bool
+}
+
+// NewResource returns a resource without attributes
+func NewResource(resourceType, name string) *Resource {
+	return &Resource{Type: resourceType, Name: name}
+}
+
+// Address returns the address of the resource, e.g. pingone_davinci_flow.login
+func (r *Resource) Address() string {
+	return r.Type + "." + r.Name
+}
+
+// References returns the objects the resource's values refer to, sorted: resource addresses
+// such as pingone_davinci_flow.login and input variables such as var.pingone_environment_id
+func (r *Resource) References() []string {
+	seen := make(map[string]bool)
+	for _, attr := range r.Body.Attributes() {
+		walkValue(attr.Value, func(v Value) {
+			var refs []Reference
+			switch value := v.(type) {
+			case Reference:
+				refs = append(refs, value)
+			case Template:
+				for _, part := range value {
+					if part.Reference != nil {
+						refs = append(refs, Reference{Traversal: part.Reference})
+					}
+				}
+			}
+			for _, ref := range refs {
+				if address := ref.Object(); address != "" {
+					seen[address] = true
+				}
+			}
+		})
+	}
+
+	references := make([]string, 0, len(seen))
+	for address := range seen {
+		references = append(references, address)
+	}
+	sort.Strings(references)
+	return references
+}
+
+// MarkSensitive marks the value at path as sensitive, e.g. a secret connector property
+func (r *Resource) MarkSensitive(path Path) {
+	if r.sensitive == nil {
+		r.sensitive = make(map[string]bool)
+	}
+	r.sensitive[path.String()] = true
+}
+
+// Sensitive reports whether the value at path, or a value holding it, is marked sensitive
+func (r *Resource) Sensitive(path Path) bool {
+	for i := len(path); i > 0; i-- {
+		if r.sensitive[path[:i].String()] {
+			return true
+		}
+	}
+	return false
+}
+
+// SensitivePaths returns the paths marked sensitive, sorted
+func (r *Resource) SensitivePaths() []Path {
+	keys := make([]string, 0, len(r.sensitive))
+	for key := range r.sensitive {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	paths := make([]Path, 0, len(keys))
+	for _, key := range keys {
+		paths = append(paths, ParsePath(key))
+	}
+	return paths
+}
+
+// Get returns the value at path
+func (r *Resource) Get(path Path) (Value, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	attr := r.Body.Attribute(path[0])
+	if attr == nil {
+		return nil, false
+	}
+	value := attr.Value
+	for _, step := range path[1:] {
+		var ok bool
+		if value, ok = child(value, step); !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Set replaces the value at path. A single-element path sets an attribute, which is added if
+// missing; longer paths must exist.
+func (r *Resource) Set(path Path, value Value) error {
+	if len(path) == 0 {
+		return fmt.Errorf("empty path")
+	}
+	if len(path) == 1 {
+		r.Body.SetAttribute(path[0], value)
+		return nil
+	}
+	attr := r.Body.Attribute(path[0])
+	if attr == nil {
+		return fmt.Errorf("%s has no attribute %s", r.Address(), path[0])
+	}
+	parent := attr.Value
+	for i, step := range path[1 : len(path)-1] {
+		var ok bool
+		if parent, ok = child(parent, step); !ok {
+			return fmt.Errorf("%s has no value at %s", r.Address(), path[:i+2])
+		}
+	}
+	if !setChild(parent, path[len(path)-1], value) {
+		return fmt.Errorf("%s has no value at %s", r.Address(), path)
+	}
+	return nil
+}
+
+// child returns the value of an object key or tuple index in value, looking through
+// jsonencode() calls and comments
+func child(value Value, step string) (Value, bool) {
+	switch v := unwrap(value).(type) {
+	case *Object:
+		return v.Get(step)
+	case *Tuple:
+		i, err := strconv.Atoi(step)
+		if err != nil || i < 0 || i >= len(v.Elems) {
+			return nil, false
+		}
+		return v.Elems[i], true
+	}
+	return nil, false
+}
+
+// setChild replaces the value of an object key or tuple index in parent
+func setChild(parent Value, step string, value Value) bool {
+	switch v := unwrap(parent).(type) {
+	case *Object:
+		for i := range v.Attrs {
+			if v.Attrs[i].Key == step {
+				v.Attrs[i].Value = value
+				return true
+			}
+		}
+	case *Tuple:
+		i, err := strconv.Atoi(step)
+		if err != nil || i < 0 || i >= len(v.Elems) {
+			return false
+		}
+		v.Elems[i] = value
+		return true
+	}
+	return false
+}
+
+// unwrap returns the value inside comments and jsonencode() calls
+func unwrap(value Value) Value {
+	for {
+		switch v := value.(type) {
+		case Commented:
+			value = v.Value
+		case *Call:
+			if v.Name != "jsonencode" || len(v.Args) != 1 {
+				return value
+			}
+			value = v.Args[0]
+		default:
+			return value
+		}
+	}
+}
+
+// Path locates a value in a resource: an attribute name followed by object keys and tuple
+// indexes, e.g. properties.baseUrl.value
+type Path []string
+
+// ParsePath splits a dotted path
+func ParsePath(s string) Path {
+	return strings.Split(s, ".")
+}
+
+// String returns the dotted path
+func (p Path) String() string {
+	return strings.Join(p, ".")
+}
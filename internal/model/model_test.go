@@ -0,0 +1,117 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConnector returns a connector instance resource with properties in jsonencode()
+func testConnector(t *testing.T) *Resource {
+	t.Helper()
+	env, err := Var("pingone_environment_id")
+	require.NoError(t, err)
+
+	baseURL := &Object{}
+	baseURL.Set("type", String("string"))
+	baseURL.Set("value", String("https://example.com"))
+	properties := &Object{}
+	properties.SetKey("baseUrl", baseURL)
+
+	resource := NewResource("pingone_davinci_connector_instance", "http")
+	resource.Body.SetAttribute("environment_id", env)
+	resource.Body.SetAttribute("name", String("Http"))
+	resource.Body.SetAttribute("properties", JSONEncode(properties))
+	return resource
+}
+
+// TestResource_GetSet verifies values are found and replaced by path, through jsonencode()
+func TestResource_GetSet(t *testing.T) {
+	resource := testConnector(t)
+	path := Path{"properties", "baseUrl", "value"}
+
+	value, ok := resource.Get(path)
+	require.True(t, ok)
+	s, _ := AsString(value)
+	assert.Equal(t, "https://example.com", s)
+
+	ref, err := VarInterpolation("davinci_connection_http_base_url")
+	require.NoError(t, err)
+	require.NoError(t, resource.Set(path, ref))
+	value, ok = resource.Get(path)
+	require.True(t, ok)
+	assert.Equal(t, ref, value)
+
+	_, ok = resource.Get(Path{"properties", "missing"})
+	assert.False(t, ok)
+	assert.Error(t, resource.Set(Path{"properties", "missing", "value"}, String("x")))
+	assert.Error(t, resource.Set(Path{"missing", "value"}, String("x")))
+	assert.Error(t, resource.Set(Path{}, String("x")))
+
+	// A single-element path adds a missing attribute
+	require.NoError(t, resource.Set(Path{"description"}, String("added")))
+	assert.NotNil(t, resource.Body.Attribute("description"))
+}
+
+// TestResource_References verifies references in values and templates are collected
+func TestResource_References(t *testing.T) {
+	resource := testConnector(t)
+	flowID, err := ResourceAttribute("pingone_davinci_flow", "login", "id")
+	require.NoError(t, err)
+	template, err := ParseExpression(`"{{global.variables.${pingone_davinci_variable.color.name}}}"`)
+	require.NoError(t, err)
+	resource.Body.SetAttribute("flow", &Tuple{Elems: []Value{WithComment(flowID, "login flow")}})
+	resource.Body.SetAttribute("label", template)
+
+	assert.Equal(t, []string{
+		"pingone_davinci_flow.login",
+		"pingone_davinci_variable.color",
+		"var.pingone_environment_id",
+	}, resource.References())
+}
+
+// TestResource_Sensitive verifies a value is sensitive when it or a value holding it is marked
+func TestResource_Sensitive(t *testing.T) {
+	resource := testConnector(t)
+	resource.MarkSensitive(Path{"properties", "clientSecret"})
+
+	assert.True(t, resource.Sensitive(Path{"properties", "clientSecret"}))
+	assert.True(t, resource.Sensitive(Path{"properties", "clientSecret", "value"}))
+	assert.False(t, resource.Sensitive(Path{"properties", "baseUrl", "value"}))
+	assert.False(t, resource.Sensitive(Path{"properties"}))
+	assert.Equal(t, []Path{{"properties", "clientSecret"}}, resource.SensitivePaths())
+}
+
+// TestBody_SetAttributeReplacesComments verifies a comment about a missing attribute is
+// replaced by the attribute when it is set
+func TestBody_SetAttributeReplacesComments(t *testing.T) {
+	resource := NewResource("pingone_davinci_variable", "color")
+	resource.Body.SetAttribute("name", String("color"))
+	resource.Body.AppendCommentAbout("value", "TODO: Set value")
+	resource.Body.SetAttribute("mutable", Bool(true))
+
+	assert.True(t, resource.Body.HasCommentAbout("value"))
+	hcl, err := HCL(resource)
+	require.NoError(t, err)
+	assert.Contains(t, hcl, "# TODO: Set value")
+
+	resource.Body.SetAttribute("value", String("blue"))
+	assert.False(t, resource.Body.HasCommentAbout("value"))
+
+	var names []string
+	for _, attr := range resource.Body.Attributes() {
+		names = append(names, attr.Name)
+	}
+	assert.Equal(t, []string{"name", "value", "mutable"}, names)
+	hcl, err = HCL(resource)
+	require.NoError(t, err)
+	assert.NotContains(t, hcl, "TODO")
+}
+
+// TestParsePath verifies paths round-trip through their dotted form
+func TestParsePath(t *testing.T) {
+	path := ParsePath("properties.baseUrl.value")
+	assert.Equal(t, Path{"properties", "baseUrl", "value"}, path)
+	assert.Equal(t, "properties.baseUrl.value", path.String())
+}
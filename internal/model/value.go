@@ -0,0 +1,335 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Value is the value of an attribute: a Literal, a Reference, a Template, an *Object, a
+// *Tuple, a *Call, an Expression or a Commented value
+type Value interface {
+	isValue()
+}
+
+// Literal is a constant: a string, number, bool or null
+type Literal struct {
+	Value cty.Value
+}
+
+// Reference is a reference such as var.pingone_environment_id or pingone_davinci_flow.login.id
+type Reference struct {
+	Traversal hcl.Traversal
+}
+
+// Template is a quoted string template whose parts are literal strings and interpolated
+// references, e.g. "{{global.variables.${pingone_davinci_variable.color.name}}}"
+type Template []TemplatePart
+
+// TemplatePart is a literal string or, when Reference is set, an interpolated reference
+type TemplatePart struct {
+	Literal   string
+	Reference hcl.Traversal
+}
+
+// Object is an object constructor, keeping its attributes in the order they are set
+type Object struct {
+	Attrs []ObjectAttr
+}
+
+// ObjectAttr is an attribute of an object constructor. Quoted keys are written as strings,
+// others as identifiers.
+type ObjectAttr struct {
+	Key    string
+	Quoted bool
+	Value  Value
+}
+
+// Tuple is a tuple constructor
+type Tuple struct {
+	Elems []Value
+}
+
+// Call is a function call such as jsonencode({...})
+type Call struct {
+	Name string
+	Args []Value
+}
+
+// Expression is an expression the other values have no form for, kept as its native source,
+// e.g. the type constraint list(string) or the condition var.count >= 0
+type Expression struct {
+	Source string
+}
+
+// Commented is a value followed by a line comment, e.g. a TODO placeholder
+type Commented struct {
+	Value   Value
+	Comment string
+}
+
+func (Literal) isValue()    {}
+func (Reference) isValue()  {}
+func (Template) isValue()   {}
+func (*Object) isValue()    {}
+func (*Tuple) isValue()     {}
+func (*Call) isValue()      {}
+func (Expression) isValue() {}
+func (Commented) isValue()  {}
+
+// String returns a string literal
+func String(s string) Value {
+	return Literal{Value: cty.StringVal(s)}
+}
+
+// Bool returns a bool literal
+func Bool(b bool) Value {
+	return Literal{Value: cty.BoolVal(b)}
+}
+
+// Int returns an integer literal
+func Int(i int64) Value {
+	return Literal{Value: cty.NumberIntVal(i)}
+}
+
+// Number returns a number literal
+func Number(f float64) Value {
+	return Literal{Value: cty.NumberFloatVal(f)}
+}
+
+// Null returns the null literal
+func Null() Value {
+	return Literal{Value: cty.NullVal(cty.DynamicPseudoType)}
+}
+
+// StringList returns a tuple of string literals
+func StringList(items []string) *Tuple {
+	elems := make([]Value, 0, len(items))
+	for _, item := range items {
+		elems = append(elems, String(item))
+	}
+	return &Tuple{Elems: elems}
+}
+
+// JSONEncode wraps value in a jsonencode() call
+func JSONEncode(value Value) *Call {
+	return &Call{Name: "jsonencode", Args: []Value{value}}
+}
+
+// WithComment returns value followed by a line comment
+func WithComment(value Value, text string) Value {
+	return Commented{Value: value, Comment: text}
+}
+
+// ResourceAttribute returns a reference to an attribute of a resource, e.g.
+// pingone_davinci_flow.login.id
+func ResourceAttribute(resourceType, resourceName, attribute string) (Value, error) {
+	if !hclsyntax.ValidIdentifier(resourceName) {
+		return nil, fmt.Errorf("invalid resource name %q", resourceName)
+	}
+	return Reference{Traversal: hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: resourceName},
+		hcl.TraverseAttr{Name: attribute},
+	}}, nil
+}
+
+// Var returns a reference to the input variable name
+func Var(name string) (Value, error) {
+	traversal, err := varTraversal(name)
+	if err != nil {
+		return nil, err
+	}
+	return Reference{Traversal: traversal}, nil
+}
+
+// VarInterpolation returns a template interpolating the input variable name, "${var.name}",
+// e.g. for a value inside jsonencode()
+func VarInterpolation(name string) (Value, error) {
+	traversal, err := varTraversal(name)
+	if err != nil {
+		return nil, err
+	}
+	return Template{{Reference: traversal}}, nil
+}
+
+// varTraversal returns the traversal of var.name
+func varTraversal(name string) (hcl.Traversal, error) {
+	if !hclsyntax.ValidIdentifier(name) {
+		return nil, fmt.Errorf("invalid variable name %q", name)
+	}
+	return hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: name}}, nil
+}
+
+// ParseExpression returns the value of a Terraform expression built by the converters or the
+// resolver: a reference (e.g. var.pingone_environment_id or pingone_davinci_flow.login.id),
+// a literal, or a TODO placeholder such as `"" # TODO: ...` whose comment is kept. Anything
+// else, e.g. a function call or an interpolation of something other than a reference, is
+// rejected, so only references and escaped literals reach the output.
+func ParseExpression(expr string) (Value, error) {
+	expr, comment, _ := strings.Cut(expr, " # ")
+	parsed, diags := hclsyntax.ParseExpression([]byte(expr), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid expression %q: %s", expr, diags.Error())
+	}
+
+	var value Value
+	switch e := parsed.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		value = Reference{Traversal: e.Traversal}
+	case *hclsyntax.TemplateExpr:
+		template := make(Template, 0, len(e.Parts))
+		for _, part := range e.Parts {
+			switch p := part.(type) {
+			case *hclsyntax.LiteralValueExpr:
+				if p.Val.Type() != cty.String {
+					return nil, fmt.Errorf("unsupported template part in %q", expr)
+				}
+				template = append(template, TemplatePart{Literal: p.Val.AsString()})
+			case *hclsyntax.ScopeTraversalExpr:
+				template = append(template, TemplatePart{Reference: p.Traversal})
+			default:
+				return nil, fmt.Errorf("unsupported template part in %q", expr)
+			}
+		}
+		value = template.simplify()
+	case *hclsyntax.TemplateWrapExpr:
+		traversal, ok := e.Wrapped.(*hclsyntax.ScopeTraversalExpr)
+		if !ok {
+			return nil, fmt.Errorf("unsupported interpolation in %q", expr)
+		}
+		value = Template{{Reference: traversal.Traversal}}
+	default:
+		if len(parsed.Variables()) > 0 {
+			return nil, fmt.Errorf("unsupported expression %q", expr)
+		}
+		literal, diags := parsed.Value(nil)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("unsupported expression %q: %s", expr, diags.Error())
+		}
+		value = Literal{Value: literal}
+	}
+
+	if comment != "" {
+		value = WithComment(value, comment)
+	}
+	return value, nil
+}
+
+// NativeExpression returns an Expression for a single expression in native syntax, such as a
+// variable type or validation condition. Comments are rejected, as they would end the
+// interpolation the expression is written in by the JSON writer.
+func NativeExpression(src string) (Value, error) {
+	if _, diags := hclsyntax.ParseExpression([]byte(src), "", hcl.InitialPos); diags.HasErrors() {
+		return nil, fmt.Errorf("invalid expression %q: %s", src, diags.Error())
+	}
+	tokens, _ := hclsyntax.LexExpression([]byte(src), "", hcl.InitialPos)
+	for _, token := range tokens {
+		if token.Type == hclsyntax.TokenComment {
+			return nil, fmt.Errorf("invalid expression %q: comments are not supported", src)
+		}
+	}
+	return Expression{Source: strings.TrimSpace(src)}, nil
+}
+
+// AsString returns the string of a string literal
+func AsString(value Value) (string, bool) {
+	literal, ok := value.(Literal)
+	if !ok || !literal.Value.IsKnown() || literal.Value.IsNull() || literal.Value.Type() != cty.String {
+		return "", false
+	}
+	return literal.Value.AsString(), true
+}
+
+// simplify returns a template without references as a string literal
+func (t Template) simplify() Value {
+	var sb strings.Builder
+	for _, part := range t {
+		if part.Reference != nil {
+			return t
+		}
+		sb.WriteString(part.Literal)
+	}
+	return String(sb.String())
+}
+
+// Object returns the address of the object the reference refers to: var.<name> for an input
+// variable or <type>.<name> for a resource. Other references, e.g. to locals, have none.
+func (r Reference) Object() string {
+	if len(r.Traversal) < 2 {
+		return ""
+	}
+	root, ok := r.Traversal[0].(hcl.TraverseRoot)
+	if !ok {
+		return ""
+	}
+	attr, ok := r.Traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return ""
+	}
+	switch root.Name {
+	case "local", "data", "module", "path", "count", "each", "self", "terraform":
+		return ""
+	}
+	return root.Name + "." + attr.Name
+}
+
+// Set sets the attribute name, which must be an identifier
+func (o *Object) Set(name string, value Value) {
+	o.Attrs = append(o.Attrs, ObjectAttr{Key: name, Value: value})
+}
+
+// SetKey sets an attribute whose key is written as a quoted string
+func (o *Object) SetKey(key string, value Value) {
+	o.Attrs = append(o.Attrs, ObjectAttr{Key: key, Quoted: true, Value: value})
+}
+
+// Get returns the value of the attribute key
+func (o *Object) Get(key string) (Value, bool) {
+	for _, attr := range o.Attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Len returns the number of attributes
+func (o *Object) Len() int {
+	return len(o.Attrs)
+}
+
+// walkValue calls fn for value and every value nested in it
+func walkValue(value Value, fn func(Value)) {
+	fn(value)
+	switch v := value.(type) {
+	case *Object:
+		for _, attr := range v.Attrs {
+			walkValue(attr.Value, fn)
+		}
+	case *Tuple:
+		for _, elem := range v.Elems {
+			walkValue(elem, fn)
+		}
+	case *Call:
+		for _, arg := range v.Args {
+			walkValue(arg, fn)
+		}
+	case Commented:
+		walkValue(v.Value, fn)
+	}
+}
+
+// integer returns a number literal as an int64 when it is an integer in range
+func integer(value cty.Value) (int64, bool) {
+	bf := value.AsBigFloat()
+	if !bf.IsInt() {
+		return 0, false
+	}
+	i, accuracy := bf.Int64()
+	return i, accuracy == big.Exact
+}
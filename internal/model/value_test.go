@@ -0,0 +1,109 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseExpression_RejectsNonReferences verifies only references, literals and TODO
+// placeholders are parsed
+func TestParseExpression_RejectsNonReferences(t *testing.T) {
+	valid := []string{
+		"var.pingone_environment_id",
+		"pingone_davinci_flow.login.id",
+		`"" # TODO: Reference to pingone_davinci_flow flow-1 not found`,
+		`"${pingone_davinci_variable.color_company.name}"`,
+	}
+	for _, expr := range valid {
+		_, err := ParseExpression(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	invalid := []string{
+		`file("/etc/passwd")`,
+		`"${file("/etc/passwd")}"`,
+		`"%{ for x in var.list }${x}%{ endfor }"`,
+		`var.a + 1`,
+		`pingone_davinci_flow.login.id"`,
+	}
+	for _, expr := range invalid {
+		_, err := ParseExpression(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+// TestParseExpression_Values verifies the kind of value each expression is parsed to
+func TestParseExpression_Values(t *testing.T) {
+	value, err := ParseExpression("var.pingone_environment_id")
+	require.NoError(t, err)
+	ref, ok := value.(Reference)
+	require.True(t, ok)
+	assert.Equal(t, "var.pingone_environment_id", ref.Object())
+
+	value, err = ParseExpression(`"plain"`)
+	require.NoError(t, err)
+	s, ok := AsString(value)
+	require.True(t, ok)
+	assert.Equal(t, "plain", s)
+
+	value, err = ParseExpression(`"{{global.variables.${pingone_davinci_variable.color.name}}}"`)
+	require.NoError(t, err)
+	template, ok := value.(Template)
+	require.True(t, ok)
+	require.Len(t, template, 3)
+	assert.Equal(t, "{{global.variables.", template[0].Literal)
+	assert.NotNil(t, template[1].Reference)
+	assert.Equal(t, "}}", template[2].Literal)
+
+	value, err = ParseExpression(`"" # TODO: Replace with flow reference`)
+	require.NoError(t, err)
+	commented, ok := value.(Commented)
+	require.True(t, ok)
+	assert.Equal(t, "TODO: Replace with flow reference", commented.Comment)
+	s, ok = AsString(commented.Value)
+	require.True(t, ok)
+	assert.Empty(t, s)
+}
+
+// TestReference_Object verifies the address of the object a reference refers to
+func TestReference_Object(t *testing.T) {
+	tests := map[string]string{
+		"var.pingone_environment_id":    "var.pingone_environment_id",
+		"pingone_davinci_flow.login.id": "pingone_davinci_flow.login",
+		"local.settings":                "",
+		"data.pingone_environment.x.id": "",
+	}
+	for expr, want := range tests {
+		value, err := ParseExpression(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, value.(Reference).Object(), expr)
+	}
+}
+
+// TestVar_RejectsInvalidNames verifies variable references are only built from identifiers
+func TestVar_RejectsInvalidNames(t *testing.T) {
+	_, err := Var("davinci_flow_main_name")
+	assert.NoError(t, err)
+
+	_, err = Var("a.b")
+	assert.Error(t, err)
+	_, err = VarInterpolation("${x}")
+	assert.Error(t, err)
+	_, err = ResourceAttribute("pingone_davinci_flow", "bad name", "id")
+	assert.Error(t, err)
+}
+
+// TestNativeExpression verifies single expressions are kept and anything that could end the
+// expression is rejected
+func TestNativeExpression(t *testing.T) {
+	value, err := NativeExpression(" list(string) ")
+	require.NoError(t, err)
+	assert.Equal(t, Expression{Source: "list(string)"}, value)
+
+	for _, src := range []string{"", "var.a !=", "var.a\nb = 1", `var.a # comment`, "var.a /* comment */ == 1"} {
+		_, err := NativeExpression(src)
+		assert.Error(t, err, src)
+	}
+}
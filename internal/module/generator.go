@@ -4,17 +4,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/zclconf/go-cty/cty"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
+// ProviderVersion is the pingone provider version required by generated configuration
+const ProviderVersion = "1.16.0-beta"
+
 // Generator handles the generation of Terraform module structure
 type Generator struct {
 	config ModuleConfig
+
+	generated map[string]map[string]string // File path relative to OutputDir -> block key -> content hash
+	update    *updateState                 // Set while updating an existing module
 }
 
 // NewGenerator creates a new module generator with the given configuration
@@ -26,14 +35,25 @@ func NewGenerator(config ModuleConfig) *Generator {
 	if config.ModuleDirName == "" {
 		config.ModuleDirName = "ping-export-module"
 	}
+	if config.Format == "" {
+		config.Format = FormatHCL
+	}
 
 	return &Generator{
-		config: config,
+		config:    config,
+		generated: make(map[string]map[string]string),
 	}
 }
 
 // Generate creates the complete module structure
 func (g *Generator) Generate(structure *ModuleStructure) error {
+	// Read the previous export before it is overwritten to detect renamed resources
+	previous, err := g.previousManifest()
+	if err != nil {
+		return err
+	}
+	renames := g.resourceRenames(previous, structure)
+
 	// Create directory structure
 	if err := g.createDirectories(); err != nil {
 		return fmt.Errorf("failed to create directories: %w", err)
@@ -65,6 +85,12 @@ func (g *Generator) Generate(structure *ModuleStructure) error {
 		return fmt.Errorf("failed to generate module.tf: %w", err)
 	}
 
+	if g.config.ProviderRegionCode != "" {
+		if err := g.generateProviderTF(); err != nil {
+			return fmt.Errorf("failed to generate provider.tf: %w", err)
+		}
+	}
+
 	if g.config.IncludeImports {
 		if err := g.generateImportsTF(structure.ImportBlocks); err != nil {
 			return fmt.Errorf("failed to generate imports.tf: %w", err)
@@ -76,6 +102,18 @@ func (g *Generator) Generate(structure *ModuleStructure) error {
 		return fmt.Errorf("failed to generate tfvars: %w", err)
 	}
 
+	// Moved blocks let existing state follow renamed resources
+	if len(renames) > 0 {
+		if err := g.generateMovedTF(renames, structure); err != nil {
+			return fmt.Errorf("failed to generate moved.tf: %w", err)
+		}
+	}
+
+	// Record what was generated for later updates
+	if err := g.writeManifest(structure); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
 	return nil
 }
 
@@ -90,135 +128,236 @@ func (g *Generator) childModulePath() string {
 	return filepath.Join(g.config.OutputDir, g.config.ModuleDirName)
 }
 
-// writeFile writes content to a file in the specified directory
-func (g *Generator) writeFile(dir, filename, content string) error {
-	filePath := filepath.Join(dir, filename)
-	return os.WriteFile(filePath, []byte(content), 0644)
+// writeModelFile writes a file built from the model to the specified directory, recording the
+// hash of each block for the manifest. When updating, the content is merged into the existing
+// file.
+func (g *Generator) writeModelFile(dir, filename string, file *model.Body) error {
+	content, jsonData, err := g.renderModelFile(filename, file)
+	if err != nil {
+		return err
+	}
+	return g.writeGeneratedFile(dir, filename, content, jsonData, g.update != nil)
+}
+
+// renderModelFile returns a file built from the model in native syntax and, in JSON format,
+// as written by the model's JSON writer
+func (g *Generator) renderModelFile(filename string, file *model.Body) (string, []byte, error) {
+	content, err := model.FileHCL(file)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	if !g.jsonFormat() {
+		return content, nil, nil
+	}
+
+	var jsonData []byte
+	if strings.HasSuffix(filename, ".tfvars") {
+		jsonData, err = model.VariableValuesJSON(file)
+	} else {
+		jsonData, err = model.FileJSON(file)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write %s: %w", g.fileName(filename), err)
+	}
+	return content, jsonData, nil
+}
+
+// jsonFormat reports whether files are written in Terraform JSON syntax
+func (g *Generator) jsonFormat() bool {
+	return g.config.Format == FormatJSON
+}
+
+// fileName returns the name a generated file is written under in the configured format
+func (g *Generator) fileName(filename string) string {
+	if g.jsonFormat() {
+		return filename + ".json"
+	}
+	return filename
 }
 
+// writeGeneratedFile writes native syntax content to a file, merging it into the existing file
+// when merge is set. In JSON format jsonData, the same configuration in JSON syntax, is written
+// to <filename>.json instead.
+func (g *Generator) writeGeneratedFile(dir, filename, content string, jsonData []byte, merge bool) error {
+	filePath := filepath.Join(dir, g.fileName(filename))
+	rel, err := filepath.Rel(g.config.OutputDir, filePath)
+	if err != nil {
+		return err
+	}
+	rel = filepath.ToSlash(rel)
+	data := []byte(content)
+	if g.jsonFormat() {
+		data = jsonData
+	}
+
+	items, err := parseItems(filename, []byte(content))
+	if err != nil {
+		if merge {
+			return fmt.Errorf("cannot update %s: %w", rel, err)
+		}
+		// Written as before; the file is not recorded, so an update treats its blocks as new
+		return os.WriteFile(filePath, data, 0644)
+	}
+	hashes := make(map[string]string, len(items))
+	for _, item := range items {
+		hashes[item.key] = hashText([]byte(content[item.start:item.end]))
+	}
+	g.generated[rel] = hashes
+
+	if merge {
+		return g.mergeFile(filePath, rel, []byte(content), items)
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// environmentIDPattern matches a PingOne resource ID
+const environmentIDPattern = "^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"
+
+// variableOrder is the order the files list the variables of each resource type in
+var variableOrder = []string{"flow", "variable", "connection", "application", "flow_policy"}
+
 // generateVersionsTF creates the versions.tf file in the child module
 func (g *Generator) generateVersionsTF() error {
-	content := `terraform {
-  required_version = ">= 1.5"
-
-  required_providers {
-    pingone = {
-      source  = "pingidentity/pingone"
-      version = "1.16.0-beta"
-    }
-  }
+	file := &model.Body{}
+	appendTerraformBlock(file)
+	return g.writeModelFile(g.childModulePath(), "versions.tf", file)
+}
+
+// appendTerraformBlock appends the terraform block requiring the pingone provider
+func appendTerraformBlock(file *model.Body) {
+	terraform := file.AppendBlock("terraform")
+	terraform.Body.SetAttribute("required_version", model.String(">= 1.5"))
+	terraform.Body.AppendNewline()
+
+	pingone := &model.Object{}
+	pingone.Set("source", model.String("pingidentity/pingone"))
+	pingone.Set("version", model.String(ProviderVersion))
+	providers := terraform.Body.AppendBlock("required_providers")
+	providers.Body.SetAttribute("pingone", pingone)
+}
+
+// generateProviderTF creates the root provider file configuring the pingone provider region
+func (g *Generator) generateProviderTF() error {
+	file := &model.Body{}
+	appendTerraformBlock(file)
+	file.AppendNewline()
+
+	provider := file.AppendBlock("provider", "pingone")
+	provider.Body.SetAttribute("region_code", model.String(g.config.ProviderRegionCode))
+	provider.Body.AppendComment("Configure authentication via environment variables:")
+	provider.Body.AppendComment("PINGONE_CLIENT_ID")
+	provider.Body.AppendComment("PINGONE_CLIENT_SECRET")
+	provider.Body.AppendComment("PINGONE_ENVIRONMENT_ID (for OAuth client)")
+
+	return g.writeModelFile(g.config.OutputDir, fmt.Sprintf("%s-provider.tf", g.config.ModuleName), file)
 }
-`
-	return g.writeFile(g.childModulePath(), "versions.tf", content)
+
+// appendEnvironmentIDVariable appends the pingone_environment_id variable, which every
+// resource of the child module uses
+func appendEnvironmentIDVariable(file *model.Body) {
+	environmentID, _ := model.Var("pingone_environment_id") // A valid name
+	regex := &model.Call{Name: "regex", Args: []model.Value{model.String(environmentIDPattern), environmentID}}
+
+	variable := file.AppendBlock("variable", "pingone_environment_id")
+	variable.Body.SetAttribute("type", model.Expression{Source: "string"})
+	variable.Body.SetAttribute("description", model.String("The PingOne environment ID to configure DaVinci resources in"))
+	variable.Body.AppendNewline()
+	validation := variable.Body.AppendBlock("validation")
+	validation.Body.SetAttribute("condition", &model.Call{Name: "can", Args: []model.Value{regex}})
+	validation.Body.SetAttribute("error_message", model.String("The PingOne Environment ID must be a valid PingOne resource ID (UUID format)."))
 }
 
 // generateVariablesTF creates the variables.tf file in the child module
 func (g *Generator) generateVariablesTF(variables []Variable) error {
-	var sb strings.Builder
+	file := &model.Body{}
 
 	// Always include the core environment_id variable that child module resources use
-	sb.WriteString(`variable "pingone_environment_id" {
-  type        = string
-  description = "The PingOne environment ID to configure DaVinci resources in"
-
-  validation {
-    condition     = can(regex("^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$", var.pingone_environment_id))
-    error_message = "The PingOne Environment ID must be a valid PingOne resource ID (UUID format)."
-  }
-}
-
-`)
+	appendEnvironmentIDVariable(file)
 
 	// Group variables by resource type for better organization
 	groupedVars := g.groupVariablesByResourceType(variables)
 
 	// Generate variables in a logical order
-	order := []string{"flow", "variable", "connection", "application", "flow_policy"}
-	for _, resourceType := range order {
+	for _, resourceType := range variableOrder {
 		vars, exists := groupedVars[resourceType]
 		if !exists {
 			continue
 		}
 
 		// Section header
-		hdr := cases.Title(language.English).String(resourceType)
-		sb.WriteString(fmt.Sprintf("# %s Variables\n\n", hdr))
+		file.AppendNewline()
+		file.AppendComment(fmt.Sprintf("%s Variables", cases.Title(language.English).String(resourceType)))
 
 		// Sort variables alphabetically by name for deterministic output
 		sort.Slice(vars, func(i, j int) bool {
 			return strings.ToLower(vars[i].Name) < strings.ToLower(vars[j].Name)
 		})
 		for _, v := range vars {
-			sb.WriteString(g.generateVariableBlock(v))
+			file.AppendNewline()
+			if err := g.generateVariableBlock(file, v); err != nil {
+				return err
+			}
 		}
 	}
 
-	return g.writeFile(g.childModulePath(), "variables.tf", sb.String())
+	return g.writeModelFile(g.childModulePath(), "variables.tf", file)
 }
 
 // generateRootVariablesTF creates the variables.tf file in the root module
 // This mirrors the child module variables for use in module invocation
 func (g *Generator) generateRootVariablesTF(variables []Variable) error {
-	var sb strings.Builder
+	file := &model.Body{}
 
 	// Always include the core environment_id variable
-	sb.WriteString(`variable "pingone_environment_id" {
-  type        = string
-  description = "The PingOne environment ID to configure DaVinci resources in"
-
-  validation {
-    condition     = can(regex("^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$", var.pingone_environment_id))
-    error_message = "The PingOne Environment ID must be a valid PingOne resource ID (UUID format)."
-  }
-}
-
-`)
+	appendEnvironmentIDVariable(file)
 
 	// Group variables by resource type for better organization
 	groupedVars := g.groupVariablesByResourceType(variables)
 
 	// Generate variables in a logical order
-	order := []string{"flow", "variable", "connection", "application", "flow_policy"}
-	for _, resourceType := range order {
+	for _, resourceType := range variableOrder {
 		vars, exists := groupedVars[resourceType]
 		if !exists {
 			continue
 		}
 
 		// Section header
-		hdr := cases.Title(language.English).String(resourceType)
-		sb.WriteString(fmt.Sprintf("# %s Variables\n\n", hdr))
+		file.AppendNewline()
+		file.AppendComment(fmt.Sprintf("%s Variables", cases.Title(language.English).String(resourceType)))
 
 		// Sort variables alphabetically by name for deterministic output
 		sort.Slice(vars, func(i, j int) bool {
 			return strings.ToLower(vars[i].Name) < strings.ToLower(vars[j].Name)
 		})
 		for _, v := range vars {
-			sb.WriteString(g.generateRootVariableBlock(v))
-			sb.WriteString("\n")
+			file.AppendNewline()
+			if err := g.generateRootVariableBlock(file, v); err != nil {
+				return err
+			}
 		}
 	}
 
 	// Root variables file is prefixed by module name
-	return g.writeFile(g.config.OutputDir, fmt.Sprintf("%s-variables.tf", g.config.ModuleName), sb.String())
+	return g.writeModelFile(g.config.OutputDir, fmt.Sprintf("%s-variables.tf", g.config.ModuleName), file)
 }
 
-// generateRootVariableBlock generates a single variable block for the root module
+// generateRootVariableBlock appends a single variable block for the root module
 // Root variables do not have default values - those come from tfvars
-func (g *Generator) generateRootVariableBlock(v Variable) string {
-	var sb strings.Builder
+func (g *Generator) generateRootVariableBlock(file *model.Body, v Variable) error {
+	variableType, err := model.NativeExpression(v.Type)
+	if err != nil {
+		return fmt.Errorf("variable %s: %w", v.Name, err)
+	}
 
-	sb.WriteString(fmt.Sprintf("variable \"%s\" {\n", v.Name))
-	sb.WriteString(fmt.Sprintf("  type        = %s\n", v.Type))
-	sb.WriteString(fmt.Sprintf("  description = %q\n", v.Description))
+	variable := file.AppendBlock("variable", v.Name)
+	variable.Body.SetAttribute("type", variableType)
+	variable.Body.SetAttribute("description", model.String(v.Description))
 
 	if v.Sensitive {
-		sb.WriteString("  sensitive   = true\n")
+		variable.Body.SetAttribute("sensitive", model.Bool(true))
 	}
 
-	sb.WriteString("}\n")
-
-	return sb.String()
+	return nil
 }
 
 // groupVariablesByResourceType groups variables by their resource type
@@ -230,108 +369,142 @@ func (g *Generator) groupVariablesByResourceType(variables []Variable) map[strin
 	return grouped
 }
 
-// generateVariableBlock generates a single variable block
-func (g *Generator) generateVariableBlock(v Variable) string {
-	var sb strings.Builder
+// generateVariableBlock appends a single variable block for the child module
+func (g *Generator) generateVariableBlock(file *model.Body, v Variable) error {
+	variableType, err := model.NativeExpression(v.Type)
+	if err != nil {
+		return fmt.Errorf("variable %s: %w", v.Name, err)
+	}
 
-	sb.WriteString(fmt.Sprintf("variable \"%s\" {\n", v.Name))
-	sb.WriteString(fmt.Sprintf("  type        = %s\n", v.Type))
-	sb.WriteString(fmt.Sprintf("  description = %q\n", v.Description))
+	variable := file.AppendBlock("variable", v.Name)
+	variable.Body.SetAttribute("type", variableType)
+	variable.Body.SetAttribute("description", model.String(v.Description))
 
 	// Do not include default values in child module variables.tf to avoid leaking secrets.
 	// Actual values must be provided via ping-export-terraform.auto.tfvars.
 
 	if v.Sensitive {
-		sb.WriteString("  sensitive   = true\n")
+		variable.Body.SetAttribute("sensitive", model.Bool(true))
 	}
 
 	if v.Validation != nil {
-		sb.WriteString("\n  validation {\n")
-		sb.WriteString(fmt.Sprintf("    condition     = %s\n", v.Validation.Condition))
-		sb.WriteString(fmt.Sprintf("    error_message = %q\n", v.Validation.ErrorMessage))
-		sb.WriteString("  }\n")
+		condition, err := model.NativeExpression(v.Validation.Condition)
+		if err != nil {
+			return fmt.Errorf("variable %s: validation: %w", v.Name, err)
+		}
+		variable.Body.AppendNewline()
+		validation := variable.Body.AppendBlock("validation")
+		validation.Body.SetAttribute("condition", condition)
+		validation.Body.SetAttribute("error_message", model.String(v.Validation.ErrorMessage))
 	}
 
-	sb.WriteString("}\n")
-
-	return sb.String()
+	return nil
 }
 
-// formatDefaultValue formats a default value based on its type
-func (g *Generator) formatDefaultValue(value interface{}, varType string) string {
+// formatDefaultValue returns a default value as a literal of the variable type. The model's
+// writers escape strings, so quotes, control characters and the "${" and "%{" template
+// sequences are written the way Terraform reads them.
+func (g *Generator) formatDefaultValue(value interface{}, varType string) model.Value {
 	if value == nil {
-		return "null"
+		return model.Null()
 	}
 
 	switch varType {
-	case "string":
-		return fmt.Sprintf("%q", value)
 	case "number":
-		return fmt.Sprintf("%v", value)
+		switch n := value.(type) {
+		case int:
+			return model.Int(int64(n))
+		case int64:
+			return model.Int(n)
+		case float64:
+			return model.Number(n)
+		}
+		if number, err := cty.ParseNumberVal(fmt.Sprint(value)); err == nil {
+			return model.Literal{Value: number}
+		}
 	case "bool":
-		return fmt.Sprintf("%v", value)
-	default:
-		return fmt.Sprintf("%q", value)
+		if b, err := strconv.ParseBool(fmt.Sprint(value)); err == nil {
+			return model.Bool(b)
+		}
+	case "list(string)":
+		items, _ := value.([]string)
+		return model.StringList(items)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
 	}
+	return model.String(s)
 }
 
 // generateOutputsTF creates the outputs.tf file in the child module
 func (g *Generator) generateOutputsTF(outputs []Output) error {
-	var sb strings.Builder
+	file := &model.Body{}
 
-	for _, o := range outputs {
-		sb.WriteString(fmt.Sprintf("output \"%s\" {\n", o.Name))
-		sb.WriteString(fmt.Sprintf("  description = %q\n", o.Description))
-		sb.WriteString(fmt.Sprintf("  value       = %s\n", o.Value))
+	for i, o := range outputs {
+		value, err := model.NativeExpression(o.Value)
+		if err != nil {
+			return fmt.Errorf("output %s: %w", o.Name, err)
+		}
 
-		if o.Sensitive {
-			sb.WriteString("  sensitive   = true\n")
+		if i > 0 {
+			file.AppendNewline()
 		}
+		output := file.AppendBlock("output", o.Name)
+		output.Body.SetAttribute("description", model.String(o.Description))
+		output.Body.SetAttribute("value", value)
 
-		sb.WriteString("}\n\n")
+		if o.Sensitive {
+			output.Body.SetAttribute("sensitive", model.Bool(true))
+		}
 	}
 
-	return g.writeFile(g.childModulePath(), "outputs.tf", sb.String())
+	return g.writeModelFile(g.childModulePath(), "outputs.tf", file)
 }
 
-// generateResourceFiles creates the resource files in the child module
-func (g *Generator) generateResourceFiles(resources ModuleResources) error {
-	// Generate pingone_davinci_flow.tf
-	if resources.FlowsHCL != "" {
-		sorted := utils.SortAllResourceBlocks(resources.FlowsHCL)
-		if err := g.writeFile(g.childModulePath(), "pingone_davinci_flow.tf", sorted); err != nil {
-			return err
-		}
+// resourceFileName returns the child module file a resource type is written to: a flow's
+// enable and deploy resources go with the flows, other types get a file of their own
+func resourceFileName(resourceType string) string {
+	switch resourceType {
+	case "pingone_davinci_flow_enable", "pingone_davinci_flow_deploy":
+		return "pingone_davinci_flow.tf"
 	}
+	return resourceType + ".tf"
+}
 
-	// Generate pingone_davinci_connector_instance.tf
-	if resources.ConnectionsHCL != "" {
-		sorted := utils.SortAllResourceBlocks(resources.ConnectionsHCL)
-		if err := g.writeFile(g.childModulePath(), "pingone_davinci_connector_instance.tf", sorted); err != nil {
-			return err
+// generateResourceFiles creates the resource files in the child module, with resources sorted
+// by name within each file
+func (g *Generator) generateResourceFiles(resources []*model.Resource) error {
+	var files []string
+	byFile := make(map[string][]*model.Resource)
+	for _, resource := range resources {
+		file := resourceFileName(resource.Type)
+		if _, ok := byFile[file]; !ok {
+			files = append(files, file)
 		}
+		byFile[file] = append(byFile[file], resource)
 	}
 
-	// Generate pingone_davinci_variable.tf
-	if resources.VariablesHCL != "" {
-		sorted := utils.SortAllResourceBlocks(resources.VariablesHCL)
-		if err := g.writeFile(g.childModulePath(), "pingone_davinci_variable.tf", sorted); err != nil {
-			return err
-		}
-	}
+	for _, file := range files {
+		// A stable sort keeps each flow's resources in the order they were converted
+		sorted := slices.Clone(byFile[file])
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		})
 
-	// Generate pingone_davinci_application.tf
-	if resources.ApplicationsHCL != "" {
-		sorted := utils.SortAllResourceBlocks(resources.ApplicationsHCL)
-		if err := g.writeFile(g.childModulePath(), "pingone_davinci_application.tf", sorted); err != nil {
-			return err
+		content, err := model.HCL(sorted...)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
 		}
-	}
-
-	// Generate pingone_davinci_application_flow_policy.tf (correct filename)
-	if resources.FlowPoliciesHCL != "" {
-		sorted := utils.SortAllResourceBlocks(resources.FlowPoliciesHCL)
-		if err := g.writeFile(g.childModulePath(), "pingone_davinci_application_flow_policy.tf", sorted); err != nil {
+		// JSON is written from the resources rather than converted, so their comments are kept
+		var jsonData []byte
+		if g.jsonFormat() {
+			if jsonData, err = model.JSON(sorted...); err != nil {
+				return fmt.Errorf("failed to write %s: %w", g.fileName(file), err)
+			}
+		}
+		if err := g.writeGeneratedFile(g.childModulePath(), file, content, jsonData, g.update != nil); err != nil {
 			return err
 		}
 	}
@@ -341,142 +514,145 @@ func (g *Generator) generateResourceFiles(resources ModuleResources) error {
 
 // generateModuleTF creates the module.tf file in the root module
 func (g *Generator) generateModuleTF(structure *ModuleStructure) error {
-	var sb strings.Builder
+	file := &model.Body{}
 
-	sb.WriteString(fmt.Sprintf("module \"%s\" {\n", g.config.ModuleName))
-	sb.WriteString(fmt.Sprintf("  source = \"./%s\"\n\n", g.config.ModuleDirName))
+	module := file.AppendBlock("module", g.config.ModuleName)
+	module.Body.SetAttribute("source", model.String("./"+g.config.ModuleDirName))
+	module.Body.AppendNewline()
 
 	// Core environment ID - always use variable reference
-	sb.WriteString("  pingone_environment_id = var.pingone_environment_id\n\n")
+	environmentID, _ := model.Var("pingone_environment_id") // A valid name
+	module.Body.SetAttribute("pingone_environment_id", environmentID)
 
 	// Group variables by resource type
 	groupedVars := g.groupVariablesByResourceType(structure.Variables)
 
 	// Generate variable inputs
-	order := []string{"flow", "variable", "connection", "application", "flow_policy"}
-	for _, resourceType := range order {
+	for _, resourceType := range variableOrder {
 		vars, exists := groupedVars[resourceType]
 		if !exists {
 			continue
 		}
 
-		hdr := cases.Title(language.English).String(resourceType)
-		sb.WriteString(fmt.Sprintf("  # %s Variables\n", hdr))
+		module.Body.AppendNewline()
+		module.Body.AppendComment(fmt.Sprintf("%s Variables", cases.Title(language.English).String(resourceType)))
 
 		for _, v := range vars {
-			sb.WriteString(g.generateModuleInput(v))
+			if err := g.generateModuleInput(&module.Body, v); err != nil {
+				return err
+			}
 		}
-
-		sb.WriteString("\n")
 	}
 
-	sb.WriteString("}\n")
-
 	// Root file name is prefixed by module name
-	return g.writeFile(g.config.OutputDir, fmt.Sprintf("%s-module.tf", g.config.ModuleName), sb.String())
+	return g.writeModelFile(g.config.OutputDir, fmt.Sprintf("%s-module.tf", g.config.ModuleName), file)
 }
 
-// generateModuleInput generates a single module input line
+// generateModuleInput sets a single module input
 // Always uses variable references (var.{name}) - values come from tfvars
-func (g *Generator) generateModuleInput(v Variable) string {
-	return fmt.Sprintf("  %s = var.%s\n", v.Name, v.Name)
+func (g *Generator) generateModuleInput(body *model.Body, v Variable) error {
+	value, err := model.Var(v.Name)
+	if err != nil {
+		return err
+	}
+	body.SetAttribute(v.Name, value)
+	return nil
 }
 
 // generateImportsTF creates the imports.tf file in the root module
 func (g *Generator) generateImportsTF(importBlocks []ImportBlock) error {
-	var comments strings.Builder
-	var blocks strings.Builder
+	file := &model.Body{}
 
 	// First, emit all commented terraform import commands together
 	for _, ib := range importBlocks {
-		comments.WriteString(fmt.Sprintf("# terraform import %s %q\n", ib.To, ib.ID))
+		file.AppendComment(fmt.Sprintf("terraform import %s %s", ib.To, strconv.Quote(ib.ID)))
 	}
 
-	// Then emit actual import blocks
+	// Then emit actual import blocks, after a blank line
 	for _, ib := range importBlocks {
-		blocks.WriteString("import {\n")
-		blocks.WriteString(fmt.Sprintf("  to = %s\n", ib.To))
-		blocks.WriteString(fmt.Sprintf("  id = %q\n", ib.ID))
-		blocks.WriteString("}\n\n")
-	}
+		to, err := model.ParseExpression(ib.To)
+		if _, ok := to.(model.Reference); err != nil || !ok {
+			return fmt.Errorf("invalid import address %q", ib.To)
+		}
 
-	// Combine: comments at top, a blank line, then blocks
-	final := comments.String()
-	if blocks.Len() > 0 {
-		final += "\n" + blocks.String()
+		file.AppendNewline()
+		block := file.AppendBlock("import")
+		block.Body.SetAttribute("to", to)
+		block.Body.SetAttribute("id", model.String(ib.ID))
 	}
 
 	// Root file name is prefixed by module name
-	return g.writeFile(g.config.OutputDir, fmt.Sprintf("%s-imports.tf", g.config.ModuleName), final)
+	return g.writeModelFile(g.config.OutputDir, fmt.Sprintf("%s-imports.tf", g.config.ModuleName), file)
 }
 
 // generateTFVarsFile creates the ping-export-terraform.auto.tfvars file
 // When IncludeValues is false, creates a template with empty values
 // When IncludeValues is true, populates with actual values from variables
 func (g *Generator) generateTFVarsFile(structure *ModuleStructure) error {
-	var sb strings.Builder
+	file := &model.Body{}
 
 	// Add file header comment
-	sb.WriteString("# Terraform variable values for DaVinci export\n")
-	sb.WriteString("# Generated by pingcli tf export\n\n")
+	file.AppendComment("Terraform variable values for DaVinci export")
+	file.AppendComment("Generated by pingcli tf export")
+	file.AppendNewline()
 
 	// Environment ID
 	if g.config.IncludeValues {
-		sb.WriteString(fmt.Sprintf("pingone_environment_id = %q\n\n", g.config.EnvironmentID))
+		file.SetAttribute("pingone_environment_id", model.String(g.config.EnvironmentID))
 	} else {
-		sb.WriteString(`pingone_environment_id = ""  # TODO: Provide PingOne environment ID` + "\n\n")
+		file.SetAttribute("pingone_environment_id", model.WithComment(model.String(""), "TODO: Provide PingOne environment ID"))
 	}
 
 	// Group variables by resource type
 	groupedVars := g.groupVariablesByResourceType(structure.Variables)
 
 	// Generate variable values
-	order := []string{"flow", "variable", "connection", "application", "flow_policy"}
-	for _, resourceType := range order {
+	for _, resourceType := range variableOrder {
 		vars, exists := groupedVars[resourceType]
 		if !exists {
 			continue
 		}
 
-		hdr := cases.Title(language.English).String(resourceType)
-		sb.WriteString(fmt.Sprintf("# %s Variables\n\n", hdr))
+		file.AppendNewline()
+		file.AppendComment(fmt.Sprintf("%s Variables", cases.Title(language.English).String(resourceType)))
+		file.AppendNewline()
 
 		// Sort variables alphabetically within each resource type group
 		sort.Slice(vars, func(i, j int) bool {
 			return strings.ToLower(vars[i].Name) < strings.ToLower(vars[j].Name)
 		})
 		for _, v := range vars {
-			sb.WriteString(g.generateTFVarValue(v))
+			file.SetAttribute(v.Name, g.generateTFVarValue(v))
 		}
-
-		sb.WriteString("\n")
 	}
 
 	// Root tfvars file is prefixed by module name
-	return g.writeFile(g.config.OutputDir, fmt.Sprintf("%s-terraform.auto.tfvars", g.config.ModuleName), sb.String())
+	return g.writeModelFile(g.config.OutputDir, fmt.Sprintf("%s-terraform.auto.tfvars", g.config.ModuleName), file)
 }
 
-// generateTFVarValue generates a single tfvar value line
-func (g *Generator) generateTFVarValue(v Variable) string {
+// generateTFVarValue returns the value of a single tfvar
+func (g *Generator) generateTFVarValue(v Variable) model.Value {
 	// Secrets always get empty values regardless of IncludeValues
 	if v.IsSecret {
-		return fmt.Sprintf("%s = \"\"  # Secret value - provide manually\n", v.Name)
+		return model.WithComment(model.String(""), "Secret value - provide manually")
 	}
 
 	// If IncludeValues is true and we have a default, use it
 	if g.config.IncludeValues && v.Default != nil {
-		return fmt.Sprintf("%s = %s\n", v.Name, g.formatDefaultValue(v.Default, v.Type))
+		return g.formatDefaultValue(v.Default, v.Type)
 	}
 
 	// Otherwise, use empty/zero values based on type
 	switch v.Type {
 	case "string":
-		return fmt.Sprintf("%s = \"\"\n", v.Name)
+		return model.String("")
 	case "number":
-		return fmt.Sprintf("%s = 0\n", v.Name)
+		return model.Int(0)
 	case "bool":
-		return fmt.Sprintf("%s = false\n", v.Name)
+		return model.Bool(false)
+	case "list(string)":
+		return &model.Tuple{}
 	default:
-		return fmt.Sprintf("%s = null\n", v.Name)
+		return model.Null()
 	}
 }
@@ -3,13 +3,26 @@ package module
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// alignment is the padding terraform fmt adds before the equals signs of consecutive attributes
+var alignment = regexp.MustCompile(` +=`)
+
+// unaligned returns content without the padding before equals signs, so one attribute can be
+// looked for without the names around it
+func unaligned(content string) string {
+	return alignment.ReplaceAllString(content, " =")
+}
+
 func TestGeneratorCreateDirectories(t *testing.T) {
 	// Create temp directory
 	tmpDir := t.TempDir()
@@ -286,11 +299,12 @@ func TestGeneratorResourceFiles(t *testing.T) {
 	require.NoError(t, err)
 
 	// Generate resource files
-	resources := ModuleResources{
-		FlowsHCL:        "resource \"pingone_davinci_flow\" \"test\" {}",
-		ConnectionsHCL:  "resource \"pingone_davinci_connector_instance\" \"http\" {}",
-		VariablesHCL:    "resource \"pingone_davinci_variable\" \"company_name\" {}",
-		ApplicationsHCL: "resource \"pingone_davinci_application\" \"app\" {}",
+	resources := []*model.Resource{
+		model.NewResource("pingone_davinci_flow", "test"),
+		model.NewResource("pingone_davinci_flow_enable", "test"),
+		model.NewResource("pingone_davinci_connector_instance", "http"),
+		model.NewResource("pingone_davinci_variable", "company_name"),
+		model.NewResource("pingone_davinci_application", "app"),
 	}
 
 	err = generator.generateResourceFiles(resources)
@@ -303,6 +317,8 @@ func TestGeneratorResourceFiles(t *testing.T) {
 	content, err := os.ReadFile(flowsPath)
 	require.NoError(t, err)
 	assert.Contains(t, string(content), "pingone_davinci_flow")
+	assert.Contains(t, string(content), `resource "pingone_davinci_flow_enable" "test"`, "flow_enable is written with the flows")
+	assert.NoFileExists(t, filepath.Join(childModulePath, "pingone_davinci_flow_enable.tf"))
 
 	connectionsPath := filepath.Join(childModulePath, "pingone_davinci_connector_instance.tf")
 	content, err = os.ReadFile(connectionsPath)
@@ -364,6 +380,14 @@ func TestFullModuleGeneration(t *testing.T) {
 	}
 	generator := NewGenerator(config)
 
+	flow := model.NewResource("pingone_davinci_flow", "main")
+	environment, err := model.Var("pingone_environment_id")
+	require.NoError(t, err)
+	flow.Body.SetAttribute("environment_id", environment)
+	name, err := model.Var("davinci_flow_main_name")
+	require.NoError(t, err)
+	flow.Body.SetAttribute("name", name)
+
 	// Create full module structure
 	structure := &ModuleStructure{
 		Config: config,
@@ -383,9 +407,9 @@ func TestFullModuleGeneration(t *testing.T) {
 				Value:       "pingone_davinci_flow.main.id",
 			},
 		},
-		Resources: ModuleResources{
-			FlowsHCL:       "resource \"pingone_davinci_flow\" \"main\" {\n  environment_id = var.pingone_environment_id\n  name = var.davinci_flow_main_name\n}",
-			ConnectionsHCL: "resource \"pingone_davinci_connector_instance\" \"http\" {}",
+		Resources: []*model.Resource{
+			flow,
+			model.NewResource("pingone_davinci_connector_instance", "http"),
 		},
 		ImportBlocks: []ImportBlock{
 			{
@@ -396,7 +420,7 @@ func TestFullModuleGeneration(t *testing.T) {
 	}
 
 	// Generate
-	err := generator.Generate(structure)
+	err = generator.Generate(structure)
 	require.NoError(t, err)
 
 	// Verify all files exist
@@ -412,6 +436,25 @@ func TestFullModuleGeneration(t *testing.T) {
 	// Check root module files
 	assert.FileExists(t, filepath.Join(tmpDir, "ping-export-module.tf"))
 	assert.FileExists(t, filepath.Join(tmpDir, "ping-export-imports.tf"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "ping-export-provider.tf"), "no provider file without a region")
+}
+
+func TestGeneratorProviderTF(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	config := ModuleConfig{
+		OutputDir:          tmpDir,
+		ProviderRegionCode: "AU",
+	}
+	generator := NewGenerator(config)
+	require.NoError(t, generator.Generate(&ModuleStructure{Config: config}))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "ping-export-provider.tf"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), `source  = "pingidentity/pingone"`)
+	assert.Contains(t, string(content), `provider "pingone" {`)
+	assert.Contains(t, string(content), `region_code = "AU"`)
 }
 
 // TestGenerator_GenerateRootVariablesTF verifies that root module variables.tf is generated correctly
@@ -552,7 +595,7 @@ func TestGenerator_GenerateModuleTF_UsesVariableReferences(t *testing.T) {
 			require.FileExists(t, moduleTFPath)
 			content, err := os.ReadFile(moduleTFPath)
 			require.NoError(t, err)
-			contentStr := string(content)
+			contentStr := unaligned(string(content))
 
 			// Verify module block
 			assert.Contains(t, contentStr, `module "ping-export" {`) // Default module name
@@ -622,6 +665,13 @@ func TestGenerator_GenerateTFVarsTemplate_WithoutValues(t *testing.T) {
 				ResourceType: "variable",
 				ResourceName: "enabled",
 			},
+			{
+				Name:         "davinci_application_app_redirect_uris",
+				Type:         "list(string)",
+				Description:  "OAuth redirect_uris for App application",
+				ResourceType: "application",
+				ResourceName: "app",
+			},
 		},
 	}
 
@@ -636,7 +686,7 @@ func TestGenerator_GenerateTFVarsTemplate_WithoutValues(t *testing.T) {
 	// Read and verify content
 	content, err := os.ReadFile(tfvarsPath)
 	require.NoError(t, err)
-	contentStr := string(content)
+	contentStr := unaligned(string(content))
 
 	// Verify environment_id with empty value and TODO comment
 	assert.Contains(t, contentStr, `pingone_environment_id = ""`)
@@ -655,6 +705,9 @@ func TestGenerator_GenerateTFVarsTemplate_WithoutValues(t *testing.T) {
 	// Verify bool variable with false
 	assert.Contains(t, contentStr, `davinci_variable_enabled_value = false`)
 
+	// Verify list variable with an empty list
+	assert.Contains(t, contentStr, `davinci_application_app_redirect_uris = []`)
+
 	// Verify grouping comments
 	assert.Contains(t, contentStr, "# Variable Variables")
 
@@ -721,6 +774,14 @@ func TestGenerator_GenerateTFVarsTemplate_WithValues(t *testing.T) {
 				ResourceType: "variable",
 				ResourceName: "enabled",
 			},
+			{
+				Name:         "davinci_application_app_redirect_uris",
+				Type:         "list(string)",
+				Description:  "OAuth redirect_uris for App application",
+				Default:      []string{"https://example.com/callback", "https://example.com/alt"},
+				ResourceType: "application",
+				ResourceName: "app",
+			},
 		},
 	}
 
@@ -735,7 +796,7 @@ func TestGenerator_GenerateTFVarsTemplate_WithValues(t *testing.T) {
 	// Read and verify content
 	content, err := os.ReadFile(tfvarsPath)
 	require.NoError(t, err)
-	contentStr := string(content)
+	contentStr := unaligned(string(content))
 
 	// Verify environment_id with actual value
 	assert.Contains(t, contentStr, `environment_id = "a1b2c3d4-e5f6-7890-abcd-ef1234567890"`)
@@ -758,6 +819,9 @@ func TestGenerator_GenerateTFVarsTemplate_WithValues(t *testing.T) {
 	assert.Contains(t, contentStr, `davinci_variable_enabled_value = true`)
 	assert.NotContains(t, contentStr, `davinci_variable_enabled_value = false`)
 
+	// Verify list variable with actual values
+	assert.Contains(t, contentStr, `davinci_application_app_redirect_uris = ["https://example.com/callback", "https://example.com/alt"]`)
+
 	// Verify grouping comments
 	assert.Contains(t, contentStr, "# Variable Variables")
 
@@ -774,6 +838,51 @@ func TestGenerator_GenerateTFVarsTemplate_WithValues(t *testing.T) {
 	}
 }
 
+// TestGenerator_GenerateTFVars_EscapesValues verifies free-form values read back unchanged,
+// with template sequences escaped rather than interpolated
+func TestGenerator_GenerateTFVars_EscapesValues(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, IncludeValues: true, EnvironmentID: "env"}
+
+	values := map[string]interface{}{
+		"davinci_flow_login_css_links":   []string{"https://cdn.example.com/${theme}.css", `a "quoted" \ path`},
+		"davinci_flow_login_logo_url":    "https://example.com/logo.png?size=%{w}",
+		"davinci_application_app_notice": "tab\tbell\a emoji \U0001F600 del\x7f",
+	}
+	structure := &ModuleStructure{Config: config}
+	for name, value := range values {
+		varType := "string"
+		if _, ok := value.([]string); ok {
+			varType = "list(string)"
+		}
+		structure.Variables = append(structure.Variables, Variable{Name: name, Type: varType, Default: value, ResourceType: "flow"})
+	}
+	require.NoError(t, NewGenerator(config).generateTFVarsFile(structure))
+
+	src, err := os.ReadFile(filepath.Join(tmpDir, "ping-export-terraform.auto.tfvars"))
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "$${theme}")
+	assert.Contains(t, string(src), "%%{w}")
+
+	file, diags := hclsyntax.ParseConfig(src, "terraform.auto.tfvars", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	attributes, diags := file.Body.JustAttributes()
+	require.False(t, diags.HasErrors(), diags.Error())
+	for name, want := range values {
+		value, diags := attributes[name].Expr.Value(nil)
+		require.False(t, diags.HasErrors(), "%s: %s", name, diags.Error())
+		if list, ok := want.([]string); ok {
+			got := make([]string, 0, len(list))
+			for _, elem := range value.AsValueSlice() {
+				got = append(got, elem.AsString())
+			}
+			assert.Equal(t, list, got, name)
+			continue
+		}
+		assert.Equal(t, want, value.AsString(), name)
+	}
+}
+
 // TestGenerator_DefaultModuleName tests that the default module name "ping-export" is used in module.tf
 func TestGenerator_DefaultModuleName(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -788,10 +897,8 @@ func TestGenerator_DefaultModuleName(t *testing.T) {
 	}
 
 	structure := &ModuleStructure{
-		Config: config,
-		Resources: ModuleResources{
-			FlowsHCL: "resource \"pingone_davinci_flow\" \"test\" {}\n",
-		},
+		Config:    config,
+		Resources: []*model.Resource{model.NewResource("pingone_davinci_flow", "test")},
 	}
 
 	generator := NewGenerator(config)
@@ -825,10 +932,8 @@ func TestGenerator_CustomModuleName(t *testing.T) {
 	}
 
 	structure := &ModuleStructure{
-		Config: config,
-		Resources: ModuleResources{
-			FlowsHCL: "resource \"pingone_davinci_flow\" \"test\" {}\n",
-		},
+		Config:    config,
+		Resources: []*model.Resource{model.NewResource("pingone_davinci_flow", "test")},
 	}
 
 	generator := NewGenerator(config)
@@ -877,9 +982,7 @@ func TestGenerator_ImportBlocksUseModuleName(t *testing.T) {
 	structure := &ModuleStructure{
 		Config:       config,
 		ImportBlocks: importBlocks,
-		Resources: ModuleResources{
-			FlowsHCL: "resource \"pingone_davinci_flow\" \"test_flow\" {}\n",
-		},
+		Resources:    []*model.Resource{model.NewResource("pingone_davinci_flow", "test_flow")},
 	}
 
 	generator := NewGenerator(config)
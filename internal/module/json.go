@@ -0,0 +1,29 @@
+package module
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Output formats of the generated files
+const (
+	// FormatHCL writes native Terraform syntax (.tf, .tfvars)
+	FormatHCL = "hcl"
+
+	// FormatJSON writes Terraform JSON syntax (.tf.json, .tfvars.json)
+	FormatJSON = "json"
+)
+
+// Formats returns the supported output formats
+func Formats() []string {
+	return []string{FormatHCL, FormatJSON}
+}
+
+// ValidateFormat returns an error for an unsupported output format. An empty format is HCL.
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatHCL, FormatJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported format %q: use one of %s", format, strings.Join(Formats(), ", "))
+}
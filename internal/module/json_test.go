@@ -0,0 +1,408 @@
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// formatTestStructure returns a module structure covering every generated file, with
+// references, templates, function calls, comments and strings holding template sequences
+func formatTestStructure(config ModuleConfig) *ModuleStructure {
+	return &ModuleStructure{
+		Config: config,
+		Variables: []Variable{
+			{Name: "davinci_flow_main_name", Type: "string", Description: "Name of \"main\" flow", Default: "Main <flow> & co", ResourceType: "flow"},
+			{Name: "davinci_variable_count_value", Type: "number", Description: "Count", Default: 3, ResourceType: "variable"},
+			{Name: "davinci_variable_enabled_value", Type: "bool", Description: "Enabled", Default: true, ResourceType: "variable"},
+			{Name: "davinci_connection_http_secret", Type: "string", Description: "Secret", IsSecret: true, Sensitive: true, ResourceType: "connection",
+				Validation: &VariableValidation{Condition: `var.davinci_connection_http_secret != ""`, ErrorMessage: "Secret must be set."}},
+		},
+		Outputs: []Output{
+			{Name: "main_flow_id", Description: "ID of main flow", Value: "pingone_davinci_flow.main.id"},
+			{Name: "http_secret", Description: "Secret", Value: "var.davinci_connection_http_secret", Sensitive: true},
+		},
+		Resources: formatTestResources(),
+		ImportBlocks: []ImportBlock{
+			{To: "module.ping-export.pingone_davinci_flow.main", ID: "env/flow-1"},
+			{To: "module.ping-export.pingone_davinci_variable.color", ID: "env/var-1"},
+		},
+	}
+}
+
+// formatTestResources returns a flow and a variable holding every kind of value the model has
+func formatTestResources() []*model.Resource {
+	expr := func(s string) model.Value {
+		value, err := model.ParseExpression(s)
+		if err != nil {
+			panic(err)
+		}
+		return value
+	}
+
+	flow := model.NewResource("pingone_davinci_flow", "main")
+	flow.Body.SetAttribute("environment_id", expr("var.pingone_environment_id"))
+	flow.Body.SetAttribute("name", expr("var.davinci_flow_main_name"))
+	flow.Body.SetAttribute("description", model.String(`Uses ${literal} and %{literal} with "quotes" <html> & \`))
+	flow.Body.SetAttribute("color", model.Null())
+
+	jsLink := &model.Object{}
+	jsLink.Set("defer", model.Bool(true))
+	jsLink.Set("value", model.String("https://example.com/app.js"))
+	settings := &model.Object{}
+	settings.Set("log_level", model.Int(3))
+	settings.Set("css_links", &model.Tuple{})
+	settings.Set("js_links", &model.Tuple{Elems: []model.Value{jsLink}})
+	flow.Body.AppendNewline()
+	flow.Body.SetAttribute("settings", settings)
+
+	message := &model.Object{}
+	message.Set("value", expr(`"{{global.variables.${pingone_davinci_variable.color.name}}} $${x}"`))
+	properties := &model.Object{}
+	properties.Set("message", message)
+	properties.Set("list", &model.Tuple{Elems: []model.Value{model.Number(1.5), model.Bool(true), model.Null()}})
+	data := &model.Object{}
+	data.Set("id", model.String("node-1"))
+	data.Set("label", expr(`"Color is ${pingone_davinci_variable.color.name}!"`))
+	data.Set("properties", model.JSONEncode(properties))
+	node := &model.Object{}
+	node.Set("data", data)
+	nodes := &model.Object{}
+	nodes.SetKey("node-1", node)
+	elements := &model.Object{}
+	elements.Set("nodes", nodes)
+	graphData := &model.Object{}
+	graphData.Set("elements", elements)
+	flow.Body.AppendNewline()
+	flow.Body.SetAttribute("graph_data", graphData)
+
+	variable := model.NewResource("pingone_davinci_variable", "color")
+	variable.Body.SetAttribute("environment_id", expr("var.pingone_environment_id"))
+	variable.Body.SetAttribute("name", model.String("color"))
+	flowID := &model.Object{}
+	flowID.Set("id", expr(`"flow-1" # TODO: Replace with flow reference`))
+	variable.Body.SetAttribute("flow", flowID)
+	value := &model.Object{}
+	value.Set("string", expr(`"multi\nline ${var.davinci_flow_main_name}\n"`))
+	variable.Body.SetAttribute("value", value)
+
+	return []*model.Resource{flow, variable}
+}
+
+// generatedConfigSchema lists the top-level block types of generated files with their labels
+// and the nested block types of their bodies
+var generatedConfigSchema = map[string]struct {
+	labels []string
+	nested []string
+}{
+	"terraform": {nested: []string{"required_providers"}},
+	"provider":  {labels: []string{"name"}},
+	"variable":  {labels: []string{"name"}, nested: []string{"validation"}},
+	"output":    {labels: []string{"name"}},
+	"module":    {labels: []string{"name"}},
+	"resource":  {labels: []string{"type", "name"}},
+	"import":    {},
+	"moved":     {},
+}
+
+// expressionAttributes are the attributes, by block type, whose JSON value is an expression
+// written as a string instead of a string template
+var expressionAttributes = map[string]map[string]bool{
+	"variable": {"type": true},
+	"import":   {"to": true},
+	"moved":    {"from": true, "to": true},
+}
+
+// decodeGeneratedFile parses a generated file in either syntax and evaluates it to plain values,
+// keyed by block type and labels. References evaluate to their own address.
+func decodeGeneratedFile(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	src, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = hcljson.Parse(src, path)
+	} else {
+		file, diags = hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	}
+	require.False(t, diags.HasErrors(), "%s does not parse: %s", path, diags.Error())
+
+	// Variable definition values are literal
+	if strings.HasSuffix(strings.TrimSuffix(path, ".json"), ".tfvars") {
+		attributes, diags := file.Body.JustAttributes()
+		require.False(t, diags.HasErrors(), diags.Error())
+		decoded := make(map[string]interface{})
+		for name, attr := range attributes {
+			value, diags := attr.Expr.Value(nil)
+			require.False(t, diags.HasErrors(), "%s in %s: %s", name, path, diags.Error())
+			decoded[name] = plainValue(t, value)
+		}
+		return decoded
+	}
+
+	schema := &hcl.BodySchema{}
+	for blockType, block := range generatedConfigSchema {
+		schema.Blocks = append(schema.Blocks, hcl.BlockHeaderSchema{Type: blockType, LabelNames: block.labels})
+	}
+	content, diags := file.Body.Content(schema)
+	require.False(t, diags.HasErrors(), "%s: %s", path, diags.Error())
+
+	decoded := make(map[string]interface{})
+	counts := make(map[string]int)
+	for _, block := range content.Blocks {
+		key := strings.Join(append([]string{block.Type}, block.Labels...), ".")
+		if len(block.Labels) == 0 {
+			key += "." + strconv.Itoa(counts[block.Type])
+			counts[block.Type]++
+		}
+		decoded[key] = decodeGeneratedBody(t, block.Body, block.Type, generatedConfigSchema[block.Type].nested)
+	}
+	return decoded
+}
+
+// decodeGeneratedBody evaluates the attributes and nested blocks of a block body
+func decodeGeneratedBody(t *testing.T, body hcl.Body, blockType string, nested []string) map[string]interface{} {
+	t.Helper()
+	schema := &hcl.BodySchema{}
+	for _, nestedType := range nested {
+		schema.Blocks = append(schema.Blocks, hcl.BlockHeaderSchema{Type: nestedType})
+	}
+	content, remain, diags := body.PartialContent(schema)
+	require.False(t, diags.HasErrors(), diags.Error())
+	// Native bodies report the nested blocks PartialContent already took
+	attributes, diags := remain.JustAttributes()
+	for _, diag := range diags {
+		taken := false
+		for _, nestedType := range nested {
+			taken = taken || diag.Summary == fmt.Sprintf("Unexpected %q block", nestedType)
+		}
+		require.True(t, taken, diag.Error())
+	}
+
+	decoded := make(map[string]interface{})
+	for name, attr := range attributes {
+		if expressionAttributes[blockType][name] {
+			traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+			require.False(t, diags.HasErrors(), "%s.%s: %s", blockType, name, diags.Error())
+			decoded[name] = traversalAddress(traversal)
+			continue
+		}
+		value, diags := attr.Expr.Value(referenceContext(attr.Expr))
+		require.False(t, diags.HasErrors(), "%s.%s: %s", blockType, name, diags.Error())
+		decoded[name] = plainValue(t, value)
+	}
+	for i, block := range content.Blocks {
+		decoded[block.Type+"."+strconv.Itoa(i)] = decodeGeneratedBody(t, block.Body, block.Type, nil)
+	}
+	return decoded
+}
+
+// referenceContext evaluates the references of an expression to their own address, with the
+// functions used by generated files
+func referenceContext(expr hcl.Expression) *hcl.EvalContext {
+	tree := make(map[string]interface{})
+	for _, traversal := range expr.Variables() {
+		node := tree
+		for i, name := range traversalNames(traversal) {
+			if i == len(traversalNames(traversal))-1 {
+				if _, isObject := node[name].(map[string]interface{}); !isObject {
+					node[name] = traversalAddress(traversal)
+				}
+				break
+			}
+			child, ok := node[name].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[name] = child
+			}
+			node = child
+		}
+	}
+
+	var toValue func(interface{}) cty.Value
+	toValue = func(node interface{}) cty.Value {
+		if address, ok := node.(string); ok {
+			return cty.StringVal("ref:" + address)
+		}
+		attributes := make(map[string]cty.Value)
+		for name, child := range node.(map[string]interface{}) {
+			attributes[name] = toValue(child)
+		}
+		return cty.ObjectVal(attributes)
+	}
+	variables := make(map[string]cty.Value, len(tree))
+	for name, node := range tree {
+		variables[name] = toValue(node)
+	}
+
+	return &hcl.EvalContext{
+		Variables: variables,
+		Functions: map[string]function.Function{
+			"can":        tryfunc.CanFunc,
+			"jsonencode": stdlib.JSONEncodeFunc,
+			"regex":      stdlib.RegexFunc,
+		},
+	}
+}
+
+func traversalNames(traversal hcl.Traversal) []string {
+	var names []string
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, s.Name)
+		case hcl.TraverseAttr:
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+func traversalAddress(traversal hcl.Traversal) string {
+	return strings.Join(traversalNames(traversal), ".")
+}
+
+// plainValue converts a value to its JSON form, so object and map types compare equal
+func plainValue(t *testing.T, value cty.Value) interface{} {
+	t.Helper()
+	data, err := ctyjson.SimpleJSONValue{Value: value}.MarshalJSON()
+	require.NoError(t, err)
+	var plain interface{}
+	require.NoError(t, json.Unmarshal(data, &plain))
+	return plain
+}
+
+// generatedFiles lists the files under dir, relative to dir
+func generatedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	require.NoError(t, filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	}))
+	sort.Strings(files)
+	return files
+}
+
+func TestGenerateFormats_DescribeSameConfiguration(t *testing.T) {
+	hclDir, jsonDir := t.TempDir(), t.TempDir()
+	for _, config := range []ModuleConfig{
+		{OutputDir: hclDir, IncludeImports: true, IncludeValues: true, EnvironmentID: "env-1", ProviderRegionCode: "EU"},
+		{OutputDir: jsonDir, IncludeImports: true, IncludeValues: true, EnvironmentID: "env-1", ProviderRegionCode: "EU", Format: FormatJSON},
+	} {
+		require.NoError(t, NewGenerator(config).Generate(formatTestStructure(config)))
+	}
+
+	hclFiles := generatedFiles(t, hclDir)
+	var expected []string
+	for _, file := range hclFiles {
+		if file != "ping-export-manifest.json" {
+			file += ".json"
+		}
+		expected = append(expected, file)
+	}
+	assert.Equal(t, expected, generatedFiles(t, jsonDir), "every file is generated in JSON syntax")
+	assert.Contains(t, hclFiles, "ping-export-terraform.auto.tfvars")
+
+	for _, file := range hclFiles {
+		if file == "ping-export-manifest.json" {
+			continue
+		}
+		t.Run(file, func(t *testing.T) {
+			native := decodeGeneratedFile(t, filepath.Join(hclDir, file))
+			assert.NotEmpty(t, native)
+			assert.Equal(t, native, decodeGeneratedFile(t, filepath.Join(jsonDir, file+".json")))
+		})
+	}
+}
+
+func TestGenerateJSONFormat_Syntax(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, IncludeImports: true, IncludeValues: true, Format: FormatJSON}
+	require.NoError(t, NewGenerator(config).Generate(formatTestStructure(config)))
+
+	flows := readUpdateFile(t, tmpDir, "ping-export-module/pingone_davinci_flow.tf.json")
+	assert.Contains(t, flows, `"environment_id": "${var.pingone_environment_id}"`)
+	assert.Contains(t, flows, `"description": "Uses $${literal} and %%{literal} with \"quotes\" <html> & \\"`, "template sequences stay escaped")
+	assert.Contains(t, flows, `"label": "Color is ${pingone_davinci_variable.color.name}!"`)
+	assert.Contains(t, flows, `"log_level": 3`, "constants are JSON values")
+	assert.Contains(t, flows, `"color": null`)
+
+	variables := readUpdateFile(t, tmpDir, "ping-export-module/variables.tf.json")
+	assert.Contains(t, variables, `"type": "number"`, "types are expressions, not templates")
+	assert.Contains(t, variables, `"condition": "${var.davinci_connection_http_secret != \"\"}"`)
+	assert.Contains(t, readUpdateFile(t, tmpDir, "ping-export-module/pingone_davinci_variable.tf.json"),
+		`"//": "flow.id: TODO: Replace with flow reference"`, "comments become comment members")
+
+	imports := readUpdateFile(t, tmpDir, "ping-export-imports.tf.json")
+	assert.Contains(t, imports, `"to": "module.ping-export.pingone_davinci_flow.main"`)
+	assert.Contains(t, imports, `"id": "env/flow-1"`)
+
+	tfvars := readUpdateFile(t, tmpDir, "ping-export-terraform.auto.tfvars.json")
+	assert.Contains(t, tfvars, `"davinci_flow_main_name": "Main <flow> & co"`)
+	assert.Contains(t, tfvars, `"davinci_variable_count_value": 3`)
+	assert.Contains(t, tfvars, `"davinci_connection_http_secret: Secret value - provide manually"`)
+}
+
+func TestGeneratorMovedBlocks_JSONFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, ModuleDirName: "ping-export-module", IncludeImports: true, Format: FormatJSON}
+
+	// Renames are found from the imports file of modules generated before the manifest existed
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "ping-export-manifest.json")))
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "renamed", "a", "second")))
+
+	// Existing moved blocks are kept
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "renamed", "a", "latest")))
+	assert.Equal(t, `{
+  "moved": [
+    {
+      "from": "module.ping-export.pingone_davinci_variable.first",
+      "to": "module.ping-export.pingone_davinci_variable.renamed"
+    },
+    {
+      "from": "module.ping-export.pingone_davinci_variable.second",
+      "to": "module.ping-export.pingone_davinci_variable.latest"
+    }
+  ]
+}
+`, readUpdateFile(t, tmpDir, "ping-export-moved.tf.json"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "ping-export-moved.tf"))
+}
+
+func TestGeneratorUpdate_JSONFormat(t *testing.T) {
+	config := ModuleConfig{OutputDir: t.TempDir(), Format: FormatJSON}
+	_, err := NewGenerator(config).Update(updateTestStructure(config, "first", "a", "second"))
+	assert.ErrorContains(t, err, "only supported in hcl format")
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{"", FormatHCL, FormatJSON} {
+		assert.NoError(t, ValidateFormat(format))
+	}
+	assert.ErrorContains(t, ValidateFormat("yaml"), `unsupported format "yaml": use one of hcl, json`)
+}
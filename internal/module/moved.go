@@ -0,0 +1,212 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// movedFileName returns the name of the root file holding moved blocks
+func (g *Generator) movedFileName() string {
+	return fmt.Sprintf("%s-moved.tf", g.config.ModuleName)
+}
+
+// resourceRenames matches the exported resources with the previous export by resource type and
+// import ID, as resources of different types can share an import ID (a flow and its enable
+// resource), and returns those whose address changed, in address order. Addresses still
+// exported are never the source of a rename.
+func (g *Generator) resourceRenames(previous *Manifest, structure *ModuleStructure) []ResourceRename {
+	identity := func(address, id string) string {
+		resourceType, _, _ := strings.Cut(address, ".")
+		return resourceType + " " + id
+	}
+
+	current := g.resourceImportIDs(structure)
+	previousAddresses := make(map[string]string, len(previous.Resources))
+	for address, id := range previous.Resources {
+		previousAddresses[identity(address, id)] = address
+	}
+
+	var renames []ResourceRename
+	for _, address := range sortedKeys(current) {
+		from, ok := previousAddresses[identity(address, current[address])]
+		if !ok || from == address {
+			continue
+		}
+		if _, stillExported := current[from]; stillExported {
+			continue
+		}
+		renames = append(renames, ResourceRename{From: from, To: address})
+	}
+	return renames
+}
+
+// readImportedResources reads the resource addresses and import IDs of the import blocks in
+// the root imports file, for modules generated before the manifest was written
+func (g *Generator) readImportedResources() (map[string]string, error) {
+	blocks, err := g.readRootBlocks(fmt.Sprintf("%s-imports.tf", g.config.ModuleName), "import")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read imports: %w", err)
+	}
+
+	prefix := fmt.Sprintf("module.%s.", g.config.ModuleName)
+	resources := make(map[string]string)
+	for _, block := range blocks {
+		to, hasTo := block.references["to"]
+		id, hasID := block.values["id"]
+		if hasTo && hasID && strings.HasPrefix(to, prefix) {
+			resources[strings.TrimPrefix(to, prefix)] = id
+		}
+	}
+	return resources, nil
+}
+
+// readMovedBlocks reads the from and to addresses of the moved blocks in the root moved file
+func (g *Generator) readMovedBlocks() ([]ResourceRename, error) {
+	blocks, err := g.readRootBlocks(g.movedFileName(), "moved")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read moved blocks: %w", err)
+	}
+
+	var moves []ResourceRename
+	for _, block := range blocks {
+		from, hasFrom := block.references["from"]
+		to, hasTo := block.references["to"]
+		if hasFrom && hasTo {
+			moves = append(moves, ResourceRename{From: from, To: to})
+		}
+	}
+	return moves, nil
+}
+
+// rootBlock holds the attributes of a block read back from a generated root file
+type rootBlock struct {
+	values     map[string]string // String constants
+	references map[string]string // Every attribute as an address: its native source, or its string in JSON syntax
+}
+
+// readRootBlocks reads the attributes of the blocks of a type in a generated root file, in
+// the syntax of the configured format. A missing file has no blocks.
+func (g *Generator) readRootBlocks(filename, blockType string) ([]rootBlock, error) {
+	path := filepath.Join(g.config.OutputDir, g.fileName(filename))
+	src, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if g.jsonFormat() {
+		file, diags = hcljson.Parse(src, path)
+	} else {
+		file, diags = hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	}
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, diags.Error())
+	}
+	content, _, diags := file.Body.PartialContent(&hcl.BodySchema{Blocks: []hcl.BlockHeaderSchema{{Type: blockType}}})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, diags.Error())
+	}
+
+	var blocks []rootBlock
+	for _, block := range content.Blocks {
+		attributes, diags := block.Body.JustAttributes()
+		if diags.HasErrors() {
+			continue
+		}
+		read := rootBlock{values: make(map[string]string), references: make(map[string]string)}
+		for name, attr := range attributes {
+			// A nil context reads JSON strings verbatim, and fails on native references
+			value, diags := attr.Expr.Value(nil)
+			if !diags.HasErrors() && value.Type() == cty.String && !value.IsNull() {
+				read.values[name] = value.AsString()
+				read.references[name] = value.AsString()
+				continue
+			}
+			rng := attr.Expr.Range()
+			read.references[name] = strings.TrimSpace(string(src[rng.Start.Byte:rng.End.Byte]))
+		}
+		blocks = append(blocks, read)
+	}
+	return blocks, nil
+}
+
+// generateMovedTF writes moved blocks to the root module so existing state follows renamed
+// resources. The file is owned by the generator: moved blocks already in it are kept, as state
+// that has not been applied since still needs them, except those moving away from an address
+// that is exported again.
+func (g *Generator) generateMovedTF(renames []ResourceRename, structure *ModuleStructure) error {
+	existing, err := g.readMovedBlocks()
+	if err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("module.%s.", g.config.ModuleName)
+	current := g.resourceImportIDs(structure)
+
+	seen := make(map[ResourceRename]bool)
+	var blocks []ResourceRename
+	for _, move := range existing {
+		if _, exported := current[strings.TrimPrefix(move.From, prefix)]; exported || seen[move] {
+			continue
+		}
+		seen[move] = true
+		blocks = append(blocks, move)
+	}
+	for _, rename := range renames {
+		move := ResourceRename{From: prefix + rename.From, To: prefix + rename.To}
+		if !seen[move] {
+			seen[move] = true
+			blocks = append(blocks, move)
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].From < blocks[j].From })
+
+	file := &model.Body{}
+	for i, move := range blocks {
+		from, err := movedAddress(move.From)
+		if err != nil {
+			return err
+		}
+		to, err := movedAddress(move.To)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			file.AppendNewline()
+		}
+		block := file.AppendBlock("moved")
+		block.Body.SetAttribute("from", from)
+		block.Body.SetAttribute("to", to)
+	}
+
+	content, jsonData, err := g.renderModelFile(g.movedFileName(), file)
+	if err != nil {
+		return err
+	}
+	// Root file name is prefixed by module name; never merged, as it already has every moved block
+	return g.writeGeneratedFile(g.config.OutputDir, g.movedFileName(), content, jsonData, false)
+}
+
+// movedAddress returns the reference to a resource address of a moved block
+func movedAddress(address string) (model.Value, error) {
+	value, err := model.ParseExpression(address)
+	if _, ok := value.(model.Reference); err != nil || !ok {
+		return nil, fmt.Errorf("invalid moved address %q", address)
+	}
+	return value, nil
+}
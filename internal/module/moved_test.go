@@ -0,0 +1,66 @@
+package module
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorMovedBlocks(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, ModuleDirName: "ping-export-module"}
+	movedPath := filepath.Join(tmpDir, "ping-export-moved.tf")
+
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+	assert.NoFileExists(t, movedPath, "no moved blocks without renames")
+
+	// var-1 is renamed
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "renamed", "a", "second")))
+	assert.Equal(t, `moved {
+  from = module.ping-export.pingone_davinci_variable.first
+  to   = module.ping-export.pingone_davinci_variable.renamed
+}
+`, readUpdateFile(t, tmpDir, "ping-export-moved.tf"))
+
+	// Renamed again: the earlier moved block is kept so unapplied state still follows the chain
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "latest", "a", "second")))
+	assert.Equal(t, `moved {
+  from = module.ping-export.pingone_davinci_variable.first
+  to   = module.ping-export.pingone_davinci_variable.renamed
+}
+
+moved {
+  from = module.ping-export.pingone_davinci_variable.renamed
+  to   = module.ping-export.pingone_davinci_variable.latest
+}
+`, readUpdateFile(t, tmpDir, "ping-export-moved.tf"))
+
+	// Renamed back: blocks moving away from the address in use again are dropped
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+	assert.Equal(t, `moved {
+  from = module.ping-export.pingone_davinci_variable.latest
+  to   = module.ping-export.pingone_davinci_variable.first
+}
+
+moved {
+  from = module.ping-export.pingone_davinci_variable.renamed
+  to   = module.ping-export.pingone_davinci_variable.latest
+}
+`, readUpdateFile(t, tmpDir, "ping-export-moved.tf"))
+}
+
+func TestGeneratorMovedBlocks_FromImportsFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, ModuleDirName: "ping-export-module", IncludeImports: true}
+
+	// A module generated before the manifest existed only has its imports file
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "ping-export-manifest.json")))
+
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "renamed")))
+	assert.Contains(t, readUpdateFile(t, tmpDir, "ping-export-moved.tf"), `  from = module.ping-export.pingone_davinci_variable.second
+  to   = module.ping-export.pingone_davinci_variable.renamed`)
+}
@@ -1,5 +1,7 @@
 package module
 
+import "github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+
 // ModuleConfig contains configuration for module generation
 type ModuleConfig struct {
 	// OutputDir is the root directory where module files will be generated
@@ -19,6 +21,14 @@ type ModuleConfig struct {
 
 	// EnvironmentID is the PingOne environment ID from the export
 	EnvironmentID string
+
+	// ProviderRegionCode is the pingone provider region_code for the exported environment.
+	// When set, a root provider file configuring the provider is generated.
+	ProviderRegionCode string
+
+	// Format is the syntax of the generated files: FormatHCL (default) or FormatJSON, which
+	// writes the same configuration as .tf.json and .tfvars.json files
+	Format string
 }
 
 // ModuleStructure represents the complete module structure to generate
@@ -31,8 +41,8 @@ type ModuleStructure struct {
 	// Outputs to be defined in the child module's outputs.tf
 	Outputs []Output
 
-	// Resources to be written to the child module (flows, connections, variables)
-	Resources ModuleResources
+	// Resources to be written to the child module, one file per resource type
+	Resources []*model.Resource
 
 	// ImportBlocks for the root module's imports.tf (if IncludeImports is true)
 	ImportBlocks []ImportBlock
@@ -65,24 +75,6 @@ type Output struct {
 	Sensitive   bool
 }
 
-// ModuleResources contains the HCL content for different resource types
-type ModuleResources struct {
-	// FlowsHCL contains all flow resource definitions
-	FlowsHCL string
-
-	// ConnectionsHCL contains all connection instance resource definitions
-	ConnectionsHCL string
-
-	// VariablesHCL contains all DaVinci variable resource definitions
-	VariablesHCL string
-
-	// ApplicationsHCL contains all application resource definitions
-	ApplicationsHCL string
-
-	// FlowPoliciesHCL contains all flow policy resource definitions
-	FlowPoliciesHCL string
-}
-
 // ImportBlock represents a Terraform import block
 type ImportBlock struct {
 	To string // The resource address (e.g., "module.davinci.pingone_davinci_flow.main")
@@ -0,0 +1,369 @@
+package module
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// manifestVersion is the version of the manifest file format
+const manifestVersion = 1
+
+// Manifest records what the generator last wrote, so an update can tell upstream changes
+// from local edits. It is written to <module name>-manifest.json in the output directory.
+type Manifest struct {
+	Version int `json:"version"`
+
+	// Resources maps each generated resource address in the child module to its import ID
+	Resources map[string]string `json:"resources"`
+
+	// Files maps each generated file path, relative to the output directory, to the hash
+	// of every block (or tfvars value) as it was generated, keyed by block key
+	Files map[string]map[string]string `json:"files"`
+}
+
+// UpdateChange identifies a block (or tfvars value) in a generated file
+type UpdateChange struct {
+	File string
+	Key  string // Resource address, "variable.<name>", "import.<to>", tfvars variable name, ...
+}
+
+// ResourceRename is a resource whose address changed upstream, matched by import ID
+type ResourceRename struct {
+	From string
+	To   string
+}
+
+// UpdateReport summarizes an in-place update of an existing module
+type UpdateReport struct {
+	Added     []UpdateChange   // Blocks that were not in the module yet
+	Updated   []UpdateChange   // Blocks rewritten because their upstream content changed
+	Conflicts []UpdateChange   // Updated blocks whose local edits were overwritten
+	Kept      []UpdateChange   // tfvars values that changed upstream but were kept because they were set locally
+	Renamed   []ResourceRename // Resources whose block was replaced under a new address
+	Deleted   []string         // Resource addresses no longer exported; their blocks are left in place
+}
+
+// updateState holds the previous manifest and the progress of an update
+type updateState struct {
+	previous *Manifest
+	renamed  map[string]string // Block key in the existing module -> block key it becomes
+	report   *UpdateReport
+}
+
+// Update merges the module structure into the module previously generated in OutputDir
+// instead of rewriting it. Blocks are matched by key, and resources also by import ID so
+// renamed resources replace their old block. A block is only rewritten when its generated
+// content differs from the previous generation recorded in the manifest, which keeps local
+// edits to unchanged blocks. Blocks and files the generator did not write are never touched,
+// and tfvars values are only replaced while they still hold the previously generated value.
+// Without a manifest every generated block that differs is rewritten and every existing
+// tfvars value is kept. Modules generated in JSON format cannot be updated.
+func (g *Generator) Update(structure *ModuleStructure) (*UpdateReport, error) {
+	if g.jsonFormat() {
+		return nil, fmt.Errorf("updating a module is only supported in %s format", FormatHCL)
+	}
+
+	previous, err := g.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	g.update = &updateState{
+		previous: previous,
+		renamed:  make(map[string]string),
+		report:   &UpdateReport{},
+	}
+	defer func() { g.update = nil }()
+
+	// Renamed resources replace their old block and import block
+	renames := g.resourceRenames(previous, structure)
+	for _, rename := range renames {
+		g.update.renamed[rename.From] = rename.To
+		g.update.renamed[g.importKey(rename.From)] = g.importKey(rename.To)
+	}
+	g.update.report.Renamed = renames
+
+	// Resources exported before but no longer
+	current := g.resourceImportIDs(structure)
+	for _, address := range sortedKeys(previous.Resources) {
+		if _, ok := current[address]; ok {
+			continue
+		}
+		if _, ok := g.update.renamed[address]; ok {
+			continue
+		}
+		g.update.report.Deleted = append(g.update.report.Deleted, address)
+	}
+
+	if err := g.Generate(structure); err != nil {
+		return nil, err
+	}
+	return g.update.report, nil
+}
+
+// manifestPath returns the path of the manifest file in the output directory
+func (g *Generator) manifestPath() string {
+	return filepath.Join(g.config.OutputDir, fmt.Sprintf("%s-manifest.json", g.config.ModuleName))
+}
+
+// importKey returns the block key of the import block for a resource address in the child module
+func (g *Generator) importKey(address string) string {
+	return fmt.Sprintf("import.module.%s.%s", g.config.ModuleName, address)
+}
+
+// resourceImportIDs maps each resource address in the child module to its import ID
+func (g *Generator) resourceImportIDs(structure *ModuleStructure) map[string]string {
+	prefix := fmt.Sprintf("module.%s.", g.config.ModuleName)
+	resources := make(map[string]string, len(structure.ImportBlocks))
+	for _, ib := range structure.ImportBlocks {
+		resources[strings.TrimPrefix(ib.To, prefix)] = ib.ID
+	}
+	return resources
+}
+
+// previousManifest returns the manifest of the module being updated, or reads it from the
+// output directory before it is overwritten
+func (g *Generator) previousManifest() (*Manifest, error) {
+	if g.update != nil {
+		return g.update.previous, nil
+	}
+	return g.readManifest()
+}
+
+// readManifest reads the manifest in the output directory. Modules generated without one
+// get their resource import IDs from the root imports file, if any.
+func (g *Generator) readManifest() (*Manifest, error) {
+	manifest := &Manifest{Version: manifestVersion, Resources: map[string]string{}, Files: map[string]map[string]string{}}
+
+	path := g.manifestPath()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		resources, err := g.readImportedResources()
+		if err != nil {
+			return nil, err
+		}
+		manifest.Resources = resources
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d in %s", manifest.Version, path)
+	}
+	if manifest.Resources == nil {
+		manifest.Resources = map[string]string{}
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]map[string]string{}
+	}
+	return manifest, nil
+}
+
+// writeManifest records the generated blocks and resource import IDs. Files not generated
+// this time keep their previous entries so a later update still recognizes their blocks.
+func (g *Generator) writeManifest(structure *ModuleStructure) error {
+	manifest := &Manifest{
+		Version:   manifestVersion,
+		Resources: g.resourceImportIDs(structure),
+		Files:     make(map[string]map[string]string),
+	}
+	if g.update != nil {
+		for file, hashes := range g.update.previous.Files {
+			manifest.Files[file] = hashes
+		}
+	}
+	for file, hashes := range g.generated {
+		manifest.Files[file] = hashes
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return os.WriteFile(g.manifestPath(), append(data, '\n'), 0644)
+}
+
+// hclItem is a top-level block of a .tf file or an attribute of a .tfvars file
+type hclItem struct {
+	key        string
+	start, end int // Byte range of the item in the source
+}
+
+// parseItems returns the top-level items of an HCL file in source order
+func parseItems(filename string, src []byte) ([]hclItem, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %s", filename, diags.Error())
+	}
+	body := file.Body.(*hclsyntax.Body)
+
+	var items []hclItem
+	if strings.HasSuffix(filename, ".tfvars") {
+		for name, attr := range body.Attributes {
+			items = append(items, hclItem{key: name, start: attr.SrcRange.Start.Byte, end: attr.SrcRange.End.Byte})
+		}
+	} else {
+		for _, block := range body.Blocks {
+			rng := block.Range()
+			items = append(items, hclItem{key: blockKey(block, src), start: rng.Start.Byte, end: rng.End.Byte})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].start < items[j].start })
+	return items, nil
+}
+
+// blockKey identifies a top-level block: the address for resources, the block type and
+// labels otherwise, and the target for import and moved blocks, which have no labels
+func blockKey(block *hclsyntax.Block, src []byte) string {
+	switch block.Type {
+	case "resource":
+		return strings.Join(block.Labels, ".")
+	case "import", "moved":
+		attribute := "to"
+		if block.Type == "moved" {
+			attribute = "from"
+		}
+		if attr, ok := block.Body.Attributes[attribute]; ok {
+			rng := attr.Expr.Range()
+			return block.Type + "." + strings.TrimSpace(string(src[rng.Start.Byte:rng.End.Byte]))
+		}
+	}
+	return strings.Join(append([]string{block.Type}, block.Labels...), ".")
+}
+
+// itemText returns the source of an item, extended to the end of its line so trailing
+// comments are kept with it
+func itemText(src []byte, item hclItem) []byte {
+	end := item.end
+	for end < len(src) && src[end] != '\n' {
+		end++
+	}
+	return src[item.start:end]
+}
+
+func hashText(text []byte) string {
+	sum := sha256.Sum256(text)
+	return hex.EncodeToString(sum[:])
+}
+
+// mergeFile merges generated content into the existing file at path
+func (g *Generator) mergeFile(path, file string, generated []byte, items []hclItem) error {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		for _, item := range items {
+			g.update.report.Added = append(g.update.report.Added, UpdateChange{File: file, Key: item.key})
+		}
+		return os.WriteFile(path, generated, 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	existingItems, err := parseItems(path, existing)
+	if err != nil {
+		return fmt.Errorf("cannot update %s: %w", file, err)
+	}
+
+	generatedItems := make(map[string]hclItem, len(items))
+	for _, item := range items {
+		generatedItems[item.key] = item
+	}
+	baseline := g.update.previous.Files[file]
+	tfvars := strings.HasSuffix(file, ".tfvars")
+
+	var out bytes.Buffer
+	used := make(map[string]bool)
+	prev := 0
+	for _, item := range existingItems {
+		out.Write(existing[prev:item.start])
+		prev = item.end
+		text := existing[item.start:item.end]
+
+		key := item.key
+		if renamed, ok := g.update.renamed[key]; ok {
+			key = renamed
+		}
+		generatedItem, ok := generatedItems[key]
+		if !ok || used[key] {
+			// Added by hand, or generated before and no longer exported
+			out.Write(text)
+			continue
+		}
+		used[key] = true
+		generatedText := generated[generatedItem.start:generatedItem.end]
+
+		switch {
+		case bytes.Equal(text, generatedText):
+			out.Write(text)
+		case tfvars:
+			// Values are replaced only while they still hold the previously generated value
+			if previous, ok := baseline[item.key]; ok && previous == hashText(text) {
+				out.Write(generatedText)
+				g.update.report.Updated = append(g.update.report.Updated, UpdateChange{File: file, Key: key})
+			} else {
+				out.Write(text)
+				if previous, ok := baseline[item.key]; !ok || previous != hashText(generatedText) {
+					g.update.report.Kept = append(g.update.report.Kept, UpdateChange{File: file, Key: key})
+				}
+			}
+		case key == item.key && baseline[item.key] == hashText(generatedText):
+			// Unchanged upstream: keep local edits
+			out.Write(text)
+		default:
+			out.Write(generatedText)
+			if key == item.key {
+				g.update.report.Updated = append(g.update.report.Updated, UpdateChange{File: file, Key: key})
+			}
+			if previous, ok := baseline[item.key]; ok && previous != hashText(text) {
+				g.update.report.Conflicts = append(g.update.report.Conflicts, UpdateChange{File: file, Key: key})
+			}
+		}
+	}
+	out.Write(existing[prev:])
+
+	// Append blocks that are new to the module
+	for _, item := range items {
+		if used[item.key] {
+			continue
+		}
+		if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+			out.WriteString("\n")
+		}
+		if !tfvars && out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		out.Write(itemText(generated, item))
+		out.WriteString("\n")
+		g.update.report.Added = append(g.update.report.Added, UpdateChange{File: file, Key: item.key})
+	}
+
+	if bytes.Equal(out.Bytes(), existing) {
+		return nil
+	}
+	return os.WriteFile(path, out.Bytes(), 0644)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,143 @@
+package module
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateTestStructure returns a module structure with two variables, named by the given
+// resource names, and one connector instance variable
+func updateTestStructure(config ModuleConfig, first, firstValue, second string) *ModuleStructure {
+	variable := func(name, value string) *model.Resource {
+		resource := model.NewResource("pingone_davinci_variable", name)
+		environment, err := model.Var("pingone_environment_id")
+		if err != nil {
+			panic(err)
+		}
+		resource.Body.SetAttribute("environment_id", environment)
+		resource.Body.SetAttribute("name", model.String(name))
+		resource.Body.SetAttribute("value", model.String(value))
+		return resource
+	}
+	return &ModuleStructure{
+		Config:    config,
+		Resources: []*model.Resource{variable(first, firstValue), variable(second, "b")},
+		Variables: []Variable{
+			{Name: "davinci_connection_http_secret", Type: "string", Description: "Secret", IsSecret: true, Sensitive: true, ResourceType: "connection"},
+		},
+		ImportBlocks: []ImportBlock{
+			{To: "module.ping-export.pingone_davinci_variable." + first, ID: "env/var-1"},
+			{To: "module.ping-export.pingone_davinci_variable." + second, ID: "env/var-2"},
+		},
+	}
+}
+
+func readUpdateFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func writeUpdateFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestGeneratorUpdate(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, ModuleDirName: "ping-export-module", IncludeImports: true}
+	variablesFile := filepath.Join("ping-export-module", "pingone_davinci_variable.tf")
+
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+	assert.FileExists(t, filepath.Join(tmpDir, "ping-export-manifest.json"))
+
+	// Local edits: a hand-edited generated block, a hand-added block and file, and a filled secret
+	variables := readUpdateFile(t, tmpDir, variablesFile)
+	variables = strings.Replace(variables, `name           = "second"`, `name           = "second" # reviewed`, 1)
+	variables += "\nresource \"pingone_davinci_variable\" \"manual\" {\n  name = \"manual\"\n}\n"
+	writeUpdateFile(t, tmpDir, variablesFile, variables)
+	writeUpdateFile(t, tmpDir, "extra.tf", "# hand written\n")
+	tfvars := strings.Replace(readUpdateFile(t, tmpDir, "ping-export-terraform.auto.tfvars"), `davinci_connection_http_secret = ""`, `davinci_connection_http_secret = "s3cret"`, 1)
+	writeUpdateFile(t, tmpDir, "ping-export-terraform.auto.tfvars", tfvars)
+
+	// Upstream: first changes value, second is unchanged
+	report, err := NewGenerator(config).Update(updateTestStructure(config, "first", "changed", "second"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []UpdateChange{{File: "ping-export-module/pingone_davinci_variable.tf", Key: "pingone_davinci_variable.first"}}, report.Updated)
+	assert.Empty(t, report.Added)
+	assert.Empty(t, report.Conflicts)
+	assert.Empty(t, report.Deleted)
+
+	updated := readUpdateFile(t, tmpDir, variablesFile)
+	assert.Contains(t, updated, `value          = "changed"`)
+	assert.Contains(t, updated, `name           = "second" # reviewed`, "local edit to block unchanged upstream is kept")
+	assert.Contains(t, updated, `resource "pingone_davinci_variable" "manual"`, "hand-added block is kept")
+	assert.Equal(t, "# hand written\n", readUpdateFile(t, tmpDir, "extra.tf"))
+	assert.Contains(t, readUpdateFile(t, tmpDir, "ping-export-terraform.auto.tfvars"), `davinci_connection_http_secret = "s3cret"`)
+}
+
+func TestGeneratorUpdate_RenamedAndDeleted(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, ModuleDirName: "ping-export-module", IncludeImports: true}
+
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+
+	// Upstream: var-1 is renamed, var-2 is deleted and var-3 is new
+	structure := updateTestStructure(config, "renamed", "a", "third")
+	structure.ImportBlocks[1].ID = "env/var-3"
+	report, err := NewGenerator(config).Update(structure)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ResourceRename{{From: "pingone_davinci_variable.first", To: "pingone_davinci_variable.renamed"}}, report.Renamed)
+	assert.Equal(t, []string{"pingone_davinci_variable.second"}, report.Deleted)
+	assert.Contains(t, report.Added, UpdateChange{File: "ping-export-module/pingone_davinci_variable.tf", Key: "pingone_davinci_variable.third"})
+
+	variables := readUpdateFile(t, tmpDir, filepath.Join("ping-export-module", "pingone_davinci_variable.tf"))
+	assert.NotContains(t, variables, `"first"`, "renamed block replaces the old one")
+	assert.Contains(t, variables, `resource "pingone_davinci_variable" "renamed"`)
+	assert.Contains(t, variables, `resource "pingone_davinci_variable" "second"`, "deleted resources are left for the user to remove")
+	assert.Contains(t, variables, `resource "pingone_davinci_variable" "third"`)
+
+	assert.Contains(t, readUpdateFile(t, tmpDir, "ping-export-moved.tf"), "from = module.ping-export.pingone_davinci_variable.first")
+
+	imports := readUpdateFile(t, tmpDir, "ping-export-imports.tf")
+	assert.Contains(t, imports, "module.ping-export.pingone_davinci_variable.renamed")
+	assert.NotContains(t, imports, "to = module.ping-export.pingone_davinci_variable.first")
+}
+
+func TestGeneratorUpdate_Conflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, ModuleDirName: "ping-export-module"}
+	variablesFile := filepath.Join("ping-export-module", "pingone_davinci_variable.tf")
+
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+	writeUpdateFile(t, tmpDir, variablesFile, strings.Replace(readUpdateFile(t, tmpDir, variablesFile), `value          = "a"`, `value          = "local"`, 1))
+
+	report, err := NewGenerator(config).Update(updateTestStructure(config, "first", "upstream", "second"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []UpdateChange{{File: "ping-export-module/pingone_davinci_variable.tf", Key: "pingone_davinci_variable.first"}}, report.Conflicts)
+	assert.Contains(t, readUpdateFile(t, tmpDir, variablesFile), `value          = "upstream"`)
+}
+
+func TestGeneratorUpdate_InvalidExistingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := ModuleConfig{OutputDir: tmpDir, ModuleDirName: "ping-export-module"}
+	variablesFile := filepath.Join("ping-export-module", "pingone_davinci_variable.tf")
+
+	require.NoError(t, NewGenerator(config).Generate(updateTestStructure(config, "first", "a", "second")))
+	writeUpdateFile(t, tmpDir, variablesFile, `resource "broken" {`)
+
+	_, err := NewGenerator(config).Update(updateTestStructure(config, "first", "a", "second"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot update ping-export-module/pingone_davinci_variable.tf")
+	assert.Equal(t, `resource "broken" {`, readUpdateFile(t, tmpDir, variablesFile), "unparseable files are not overwritten")
+}
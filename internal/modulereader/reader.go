@@ -0,0 +1,131 @@
+// Package modulereader reads back the files of a generated Terraform module, for the commands
+// that parse modules rather than write them, such as drift and convert. Both read the same
+// files the same way: the .tf files of the child module, and the .tf and .tfvars files of the
+// root module in its parent directory, where the generator writes the module call, import
+// blocks and variable values.
+package modulereader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// File is a configuration file parsed in native syntax
+type File struct {
+	Path string
+	Src  []byte
+	Body *hclsyntax.Body
+}
+
+// Module holds the parsed files of a child module and of its root module, each in file name order
+type Module struct {
+	Dir       string
+	Files     []*File // .tf files of the child module
+	RootDir   string
+	RootFiles []*File // .tf files of the root module
+	VarFiles  []*File // .tfvars files of the root module
+}
+
+// jsonSyntaxPatterns match configuration files in JSON syntax, such as those generated with
+// --format json
+var jsonSyntaxPatterns = []string{"*.tf.json", "*.tfvars.json"}
+
+// Read parses the child module in dir and the root module in its parent directory. Modules
+// in JSON syntax are rejected rather than read without those files.
+func Read(dir string) (*Module, error) {
+	module := &Module{Dir: dir, RootDir: filepath.Dir(filepath.Clean(dir))}
+	for _, d := range []string{module.Dir, module.RootDir} {
+		if err := RejectJSONSyntax(d); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if module.Files, err = parseFiles(module.Dir, "*.tf"); err != nil {
+		return nil, err
+	}
+	if len(module.Files) == 0 {
+		return nil, fmt.Errorf("no .tf files found in %s", dir)
+	}
+	if module.RootFiles, err = parseFiles(module.RootDir, "*.tf"); err != nil {
+		return nil, err
+	}
+	if module.VarFiles, err = parseFiles(module.RootDir, "*.tfvars"); err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// RejectJSONSyntax returns an error if dir holds configuration files in JSON syntax. Only
+// native syntax is read, and a module is rejected rather than read without those files.
+func RejectJSONSyntax(dir string) error {
+	for _, pattern := range jsonSyntaxPatterns {
+		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return fmt.Errorf("failed to list module files: %w", err)
+		}
+		if len(files) > 0 {
+			return fmt.Errorf("%s is in JSON syntax: only modules in HCL syntax can be read, generate the module with --format hcl", files[0])
+		}
+	}
+	return nil
+}
+
+// parseFiles parses the files of dir matching pattern, in file name order
+func parseFiles(dir, pattern string) ([]*File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list module files: %w", err)
+	}
+	sort.Strings(paths)
+
+	files := make([]*File, 0, len(paths))
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		file, err := ParseFile(src, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// ParseFile parses a configuration file in native syntax
+func ParseFile(src []byte, filename string) (*File, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %s", filename, diags.Error())
+	}
+	return &File{Path: filename, Src: src, Body: file.Body.(*hclsyntax.Body)}, nil
+}
+
+// Resources returns the resource blocks of the file, in file order
+func (f *File) Resources() []*hclsyntax.Block {
+	var blocks []*hclsyntax.Block
+	for _, block := range f.Body.Blocks {
+		if block.Type == "resource" && len(block.Labels) == 2 {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// Blocks returns the blocks of the file of a type, in file order
+func (f *File) Blocks(blockType string) []*hclsyntax.Block {
+	var blocks []*hclsyntax.Block
+	for _, block := range f.Body.Blocks {
+		if block.Type == blockType {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
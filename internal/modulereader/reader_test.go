@@ -0,0 +1,86 @@
+package modulereader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes files given by path relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// paths returns the base names of files
+func paths(files []*File) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	return names
+}
+
+func TestRead(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"child/variables.tf":    "variable \"name\" {}\n",
+		"child/b.tf":            "resource \"pingone_davinci_flow\" \"b\" {}\nimport {\n  to = pingone_davinci_flow.b\n  id = \"env/b\"\n}\n",
+		"child/a.tf":            "resource \"pingone_davinci_flow\" \"a\" {}\nresource \"pingone_davinci_flow\" {}\n",
+		"child/notes.txt":       "not configuration",
+		"module.tf":             "module \"child\" {\n  source = \"./child\"\n}\n",
+		"terraform.auto.tfvars": "name = \"x\"\n",
+	})
+
+	module, err := Read(filepath.Join(root, "child"))
+	require.NoError(t, err)
+	assert.Equal(t, root, module.RootDir)
+	assert.Equal(t, []string{"a.tf", "b.tf", "variables.tf"}, paths(module.Files))
+	assert.Equal(t, []string{"module.tf"}, paths(module.RootFiles))
+	assert.Equal(t, []string{"terraform.auto.tfvars"}, paths(module.VarFiles))
+
+	assert.Len(t, module.Files[0].Resources(), 1, "resource blocks need a type and a name")
+	assert.Len(t, module.Files[1].Blocks("import"), 1)
+	assert.Empty(t, module.Files[2].Resources())
+
+	t.Run("Returns error without .tf files", func(t *testing.T) {
+		_, err := Read(t.TempDir())
+		assert.ErrorContains(t, err, "no .tf files found")
+	})
+
+	t.Run("Returns error for invalid files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"child/main.tf": "terraform {}\n", "broken.tfvars": "name = "})
+		_, err := Read(filepath.Join(dir, "child"))
+		assert.ErrorContains(t, err, "failed to parse")
+	})
+
+	t.Run("Rejects JSON syntax in the root module", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"child/main.tf": "terraform {}\n", "module.tf.json": "{}"})
+		_, err := Read(filepath.Join(dir, "child"))
+		assert.ErrorContains(t, err, "module.tf.json is in JSON syntax")
+	})
+}
+
+func TestRejectJSONSyntax(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("terraform {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ping-export-manifest.json"), []byte("{}"), 0644))
+	assert.NoError(t, RejectJSONSyntax(dir), "native syntax and other JSON files are accepted")
+
+	for _, file := range []string{"pingone_davinci_flow.tf.json", "terraform.auto.tfvars.json"} {
+		t.Run(file, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("{}"), 0644))
+			assert.ErrorContains(t, RejectJSONSyntax(dir), file+" is in JSON syntax")
+		})
+	}
+}
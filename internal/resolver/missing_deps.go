@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -57,16 +58,20 @@ type MissingDependencyTracker struct {
 	missing []MissingDependency
 
 	// For tracking excluded/not-included resources
-	excludedResources map[string]map[string]bool // type -> id -> true
-	includedTypes     map[string]bool            // type -> included
+	excludedResources    map[string]map[string]bool // type -> id -> true
+	notIncludedResources map[string]map[string]bool // type -> id -> true
+	includedTypes        map[string]bool            // type -> included
+	resourceNames        map[string]string          // type:id -> human-readable name
 }
 
 // NewMissingDependencyTracker creates a new tracker
 func NewMissingDependencyTracker() *MissingDependencyTracker {
 	return &MissingDependencyTracker{
-		missing:           []MissingDependency{},
-		excludedResources: make(map[string]map[string]bool),
-		includedTypes:     make(map[string]bool),
+		missing:              []MissingDependency{},
+		excludedResources:    make(map[string]map[string]bool),
+		notIncludedResources: make(map[string]map[string]bool),
+		includedTypes:        make(map[string]bool),
+		resourceNames:        make(map[string]string),
 	}
 }
 
@@ -78,6 +83,25 @@ func (t *MissingDependencyTracker) MarkExcluded(resourceType, resourceID string)
 	t.excludedResources[resourceType][resourceID] = true
 }
 
+// MarkNotIncluded marks a resource as not matching the --include filters
+func (t *MissingDependencyTracker) MarkNotIncluded(resourceType, resourceID string) {
+	if t.notIncludedResources[resourceType] == nil {
+		t.notIncludedResources[resourceType] = make(map[string]bool)
+	}
+	t.notIncludedResources[resourceType][resourceID] = true
+}
+
+// SetResourceName records the human-readable name of a resource that is not exported,
+// so TODO placeholders and the summary report can show it
+func (t *MissingDependencyTracker) SetResourceName(resourceType, resourceID, name string) {
+	t.resourceNames[makeKey(resourceType, resourceID)] = name
+}
+
+// GetResourceName returns the name recorded with SetResourceName, or "" if unknown
+func (t *MissingDependencyTracker) GetResourceName(resourceType, resourceID string) string {
+	return t.resourceNames[makeKey(resourceType, resourceID)]
+}
+
 // SetIncludedTypes sets which resource types are included in export
 func (t *MissingDependencyTracker) SetIncludedTypes(types []string) {
 	t.includedTypes = make(map[string]bool)
@@ -95,6 +119,13 @@ func (t *MissingDependencyTracker) DetermineMissingReason(resourceType, resource
 		}
 	}
 
+	// Check if resource didn't match the include filters
+	if typeMap, exists := t.notIncludedResources[resourceType]; exists {
+		if typeMap[resourceID] {
+			return NotIncluded
+		}
+	}
+
 	// Check if type not included in export
 	if len(t.includedTypes) > 0 && !t.includedTypes[resourceType] {
 		return NotIncluded
@@ -104,14 +135,15 @@ func (t *MissingDependencyTracker) DetermineMissingReason(resourceType, resource
 	return NotFound
 }
 
-// RecordMissing records a missing dependency
+// RecordMissing records a missing dependency. A reference resolved again, e.g. when HCL is
+// regenerated for a module, is recorded once.
 func (t *MissingDependencyTracker) RecordMissing(
 	fromType, fromID, fromName string,
 	toType, toID, toName string,
 	reason MissingReason,
 	fieldName, location string,
 ) {
-	t.missing = append(t.missing, MissingDependency{
+	dep := MissingDependency{
 		FromType:  fromType,
 		FromID:    fromID,
 		FromName:  fromName,
@@ -121,7 +153,11 @@ func (t *MissingDependencyTracker) RecordMissing(
 		Reason:    reason,
 		FieldName: fieldName,
 		Location:  location,
-	})
+	}
+	if slices.Contains(t.missing, dep) {
+		return
+	}
+	t.missing = append(t.missing, dep)
 }
 
 // GetMissing returns all missing dependencies
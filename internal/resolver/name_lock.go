@@ -0,0 +1,167 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
+)
+
+// NameLockFile is the file name of the name lock written next to a generated module
+const NameLockFile = "names.lock.json"
+
+// nameLockVersion is the version of the name lock file format
+const nameLockVersion = 1
+
+// NameLock pins the Terraform resource name of exported resources across exports, so names
+// do not depend on the order resources are returned by the API
+type NameLock struct {
+	Version int `json:"version"`
+
+	// NamingStrategy is the naming strategy the names were derived with; empty for locks written
+	// before it was recorded, which used the pingcli strategy
+	NamingStrategy string `json:"naming_strategy,omitempty"`
+
+	// Resources maps resource type -> resource ID -> Terraform resource name
+	Resources map[string]map[string]string `json:"resources"`
+}
+
+// NewNameLock creates an empty name lock
+func NewNameLock() *NameLock {
+	return &NameLock{
+		Version:   nameLockVersion,
+		Resources: make(map[string]map[string]string),
+	}
+}
+
+// ReadNameLock reads a name lock file. A missing file yields an empty lock.
+func ReadNameLock(path string) (*NameLock, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewNameLock(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read name lock: %w", err)
+	}
+
+	lock := NewNameLock()
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse name lock %s: %w", path, err)
+	}
+	if lock.Version != nameLockVersion {
+		return nil, fmt.Errorf("unsupported name lock version %d in %s", lock.Version, path)
+	}
+	if lock.Resources == nil {
+		lock.Resources = make(map[string]map[string]string)
+	}
+
+	// Names are unique across resource types, as in the dependency graph
+	owners := make(map[string]string)
+	for _, resourceType := range slices.Sorted(maps.Keys(lock.Resources)) {
+		ids := lock.Resources[resourceType]
+		for _, id := range slices.Sorted(maps.Keys(ids)) {
+			name := ids[id]
+			if name == "" {
+				return nil, fmt.Errorf("invalid name lock %s: empty name for %s %s", path, resourceType, id)
+			}
+			owner := resourceType + " " + id
+			if previous, ok := owners[name]; ok {
+				return nil, fmt.Errorf("invalid name lock %s: name %q is locked by both %s and %s", path, name, previous, owner)
+			}
+			owners[name] = owner
+		}
+	}
+	return lock, nil
+}
+
+// RecordedNamingStrategy returns the naming strategy of the locked names, or "" for an empty lock
+func (l *NameLock) RecordedNamingStrategy() string {
+	if l == nil || (l.NamingStrategy == "" && len(l.Resources) == 0) {
+		return ""
+	}
+	if l.NamingStrategy == "" {
+		return utils.NamingStrategyPingCLI
+	}
+	return l.NamingStrategy
+}
+
+// Lookup returns the locked name of a resource. A nil lock has no entries.
+func (l *NameLock) Lookup(resourceType, id string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	name, ok := l.Resources[resourceType][id]
+	return name, ok
+}
+
+// Set locks the name of a resource
+func (l *NameLock) Set(resourceType, id, name string) {
+	if l.Resources[resourceType] == nil {
+		l.Resources[resourceType] = make(map[string]string)
+	}
+	l.Resources[resourceType][id] = name
+}
+
+// Merge adds the entries of previous for resources not in l whose name is still free.
+// Used after a partial export so resources left out keep their names.
+func (l *NameLock) Merge(previous *NameLock) {
+	if previous == nil {
+		return
+	}
+	used := make(map[string]bool)
+	for _, ids := range l.Resources {
+		for _, name := range ids {
+			used[name] = true
+		}
+	}
+	for resourceType, ids := range previous.Resources {
+		for id, name := range ids {
+			if _, ok := l.Lookup(resourceType, id); ok || used[name] {
+				continue
+			}
+			l.Set(resourceType, id, name)
+			used[name] = true
+		}
+	}
+}
+
+// Write writes the name lock to path. Entries are sorted so the file diffs cleanly.
+func (l *NameLock) Write(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode name lock: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write name lock: %w", err)
+	}
+	return nil
+}
+
+// SetNameLock makes resources added afterwards keep their locked name. Every locked name is
+// reserved up front, so new resources get suffixes that never take a locked name, whatever
+// order resources are added in.
+func (g *DependencyGraph) SetNameLock(lock *NameLock) {
+	g.nameLock = lock
+	if lock == nil {
+		return
+	}
+	for _, ids := range lock.Resources {
+		for _, name := range ids {
+			g.takenNames[name] = true
+		}
+	}
+}
+
+// NameLock returns a name lock with the name of every resource in the graph and its naming strategy
+func (g *DependencyGraph) NameLock() *NameLock {
+	lock := NewNameLock()
+	lock.NamingStrategy = g.naming.Name()
+	for _, ref := range g.resources {
+		lock.Set(ref.Type, ref.ID, ref.Name)
+	}
+	return lock
+}
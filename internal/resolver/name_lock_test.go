@@ -0,0 +1,183 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
+)
+
+func TestAddResourceWithNameLock(t *testing.T) {
+	lock := NewNameLock()
+	lock.Set("pingone_davinci_flow", "flow-2", "login")
+	lock.Set("pingone_davinci_flow", "flow-3", "login_2")
+
+	graph := NewDependencyGraph()
+	graph.SetNameLock(lock)
+
+	// Unlocked resources arrive first but never take a locked name
+	graph.AddResource("pingone_davinci_flow", "flow-1", "login")
+	graph.AddResource("pingone_davinci_flow", "flow-2", "renamed")
+	graph.AddResource("pingone_davinci_flow", "flow-3", "login")
+	graph.AddResource("pingone_davinci_variable", "var-1", "login")
+
+	tests := []struct {
+		resourceType string
+		id           string
+		expected     string
+	}{
+		{"pingone_davinci_flow", "flow-1", "login_3"},
+		{"pingone_davinci_flow", "flow-2", "login"},
+		{"pingone_davinci_flow", "flow-3", "login_2"},
+		{"pingone_davinci_variable", "var-1", "login_4"},
+	}
+	for _, tt := range tests {
+		name, err := graph.GetReferenceName(tt.resourceType, tt.id)
+		if err != nil {
+			t.Fatalf("GetReferenceName(%s, %s) returned error: %v", tt.resourceType, tt.id, err)
+		}
+		if name != tt.expected {
+			t.Errorf("%s %s: expected %q, got %q", tt.resourceType, tt.id, tt.expected, name)
+		}
+	}
+
+	got := graph.NameLock()
+	if name, _ := got.Lookup("pingone_davinci_flow", "flow-1"); name != "login_3" {
+		t.Errorf("Expected graph lock to record login_3 for flow-1, got %q", name)
+	}
+	if len(got.Resources["pingone_davinci_flow"]) != 3 || len(got.Resources["pingone_davinci_variable"]) != 1 {
+		t.Errorf("Expected graph lock to record every resource, got %v", got.Resources)
+	}
+}
+
+func TestEnsureUniqueNameSkipsTakenSuffix(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddResource("pingone_davinci_flow", "flow-1", "login_2")
+	graph.AddResource("pingone_davinci_flow", "flow-2", "login")
+	graph.AddResource("pingone_davinci_flow", "flow-3", "login")
+
+	name, _ := graph.GetReferenceName("pingone_davinci_flow", "flow-3")
+	if name != "login_3" {
+		t.Errorf("Expected login_3, got %q", name)
+	}
+}
+
+func TestReadNameLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, NameLockFile)
+
+	lock, err := ReadNameLock(path)
+	if err != nil {
+		t.Fatalf("ReadNameLock on missing file returned error: %v", err)
+	}
+	if len(lock.Resources) != 0 {
+		t.Errorf("Expected empty lock, got %v", lock.Resources)
+	}
+
+	lock.Set("pingone_davinci_flow", "flow-1", "login")
+	lock.Set("pingone_davinci_variable", "var-1", "company")
+	if err := lock.Write(path); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	read, err := ReadNameLock(path)
+	if err != nil {
+		t.Fatalf("ReadNameLock returned error: %v", err)
+	}
+	if name, ok := read.Lookup("pingone_davinci_variable", "var-1"); !ok || name != "company" {
+		t.Errorf("Expected company for var-1, got %q", name)
+	}
+
+	invalid := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"malformed", `{`, "failed to parse name lock"},
+		{"version", `{"version": 2, "resources": {}}`, "unsupported name lock version 2"},
+		{"empty name", `{"version": 1, "resources": {"pingone_davinci_flow": {"flow-1": ""}}}`, "empty name for pingone_davinci_flow flow-1"},
+		{"duplicate name", `{"version": 1, "resources": {"pingone_davinci_flow": {"flow-1": "login"}, "pingone_davinci_variable": {"var-1": "login"}}}`, `name "login" is locked by both pingone_davinci_flow flow-1 and pingone_davinci_variable var-1`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ReadNameLock(path)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestNameLockMerge(t *testing.T) {
+	previous := NewNameLock()
+	previous.Set("pingone_davinci_flow", "flow-1", "login")
+	previous.Set("pingone_davinci_flow", "flow-2", "signup")
+	previous.Set("pingone_davinci_variable", "var-1", "taken")
+
+	lock := NewNameLock()
+	lock.Set("pingone_davinci_flow", "flow-1", "login_2")
+	lock.Set("pingone_davinci_flow", "flow-3", "taken")
+	lock.Merge(previous)
+
+	expected := map[string]string{"flow-1": "login_2", "flow-2": "signup", "flow-3": "taken"}
+	for id, name := range expected {
+		if got, _ := lock.Lookup("pingone_davinci_flow", id); got != name {
+			t.Errorf("%s: expected %q, got %q", id, name, got)
+		}
+	}
+	if _, ok := lock.Lookup("pingone_davinci_variable", "var-1"); ok {
+		t.Error("Expected entry whose name is taken not to be merged")
+	}
+}
+
+func TestAddResourceWithNamingStrategy(t *testing.T) {
+	strategy, err := utils.NewNamingStrategy(utils.NamingStrategyKebab)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock := NewNameLock()
+	lock.Set("pingone_davinci_flow", "flow-3", "my-flow-2")
+
+	graph := NewDependencyGraph()
+	graph.SetNamingStrategy(strategy)
+	graph.SetNameLock(lock)
+	for _, id := range []string{"flow-1", "flow-2", "flow-3"} {
+		graph.AddResource("pingone_davinci_flow", id, graph.ResourceName(id, "My Flow"))
+	}
+
+	for id, expected := range map[string]string{"flow-1": "my-flow", "flow-2": "my-flow-3", "flow-3": "my-flow-2"} {
+		if name, _ := graph.GetReferenceName("pingone_davinci_flow", id); name != expected {
+			t.Errorf("%s: expected %q, got %q", id, expected, name)
+		}
+	}
+
+	if got := graph.NameLock().NamingStrategy; got != utils.NamingStrategyKebab {
+		t.Errorf("Expected graph lock to record the kebab strategy, got %q", got)
+	}
+	if got := SanitizeName("My Flow", nil); got != "pingcli__My-0020-Flow" {
+		t.Errorf("Expected nil graph to use the pingcli strategy, got %q", got)
+	}
+}
+
+func TestRecordedNamingStrategy(t *testing.T) {
+	lock := NewNameLock()
+	if got := lock.RecordedNamingStrategy(); got != "" {
+		t.Errorf("Expected empty lock to record no strategy, got %q", got)
+	}
+
+	// Locks written before the strategy was recorded used pingcli names
+	lock.Set("pingone_davinci_flow", "flow-1", "pingcli__Login")
+	if got := lock.RecordedNamingStrategy(); got != utils.NamingStrategyPingCLI {
+		t.Errorf("Expected pingcli for a lock without a strategy, got %q", got)
+	}
+
+	lock.NamingStrategy = utils.NamingStrategySnake
+	if got := lock.RecordedNamingStrategy(); got != utils.NamingStrategySnake {
+		t.Errorf("Expected snake, got %q", got)
+	}
+}
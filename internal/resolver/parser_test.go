@@ -354,3 +354,40 @@ func TestFindReferencesInFlow(t *testing.T) {
 		t.Error("Did not find expected connection dependency")
 	}
 }
+
+func TestFindReferencesInFlowSubflowSelection(t *testing.T) {
+	// Subflow nodes store the selected flow as {"value": {"label": <name>, "value": <flowId>}}
+	flowData := map[string]interface{}{
+		"graphData": map[string]interface{}{
+			"elements": map[string]interface{}{
+				"nodes": []interface{}{
+					map[string]interface{}{
+						"data": map[string]interface{}{
+							"connectionId": "conn-123",
+							"properties": map[string]interface{}{
+								"subFlowId": map[string]interface{}{
+									"value": map[string]interface{}{"label": "MFA subflow", "value": "flow-789"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	deps, err := FindReferencesInFlow("my-flow", flowData)
+	if err != nil {
+		t.Fatalf("FindReferencesInFlow() error = %v", err)
+	}
+
+	found := false
+	for _, dep := range deps {
+		if dep.To.Type == "pingone_davinci_flow" && dep.To.ID == "flow-789" && dep.Field == "subflow_id" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Did not find subflow dependency in %v", deps)
+	}
+}
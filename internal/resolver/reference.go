@@ -20,3 +20,34 @@ func GenerateTerraformReference(graph *DependencyGraph, resourceType, resourceID
 func GenerateTODOPlaceholder(resourceType, resourceID string, err error) string {
 	return fmt.Sprintf(`"" # TODO: Reference to %s %s not found - %v`, resourceType, resourceID, err)
 }
+
+// ResolveReference returns a Terraform reference from one resource to another, or a TODO
+// placeholder if the target is not in the graph. When a MissingDependencyTracker is attached
+// to the graph, the missing reference is recorded and the placeholder states why it is missing
+// (excluded, not included or not found).
+func ResolveReference(graph *DependencyGraph, from ResourceRef, toType, toID, attribute, fieldName, location string) string {
+	ref, err := GenerateTerraformReference(graph, toType, toID, attribute)
+	if err == nil {
+		return ref
+	}
+
+	tracker := graph.MissingTracker()
+	if tracker == nil {
+		return GenerateTODOPlaceholder(toType, toID, err)
+	}
+
+	dep := MissingDependency{
+		FromType:  from.Type,
+		FromID:    from.ID,
+		FromName:  from.Name,
+		ToType:    toType,
+		ToID:      toID,
+		ToName:    tracker.GetResourceName(toType, toID),
+		Reason:    tracker.DetermineMissingReason(toType, toID, graph),
+		FieldName: fieldName,
+		Location:  location,
+	}
+	tracker.RecordMissing(dep.FromType, dep.FromID, dep.FromName, dep.ToType, dep.ToID, dep.ToName, dep.Reason, dep.FieldName, dep.Location)
+
+	return GenerateTODOPlaceholderWithReason(dep)
+}
@@ -93,3 +93,60 @@ func TestGenerateTODOPlaceholder(t *testing.T) {
 		t.Errorf("Expected resource ID in placeholder, got: %s", result)
 	}
 }
+
+func TestResolveReference(t *testing.T) {
+	from := ResourceRef{Type: "pingone_davinci_flow", ID: "flow-1", Name: "pingcli__login"}
+
+	t.Run("Resolves resources in the graph", func(t *testing.T) {
+		graph := NewDependencyGraph()
+		graph.AddResource("pingone_davinci_connector_instance", "conn-1", "pingcli__http")
+
+		result := ResolveReference(graph, from, "pingone_davinci_connector_instance", "conn-1", "id", "connectionId", "")
+		if result != "pingone_davinci_connector_instance.pingcli__http.id" {
+			t.Errorf("Unexpected reference: %s", result)
+		}
+	})
+
+	t.Run("Falls back to generic TODO without tracker", func(t *testing.T) {
+		graph := NewDependencyGraph()
+
+		result := ResolveReference(graph, from, "pingone_davinci_connector_instance", "conn-1", "id", "connectionId", "")
+		if !strings.Contains(result, "TODO: Reference to pingone_davinci_connector_instance conn-1 not found") {
+			t.Errorf("Unexpected placeholder: %s", result)
+		}
+	})
+
+	t.Run("Records excluded references on the tracker", func(t *testing.T) {
+		graph := NewDependencyGraph()
+		tracker := NewMissingDependencyTracker()
+		tracker.MarkExcluded("pingone_davinci_connector_instance", "conn-1")
+		tracker.SetResourceName("pingone_davinci_connector_instance", "conn-1", "Http")
+		graph.SetMissingTracker(tracker)
+
+		result := ResolveReference(graph, from, "pingone_davinci_connector_instance", "conn-1", "id", "connectionId", "graphData.elements.nodes[n1].data.connectionId")
+		expected := `"" # TODO: Reference to "Http" (pingone_davinci_connector_instance conn-1) was excluded from export`
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+
+		missing := tracker.GetMissing()
+		if len(missing) != 1 {
+			t.Fatalf("Expected 1 missing dependency, got %d", len(missing))
+		}
+		if missing[0].FromID != "flow-1" || missing[0].Reason != Excluded || missing[0].FieldName != "connectionId" {
+			t.Errorf("Unexpected missing dependency: %+v", missing[0])
+		}
+	})
+
+	t.Run("Reports resources outside the include filters", func(t *testing.T) {
+		graph := NewDependencyGraph()
+		tracker := NewMissingDependencyTracker()
+		tracker.MarkNotIncluded("pingone_davinci_application", "app-1")
+		graph.SetMissingTracker(tracker)
+
+		result := ResolveReference(graph, from, "pingone_davinci_application", "app-1", "id", "applicationId", "")
+		if !strings.Contains(result, "was not included in export filters") {
+			t.Errorf("Unexpected placeholder: %s", result)
+		}
+	})
+}
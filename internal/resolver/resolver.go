@@ -2,6 +2,9 @@ package resolver
 
 import (
 	"fmt"
+	"sort"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
 )
 
 // ResourceRef represents a reference to a DaVinci resource
@@ -23,7 +26,12 @@ type Dependency struct {
 type DependencyGraph struct {
 	resources    map[string]ResourceRef // ID -> ResourceRef (composite key: type:id)
 	dependencies []Dependency
-	nameUsage    map[string]int // Track name usage for uniqueness
+	nameUsage    map[string]int  // Track name usage for uniqueness
+	takenNames   map[string]bool // Names assigned or reserved by the name lock
+	nameLock     *NameLock
+	naming       utils.NamingStrategy
+	missing      *MissingDependencyTracker
+	variables    map[string]map[string]string // Variable name -> context -> variable ID
 }
 
 // NewDependencyGraph creates a new dependency graph
@@ -32,15 +40,45 @@ func NewDependencyGraph() *DependencyGraph {
 		resources:    make(map[string]ResourceRef),
 		dependencies: make([]Dependency, 0),
 		nameUsage:    make(map[string]int),
+		takenNames:   make(map[string]bool),
+		naming:       utils.DefaultNamingStrategy(),
+		variables:    make(map[string]map[string]string),
+	}
+}
+
+// SetNamingStrategy sets the strategy deriving resource names and their deduplication suffix;
+// nil selects the pingcli strategy
+func (g *DependencyGraph) SetNamingStrategy(strategy utils.NamingStrategy) {
+	if strategy == nil {
+		strategy = utils.DefaultNamingStrategy()
+	}
+	g.naming = strategy
+}
+
+// NamingStrategy returns the strategy deriving resource names
+func (g *DependencyGraph) NamingStrategy() utils.NamingStrategy {
+	return g.naming
+}
+
+// ResourceName derives a resource name from the resource ID and display name components using
+// the graph's naming strategy, or the pingcli strategy for a nil graph. Uniqueness is applied
+// when the resource is added.
+func (g *DependencyGraph) ResourceName(id string, keys ...string) string {
+	if g == nil {
+		return utils.DefaultNamingStrategy().ResourceName(id, keys...)
 	}
+	return g.naming.ResourceName(id, keys...)
 }
 
 // AddResource registers a resource in the graph with a pre-sanitized name
 // The name should already be sanitized using SanitizeName() before calling this method
-// This method handles uniqueness tracking automatically
+// This method handles uniqueness tracking automatically; resources in the name lock keep their locked name
 func (g *DependencyGraph) AddResource(resourceType, id, name string) {
 	// Ensure uniqueness (name should already be sanitized by caller)
-	uniqueName := g.ensureUniqueName(name)
+	uniqueName, locked := g.nameLock.Lookup(resourceType, id)
+	if !locked {
+		uniqueName = g.ensureUniqueName(name)
+	}
 	key := makeKey(resourceType, id)
 	g.resources[key] = ResourceRef{
 		Type: resourceType,
@@ -49,6 +87,37 @@ func (g *DependencyGraph) AddResource(resourceType, id, name string) {
 	}
 }
 
+// AddVariableName records the name and context of a variable registered with AddResource, as
+// flows refer to variables by name in node properties
+func (g *DependencyGraph) AddVariableName(id, name, context string) {
+	if g.variables[name] == nil {
+		g.variables[name] = make(map[string]string)
+	}
+	g.variables[name][context] = id
+}
+
+// LookupVariable returns the variable with the given name and context
+func (g *DependencyGraph) LookupVariable(name, context string) (ResourceRef, bool) {
+	id, ok := g.variables[name][context]
+	if !ok {
+		return ResourceRef{}, false
+	}
+	ref, ok := g.resources[makeKey("pingone_davinci_variable", id)]
+	return ref, ok
+}
+
+// VariableContexts returns the contexts of the variables in the graph with the given name, sorted
+func (g *DependencyGraph) VariableContexts(name string) []string {
+	var contexts []string
+	for context := range g.variables[name] {
+		if _, ok := g.LookupVariable(name, context); ok {
+			contexts = append(contexts, context)
+		}
+	}
+	sort.Strings(contexts)
+	return contexts
+}
+
 // AddDependency registers a dependency relationship
 func (g *DependencyGraph) AddDependency(from, to ResourceRef, field, location string) {
 	g.dependencies = append(g.dependencies, Dependency{
@@ -112,21 +181,42 @@ func (g *DependencyGraph) GetAllDependencies() []Dependency {
 	return g.dependencies
 }
 
-// ensureUniqueName tracks name usage and appends suffix if duplicate
+// SetMissingTracker attaches a tracker that records references to resources missing from the graph
+func (g *DependencyGraph) SetMissingTracker(tracker *MissingDependencyTracker) {
+	g.missing = tracker
+}
+
+// MissingTracker returns the attached missing dependency tracker, or nil if none is attached
+func (g *DependencyGraph) MissingTracker() *MissingDependencyTracker {
+	return g.missing
+}
+
+// ensureUniqueName tracks name usage and appends suffix if duplicate (pingcli strategy shown)
 // First usage: "my_name" -> "my_name"
 // Second usage: "my_name" -> "my_name_2"
 // Third usage: "my_name" -> "my_name_3"
+// Suffixes already taken (including names reserved by the name lock) are skipped
 func (g *DependencyGraph) ensureUniqueName(name string) string {
 	count, exists := g.nameUsage[name]
-	if !exists {
+	if !exists && !g.takenNames[name] {
 		// First usage - register and return as-is
 		g.nameUsage[name] = 1
+		g.takenNames[name] = true
 		return name
 	}
+	if !exists {
+		count = 1
+	}
 
 	// Duplicate - increment and append suffix
-	g.nameUsage[name] = count + 1
-	return fmt.Sprintf("%s_%d", name, count+1)
+	unique := name
+	for g.takenNames[unique] {
+		count++
+		unique = g.naming.Deduplicate(name, count)
+	}
+	g.nameUsage[name] = count
+	g.takenNames[unique] = true
+	return unique
 }
 
 // makeKey creates a composite key for resource lookup
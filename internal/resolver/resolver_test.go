@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -105,6 +106,45 @@ func TestGetReferenceName(t *testing.T) {
 	}
 }
 
+func TestLookupVariable(t *testing.T) {
+	graph := NewDependencyGraph()
+
+	graph.AddResource("pingone_davinci_variable", "var-1", "pingcli__origin_company")
+	graph.AddVariableName("var-1", "origin", "company")
+	graph.AddVariableName("var-2", "origin", "flowInstance")
+
+	ref, ok := graph.LookupVariable("origin", "company")
+	if !ok || ref.ID != "var-1" || ref.Name != "pingcli__origin_company" {
+		t.Errorf("LookupVariable() = %v, %v, expected var-1", ref, ok)
+	}
+
+	// Names are scoped by context, and variables must be in the graph
+	if _, ok := graph.LookupVariable("origin", "flow"); ok {
+		t.Error("LookupVariable() found a variable in the wrong context")
+	}
+	if _, ok := graph.LookupVariable("origin", "flowInstance"); ok {
+		t.Error("LookupVariable() found a variable missing from the graph")
+	}
+}
+
+func TestVariableContexts(t *testing.T) {
+	graph := NewDependencyGraph()
+
+	graph.AddResource("pingone_davinci_variable", "var-1", "origin_company")
+	graph.AddResource("pingone_davinci_variable", "var-2", "origin_user")
+	graph.AddVariableName("var-1", "origin", "company")
+	graph.AddVariableName("var-2", "origin", "user")
+	graph.AddVariableName("var-3", "origin", "flowInstance")
+
+	// Variables missing from the graph have no context
+	if got := graph.VariableContexts("origin"); !reflect.DeepEqual(got, []string{"company", "user"}) {
+		t.Errorf("VariableContexts() = %v, expected [company user]", got)
+	}
+	if got := graph.VariableContexts("unknown"); len(got) != 0 {
+		t.Errorf("VariableContexts() = %v, expected none", got)
+	}
+}
+
 func TestAddDependency(t *testing.T) {
 	graph := NewDependencyGraph()
 
@@ -48,6 +48,14 @@ func GetFlowDependencySchema() ResourceDependencySchema {
 				IsOptional:  true,
 				Description: "Subflow referenced by flow node",
 			},
+			{
+				Path:        "graphData.elements.nodes[*].data.properties.subFlowId.value.value",
+				TargetType:  "pingone_davinci_flow",
+				FieldName:   "subflow_id",
+				IsArray:     true,
+				IsOptional:  true,
+				Description: "Subflow selected in flow node properties ({\"value\": {\"label\", \"value\"}})",
+			},
 			// Note: Variable references can appear in many places in node properties
 			// We may need more sophisticated parsing for complex property structures
 		},
@@ -0,0 +1,142 @@
+// Copyright © 2025 Ping Identity Corporation
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Naming strategy names accepted by NewNamingStrategy
+const (
+	NamingStrategyPingCLI  = "pingcli"
+	NamingStrategySnake    = "snake"
+	NamingStrategyKebab    = "kebab"
+	NamingStrategyIDSuffix = "id-suffix"
+)
+
+// NamingStrategy derives Terraform resource names from resource display names
+type NamingStrategy interface {
+	// Name returns the strategy name, as accepted by NewNamingStrategy
+	Name() string
+
+	// ResourceName derives the resource name from the resource ID and the components of its
+	// display name (e.g. a variable's name and context). Uniqueness is not applied.
+	ResourceName(id string, keys ...string) string
+
+	// Deduplicate returns the name of the nth resource (n >= 2) deriving the same name
+	Deduplicate(name string, n int) string
+}
+
+// NamingStrategies returns the names of the supported naming strategies
+func NamingStrategies() []string {
+	return []string{NamingStrategyPingCLI, NamingStrategySnake, NamingStrategyKebab, NamingStrategyIDSuffix}
+}
+
+// NewNamingStrategy returns the naming strategy with the given name; an empty name selects
+// the pingcli strategy
+func NewNamingStrategy(name string) (NamingStrategy, error) {
+	switch name {
+	case "", NamingStrategyPingCLI:
+		return pingcliNaming{}, nil
+	case NamingStrategySnake:
+		return delimitedNaming{name: NamingStrategySnake, separator: "_"}, nil
+	case NamingStrategyKebab:
+		return delimitedNaming{name: NamingStrategyKebab, separator: "-"}, nil
+	case NamingStrategyIDSuffix:
+		return idSuffixNaming{}, nil
+	}
+	return nil, fmt.Errorf("unsupported naming strategy: %s. Supported: %s", name, strings.Join(NamingStrategies(), ", "))
+}
+
+// DefaultNamingStrategy returns the pingcli naming strategy
+func DefaultNamingStrategy() NamingStrategy {
+	return pingcliNaming{}
+}
+
+// pingcliNaming hex encodes special characters and prefixes names with "pingcli__", matching
+// pingcli's own export
+//
+// Examples:
+//   - "Customer HTML Form (PF)" -> "pingcli__Customer-0020-HTML-0020-Form-0020--0028-PF-0029-"
+//   - ("origin", "company") -> "pingcli__origin_company"
+type pingcliNaming struct{}
+
+func (pingcliNaming) Name() string { return NamingStrategyPingCLI }
+
+func (pingcliNaming) ResourceName(_ string, keys ...string) string {
+	return SanitizeMultiKeyResourceName(keys...)
+}
+
+func (pingcliNaming) Deduplicate(name string, n int) string {
+	return fmt.Sprintf("%s_%d", name, n)
+}
+
+// delimitedNaming lowercases the words of a name and joins them with a separator
+//
+// Examples (snake):
+//   - "Customer HTML Form (PF)" -> "customer_html_form_pf"
+//   - ("recaptchaSecret", "company") -> "recaptchasecret_company"
+type delimitedNaming struct {
+	name      string
+	separator string
+}
+
+func (s delimitedNaming) Name() string { return s.name }
+
+func (s delimitedNaming) ResourceName(_ string, keys ...string) string {
+	return joinWords(keys, s.separator)
+}
+
+func (s delimitedNaming) Deduplicate(name string, n int) string {
+	return fmt.Sprintf("%s%s%d", name, s.separator, n)
+}
+
+// idSuffixNaming is the snake strategy followed by the first 8 characters of the resource ID,
+// so names stay unique when display names collide
+//
+// Examples:
+//   - ("c2a0f8e1-...", "Customer Login") -> "customer_login_c2a0f8e1"
+type idSuffixNaming struct{}
+
+func (idSuffixNaming) Name() string { return NamingStrategyIDSuffix }
+
+func (idSuffixNaming) ResourceName(id string, keys ...string) string {
+	name := joinWords(keys, "_")
+	id = strings.ToLower(nonAlphanumeric.ReplaceAllString(id, ""))
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	if id == "" {
+		return name
+	}
+	return name + "_" + id
+}
+
+func (idSuffixNaming) Deduplicate(name string, n int) string {
+	return fmt.Sprintf("%s_%d", name, n)
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^0-9A-Za-z]+`)
+
+// joinWords lowercases the words of the keys and joins them with the separator. Names that
+// would not start with a letter are prefixed with an underscore, and names without any word
+// become "unnamed".
+func joinWords(keys []string, separator string) string {
+	var words []string
+	for _, key := range keys {
+		for _, word := range strings.Fields(nonAlphanumeric.ReplaceAllString(key, " ")) {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+	if len(words) == 0 {
+		return "unnamed"
+	}
+
+	name := strings.Join(words, separator)
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
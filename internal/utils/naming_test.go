@@ -0,0 +1,67 @@
+// Copyright © 2025 Ping Identity Corporation
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/utils"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	testCases := []struct {
+		strategy string
+		id       string
+		keys     []string
+		expected string
+		second   string
+	}{
+		{"pingcli", "c2a0f8e1-1111", []string{"Customer HTML Form (PF)"}, "pingcli__Customer-0020-HTML-0020-Form-0020--0028-PF-0029-", "pingcli__Customer-0020-HTML-0020-Form-0020--0028-PF-0029-_2"},
+		{"pingcli", "var-1", []string{"origin", "company"}, "pingcli__origin_company", "pingcli__origin_company_2"},
+		{"snake", "c2a0f8e1-1111", []string{"Customer HTML Form (PF)"}, "customer_html_form_pf", "customer_html_form_pf_2"},
+		{"snake", "var-1", []string{"recaptchaSecret", "company"}, "recaptchasecret_company", "recaptchasecret_company_2"},
+		{"snake", "flow-1", []string{"2FA -- Login!"}, "_2fa_login", "_2fa_login_2"},
+		{"snake", "flow-1", []string{"¿?"}, "unnamed", "unnamed_2"},
+		{"kebab", "c2a0f8e1-1111", []string{"Customer HTML Form (PF)"}, "customer-html-form-pf", "customer-html-form-pf-2"},
+		{"kebab", "var-1", []string{"origin", "flowInstance"}, "origin-flowinstance", "origin-flowinstance-2"},
+		{"id-suffix", "c2a0f8e1-1111-2222", []string{"Customer Login"}, "customer_login_c2a0f8e1", "customer_login_c2a0f8e1_2"},
+		{"id-suffix", "", []string{"Customer Login"}, "customer_login", "customer_login_2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.strategy+" "+strings.Join(tc.keys, " "), func(t *testing.T) {
+			strategy, err := utils.NewNamingStrategy(tc.strategy)
+			if err != nil {
+				t.Fatalf("NewNamingStrategy(%q) returned error: %v", tc.strategy, err)
+			}
+			if strategy.Name() != tc.strategy {
+				t.Errorf("Name() = %q, expected %q", strategy.Name(), tc.strategy)
+			}
+
+			name := strategy.ResourceName(tc.id, tc.keys...)
+			if name != tc.expected {
+				t.Errorf("ResourceName(%q, %q) = %q, expected %q", tc.id, tc.keys, name, tc.expected)
+			}
+			if second := strategy.Deduplicate(name, 2); second != tc.second {
+				t.Errorf("Deduplicate(%q, 2) = %q, expected %q", name, second, tc.second)
+			}
+		})
+	}
+}
+
+func TestNewNamingStrategy(t *testing.T) {
+	strategy, err := utils.NewNamingStrategy("")
+	if err != nil || strategy.Name() != utils.NamingStrategyPingCLI {
+		t.Errorf("Expected empty name to select the pingcli strategy, got %v, %v", strategy, err)
+	}
+
+	// The pingcli strategy matches the existing sanitization
+	if got := strategy.ResourceName("", "My Flow"); got != utils.SanitizeResourceName("My Flow") {
+		t.Errorf("Expected pingcli strategy to match SanitizeResourceName, got %q", got)
+	}
+
+	_, err = utils.NewNamingStrategy("camel")
+	if err == nil || !strings.Contains(err.Error(), "unsupported naming strategy: camel. Supported: pingcli, snake, kebab, id-suffix") {
+		t.Errorf("Expected unsupported strategy error, got %v", err)
+	}
+}
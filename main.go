@@ -153,6 +153,7 @@ Usage:
 
 Available subcommands:
   export         - Export Ping Identity resources to HCL
+  convert        - Convert saved DaVinci JSON files to HCL (offline)
   help           - Show this help message
 
 Examples:
@@ -160,6 +161,9 @@ Examples:
   # Export PingOne DaVinci resources to Terraform HCL
   pingcli-terraformer export --services pingone-davinci --environment-id "<uuid>"
 
+  # Convert a DaVinci flow export to Terraform HCL without credentials
+  pingcli-terraformer convert --input ./my-flow.json --out ./terraform
+
 Global Flags:
   -h, --help      Show help message
   -v, --version   Show version information
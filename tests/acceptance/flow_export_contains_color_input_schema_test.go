@@ -21,7 +21,7 @@ func TestFlowExportContainsColorAndInputSchema(t *testing.T) {
 	ctx := context.Background()
 
 	graph := resolver.NewDependencyGraph()
-	hcl, _, err := exporter.ExportFlowsWithImports(ctx, client, false, graph, nil)
+	hcl, _, err := exporter.ExportFlowsWithImports(ctx, client, false, graph, nil, nil)
 	require.NoError(t, err, "export failed")
 
 	// Allow flexible spacing around equals for Terraform formatting
@@ -6,6 +6,7 @@ package acceptance
 import (
 	"context"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -108,7 +109,7 @@ func TestExportVariablesValidateHCLStructure(t *testing.T) {
 
 		foundContexts := make(map[string]bool)
 		for _, ctx := range contexts {
-			if strings.Contains(hcl, "context        = \""+ctx+"\"") {
+			if regexp.MustCompile(`context\s+= "` + ctx + `"`).MatchString(hcl) {
 				foundContexts[ctx] = true
 				t.Logf("Found variable with context: %s", ctx)
 			}
@@ -123,7 +124,7 @@ func TestExportVariablesValidateHCLStructure(t *testing.T) {
 
 		foundTypes := make(map[string]bool)
 		for _, dt := range dataTypes {
-			if strings.Contains(hcl, "data_type      = \""+dt+"\"") {
+			if regexp.MustCompile(`data_type\s+= "` + dt + `"`).MatchString(hcl) {
 				foundTypes[dt] = true
 				t.Logf("Found variable with data type: %s", dt)
 			}
@@ -182,9 +183,9 @@ func TestExportVariablesValueHandling(t *testing.T) {
 	t.Run("ValidateValueFields", func(t *testing.T) {
 		// Variables may have values, min, max fields
 		hasValue := strings.Contains(hcl, "value =") || strings.Contains(hcl, "value = {")
-		hasMin := strings.Contains(hcl, "min            =")
-		hasMax := strings.Contains(hcl, "max            =")
-		hasMutable := strings.Contains(hcl, "mutable        =")
+		hasMin := regexp.MustCompile(`\bmin\s+=`).MatchString(hcl)
+		hasMax := regexp.MustCompile(`\bmax\s+=`).MatchString(hcl)
+		hasMutable := regexp.MustCompile(`\bmutable\s+=`).MatchString(hcl)
 
 		t.Logf("HCL contains value field: %v", hasValue)
 		t.Logf("HCL contains min field: %v", hasMin)
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/exporter"
+	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/model"
 	"github.com/samir-gandhi/pingcli-plugin-terraformer/internal/module"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,9 +44,9 @@ func TestModuleGenerationBasic(t *testing.T) {
 				Value:       "test.value",
 			},
 		},
-		Resources: module.ModuleResources{
-			FlowsHCL:       "resource \"pingone_davinci_flow\" \"test\" {}\n",
-			ConnectionsHCL: "resource \"pingone_davinci_connector_instance\" \"http\" {}\n",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_flow", "test"),
+			model.NewResource("pingone_davinci_connector_instance", "http"),
 		},
 	}
 
@@ -109,8 +110,8 @@ func TestModuleGenerationWithValues(t *testing.T) {
 				ResourceType: "variable",
 			},
 		},
-		Resources: module.ModuleResources{
-			VariablesHCL: "resource \"pingone_davinci_variable\" \"company_name\" {}\n",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_variable", "company_name"),
 		},
 	}
 
@@ -146,8 +147,8 @@ func TestModuleGenerationWithImports(t *testing.T) {
 
 	structure := &module.ModuleStructure{
 		Config: config,
-		Resources: module.ModuleResources{
-			FlowsHCL: "resource \"pingone_davinci_flow\" \"main\" {}\n",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_flow", "main"),
 		},
 		ImportBlocks: []module.ImportBlock{
 			{
@@ -189,8 +190,8 @@ func TestModuleGenerationCustomDirectory(t *testing.T) {
 
 	structure := &module.ModuleStructure{
 		Config: config,
-		Resources: module.ModuleResources{
-			FlowsHCL: "resource \"pingone_davinci_flow\" \"test\" {}\n",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_flow", "test"),
 		},
 	}
 
@@ -223,12 +224,12 @@ func TestModuleGenerationAllResourceTypes(t *testing.T) {
 
 	structure := &module.ModuleStructure{
 		Config: config,
-		Resources: module.ModuleResources{
-			FlowsHCL:        "resource \"pingone_davinci_flow\" \"main\" {}\n",
-			ConnectionsHCL:  "resource \"pingone_davinci_connector_instance\" \"http\" {}\n",
-			VariablesHCL:    "resource \"pingone_davinci_variable\" \"company_name\" {}\n",
-			ApplicationsHCL: "resource \"pingone_davinci_application\" \"app\" {}\n",
-			FlowPoliciesHCL: "resource \"pingone_davinci_application_flow_policy\" \"policy\" {}\n",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_flow", "main"),
+			model.NewResource("pingone_davinci_connector_instance", "http"),
+			model.NewResource("pingone_davinci_variable", "company_name"),
+			model.NewResource("pingone_davinci_application", "app"),
+			model.NewResource("pingone_davinci_application_flow_policy", "policy"),
 		},
 	}
 
@@ -272,12 +273,14 @@ func TestModuleGenerationAllResourceTypes(t *testing.T) {
 func TestExportedDataConversion(t *testing.T) {
 	// Create mock exported data
 	exportedData := &exporter.ExportedData{
-		EnvironmentID:   "test-env-123",
-		Region:          "NA",
-		FlowsHCL:        "resource \"pingone_davinci_flow\" \"test\" {}\n",
-		ConnectorsHCL:   "resource \"pingone_davinci_connector_instance\" \"http\" {}\n",
-		VariablesHCL:    "resource \"pingone_davinci_variable\" \"test_var\" {}\n",
-		ApplicationsHCL: "resource \"pingone_davinci_application\" \"app\" {}\n",
+		EnvironmentID: "test-env-123",
+		Region:        "NA",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_variable", "test_var"),
+			model.NewResource("pingone_davinci_connector_instance", "http"),
+			model.NewResource("pingone_davinci_flow", "test"),
+			model.NewResource("pingone_davinci_application", "app"),
+		},
 	}
 
 	// Create module config
@@ -296,10 +299,7 @@ func TestExportedDataConversion(t *testing.T) {
 
 	// Verify structure
 	assert.Equal(t, config, structure.Config)
-	assert.Equal(t, exportedData.FlowsHCL, structure.Resources.FlowsHCL)
-	assert.Equal(t, exportedData.ConnectorsHCL, structure.Resources.ConnectionsHCL)
-	assert.Equal(t, exportedData.VariablesHCL, structure.Resources.VariablesHCL)
-	assert.Equal(t, exportedData.ApplicationsHCL, structure.Resources.ApplicationsHCL)
+	assert.Equal(t, exportedData.Resources, structure.Resources)
 }
 
 // TestModuleGenerationSecretHandling tests that secrets have TODO comments
@@ -327,8 +327,8 @@ func TestModuleGenerationSecretHandling(t *testing.T) {
 				ResourceType: "connection",
 			},
 		},
-		Resources: module.ModuleResources{
-			ConnectionsHCL: "resource \"pingone_davinci_connector_instance\" \"http\" {}\n",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_connector_instance", "http"),
 		},
 	}
 
@@ -381,8 +381,8 @@ func TestModuleGenerationValidation(t *testing.T) {
 				},
 			},
 		},
-		Resources: module.ModuleResources{
-			VariablesHCL: "resource \"pingone_davinci_variable\" \"test\" {}\n",
+		Resources: []*model.Resource{
+			model.NewResource("pingone_davinci_variable", "test"),
 		},
 	}
 